@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"test-bpjs/v2/config"
+	"test-bpjs/v2/repository"
+	skillService "test-bpjs/v2/service/skill"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+// backfillskills rewrites the stored skill rows to the canonical names of the
+// skill taxonomy and the known skill levels. Run it once after deploying a
+// new version of the taxonomy; with -dry-run it only reports what it would
+// change.
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report the changes without writing them")
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
+	defer cancel()
+	cfg, err := config.LoadConfig("./config", "config")
+	if err != nil {
+		log.Fatalf("failed to load config file: %v", err)
+	}
+
+	dbConn := sql.OpenDB(pgdriver.NewConnector(
+		pgdriver.WithDSN(cfg.DatabaseURL),
+		pgdriver.WithConnParams(map[string]interface{}{
+			"search_path": cfg.DatabaseSchema,
+		})))
+	defer dbConn.Close()
+	if err := dbConn.PingContext(ctx); err != nil {
+		log.Fatalf("unable to ping database: %v", err)
+	}
+
+	bunDB := bun.NewDB(dbConn, pgdialect.New(), bun.WithDiscardUnknownColumns())
+	backfill, err := skillService.NewSkillBackfillService(repository.NewSkillRepository(bunDB)).Backfill(ctx, *dryRun)
+	if err != nil {
+		log.Fatalf("failed to backfill skills after %d rows: %v", backfill.Checked, err)
+	}
+	verb := "updated"
+	if *dryRun {
+		verb = "would update"
+	}
+	log.Printf("taxonomy %s: checked %d skill rows, %s %d, skipped %d changed meanwhile, %d not in the taxonomy, %d with an unknown level",
+		backfill.TaxonomyVersion, backfill.Checked, verb, backfill.Updated, backfill.Skipped, backfill.Unrecognized, backfill.InvalidLevels)
+}
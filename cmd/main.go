@@ -9,13 +9,28 @@ import (
 	"runtime"
 	"syscall"
 	"test-bpjs/v2/config"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/helper/fieldcrypt"
+	"test-bpjs/v2/helper/ratelimit"
 	"test-bpjs/v2/repository"
 	"test-bpjs/v2/server"
+	apiKeyService "test-bpjs/v2/service/apikey"
+	auditService "test-bpjs/v2/service/audit"
+	authService "test-bpjs/v2/service/auth"
+	authorizationService "test-bpjs/v2/service/authorization"
+	consentService "test-bpjs/v2/service/consent"
 	educationService "test-bpjs/v2/service/education"
 	employmentService "test-bpjs/v2/service/employment"
+	idempotencyService "test-bpjs/v2/service/idempotency"
+	jobService "test-bpjs/v2/service/job"
+	privacyService "test-bpjs/v2/service/privacy"
 	profileService "test-bpjs/v2/service/profile"
+	resumeService "test-bpjs/v2/service/resume"
+	searchService "test-bpjs/v2/service/search"
 	skillService "test-bpjs/v2/service/skill"
+	timelineService "test-bpjs/v2/service/timeline"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/pgdialect"
 	"github.com/uptrace/bun/driver/pgdriver"
@@ -47,15 +62,64 @@ func main() {
 	bunDB := bun.NewDB(dbConn, pgdialect.New(), bun.WithDiscardUnknownColumns())
 	bunDB.AddQueryHook(bunotel.NewQueryHook(bunotel.WithDBName("test-bpjs")))
 
-	profileRepository := repository.NewProfileRepository(bunDB)
+	cipher, err := fieldcrypt.NewCipher(cfg)
+	if err != nil {
+		log.Fatalf("failed to configure encryption: %v", err)
+	}
+
+	profileRepository := repository.NewProfileRepository(bunDB, cipher)
 	skillRepository := repository.NewSkillRepository(bunDB)
 	employmentRepository := repository.NewEmploymentRepository(bunDB)
 	educationRepository := repository.NewEducationRepository(bunDB)
+	userRepository := repository.NewUserRepository(bunDB)
+	apiKeyRepository := repository.NewApiKeyRepository(bunDB)
+	privacyRepository := repository.NewPrivacyRepository(bunDB)
+	consentRepository := repository.NewConsentRepository(bunDB)
+	auditRepository := repository.NewAuditRepository(bunDB, cipher)
+	resumeRepository := repository.NewResumeRepository(bunDB, cipher)
+	idempotencyRepository := repository.NewIdempotencyRepository(bunDB, cipher)
+	searchRepository := repository.NewSearchRepository(bunDB)
+	jobPostingRepository := repository.NewJobPostingRepository(bunDB)
+
+	tokenManager, err := auth.NewTokenManager(cfg)
+	if err != nil {
+		log.Fatalf("failed to configure jwt: %v", err)
+	}
+
+	var rateLimitStore ratelimit.Store
+	switch cfg.RateLimitStore {
+	case "", "memory":
+		rateLimitStore = ratelimit.NewMemoryStore()
+	case "redis":
+		redisOptions, err := redis.ParseURL(cfg.RedisURL)
+		if err != nil {
+			log.Fatalf("failed to parse redis url: %v", err)
+		}
+		redisClient := redis.NewClient(redisOptions)
+		defer redisClient.Close()
+		rateLimitStore = ratelimit.NewRedisStore(redisClient)
+	default:
+		log.Fatalf("unsupported rate limit store: %s", cfg.RateLimitStore)
+	}
+	limiter := ratelimit.NewLimiter(rateLimitStore, cfg.RateLimits)
+	ipLimiter := ratelimit.NewLimiter(rateLimitStore, cfg.IPRateLimits)
+
+	authorizer := authorizationService.NewAuthorizer(profileRepository, consentRepository, cfg.ConsentTermsVersion)
 
-	profileService := profileService.NewProfileService(profileRepository)
-	skillService := skillService.NewSkillService(skillRepository)
-	employmentService := employmentService.NewEmploymentService(employmentRepository)
-	educationService := educationService.NewEducationService(educationRepository)
+	auditService := auditService.NewAuditService(auditRepository, authorizer)
+	timelineService := timelineService.NewTimelineService(employmentRepository, authorizer, cfg.EmploymentGapThreshold)
+	resumeService := resumeService.NewResumeService(profileRepository, educationRepository, employmentRepository, skillRepository, resumeRepository, authorizer, auditService, timelineService)
+	profileService := profileService.NewProfileService(profileRepository, authorizer, auditService, resumeService)
+	skillService := skillService.NewSkillService(skillRepository, authorizer, auditService, resumeService)
+	employmentService := employmentService.NewEmploymentService(employmentRepository, authorizer, auditService, resumeService)
+	educationService := educationService.NewEducationService(educationRepository, authorizer, auditService, resumeService)
+	authService := authService.NewAuthService(userRepository, tokenManager)
+	apiKeyService := apiKeyService.NewApiKeyService(apiKeyRepository, authorizer)
+	privacyService := privacyService.NewPrivacyService(profileRepository, educationRepository, employmentRepository, skillRepository, consentRepository, auditRepository, resumeRepository, privacyRepository, authorizer)
+	consentService := consentService.NewConsentService(consentRepository, authorizer, cfg.ConsentTermsVersion)
+	idempotencyService := idempotencyService.NewIdempotencyService(idempotencyRepository, cfg.IdempotencyKeyTTL)
+	searchService := searchService.NewSearchService(searchRepository, authorizer)
+	jobPostingService := jobService.NewJobPostingService(jobPostingRepository, authorizer)
 
 	server.RunServer(ctx,
 		&cfg,
@@ -64,5 +128,18 @@ func main() {
 		skillService,
 		employmentService,
 		educationService,
+		authService,
+		apiKeyService,
+		privacyService,
+		consentService,
+		auditService,
+		resumeService,
+		idempotencyService,
+		searchService,
+		jobPostingService,
+		timelineService,
+		tokenManager,
+		limiter,
+		ipLimiter,
 	)
 }
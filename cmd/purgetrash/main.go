@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"test-bpjs/v2/config"
+	"test-bpjs/v2/helper/fieldcrypt"
+	"test-bpjs/v2/repository"
+	idempotencyService "test-bpjs/v2/service/idempotency"
+	trashService "test-bpjs/v2/service/trash"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+// purgetrash removes everything that has been in the trash for longer than
+// the configured retention, along with expired idempotency keys. Run it on a
+// schedule, e.g. once a day from cron.
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
+	defer cancel()
+	cfg, err := config.LoadConfig("./config", "config")
+	if err != nil {
+		log.Fatalf("failed to load config file: %v", err)
+	}
+	if cfg.TrashRetention <= 0 {
+		log.Fatalf("TRASH_RETENTION must be set to a positive duration")
+	}
+
+	dbConn := sql.OpenDB(pgdriver.NewConnector(
+		pgdriver.WithDSN(cfg.DatabaseURL),
+		pgdriver.WithConnParams(map[string]interface{}{
+			"search_path": cfg.DatabaseSchema,
+		})))
+	defer dbConn.Close()
+	if err := dbConn.PingContext(ctx); err != nil {
+		log.Fatalf("unable to ping database: %v", err)
+	}
+
+	cipher, err := fieldcrypt.NewCipher(cfg)
+	if err != nil {
+		log.Fatalf("failed to configure encryption: %v", err)
+	}
+
+	bunDB := bun.NewDB(dbConn, pgdialect.New(), bun.WithDiscardUnknownColumns())
+	trashService := trashService.NewTrashService(
+		repository.NewTrashRepository(bunDB),
+		repository.NewProfileRepository(bunDB, cipher),
+		repository.NewPrivacyRepository(bunDB),
+	)
+	purge, err := trashService.PurgeTrash(ctx, time.Now().Add(-cfg.TrashRetention))
+	if err != nil {
+		log.Fatalf("failed to purge trash after %d profiles: %v", purge.Profiles, err)
+	}
+	log.Printf("purged %d profiles, %d education, %d employment and %d skill rows", purge.Profiles, purge.Education, purge.Employment, purge.Skills)
+
+	idempotencyService := idempotencyService.NewIdempotencyService(repository.NewIdempotencyRepository(bunDB, cipher), cfg.IdempotencyKeyTTL)
+	keys, err := idempotencyService.PurgeExpired(ctx, time.Now())
+	if err != nil {
+		log.Fatalf("failed to purge idempotency keys: %v", err)
+	}
+	log.Printf("purged %d expired idempotency keys", keys)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"test-bpjs/v2/config"
+	"test-bpjs/v2/helper/fieldcrypt"
+	"test-bpjs/v2/repository"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+// rotatekeys re-encrypts the profile columns, the audit log changes, the
+// resume snapshots and the stored idempotent responses with the current
+// encryption key. Run it after adding a new key and making it
+// current; once it finishes, the old key can be removed from the config.
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
+	defer cancel()
+	cfg, err := config.LoadConfig("./config", "config")
+	if err != nil {
+		log.Fatalf("failed to load config file: %v", err)
+	}
+
+	dbConn := sql.OpenDB(pgdriver.NewConnector(
+		pgdriver.WithDSN(cfg.DatabaseURL),
+		pgdriver.WithConnParams(map[string]interface{}{
+			"search_path": cfg.DatabaseSchema,
+		})))
+	defer dbConn.Close()
+	if err := dbConn.PingContext(ctx); err != nil {
+		log.Fatalf("unable to ping database: %v", err)
+	}
+
+	cipher, err := fieldcrypt.NewCipher(cfg)
+	if err != nil {
+		log.Fatalf("failed to configure encryption: %v", err)
+	}
+
+	bunDB := bun.NewDB(dbConn, pgdialect.New(), bun.WithDiscardUnknownColumns())
+	updated, err := repository.NewProfileRepository(bunDB, cipher).RotateProfileKeys(ctx)
+	if err != nil {
+		log.Fatalf("failed to rotate keys after %d profiles: %v", updated, err)
+	}
+	log.Printf("rotated %d profiles", updated)
+
+	updated, err = repository.NewAuditRepository(bunDB, cipher).RotateAuditLogKeys(ctx)
+	if err != nil {
+		log.Fatalf("failed to rotate keys after %d audit logs: %v", updated, err)
+	}
+	log.Printf("rotated %d audit logs", updated)
+
+	updated, err = repository.NewResumeRepository(bunDB, cipher).RotateResumeKeys(ctx)
+	if err != nil {
+		log.Fatalf("failed to rotate keys after %d resume versions: %v", updated, err)
+	}
+	log.Printf("rotated %d resume versions", updated)
+
+	updated, err = repository.NewIdempotencyRepository(bunDB, cipher).RotateIdempotencyKeys(ctx)
+	if err != nil {
+		log.Fatalf("failed to rotate keys after %d idempotent responses: %v", updated, err)
+	}
+	log.Printf("rotated %d idempotent responses", updated)
+}
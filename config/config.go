@@ -1,12 +1,67 @@
 package config
 
 import (
+	"time"
+
 	"github.com/spf13/viper"
 )
 
 type Config struct {
 	DatabaseURL    string `mapstructure:"DATABASE_URL"`
 	DatabaseSchema string `mapstructure:"DATABASE_SCHEMA"`
+
+	// JWTSigningMethod is either HS256 (JWTSecret is used) or RS256 (the PEM
+	// files at JWTPrivateKeyPath and JWTPublicKeyPath are used).
+	JWTSigningMethod  string        `mapstructure:"JWT_SIGNING_METHOD"`
+	JWTSecret         string        `mapstructure:"JWT_SECRET"`
+	JWTPrivateKeyPath string        `mapstructure:"JWT_PRIVATE_KEY_PATH"`
+	JWTPublicKeyPath  string        `mapstructure:"JWT_PUBLIC_KEY_PATH"`
+	JWTIssuer         string        `mapstructure:"JWT_ISSUER"`
+	JWTExpiry         time.Duration `mapstructure:"JWT_EXPIRY"`
+
+	// RateLimitStore is either memory (the default) or redis, in which case
+	// RedisURL is used.
+	RateLimitStore string            `mapstructure:"RATE_LIMIT_STORE"`
+	RedisURL       string            `mapstructure:"REDIS_URL"`
+	RateLimits     []RateLimitPolicy `mapstructure:"RATE_LIMITS"`
+	// IPRateLimits limit every client IP before its credentials are checked,
+	// so guessing tokens or API keys is throttled too.
+	IPRateLimits []RateLimitPolicy `mapstructure:"IP_RATE_LIMITS"`
+
+	// EncryptionKeys maps key ids to base64 encoded 32 byte AES keys. New
+	// values are sealed with EncryptionKeyId; older keys stay listed until
+	// every row has been rotated. Encryption is off when no key is set.
+	EncryptionKeys   map[string]string `mapstructure:"ENCRYPTION_KEYS"`
+	EncryptionKeyId  string            `mapstructure:"ENCRYPTION_KEY_ID"`
+	BlindIndexKey    string            `mapstructure:"BLIND_INDEX_KEY"`
+	EncryptedColumns []string          `mapstructure:"ENCRYPTED_COLUMNS"`
+
+	// ConsentTermsVersion is the version of the terms candidates currently
+	// agree to. Recruiters only see profiles consenting to this version.
+	ConsentTermsVersion string `mapstructure:"CONSENT_TERMS_VERSION"`
+
+	// TrashRetention is how long deleted profiles, education, employment and
+	// skills stay in the trash before cmd/purgetrash removes them for good.
+	TrashRetention time.Duration `mapstructure:"TRASH_RETENTION"`
+
+	// IdempotencyKeyTTL is how long responses to create requests sent with
+	// an Idempotency-Key header are replayed to retries (24h when unset).
+	IdempotencyKeyTTL time.Duration `mapstructure:"IDEMPOTENCY_KEY_TTL"`
+
+	// EmploymentGapThreshold is how long a break between two jobs may last
+	// before the employment timeline reports it as a gap (90 days when unset).
+	EmploymentGapThreshold time.Duration `mapstructure:"EMPLOYMENT_GAP_THRESHOLD"`
+}
+
+// RateLimitPolicy allows Requests per Per on one route, with bursts of up to
+// Burst requests (Requests when unset). Path is the echo route, e.g.
+// /api/photo/:profileCode, or * for every route without its own policy.
+type RateLimitPolicy struct {
+	Method   string        `mapstructure:"METHOD"`
+	Path     string        `mapstructure:"PATH"`
+	Requests int           `mapstructure:"REQUESTS"`
+	Per      time.Duration `mapstructure:"PER"`
+	Burst    int           `mapstructure:"BURST"`
 }
 
 func LoadConfig(path string, filename string) (Config, error) {
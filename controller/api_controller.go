@@ -1,10 +1,18 @@
 package controller
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
+	"test-bpjs/v2/helper/etag"
+	"test-bpjs/v2/models"
 	"test-bpjs/v2/models/request"
+	apiKeyService "test-bpjs/v2/service/apikey"
+	authorizationService "test-bpjs/v2/service/authorization"
 	educationService "test-bpjs/v2/service/education"
 	employmentService "test-bpjs/v2/service/employment"
+	jobService "test-bpjs/v2/service/job"
 	profileService "test-bpjs/v2/service/profile"
 	skillService "test-bpjs/v2/service/skill"
 	"time"
@@ -43,13 +51,18 @@ func NewApiControllerHandler(
 func (h *apiControllerHandler) MapRoutes() {
 	//profile
 	h.group.GET("/profile/:profileCode", h.GetProfileByCode())
+	h.group.GET("/profiles", h.ListProfiles())
 	h.group.POST("/profile", h.CreateProfile())
 	h.group.PUT("/profile/:profileCode", h.UpdateProfile())
+	h.group.PATCH("/profile/:profileCode", h.PatchProfile())
+	h.group.DELETE("/profile/:profileCode", h.DeleteProfile())
+	h.group.POST("/profile/:profileCode/restore", h.RestoreProfile())
 
 	//photo
 	h.group.GET("/photo/:profileCode", h.DownloadPhoto())
 	h.group.PUT("/photo/:profileCode", h.UploadPhoto())
 	h.group.DELETE("/photo/:profileCode", h.DeletePhoto())
+	h.group.GET("/photo/:profileCode/avatar", h.DownloadAvatar())
 
 	//working experiences
 	h.group.GET("/working-experience/:profileCode", h.GetWorkingExperienceByCode())
@@ -58,17 +71,33 @@ func (h *apiControllerHandler) MapRoutes() {
 	//education
 	h.group.GET("/education/:profileCode", h.GetEducationListByCode())
 	h.group.POST("/education/:profileCode", h.AddEducationByCode())
+	h.group.POST("/education/:profileCode/batch", h.CreateEducationRowsByCode())
+	h.group.PUT("/education/:profileCode", h.ReplaceEducationRowsByCode())
+	h.group.PATCH("/education/:profileCode", h.PatchEducationByCodeAndId())
 	h.group.DELETE("/education/:profileCode", h.DeleteEducationByCodeAndId())
+	h.group.GET("/education/:profileCode/trash", h.GetDeletedEducationListByCode())
+	h.group.POST("/education/:profileCode/restore", h.RestoreEducationByCodeAndId())
 
 	//employment
 	h.group.GET("/employment/:profileCode", h.GetEmploymentListByCode())
 	h.group.POST("/employment/:profileCode", h.AddEmploymentByCode())
+	h.group.POST("/employment/:profileCode/batch", h.CreateEmploymentRowsByCode())
+	h.group.PUT("/employment/:profileCode", h.ReplaceEmploymentRowsByCode())
+	h.group.PATCH("/employment/:profileCode", h.PatchEmploymentByCodeAndId())
 	h.group.DELETE("/employment/:profileCode", h.DeleteEmploymentByCodeAndId())
+	h.group.GET("/employment/:profileCode/trash", h.GetDeletedEmploymentListByCode())
+	h.group.POST("/employment/:profileCode/restore", h.RestoreEmploymentByCodeAndId())
 
 	//skill
 	h.group.GET("/skill/:profileCode", h.GetSkillListByCode())
 	h.group.POST("/skill/:profileCode", h.AddSkillByCode())
+	h.group.POST("/skill/:profileCode/batch", h.CreateSkillsByCode())
+	h.group.PUT("/skill/:profileCode", h.ReplaceSkillsByCode())
+	h.group.PATCH("/skill/:profileCode", h.PatchSkillByCodeAndId())
 	h.group.DELETE("/skill/:profileCode", h.DeleteSkillByCodeAndId())
+	h.group.GET("/skill/:profileCode/trash", h.GetDeletedSkillListByCode())
+	h.group.POST("/skill/:profileCode/restore", h.RestoreSkillByCodeAndId())
+	h.group.GET("/skills/suggest", h.SuggestSkills())
 }
 
 func (h *apiControllerHandler) GetProfileByCode() echo.HandlerFunc {
@@ -88,7 +117,32 @@ func (h *apiControllerHandler) GetProfileByCode() echo.HandlerFunc {
 
 		res, err := h.profileService.GetProfileByCode(ctx, request.ProfileCode)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, err)
+			return serviceError(err)
+		}
+
+		c.Response().Header().Set(etag.HeaderETag, etag.Format(res.Version))
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+func (h *apiControllerHandler) ListProfiles() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "ListProfiles", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.ListProfilesRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		res, err := h.profileService.ListProfiles(ctx, request)
+		if err != nil {
+			return serviceError(err)
 		}
 
 		return c.JSON(http.StatusOK, res)
@@ -109,7 +163,7 @@ func (h *apiControllerHandler) CreateProfile() echo.HandlerFunc {
 
 		res, err := h.profileService.CreateProfile(ctx, request)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, err)
+			return serviceError(err)
 		}
 
 		return c.JSON(http.StatusOK, res)
@@ -131,9 +185,104 @@ func (h *apiControllerHandler) UpdateProfile() echo.HandlerFunc {
 			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
 		}
 
+		version, err := ifMatch(c)
+		if err != nil {
+			return err
+		}
+		request.Version = version
+
 		res, err := h.profileService.UpdateProfile(ctx, request)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, err)
+			return serviceError(err)
+		}
+
+		c.Response().Header().Set(etag.HeaderETag, etag.Format(res.Version))
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+func (h *apiControllerHandler) PatchProfile() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "PatchProfile", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.PatchProfileRequest
+		if err := mergePatch(c, &request); err != nil {
+			return err
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		if members := request.NullRequired(); len(members) > 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. "+strings.Join(members, ", ")+" cannot be null")
+		}
+
+		version, err := ifMatch(c)
+		if err != nil {
+			return err
+		}
+		request.Version = version
+
+		res, err := h.profileService.PatchProfile(ctx, request)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		c.Response().Header().Set(etag.HeaderETag, etag.Format(res.Version))
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+func (h *apiControllerHandler) DeleteProfile() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "DeleteProfile", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.GetProfileRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		version, err := ifMatch(c)
+		if err != nil {
+			return err
+		}
+
+		res, err := h.profileService.DeleteProfile(ctx, request.ProfileCode, version)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+func (h *apiControllerHandler) RestoreProfile() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "RestoreProfile", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.GetProfileRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		res, err := h.profileService.RestoreProfile(ctx, request.ProfileCode)
+		if err != nil {
+			return serviceError(err)
 		}
 
 		return c.JSON(http.StatusOK, res)
@@ -157,7 +306,7 @@ func (h *apiControllerHandler) DownloadPhoto() echo.HandlerFunc {
 
 		res, err := h.profileService.DownloadPhotoByCode(ctx, request.ProfileCode)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, err)
+			return serviceError(err)
 		}
 
 		return c.JSON(http.StatusOK, res)
@@ -179,9 +328,15 @@ func (h *apiControllerHandler) UploadPhoto() echo.HandlerFunc {
 			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
 		}
 
+		version, err := ifMatch(c)
+		if err != nil {
+			return err
+		}
+		request.Version = version
+
 		res, err := h.profileService.UploadPhotoByCode(ctx, request)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, err)
+			return serviceError(err)
 		}
 
 		return c.JSON(http.StatusOK, res)
@@ -203,9 +358,38 @@ func (h *apiControllerHandler) DeletePhoto() echo.HandlerFunc {
 			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
 		}
 
-		res, err := h.profileService.DeletePhotoByCode(ctx, request.ProfileCode)
+		version, err := ifMatch(c)
+		if err != nil {
+			return err
+		}
+
+		res, err := h.profileService.DeletePhotoByCode(ctx, request.ProfileCode, version)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+func (h *apiControllerHandler) DownloadAvatar() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "DownloadAvatar", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.GetAvatarRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		res, err := h.profileService.DownloadAvatarByCode(ctx, request.ProfileCode, request.Format)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, err)
+			return serviceError(err)
 		}
 
 		return c.JSON(http.StatusOK, res)
@@ -229,9 +413,10 @@ func (h *apiControllerHandler) GetWorkingExperienceByCode() echo.HandlerFunc {
 
 		res, err := h.profileService.GetWorkingExperienceByCode(ctx, request.ProfileCode)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, err)
+			return serviceError(err)
 		}
 
+		c.Response().Header().Set(etag.HeaderETag, etag.Format(res.Version))
 		return c.JSON(http.StatusOK, res)
 	}
 }
@@ -251,11 +436,18 @@ func (h *apiControllerHandler) UpdateWorkingExperienceByCode() echo.HandlerFunc
 			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
 		}
 
+		version, err := ifMatch(c)
+		if err != nil {
+			return err
+		}
+		request.Version = version
+
 		res, err := h.profileService.UpdateProfile(ctx, request)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, err)
+			return serviceError(err)
 		}
 
+		c.Response().Header().Set(etag.HeaderETag, etag.Format(res.Version))
 		return c.JSON(http.StatusOK, res)
 	}
 }
@@ -277,7 +469,7 @@ func (h *apiControllerHandler) GetEducationListByCode() echo.HandlerFunc {
 
 		res, err := h.educationService.GetEducationByCode(ctx, request.ProfileCode)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, err)
+			return serviceError(err)
 		}
 
 		return c.JSON(http.StatusOK, res)
@@ -301,13 +493,99 @@ func (h *apiControllerHandler) AddEducationByCode() echo.HandlerFunc {
 
 		res, err := h.educationService.CreateEducation(ctx, request)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, err)
+			return serviceError(err)
 		}
 
 		return c.JSON(http.StatusOK, res)
 	}
 }
 
+func (h *apiControllerHandler) CreateEducationRowsByCode() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "CreateEducationRowsByCode", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.CreateEducationRowsRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		res, err := h.educationService.CreateEducationRows(ctx, request)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+func (h *apiControllerHandler) ReplaceEducationRowsByCode() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "ReplaceEducationRowsByCode", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.ReplaceEducationRowsRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		version, err := ifMatch(c)
+		if err != nil {
+			return err
+		}
+		request.Version = version
+
+		res, err := h.educationService.ReplaceEducationRows(ctx, request)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		c.Response().Header().Set(etag.HeaderETag, etag.Format(res.Version))
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+func (h *apiControllerHandler) PatchEducationByCodeAndId() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "PatchEducationByCodeAndId", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.PatchEducationRequest
+		if err := mergePatch(c, &request); err != nil {
+			return err
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		version, err := ifMatch(c)
+		if err != nil {
+			return err
+		}
+		request.Version = version
+
+		res, err := h.educationService.PatchEducation(ctx, request)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		c.Response().Header().Set(etag.HeaderETag, etag.Format(res.Version))
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
 func (h *apiControllerHandler) DeleteEducationByCodeAndId() echo.HandlerFunc {
 	return func(c echo.Context) error {
 
@@ -323,9 +601,62 @@ func (h *apiControllerHandler) DeleteEducationByCodeAndId() echo.HandlerFunc {
 			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
 		}
 
-		res, err := h.educationService.DeleteEducation(ctx, request.ProfileCode, request.Id)
+		version, err := ifMatch(c)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, err)
+			return err
+		}
+
+		res, err := h.educationService.DeleteEducation(ctx, request.ProfileCode, request.Id, version)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+func (h *apiControllerHandler) GetDeletedEducationListByCode() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "GetDeletedEducationListByCode", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.GetProfileRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		res, err := h.educationService.GetDeletedEducationByCode(ctx, request.ProfileCode)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+func (h *apiControllerHandler) RestoreEducationByCodeAndId() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "RestoreEducationByCodeAndId", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.RestoreDataByProfileCodeAndId
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		res, err := h.educationService.RestoreEducation(ctx, request.ProfileCode, request.Id)
+		if err != nil {
+			return serviceError(err)
 		}
 
 		return c.JSON(http.StatusOK, res)
@@ -349,7 +680,7 @@ func (h *apiControllerHandler) GetEmploymentListByCode() echo.HandlerFunc {
 
 		res, err := h.employmentService.GetEmploymentByCode(ctx, request.ProfileCode)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, err)
+			return serviceError(err)
 		}
 
 		return c.JSON(http.StatusOK, res)
@@ -373,13 +704,99 @@ func (h *apiControllerHandler) AddEmploymentByCode() echo.HandlerFunc {
 
 		res, err := h.employmentService.CreateEmployment(ctx, request)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, err)
+			return serviceError(err)
 		}
 
 		return c.JSON(http.StatusOK, res)
 	}
 }
 
+func (h *apiControllerHandler) CreateEmploymentRowsByCode() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "CreateEmploymentRowsByCode", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.CreateEmploymentRowsRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		res, err := h.employmentService.CreateEmploymentRows(ctx, request)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+func (h *apiControllerHandler) ReplaceEmploymentRowsByCode() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "ReplaceEmploymentRowsByCode", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.ReplaceEmploymentRowsRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		version, err := ifMatch(c)
+		if err != nil {
+			return err
+		}
+		request.Version = version
+
+		res, err := h.employmentService.ReplaceEmploymentRows(ctx, request)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		c.Response().Header().Set(etag.HeaderETag, etag.Format(res.Version))
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+func (h *apiControllerHandler) PatchEmploymentByCodeAndId() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "PatchEmploymentByCodeAndId", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.PatchEmploymentRequest
+		if err := mergePatch(c, &request); err != nil {
+			return err
+		}
+
+		if err := c.Validate(request); err != nil || !models.ValidEmploymentType(request.EmploymentType.Value) {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		version, err := ifMatch(c)
+		if err != nil {
+			return err
+		}
+		request.Version = version
+
+		res, err := h.employmentService.PatchEmployment(ctx, request)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		c.Response().Header().Set(etag.HeaderETag, etag.Format(res.Version))
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
 func (h *apiControllerHandler) DeleteEmploymentByCodeAndId() echo.HandlerFunc {
 	return func(c echo.Context) error {
 
@@ -395,9 +812,62 @@ func (h *apiControllerHandler) DeleteEmploymentByCodeAndId() echo.HandlerFunc {
 			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
 		}
 
-		res, err := h.employmentService.DeleteEmployment(ctx, request.ProfileCode, request.Id)
+		version, err := ifMatch(c)
+		if err != nil {
+			return err
+		}
+
+		res, err := h.employmentService.DeleteEmployment(ctx, request.ProfileCode, request.Id, version)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+func (h *apiControllerHandler) GetDeletedEmploymentListByCode() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "GetDeletedEmploymentListByCode", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.GetProfileRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		res, err := h.employmentService.GetDeletedEmploymentByCode(ctx, request.ProfileCode)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, err)
+			return serviceError(err)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+func (h *apiControllerHandler) RestoreEmploymentByCodeAndId() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "RestoreEmploymentByCodeAndId", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.RestoreDataByProfileCodeAndId
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		res, err := h.employmentService.RestoreEmployment(ctx, request.ProfileCode, request.Id)
+		if err != nil {
+			return serviceError(err)
 		}
 
 		return c.JSON(http.StatusOK, res)
@@ -421,7 +891,7 @@ func (h *apiControllerHandler) GetSkillListByCode() echo.HandlerFunc {
 
 		res, err := h.skillService.GetSkillsByCode(ctx, request.ProfileCode)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, err)
+			return serviceError(err)
 		}
 
 		return c.JSON(http.StatusOK, res)
@@ -445,9 +915,95 @@ func (h *apiControllerHandler) AddSkillByCode() echo.HandlerFunc {
 
 		res, err := h.skillService.CreateSkill(ctx, request)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, err)
+			return serviceError(err)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+func (h *apiControllerHandler) CreateSkillsByCode() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "CreateSkillsByCode", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.CreateSkillsRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		res, err := h.skillService.CreateSkills(ctx, request)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+func (h *apiControllerHandler) ReplaceSkillsByCode() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "ReplaceSkillsByCode", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.ReplaceSkillsRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		version, err := ifMatch(c)
+		if err != nil {
+			return err
 		}
+		request.Version = version
 
+		res, err := h.skillService.ReplaceSkills(ctx, request)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		c.Response().Header().Set(etag.HeaderETag, etag.Format(res.Version))
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+func (h *apiControllerHandler) PatchSkillByCodeAndId() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "PatchSkillByCodeAndId", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.PatchSkillRequest
+		if err := mergePatch(c, &request); err != nil {
+			return err
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		version, err := ifMatch(c)
+		if err != nil {
+			return err
+		}
+		request.Version = version
+
+		res, err := h.skillService.PatchSkill(ctx, request)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		c.Response().Header().Set(etag.HeaderETag, etag.Format(res.Version))
 		return c.JSON(http.StatusOK, res)
 	}
 }
@@ -467,11 +1023,155 @@ func (h *apiControllerHandler) DeleteSkillByCodeAndId() echo.HandlerFunc {
 			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
 		}
 
-		res, err := h.skillService.DeleteSkill(ctx, request.ProfileCode, request.Id)
+		version, err := ifMatch(c)
+		if err != nil {
+			return err
+		}
+
+		res, err := h.skillService.DeleteSkill(ctx, request.ProfileCode, request.Id, version)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+func (h *apiControllerHandler) GetDeletedSkillListByCode() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "GetDeletedSkillListByCode", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.GetProfileRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		res, err := h.skillService.GetDeletedSkillsByCode(ctx, request.ProfileCode)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, err)
+			return serviceError(err)
 		}
 
 		return c.JSON(http.StatusOK, res)
 	}
 }
+
+func (h *apiControllerHandler) RestoreSkillByCodeAndId() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "RestoreSkillByCodeAndId", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.RestoreDataByProfileCodeAndId
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		res, err := h.skillService.RestoreSkill(ctx, request.ProfileCode, request.Id)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+func (h *apiControllerHandler) SuggestSkills() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "SuggestSkills", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.SuggestSkillsRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		res, err := h.skillService.SuggestSkills(ctx, request)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+// serviceError maps an error returned by a service to the HTTP error sent to
+// the client.
+func serviceError(err error) *echo.HTTPError {
+	switch {
+	case errors.Is(err, authorizationService.ErrUnauthenticated):
+		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	case errors.Is(err, authorizationService.ErrForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, profileService.ErrInvalidCursor),
+		errors.Is(err, skillService.ErrInvalidSkillLevel):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, profileService.ErrProfileNotFound),
+		errors.Is(err, skillService.ErrSkillNotFound),
+		errors.Is(err, educationService.ErrEducationNotFound),
+		errors.Is(err, employmentService.ErrEmploymentNotFound),
+		errors.Is(err, jobService.ErrJobPostingNotFound),
+		errors.Is(err, apiKeyService.ErrApiKeyNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, profileService.ErrProfileModified),
+		errors.Is(err, skillService.ErrSkillModified),
+		errors.Is(err, educationService.ErrEducationModified),
+		errors.Is(err, employmentService.ErrEmploymentModified):
+		return echo.NewHTTPError(http.StatusPreconditionFailed, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, err)
+}
+
+// mimeMergePatch is the media type of a JSON Merge Patch (RFC 7396).
+const mimeMergePatch = "application/merge-patch+json"
+
+// mergePatch binds the path and query parameters and decodes the body as a
+// JSON Merge Patch. echo's binder only decodes application/json bodies.
+func mergePatch(c echo.Context, patch interface{}) error {
+	contentType := c.Request().Header.Get(echo.HeaderContentType)
+	if !strings.HasPrefix(contentType, mimeMergePatch) && !strings.HasPrefix(contentType, echo.MIMEApplicationJSON) {
+		return echo.NewHTTPError(http.StatusUnsupportedMediaType, "unsupported media type. use "+mimeMergePatch)
+	}
+
+	binder := &echo.DefaultBinder{}
+	if err := binder.BindPathParams(c, patch); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+	}
+	if err := binder.BindQueryParams(c, patch); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+	}
+	decoder := json.NewDecoder(c.Request().Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(patch); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+	}
+	return nil
+}
+
+// ifMatch reads the version a write is based on from the If-Match header.
+// Writes without one are refused, so an edit made from a stale copy cannot
+// silently overwrite a newer one.
+func ifMatch(c echo.Context) (int, error) {
+	version, err := etag.Parse(c.Request().Header.Get(etag.HeaderIfMatch))
+	if errors.Is(err, etag.ErrMissing) {
+		return 0, echo.NewHTTPError(http.StatusPreconditionRequired, "precondition required. "+err.Error())
+	}
+	if err != nil {
+		return 0, echo.NewHTTPError(http.StatusPreconditionFailed, "precondition failed. "+err.Error())
+	}
+	return version, nil
+}
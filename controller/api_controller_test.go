@@ -2,16 +2,26 @@ package controller
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"regexp"
 	"strconv"
+	"strings"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/helper/etag"
+	"test-bpjs/v2/helper/storage"
 	"test-bpjs/v2/models"
 	"test-bpjs/v2/models/response"
+	dataRepository "test-bpjs/v2/repository"
 	repository "test-bpjs/v2/repository/mocks"
+	auditService "test-bpjs/v2/service/audit"
+	authorizationService "test-bpjs/v2/service/authorization"
 	educationService "test-bpjs/v2/service/education"
 	employmentService "test-bpjs/v2/service/employment"
 	profileService "test-bpjs/v2/service/profile"
@@ -26,13 +36,33 @@ import (
 )
 
 var profileRepository = &repository.ProfileRepository{Mock: mock.Mock{}}
-var profileServiceTest = profileService.NewProfileService(profileRepository)
+var consentRepository = &repository.ConsentRepository{Mock: mock.Mock{}}
+var authorizer = authorizationService.NewAuthorizer(profileRepository, consentRepository, "")
+var auditRepository = &repository.AuditRepository{Mock: mock.Mock{}}
+var auditor = auditService.NewAuditService(auditRepository, authorizer)
+var versioner = &versionRecorder{}
+var profileServiceTest = profileService.NewProfileService(profileRepository, authorizer, auditor, versioner)
 var skillRepository = &repository.SkillRepository{Mock: mock.Mock{}}
-var skillServiceTest = skillService.NewSkillService(skillRepository)
+var skillServiceTest = skillService.NewSkillService(skillRepository, authorizer, auditor, versioner)
 var educationRepository = &repository.EducationRepository{Mock: mock.Mock{}}
-var educationServiceTest = educationService.NewEducationService(educationRepository)
+var educationServiceTest = educationService.NewEducationService(educationRepository, authorizer, auditor, versioner)
 var employmentRepository = &repository.EmploymentRepository{Mock: mock.Mock{}}
-var employmentServiceTest = employmentService.NewEmploymentService(employmentRepository)
+var employmentServiceTest = employmentService.NewEmploymentService(employmentRepository, authorizer, auditor, versioner)
+
+// every request in these tests is made by user 1, who owns all profiles
+var ownerCtx = auth.WithClaims(context.Background(), &auth.Claims{UserId: 1, Role: auth.RoleUser})
+var _ = profileRepository.Mock.On("GetProfileOwner", mock.Anything, mock.Anything).Return(1, nil)
+var _ = auditRepository.Mock.On("CreateAuditLog", mock.Anything, mock.Anything).Return(nil)
+
+// versionRecorder stands in for the resume service and remembers which
+// profiles were versioned
+type versionRecorder struct {
+	codes []int
+}
+
+func (v *versionRecorder) Snapshot(ctx context.Context, code int) {
+	v.codes = append(v.codes, code)
+}
 
 type CustomValidator struct {
 	validator *validator.Validate
@@ -46,18 +76,30 @@ func (cv *CustomValidator) Validate(i interface{}) error {
 	return nil
 }
 
+// newTestContext returns an echo instance with the test validator and the
+// context of a JSON request to target, made as the user in ctx.
+func newTestContext(ctx context.Context, method, target string, body io.Reader) (*echo.Echo, echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	e.Validator = &CustomValidator{validator: validator.New()}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(method, target, body)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	return e, e.NewContext(req.WithContext(ctx), rec), rec
+}
+
 func TestGetProfileController(t *testing.T) {
 	t.Run("SuccessGetProfileController", func(t *testing.T) {
 		e := echo.New()
 		e.Validator = &CustomValidator{validator: validator.New()}
 		result := &models.ProfileDTO{
 			ProfileCode: 1,
+			Version:     3,
 		}
 
 		rec := httptest.NewRecorder()
 		req := httptest.NewRequest(http.MethodGet, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/profile")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("1")
@@ -76,6 +118,7 @@ func TestGetProfileController(t *testing.T) {
 				assert.Error(t, err, "error")
 			}
 			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, `"3"`, rec.Header().Get(etag.HeaderETag))
 		}
 	})
 
@@ -87,7 +130,7 @@ func TestGetProfileController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/profile")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("2")
@@ -117,7 +160,7 @@ func TestGetProfileController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/profile")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("0")
@@ -149,7 +192,7 @@ func TestGetProfileController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/profile")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("asd")
@@ -202,14 +245,14 @@ func TestCreateProfileController(t *testing.T) {
 		rec := httptest.NewRecorder()
 		req := httptest.NewRequest(http.MethodPost, "/api", bytes.NewBuffer(requestBody))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/profile")
 
 		apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
 
 		// apiHandler.GetProfileByCode()(c)
 		dob, _ := time.Parse("2006-01-02T15:04:05Z", "2006-01-02T00:00:00Z")
-		profileRepository.Mock.On("CreateProfile", mock.Anything, &models.Profile{
+		profileRepository.Mock.On("CreateProfile", mock.Anything, anyPublicId(&models.Profile{
 			WantedJobTitle: "Software Engineer",
 			FirstName:      "Namaku",
 			LastName:       "Ukaman",
@@ -223,7 +266,8 @@ func TestCreateProfileController(t *testing.T) {
 			Nationality:    "Indonesia",
 			PlaceOfBirth:   "Maluku",
 			DateOfBirth:    dob,
-		}).Return(result, nil)
+			OwnerId:        1,
+		})).Return(result, nil)
 
 		controller := apiHandler.CreateProfile()(c)
 		if assert.NoError(t, controller) {
@@ -256,7 +300,7 @@ func TestCreateProfileController(t *testing.T) {
 		})
 
 		dob, _ := time.Parse("2006-01-02T15:04:05Z", "2006-01-02T00:00:00Z")
-		profileRepository.Mock.On("CreateProfile", mock.Anything, &models.Profile{
+		profileRepository.Mock.On("CreateProfile", mock.Anything, anyPublicId(&models.Profile{
 			FirstName:      "Namaku",
 			LastName:       "Ukaman",
 			Email:          "ukaman.namaku@gmail.com",
@@ -269,12 +313,13 @@ func TestCreateProfileController(t *testing.T) {
 			Nationality:    "Indonesia",
 			PlaceOfBirth:   "Maluku",
 			DateOfBirth:    dob,
-		}).Return(nil, errors.New("sql: no rows in result set"))
+			OwnerId:        1,
+		})).Return(nil, errors.New("sql: no rows in result set"))
 
 		req := httptest.NewRequest(http.MethodPost, "/api", bytes.NewBuffer(requestBody))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/profile")
 
 		apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
@@ -310,7 +355,7 @@ func TestCreateProfileController(t *testing.T) {
 			"dateOfBirth":    "2006-01-02",
 		})
 		dob, _ := time.Parse("2006-01-02T15:04:05Z", "2006-01-02")
-		profileRepository.Mock.On("CreateProfile", mock.Anything, &models.Profile{
+		profileRepository.Mock.On("CreateProfile", mock.Anything, anyPublicId(&models.Profile{
 			WantedJobTitle: "",
 			FirstName:      "Namaku",
 			LastName:       "Ukaman",
@@ -324,12 +369,13 @@ func TestCreateProfileController(t *testing.T) {
 			Nationality:    "Indonesia",
 			PlaceOfBirth:   "Maluku",
 			DateOfBirth:    dob,
-		}).Return(nil, errors.New("sql: no rows in result set"))
+			OwnerId:        1,
+		})).Return(nil, errors.New("sql: no rows in result set"))
 
 		req := httptest.NewRequest(http.MethodPost, "/api", bytes.NewBuffer(requestBody))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/profile")
 
 		apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
@@ -359,7 +405,7 @@ func TestCreateProfileController(t *testing.T) {
 	// 	req := httptest.NewRequest(http.MethodPost, "/api", nil)
 	// 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	// 	rec := httptest.NewRecorder()
-	// 	c := e.NewContext(req, rec)
+	// 	c := e.NewContext(req.WithContext(ownerCtx), rec)
 	// 	c.SetPath("/profile")
 	// 	c.SetParamNames("profileCode")
 	// 	c.SetParamValues("asd")
@@ -417,7 +463,8 @@ func TestUpdateProfileController(t *testing.T) {
 		rec := httptest.NewRecorder()
 		req := httptest.NewRequest(http.MethodPut, "/api", bytes.NewBuffer(requestBody))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		c := e.NewContext(req, rec)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/profile")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("5")
@@ -426,7 +473,9 @@ func TestUpdateProfileController(t *testing.T) {
 
 		// apiHandler.GetProfileByCode()(c)
 		dob, _ := time.Parse("2006-01-02T15:04:05Z", "2006-01-02T00:00:00Z")
-		profileRepository.Mock.On("UpdateProfile", mock.Anything, 5, &models.Profile{
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 5).Return(&models.ProfileDTO{ProfileCode: 5}, nil)
+		profileRepository.Mock.On("GetWorkingExperienceByCode", mock.Anything, 5).Return(&models.ProfileDTO{}, nil)
+		profileRepository.Mock.On("UpdateProfile", mock.Anything, 5, 1, &models.Profile{
 			WantedJobTitle: "Software Engineer",
 			FirstName:      "Namaku",
 			LastName:       "Ukaman",
@@ -458,12 +507,15 @@ func TestUpdateProfileController(t *testing.T) {
 		e := echo.New()
 		e.Validator = &CustomValidator{validator: validator.New()}
 		requestBody, _ := json.Marshal(map[string]interface{}{})
-		profileRepository.Mock.On("UpdateProfile", mock.Anything, 6, &models.Profile{}).Return(nil, errors.New("sql: no rows in result set"))
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 6).Return(&models.ProfileDTO{ProfileCode: 6}, nil)
+		profileRepository.Mock.On("GetWorkingExperienceByCode", mock.Anything, 6).Return(&models.ProfileDTO{}, nil)
+		profileRepository.Mock.On("UpdateProfile", mock.Anything, 6, 1, &models.Profile{}).Return(nil, errors.New("sql: no rows in result set"))
 
 		req := httptest.NewRequest(http.MethodPut, "/api", bytes.NewBuffer(requestBody))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/profile")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("6")
@@ -485,12 +537,13 @@ func TestUpdateProfileController(t *testing.T) {
 	t.Run("FailedGetProfileController_ErrValidate", func(t *testing.T) {
 		e := echo.New()
 		e.Validator = &CustomValidator{validator: validator.New()}
-		profileRepository.Mock.On("UpdateProfile", mock.Anything, "0", &models.Profile{}).Return(nil, errors.New("sql: no rows in result set"))
+		profileRepository.Mock.On("UpdateProfile", mock.Anything, "0", 1, &models.Profile{}).Return(nil, errors.New("sql: no rows in result set"))
 
 		req := httptest.NewRequest(http.MethodPut, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/profile")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("0")
@@ -533,7 +586,7 @@ func TestUpdateProfileController(t *testing.T) {
 			"dateOfBirth":    "2006-01-02",
 		})
 		dob, _ := time.Parse("2006-01-02T15:04:05Z", "2006-01-02")
-		profileRepository.Mock.On("UpdateProfile", mock.Anything, "asd", &models.Profile{
+		profileRepository.Mock.On("UpdateProfile", mock.Anything, "asd", 1, &models.Profile{
 			WantedJobTitle: "Software Engineer",
 			FirstName:      "Namaku",
 			LastName:       "Ukaman",
@@ -551,8 +604,9 @@ func TestUpdateProfileController(t *testing.T) {
 
 		req := httptest.NewRequest(http.MethodPut, "/api", bytes.NewBuffer(requestBody))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/profile")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("asd")
@@ -584,7 +638,7 @@ func TestDownloadPhotoController(t *testing.T) {
 		rec := httptest.NewRecorder()
 		req := httptest.NewRequest(http.MethodGet, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/photo")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("8")
@@ -625,7 +679,7 @@ func TestDownloadPhotoController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPut, "/api", bytes.NewBuffer(requestBody))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/profile")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("9")
@@ -652,7 +706,7 @@ func TestDownloadPhotoController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPut, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/profile")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("0")
@@ -684,7 +738,7 @@ func TestDownloadPhotoController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPut, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/profile")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("asd")
@@ -709,8 +763,68 @@ func TestDownloadPhotoController(t *testing.T) {
 	})
 }
 
+func TestDownloadAvatarController(t *testing.T) {
+	t.Run("SuccessDownloadAvatarController", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = &CustomValidator{validator: validator.New()}
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api?format=svg", nil)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
+		c.SetPath("/photo/:profileCode/avatar")
+		c.SetParamNames("profileCode")
+		c.SetParamValues("43")
+
+		apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
+
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 43).Return(&models.ProfileDTO{
+			ProfileCode: 43,
+			FirstName:   "test",
+			LastName:    "user"}, nil)
+
+		controller := apiHandler.DownloadAvatar()(c)
+		if assert.NoError(t, controller) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Body.String(), "data:image/svg+xml;base64,")
+		}
+	})
+
+	t.Run("FailedDownloadAvatarController_ErrValidate", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = &CustomValidator{validator: validator.New()}
+
+		req := httptest.NewRequest(http.MethodGet, "/api?format=gif", nil)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
+		c.SetPath("/photo/:profileCode/avatar")
+		c.SetParamNames("profileCode")
+		c.SetParamValues("43")
+
+		apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
+
+		controller := apiHandler.DownloadAvatar()(c)
+		if assert.Error(t, controller) {
+			var errCode int
+			var errMsg string
+
+			re := regexp.MustCompile(`code=(\d+), message=(.+)`)
+			match := re.FindStringSubmatch(controller.Error())
+			errCode, _ = strconv.Atoi(match[1])
+			errMsg = match[2]
+
+			assert.Equal(t, http.StatusBadRequest, errCode)
+			assert.Equal(t, "bad request. failed to validate", errMsg)
+		}
+	})
+}
+
 func TestUploadController(t *testing.T) {
 	t.Run("SuccessUploadPhotoController", func(t *testing.T) {
+		// keep the uploaded photo out of public/image
+		storage.SetRoot(t.TempDir())
+		t.Cleanup(func() { storage.SetRoot("") })
 		e := echo.New()
 		e.Validator = &CustomValidator{validator: validator.New()}
 
@@ -721,7 +835,8 @@ func TestUploadController(t *testing.T) {
 		rec := httptest.NewRecorder()
 		req := httptest.NewRequest(http.MethodPut, "/api", bytes.NewBuffer(requestBody))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		c := e.NewContext(req, rec)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/photo")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("10")
@@ -729,9 +844,11 @@ func TestUploadController(t *testing.T) {
 		apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
 
 		// apiHandler.GetProfileByCode()(c)
-		profileRepository.Mock.On("UpdateProfile", mock.Anything, 10, &models.Profile{
-			PhotoUrl: fmt.Sprintf("public/image/10-%d.png", time.Now().Unix())}).
-			Return(&models.ProfileDTO{ProfileCode: 10, PhotoUrl: fmt.Sprintf("public/image/10-%d.png", time.Now().Unix())}, nil)
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 10).Return(&models.ProfileDTO{ProfileCode: 10}, nil)
+		profileRepository.Mock.On("UpdateProfile", mock.Anything, 10, 1, &models.Profile{
+			PhotoUrl:  "public/image/4a567c8956410440a55b7544ebae3d72433f134bf40cabf4f462da50dbb17814.png",
+			PhotoHash: "4a567c8956410440a55b7544ebae3d72433f134bf40cabf4f462da50dbb17814"}).
+			Return(&models.ProfileDTO{ProfileCode: 10, PhotoUrl: "public/image/4a567c8956410440a55b7544ebae3d72433f134bf40cabf4f462da50dbb17814.png"}, nil)
 
 		controller := apiHandler.UploadPhoto()(c)
 		if assert.NoError(t, controller) {
@@ -743,12 +860,13 @@ func TestUploadController(t *testing.T) {
 		e := echo.New()
 		e.Validator = &CustomValidator{validator: validator.New()}
 		requestBody, _ := json.Marshal(map[string]interface{}{})
-		profileRepository.Mock.On("UpdateProfile", mock.Anything, 11, &models.Profile{}).Return(nil, errors.New(""))
+		profileRepository.Mock.On("UpdateProfile", mock.Anything, 11, 1, &models.Profile{}).Return(nil, errors.New(""))
 
 		req := httptest.NewRequest(http.MethodPut, "/api", bytes.NewBuffer(requestBody))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/profile")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("11")
@@ -771,12 +889,13 @@ func TestUploadController(t *testing.T) {
 		e := echo.New()
 		e.Validator = &CustomValidator{validator: validator.New()}
 		requestBody, _ := json.Marshal(map[string]interface{}{})
-		profileRepository.Mock.On("UpdateProfile", mock.Anything, 0, &models.Profile{}).Return(nil, errors.New("a"))
+		profileRepository.Mock.On("UpdateProfile", mock.Anything, 0, 1, &models.Profile{}).Return(nil, errors.New("a"))
 
 		req := httptest.NewRequest(http.MethodPut, "/api", bytes.NewBuffer(requestBody))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/profile")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("0")
@@ -805,14 +924,15 @@ func TestUploadController(t *testing.T) {
 		requestBody, _ := json.Marshal(map[string]interface{}{
 			"base64img": "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAABgAAAAYCAYAAADgdz34AAAABHNCSVQICAgIfAhkiAAAAAlwSFlzAAAApgAAAKYB3X3/OAAAABl0RVh0U29mdHdhcmUAd3d3Lmlua3NjYXBlLm9yZ5vuPBoAAANCSURBVEiJtZZPbBtFFMZ/M7ubXdtdb1xSFyeilBapySVU8h8OoFaooFSqiihIVIpQBKci6KEg9Q6H9kovIHoCIVQJJCKE1ENFjnAgcaSGC6rEnxBwA04Tx43t2FnvDAfjkNibxgHxnWb2e/u992bee7tCa00YFsffekFY+nUzFtjW0LrvjRXrCDIAaPLlW0nHL0SsZtVoaF98mLrx3pdhOqLtYPHChahZcYYO7KvPFxvRl5XPp1sN3adWiD1ZAqD6XYK1b/dvE5IWryTt2udLFedwc1+9kLp+vbbpoDh+6TklxBeAi9TL0taeWpdmZzQDry0AcO+jQ12RyohqqoYoo8RDwJrU+qXkjWtfi8Xxt58BdQuwQs9qC/afLwCw8tnQbqYAPsgxE1S6F3EAIXux2oQFKm0ihMsOF71dHYx+f3NND68ghCu1YIoePPQN1pGRABkJ6Bus96CutRZMydTl+TvuiRW1m3n0eDl0vRPcEysqdXn+jsQPsrHMquGeXEaY4Yk4wxWcY5V/9scqOMOVUFthatyTy8QyqwZ+kDURKoMWxNKr2EeqVKcTNOajqKoBgOE28U4tdQl5p5bwCw7BWquaZSzAPlwjlithJtp3pTImSqQRrb2Z8PHGigD4RZuNX6JYj6wj7O4TFLbCO/Mn/m8R+h6rYSUb3ekokRY6f/YukArN979jcW+V/S8g0eT/N3VN3kTqWbQ428m9/8k0P/1aIhF36PccEl6EhOcAUCrXKZXXWS3XKd2vc/TRBG9O5ELC17MmWubD2nKhUKZa26Ba2+D3P+4/MNCFwg59oWVeYhkzgN/JDR8deKBoD7Y+ljEjGZ0sosXVTvbc6RHirr2reNy1OXd6pJsQ+gqjk8VWFYmHrwBzW/n+uMPFiRwHB2I7ih8ciHFxIkd/3Omk5tCDV1t+2nNu5sxxpDFNx+huNhVT3/zMDz8usXC3ddaHBj1GHj/As08fwTS7Kt1HBTmyN29vdwAw+/wbwLVOJ3uAD1wi/dUH7Qei66PfyuRj4Ik9is+hglfbkbfR3cnZm7chlUWLdwmprtCohX4HUtlOcQjLYCu+fzGJH2QRKvP3UNz8bWk1qMxjGTOMThZ3kvgLI5AzFfo379UAAAAASUVORK5CYII=",
 		})
-		profileRepository.Mock.On("UpdateProfile", mock.Anything, "asd", &models.Profile{
+		profileRepository.Mock.On("UpdateProfile", mock.Anything, "asd", 1, &models.Profile{
 			PhotoUrl: fmt.Sprintf("public/image/12-%d.png", time.Now().Unix())}).
 			Return(nil, errors.New(""))
 
 		req := httptest.NewRequest(http.MethodPut, "/api", bytes.NewBuffer(requestBody))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/profile")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("asd")
@@ -844,7 +964,8 @@ func TestDeletePhotoController(t *testing.T) {
 		rec := httptest.NewRecorder()
 		req := httptest.NewRequest(http.MethodDelete, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		c := e.NewContext(req, rec)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/photo")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("13")
@@ -852,7 +973,8 @@ func TestDeletePhotoController(t *testing.T) {
 		apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
 
 		// apiHandler.GetProfileByCode()(c)
-		profileRepository.Mock.On("DeletePhotoByCode", mock.Anything, 13).
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 13).Return(&models.ProfileDTO{ProfileCode: 13}, nil)
+		profileRepository.Mock.On("DeletePhotoByCode", mock.Anything, 13, 1).
 			Return(&models.DefaultResponse{ProfileCode: 13}, nil)
 
 		controller := apiHandler.DeletePhoto()(c)
@@ -864,13 +986,15 @@ func TestDeletePhotoController(t *testing.T) {
 	t.Run("FailedDeletePhotoController_Err500", func(t *testing.T) {
 		e := echo.New()
 		e.Validator = &CustomValidator{validator: validator.New()}
-		profileRepository.Mock.On("DeletePhotoByCode", mock.Anything, 14).
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 14).Return(&models.ProfileDTO{ProfileCode: 14}, nil)
+		profileRepository.Mock.On("DeletePhotoByCode", mock.Anything, 14, 1).
 			Return(nil, errors.New(""))
 
 		req := httptest.NewRequest(http.MethodDelete, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/profile")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("14")
@@ -892,12 +1016,13 @@ func TestDeletePhotoController(t *testing.T) {
 	t.Run("FailedDeletePhotoController_ErrValidate", func(t *testing.T) {
 		e := echo.New()
 		e.Validator = &CustomValidator{validator: validator.New()}
-		profileRepository.Mock.On("DeletePhotoByCode", mock.Anything, 0).Return(nil, errors.New("a"))
+		profileRepository.Mock.On("DeletePhotoByCode", mock.Anything, 0, 1).Return(nil, errors.New("a"))
 
 		req := httptest.NewRequest(http.MethodDelete, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/profile")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("0")
@@ -923,12 +1048,13 @@ func TestDeletePhotoController(t *testing.T) {
 	t.Run("FailedDeletePhotoController_ErrBind", func(t *testing.T) {
 		e := echo.New()
 		e.Validator = &CustomValidator{validator: validator.New()}
-		profileRepository.Mock.On("DeletePhotoByCode", mock.Anything, "asd").Return(nil, errors.New("a"))
+		profileRepository.Mock.On("DeletePhotoByCode", mock.Anything, "asd", 1).Return(nil, errors.New("a"))
 
 		req := httptest.NewRequest(http.MethodDelete, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/profile")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("asd")
@@ -958,13 +1084,14 @@ func TestGetWorkingExperienceController(t *testing.T) {
 		e.Validator = &CustomValidator{validator: validator.New()}
 		workingExperience := &models.ProfileDTO{
 			WorkingExperience: "test",
+			Version:           4,
 		}
 		profileRepository.Mock.On("GetWorkingExperienceByCode", mock.Anything, 15).Return(workingExperience, nil)
 
 		rec := httptest.NewRecorder()
 		req := httptest.NewRequest(http.MethodGet, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/working-experience")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("15")
@@ -974,6 +1101,11 @@ func TestGetWorkingExperienceController(t *testing.T) {
 		controller := apiHandler.GetWorkingExperienceByCode()(c)
 		if assert.NoError(t, controller) {
 			assert.Equal(t, http.StatusOK, rec.Code)
+			// the ETag is sent back as If-Match when updating
+			assert.Equal(t, `"4"`, rec.Header().Get(etag.HeaderETag))
+			version, err := etag.Parse(rec.Header().Get(etag.HeaderETag))
+			assert.Nil(t, err)
+			assert.Equal(t, 4, version)
 		}
 	})
 
@@ -986,7 +1118,7 @@ func TestGetWorkingExperienceController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/api", bytes.NewBuffer(requestBody))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/working_experience")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("16")
@@ -1013,7 +1145,7 @@ func TestGetWorkingExperienceController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/profile")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("0")
@@ -1045,7 +1177,7 @@ func TestGetWorkingExperienceController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/profile")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("asd")
@@ -1080,7 +1212,9 @@ func TestUpdateWorkingExperienceController(t *testing.T) {
 		requestBody, _ := json.Marshal(map[string]interface{}{
 			"workingExperience": "software engineer",
 		})
-		profileRepository.Mock.On("UpdateProfile", mock.Anything, 17,
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 17).Return(&models.ProfileDTO{ProfileCode: 17}, nil)
+		profileRepository.Mock.On("GetWorkingExperienceByCode", mock.Anything, 17).Return(&models.ProfileDTO{}, nil)
+		profileRepository.Mock.On("UpdateProfile", mock.Anything, 17, 1,
 			&models.Profile{
 				WorkingExperience: "software engineer",
 			}).Return(result, nil)
@@ -1088,7 +1222,8 @@ func TestUpdateWorkingExperienceController(t *testing.T) {
 		rec := httptest.NewRecorder()
 		req := httptest.NewRequest(http.MethodPut, "/api", bytes.NewBuffer(requestBody))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		c := e.NewContext(req, rec)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/working-experience")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("17")
@@ -1105,12 +1240,15 @@ func TestUpdateWorkingExperienceController(t *testing.T) {
 		e := echo.New()
 		e.Validator = &CustomValidator{validator: validator.New()}
 		requestBody, _ := json.Marshal(map[string]interface{}{})
-		profileRepository.Mock.On("UpdateProfile", mock.Anything, 18, &models.Profile{}).Return(nil, errors.New(""))
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 18).Return(&models.ProfileDTO{ProfileCode: 18}, nil)
+		profileRepository.Mock.On("GetWorkingExperienceByCode", mock.Anything, 18).Return(&models.ProfileDTO{}, nil)
+		profileRepository.Mock.On("UpdateProfile", mock.Anything, 18, 1, &models.Profile{}).Return(nil, errors.New(""))
 
 		req := httptest.NewRequest(http.MethodPut, "/api", bytes.NewBuffer(requestBody))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/working_experience")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("18")
@@ -1132,14 +1270,15 @@ func TestUpdateWorkingExperienceController(t *testing.T) {
 	t.Run("FailedUpdateWorkingExperienceController_ErrValidate", func(t *testing.T) {
 		e := echo.New()
 		e.Validator = &CustomValidator{validator: validator.New()}
-		profileRepository.Mock.On("UpdateProfile", mock.Anything, 0, &models.Profile{
+		profileRepository.Mock.On("UpdateProfile", mock.Anything, 0, 1, &models.Profile{
 			WorkingExperience: "software engineer",
 		}).Return(&models.ProfileDTO{}, nil)
 
 		req := httptest.NewRequest(http.MethodPut, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/profile")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("0")
@@ -1166,14 +1305,15 @@ func TestUpdateWorkingExperienceController(t *testing.T) {
 	t.Run("FailedUpdateWorkingExperienceByCodeController_ErrBind", func(t *testing.T) {
 		e := echo.New()
 		e.Validator = &CustomValidator{validator: validator.New()}
-		profileRepository.Mock.On("UpdateProfile", mock.Anything, "asd", &models.Profile{
+		profileRepository.Mock.On("UpdateProfile", mock.Anything, "asd", 1, &models.Profile{
 			WorkingExperience: "software engineer",
 		}).Return(&models.ProfileDTO{}, nil)
 
 		req := httptest.NewRequest(http.MethodGet, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/profile")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("asd")
@@ -1216,7 +1356,7 @@ func TestGetEducationListController(t *testing.T) {
 		rec := httptest.NewRecorder()
 		req := httptest.NewRequest(http.MethodGet, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/education")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("19")
@@ -1237,7 +1377,7 @@ func TestGetEducationListController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/education")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("20")
@@ -1264,7 +1404,7 @@ func TestGetEducationListController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/education")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("0")
@@ -1296,7 +1436,7 @@ func TestGetEducationListController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/education")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("asd")
@@ -1349,7 +1489,7 @@ func TestCreateEducationController(t *testing.T) {
 		rec := httptest.NewRecorder()
 		req := httptest.NewRequest(http.MethodPost, "/api", bytes.NewBuffer(requestBody))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/education")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("21")
@@ -1372,7 +1512,7 @@ func TestCreateEducationController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/education")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("22")
@@ -1399,7 +1539,7 @@ func TestCreateEducationController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/education")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("0")
@@ -1431,7 +1571,7 @@ func TestCreateEducationController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/profile")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("asd")
@@ -1461,12 +1601,13 @@ func TestDeleteEducationController(t *testing.T) {
 		e := echo.New()
 		e.Validator = &CustomValidator{validator: validator.New()}
 
-		educationRepository.Mock.On("DeleteEducation", mock.Anything, 23, 1).
-			Return(nil)
+		educationRepository.Mock.On("DeleteEducation", mock.Anything, 23, 1, 1).
+			Return(&models.EducationDTO{ProfileCode: 23, Id: 1}, nil)
 		rec := httptest.NewRecorder()
 		req := httptest.NewRequest(http.MethodDelete, "/api?id=1", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		c := e.NewContext(req, rec)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/education")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("23")
@@ -1485,13 +1626,14 @@ func TestDeleteEducationController(t *testing.T) {
 	t.Run("FailedDeleteEducationController_Err500", func(t *testing.T) {
 		e := echo.New()
 		e.Validator = &CustomValidator{validator: validator.New()}
-		educationRepository.Mock.On("DeleteEducation", mock.Anything, 23, 2).
-			Return(errors.New("sql: no rows in result set"))
+		educationRepository.Mock.On("DeleteEducation", mock.Anything, 23, 2, 1).
+			Return(nil, errors.New("sql: no rows in result set"))
 
 		req := httptest.NewRequest(http.MethodDelete, "/api?id=2", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/education")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("23")
@@ -1513,13 +1655,14 @@ func TestDeleteEducationController(t *testing.T) {
 	t.Run("FailedDeletePhotoController_ErrValidate", func(t *testing.T) {
 		e := echo.New()
 		e.Validator = &CustomValidator{validator: validator.New()}
-		educationRepository.Mock.On("DeleteEducation", mock.Anything, 0, 0).
-			Return(nil)
+		educationRepository.Mock.On("DeleteEducation", mock.Anything, 0, 0, 1).
+			Return(&models.EducationDTO{}, nil)
 
 		req := httptest.NewRequest(http.MethodDelete, "/api?id=0", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/education")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("0")
@@ -1545,13 +1688,14 @@ func TestDeleteEducationController(t *testing.T) {
 	t.Run("FailedDeleteEducationController_ErrBind", func(t *testing.T) {
 		e := echo.New()
 		e.Validator = &CustomValidator{validator: validator.New()}
-		educationRepository.Mock.On("DeleteEducation", mock.Anything, "asd", 0).
-			Return(nil)
+		educationRepository.Mock.On("DeleteEducation", mock.Anything, "asd", 0, 1).
+			Return(nil, nil)
 
 		req := httptest.NewRequest(http.MethodDelete, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/education")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("asd")
@@ -1593,7 +1737,7 @@ func TestGetEmploymentListController(t *testing.T) {
 		rec := httptest.NewRecorder()
 		req := httptest.NewRequest(http.MethodGet, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/employment")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("1")
@@ -1614,7 +1758,7 @@ func TestGetEmploymentListController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/employment")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("2")
@@ -1641,7 +1785,7 @@ func TestGetEmploymentListController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/employment")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("0")
@@ -1673,7 +1817,7 @@ func TestGetEmploymentListController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/employment")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("asd")
@@ -1726,7 +1870,7 @@ func TestCreateEmploymentController(t *testing.T) {
 		rec := httptest.NewRecorder()
 		req := httptest.NewRequest(http.MethodPost, "/api", bytes.NewBuffer(requestBody))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/employment")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("1")
@@ -1749,7 +1893,7 @@ func TestCreateEmploymentController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/employment")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("22")
@@ -1778,7 +1922,7 @@ func TestCreateEmploymentController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/employment")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("0")
@@ -1810,7 +1954,7 @@ func TestCreateEmploymentController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/employment")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("asd")
@@ -1840,11 +1984,12 @@ func TestDeleteEmploymentController(t *testing.T) {
 		e := echo.New()
 		e.Validator = &CustomValidator{validator: validator.New()}
 
-		employmentRepository.Mock.On("DeleteEmployment", mock.Anything, 1, 1).Return(nil)
+		employmentRepository.Mock.On("DeleteEmployment", mock.Anything, 1, 1, 1).Return(&models.EmploymentDTO{ProfileCode: 1, Id: 1}, nil)
 		rec := httptest.NewRecorder()
 		req := httptest.NewRequest(http.MethodDelete, "/api?id=1", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		c := e.NewContext(req, rec)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/employment")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("1")
@@ -1861,12 +2006,13 @@ func TestDeleteEmploymentController(t *testing.T) {
 	t.Run("FailedDeleteEmploymentController_Err500", func(t *testing.T) {
 		e := echo.New()
 		e.Validator = &CustomValidator{validator: validator.New()}
-		employmentRepository.Mock.On("DeleteEmployment", mock.Anything, 1, 2).Return(errors.New("sql: no rows in result set"))
+		employmentRepository.Mock.On("DeleteEmployment", mock.Anything, 1, 2, 1).Return(nil, errors.New("sql: no rows in result set"))
 
 		req := httptest.NewRequest(http.MethodDelete, "/api?id=2", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/employment")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("1")
@@ -1888,12 +2034,13 @@ func TestDeleteEmploymentController(t *testing.T) {
 	t.Run("FailedDeleteEmploymentController_ErrValidate", func(t *testing.T) {
 		e := echo.New()
 		e.Validator = &CustomValidator{validator: validator.New()}
-		employmentRepository.Mock.On("DeleteEmployment", mock.Anything, 0, 0).Return(nil)
+		employmentRepository.Mock.On("DeleteEmployment", mock.Anything, 0, 0, 1).Return(&models.EmploymentDTO{}, nil)
 
 		req := httptest.NewRequest(http.MethodDelete, "/api?id=0", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/employment")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("0")
@@ -1919,13 +2066,14 @@ func TestDeleteEmploymentController(t *testing.T) {
 	t.Run("FailedDeleteEmploymentController_ErrBind", func(t *testing.T) {
 		e := echo.New()
 		e.Validator = &CustomValidator{validator: validator.New()}
-		employmentRepository.Mock.On("DeleteEmployment", mock.Anything, "asd", 0).
-			Return(nil)
+		employmentRepository.Mock.On("DeleteEmployment", mock.Anything, "asd", 0, 1).
+			Return(nil, nil)
 
 		req := httptest.NewRequest(http.MethodDelete, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/employment")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("asd")
@@ -1965,7 +2113,7 @@ func TestGetSkillListController(t *testing.T) {
 		rec := httptest.NewRecorder()
 		req := httptest.NewRequest(http.MethodGet, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/skill")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("1")
@@ -1986,7 +2134,7 @@ func TestGetSkillListController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/skill")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("2")
@@ -2013,7 +2161,7 @@ func TestGetSkillListController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/skill")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("0")
@@ -2045,7 +2193,7 @@ func TestGetSkillListController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/skill")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("asd")
@@ -2085,14 +2233,14 @@ func TestCreateSkillController(t *testing.T) {
 		})
 		skillRepository.Mock.On("CreateSkill", mock.Anything, &models.Skill{
 			ProfileCode: 1,
-			Skill:       "Golang",
-			Level:       "Beginner",
+			Skill:       "Go",
+			Level:       "beginner",
 		}).Return(result, nil)
 
 		rec := httptest.NewRecorder()
 		req := httptest.NewRequest(http.MethodPost, "/api", bytes.NewBuffer(requestBody))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/skill")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("1")
@@ -2115,7 +2263,7 @@ func TestCreateSkillController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/skill")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("2")
@@ -2144,7 +2292,7 @@ func TestCreateSkillController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/skill")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("0")
@@ -2176,7 +2324,7 @@ func TestCreateSkillController(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/skill")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("asd")
@@ -2206,11 +2354,12 @@ func TestDeleteSkillController(t *testing.T) {
 		e := echo.New()
 		e.Validator = &CustomValidator{validator: validator.New()}
 
-		skillRepository.Mock.On("DeleteSkill", mock.Anything, 1, 1).Return(nil)
+		skillRepository.Mock.On("DeleteSkill", mock.Anything, 1, 1, 1).Return(&models.SkillDTO{ProfileCode: 1, Id: 1}, nil)
 		rec := httptest.NewRecorder()
 		req := httptest.NewRequest(http.MethodDelete, "/api?id=1", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		c := e.NewContext(req, rec)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/skill")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("1")
@@ -2227,12 +2376,13 @@ func TestDeleteSkillController(t *testing.T) {
 	t.Run("FailedDeleteSkillController_Err500", func(t *testing.T) {
 		e := echo.New()
 		e.Validator = &CustomValidator{validator: validator.New()}
-		skillRepository.Mock.On("DeleteSkill", mock.Anything, 1, 2).Return(errors.New("sql: no rows in result set"))
+		skillRepository.Mock.On("DeleteSkill", mock.Anything, 1, 2, 1).Return(nil, errors.New("sql: no rows in result set"))
 
 		req := httptest.NewRequest(http.MethodDelete, "/api?id=2", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/skill")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("1")
@@ -2251,15 +2401,42 @@ func TestDeleteSkillController(t *testing.T) {
 
 	})
 
+	t.Run("FailedDeleteSkillController_Err404", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = &CustomValidator{validator: validator.New()}
+		skillRepository.Mock.On("DeleteSkill", mock.Anything, 1, 9, 1).Return(&models.SkillDTO{}, dataRepository.ErrNotFound)
+
+		req := httptest.NewRequest(http.MethodDelete, "/api?id=9", nil)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
+		c.SetPath("/skill")
+		c.SetParamNames("profileCode")
+		c.SetParamValues("1")
+
+		apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
+
+		controller := apiHandler.DeleteSkillByCodeAndId()(c)
+		if assert.Error(t, controller) {
+			var errCode int
+			re := regexp.MustCompile(`code=(\d+)`)
+			match := re.FindStringSubmatch(controller.Error())
+			errCode, _ = strconv.Atoi(match[1])
+			assert.Equal(t, http.StatusNotFound, errCode)
+		}
+	})
+
 	t.Run("FailedDeleteSkillController_ErrValidate", func(t *testing.T) {
 		e := echo.New()
 		e.Validator = &CustomValidator{validator: validator.New()}
-		skillRepository.Mock.On("DeleteSkill", mock.Anything, 0, 0).Return(nil)
+		skillRepository.Mock.On("DeleteSkill", mock.Anything, 0, 0, 1).Return(&models.SkillDTO{}, nil)
 
 		req := httptest.NewRequest(http.MethodDelete, "/api?id=0", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/skill")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("0")
@@ -2285,13 +2462,14 @@ func TestDeleteSkillController(t *testing.T) {
 	t.Run("FailedDeleteSkillController_ErrBind", func(t *testing.T) {
 		e := echo.New()
 		e.Validator = &CustomValidator{validator: validator.New()}
-		skillRepository.Mock.On("DeleteSkill", mock.Anything, "asd", 0).
-			Return(nil)
+		skillRepository.Mock.On("DeleteSkill", mock.Anything, "asd", 0, 1).
+			Return(nil, nil)
 
 		req := httptest.NewRequest(http.MethodDelete, "/api", nil)
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
 		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
 		c.SetPath("/skill")
 		c.SetParamNames("profileCode")
 		c.SetParamValues("asd")
@@ -2314,3 +2492,561 @@ func TestDeleteSkillController(t *testing.T) {
 
 	})
 }
+
+func TestAuthorizationErrorController(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		wantCode int
+	}{
+		{"FailedDeleteSkillController_Err401", context.Background(), http.StatusUnauthorized},
+		{"FailedDeleteSkillController_Err403", auth.WithClaims(context.Background(), &auth.Claims{UserId: 2, Role: auth.RoleRecruiter}), http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = &CustomValidator{validator: validator.New()}
+
+			req := httptest.NewRequest(http.MethodDelete, "/api?id=1", nil)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set(etag.HeaderIfMatch, `"1"`)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req.WithContext(tt.ctx), rec)
+			c.SetPath("/skill/:profileCode")
+			c.SetParamNames("profileCode")
+			c.SetParamValues("1")
+
+			apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
+
+			controller := apiHandler.DeleteSkillByCodeAndId()(c)
+			if assert.Error(t, controller) {
+				re := regexp.MustCompile(`code=(\d+)`)
+				match := re.FindStringSubmatch(controller.Error())
+				errCode, _ := strconv.Atoi(match[1])
+				assert.Equal(t, tt.wantCode, errCode)
+			}
+		})
+	}
+}
+
+// anyPublicId matches a profile that got a generated public id and otherwise
+// equals expected.
+func anyPublicId(expected *models.Profile) interface{} {
+	return mock.MatchedBy(func(actual *models.Profile) bool {
+		withoutId := *actual
+		withoutId.PublicId = ""
+		return actual.PublicId != "" && reflect.DeepEqual(*expected, withoutId)
+	})
+}
+
+func TestRestoreSkillController(t *testing.T) {
+	t.Run("SuccessRestoreSkillController", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = &CustomValidator{validator: validator.New()}
+
+		skillRepository.Mock.On("RestoreSkill", mock.Anything, 1, 7).Return(&models.SkillDTO{ProfileCode: 1, Id: 7}, nil).Once()
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api", strings.NewReader(`{"id":7}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
+		c.SetPath("/skill/:profileCode/restore")
+		c.SetParamNames("profileCode")
+		c.SetParamValues("1")
+
+		apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
+		controller := apiHandler.RestoreSkillByCodeAndId()(c)
+		if assert.NoError(t, controller) {
+			var response response.DefaultResponseWithId
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, 7, response.Id)
+		}
+	})
+
+	t.Run("FailedRestoreSkillController_Err400", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = &CustomValidator{validator: validator.New()}
+
+		req := httptest.NewRequest(http.MethodPost, "/api", strings.NewReader(`{}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
+		c.SetPath("/skill/:profileCode/restore")
+		c.SetParamNames("profileCode")
+		c.SetParamValues("1")
+
+		apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
+		controller := apiHandler.RestoreSkillByCodeAndId()(c)
+		if assert.Error(t, controller) {
+			assert.Equal(t, http.StatusBadRequest, controller.(*echo.HTTPError).Code)
+		}
+	})
+}
+
+func TestDeleteProfileController(t *testing.T) {
+	t.Run("SuccessDeleteProfileController", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = &CustomValidator{validator: validator.New()}
+
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 80).Return(&models.ProfileDTO{ProfileCode: 80}, nil).Once()
+		profileRepository.Mock.On("GetWorkingExperienceByCode", mock.Anything, 80).Return(&models.ProfileDTO{}, nil).Once()
+		profileRepository.Mock.On("DeleteProfile", mock.Anything, 80, 1).Return(&models.ProfileDTO{ProfileCode: 80}, nil).Once()
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, "/api", nil)
+		req.Header.Set(etag.HeaderIfMatch, `"1"`)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
+		c.SetPath("/profile/:profileCode")
+		c.SetParamNames("profileCode")
+		c.SetParamValues("80")
+
+		apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
+		controller := apiHandler.DeleteProfile()(c)
+		if assert.NoError(t, controller) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+	t.Run("FailedDeleteProfileController_Err428", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = &CustomValidator{validator: validator.New()}
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, "/api", nil)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
+		c.SetPath("/profile/:profileCode")
+		c.SetParamNames("profileCode")
+		c.SetParamValues("81")
+
+		apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
+		controller := apiHandler.DeleteProfile()(c)
+		var httpErr *echo.HTTPError
+		if assert.ErrorAs(t, controller, &httpErr) {
+			assert.Equal(t, http.StatusPreconditionRequired, httpErr.Code)
+		}
+		profileRepository.Mock.AssertNotCalled(t, "DeleteProfile", mock.Anything, 81, mock.Anything)
+	})
+	t.Run("FailedDeleteProfileController_Err412", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = &CustomValidator{validator: validator.New()}
+
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 82).Return(&models.ProfileDTO{ProfileCode: 82}, nil).Once()
+		profileRepository.Mock.On("GetWorkingExperienceByCode", mock.Anything, 82).Return(&models.ProfileDTO{}, nil).Once()
+		profileRepository.Mock.On("DeleteProfile", mock.Anything, 82, 2).Return(&models.ProfileDTO{}, dataRepository.ErrVersionMismatch).Once()
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, "/api", nil)
+		req.Header.Set(etag.HeaderIfMatch, `"2"`)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
+		c.SetPath("/profile/:profileCode")
+		c.SetParamNames("profileCode")
+		c.SetParamValues("82")
+
+		apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
+		controller := apiHandler.DeleteProfile()(c)
+		var httpErr *echo.HTTPError
+		if assert.ErrorAs(t, controller, &httpErr) {
+			assert.Equal(t, http.StatusPreconditionFailed, httpErr.Code)
+		}
+	})
+}
+
+func TestPatchProfileController(t *testing.T) {
+	t.Run("SuccessPatchProfileController", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = &CustomValidator{validator: validator.New()}
+
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 83).Return(&models.ProfileDTO{ProfileCode: 83}, nil).Twice()
+		profileRepository.Mock.On("GetWorkingExperienceByCode", mock.Anything, 83).Return(&models.ProfileDTO{}, nil).Twice()
+		profileRepository.Mock.On("PatchProfile", mock.Anything, 83, 4, &models.Profile{}, []string{"driving_license"}).
+			Return(&models.ProfileDTO{ProfileCode: 83, Version: 5}, nil).Once()
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPatch, "/api", strings.NewReader(`{"drivingLicense": null}`))
+		req.Header.Set(echo.HeaderContentType, mimeMergePatch)
+		req.Header.Set(etag.HeaderIfMatch, `"4"`)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
+		c.SetPath("/profile/:profileCode")
+		c.SetParamNames("profileCode")
+		c.SetParamValues("83")
+
+		apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
+		controller := apiHandler.PatchProfile()(c)
+		if assert.NoError(t, controller) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, `"5"`, rec.Header().Get(etag.HeaderETag))
+		}
+	})
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantCode    int
+	}{
+		{"FailedPatchProfileController_Err415", echo.MIMETextPlain, `{"lastName": null}`, http.StatusUnsupportedMediaType},
+		{"FailedPatchProfileController_ErrUnknownMember", mimeMergePatch, `{"nickname": "Jo"}`, http.StatusBadRequest},
+		{"FailedPatchProfileController_ErrNullRequired", mimeMergePatch, `{"firstName": null}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = &CustomValidator{validator: validator.New()}
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPatch, "/api", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, tt.contentType)
+			req.Header.Set(etag.HeaderIfMatch, `"1"`)
+			c := e.NewContext(req.WithContext(ownerCtx), rec)
+			c.SetPath("/profile/:profileCode")
+			c.SetParamNames("profileCode")
+			c.SetParamValues("84")
+
+			apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
+			controller := apiHandler.PatchProfile()(c)
+			var httpErr *echo.HTTPError
+			if assert.ErrorAs(t, controller, &httpErr) {
+				assert.Equal(t, tt.wantCode, httpErr.Code)
+			}
+			profileRepository.Mock.AssertNotCalled(t, "PatchProfile", mock.Anything, 84, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestReplaceSkillsController(t *testing.T) {
+	t.Run("SuccessReplaceSkillsController", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = &CustomValidator{validator: validator.New()}
+
+		skillRepository.Mock.On("ReplaceSkills", mock.Anything, 85, 1, []*models.Skill{
+			{ProfileCode: 85, Skill: "Go", Level: "expert"},
+			{ProfileCode: 85, Skill: "SQL", Level: "beginner"},
+		}).Return([]*models.SkillDTO{}, []int{31, 32}, 2, nil).Once()
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/api", strings.NewReader(`{"data": [{"skill": "Golang", "level": "Expert"}, {"skill": "SQL", "level": "Beginner"}]}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(etag.HeaderIfMatch, etag.Format(1))
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
+		c.SetPath("/skill/:profileCode")
+		c.SetParamNames("profileCode")
+		c.SetParamValues("85")
+
+		apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
+		controller := apiHandler.ReplaceSkillsByCode()(c)
+		if assert.NoError(t, controller) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.JSONEq(t, `{"ids": [31, 32]}`, rec.Body.String())
+			assert.Equal(t, etag.Format(2), rec.Header().Get(etag.HeaderETag))
+		}
+	})
+
+	t.Run("FailedReplaceSkillsController_Err428", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = &CustomValidator{validator: validator.New()}
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/api", strings.NewReader(`{"data": []}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
+		c.SetPath("/skill/:profileCode")
+		c.SetParamNames("profileCode")
+		c.SetParamValues("87")
+
+		apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
+		controller := apiHandler.ReplaceSkillsByCode()(c)
+		var httpErr *echo.HTTPError
+		if assert.ErrorAs(t, controller, &httpErr) {
+			assert.Equal(t, http.StatusPreconditionRequired, httpErr.Code)
+		}
+		skillRepository.Mock.AssertNotCalled(t, "ReplaceSkills", mock.Anything, 87, mock.Anything, mock.Anything)
+	})
+
+	t.Run("FailedReplaceSkillsController_Err412", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = &CustomValidator{validator: validator.New()}
+
+		skillRepository.Mock.On("ReplaceSkills", mock.Anything, 88, 1, mock.Anything).Return(nil, nil, 0, dataRepository.ErrVersionMismatch).Once()
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/api", strings.NewReader(`{"data": []}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(etag.HeaderIfMatch, etag.Format(1))
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
+		c.SetPath("/skill/:profileCode")
+		c.SetParamNames("profileCode")
+		c.SetParamValues("88")
+
+		apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
+		controller := apiHandler.ReplaceSkillsByCode()(c)
+		var httpErr *echo.HTTPError
+		if assert.ErrorAs(t, controller, &httpErr) {
+			assert.Equal(t, http.StatusPreconditionFailed, httpErr.Code)
+		}
+	})
+
+	t.Run("FailedCreateSkillsController_Err400", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = &CustomValidator{validator: validator.New()}
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api", strings.NewReader(`{"data": []}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
+		c.SetPath("/skill/:profileCode/batch")
+		c.SetParamNames("profileCode")
+		c.SetParamValues("86")
+
+		apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
+		controller := apiHandler.CreateSkillsByCode()(c)
+		var httpErr *echo.HTTPError
+		if assert.ErrorAs(t, controller, &httpErr) {
+			assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+			assert.Equal(t, "bad request. failed to validate", httpErr.Message)
+		}
+	})
+}
+
+// serveRows calls a batch handler for the rows of profile code, sending
+// version 1 in If-Match.
+func serveRows(method, path, body string, code string, handler func(h *apiControllerHandler) echo.HandlerFunc) (*httptest.ResponseRecorder, error) {
+	e, c, rec := newTestContext(ownerCtx, method, "/api", strings.NewReader(body))
+	c.Request().Header.Set(etag.HeaderIfMatch, etag.Format(1))
+	c.SetPath(path)
+	c.SetParamNames("profileCode")
+	c.SetParamValues(code)
+
+	apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
+	return rec, handler(apiHandler)(c)
+}
+
+func TestEducationRowsController(t *testing.T) {
+	create := func(h *apiControllerHandler) echo.HandlerFunc { return h.CreateEducationRowsByCode() }
+	replace := func(h *apiControllerHandler) echo.HandlerFunc { return h.ReplaceEducationRowsByCode() }
+
+	t.Run("SuccessCreateEducationRowsController", func(t *testing.T) {
+		educationRepository.Mock.On("CreateEducationRows", mock.Anything, mock.MatchedBy(func(rows []*models.Education) bool {
+			return len(rows) == 2 && rows[0].ProfileCode == 90 && rows[0].School == "UI" && rows[1].School == "ITB"
+		})).Return([]int{42, 41}, nil).Once()
+
+		rec, err := serveRows(http.MethodPost, "/education/:profileCode/batch", `{"data": [{"school": "UI"}, {"school": "ITB"}]}`, "90", create)
+		if assert.NoError(t, err) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			// the ids come back in the order the rows were sent
+			assert.JSONEq(t, `{"ids": [42, 41]}`, rec.Body.String())
+		}
+	})
+
+	t.Run("FailedCreateEducationRowsController_Err400", func(t *testing.T) {
+		_, err := serveRows(http.MethodPost, "/education/:profileCode/batch", `{"data": []}`, "91", create)
+		var httpErr *echo.HTTPError
+		if assert.ErrorAs(t, err, &httpErr) {
+			assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+			assert.Equal(t, "bad request. failed to validate", httpErr.Message)
+		}
+	})
+
+	t.Run("FailedCreateEducationRowsController_InvalidRow", func(t *testing.T) {
+		rows := map[string]string{
+			"NoSchool":           `{"data": [{"school": "UI"}, {"degree": "Bachelor"}]}`,
+			"EndsBeforeItStarts": `{"data": [{"school": "UI"}, {"school": "ITB", "startDate": "2020-09-01T00:00:00Z", "endDate": "2016-06-30T00:00:00Z"}]}`,
+		}
+		for name, body := range rows {
+			t.Run(name, func(t *testing.T) {
+				_, err := serveRows(http.MethodPost, "/education/:profileCode/batch", body, "89", create)
+				var httpErr *echo.HTTPError
+				if assert.ErrorAs(t, err, &httpErr) {
+					assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+					assert.Equal(t, "bad request. failed to validate", httpErr.Message)
+				}
+			})
+		}
+		// one bad row rejects the whole batch
+		educationRepository.Mock.AssertNotCalled(t, "CreateEducationRows", mock.Anything, mock.MatchedBy(func(rows []*models.Education) bool {
+			return len(rows) > 0 && rows[0].ProfileCode == 89
+		}))
+	})
+
+	t.Run("SuccessReplaceEducationRowsController", func(t *testing.T) {
+		educationRepository.Mock.On("ReplaceEducationRows", mock.Anything, 92, 1, mock.MatchedBy(func(rows []*models.Education) bool {
+			return len(rows) == 1 && rows[0].ProfileCode == 92 && rows[0].School == "UI"
+		})).Return([]*models.EducationDTO{}, []int{43}, 2, nil).Once()
+
+		rec, err := serveRows(http.MethodPut, "/education/:profileCode", `{"data": [{"school": "UI"}]}`, "92", replace)
+		if assert.NoError(t, err) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.JSONEq(t, `{"ids": [43]}`, rec.Body.String())
+		}
+	})
+
+	t.Run("FailedReplaceEducationRowsController_Err500", func(t *testing.T) {
+		educationRepository.Mock.On("ReplaceEducationRows", mock.Anything, 93, 1, mock.Anything).Return(nil, nil, 0, errors.New("unexpected")).Once()
+
+		_, err := serveRows(http.MethodPut, "/education/:profileCode", `{"data": []}`, "93", replace)
+		var httpErr *echo.HTTPError
+		if assert.ErrorAs(t, err, &httpErr) {
+			assert.Equal(t, http.StatusInternalServerError, httpErr.Code)
+		}
+	})
+}
+
+func TestEmploymentRowsController(t *testing.T) {
+	create := func(h *apiControllerHandler) echo.HandlerFunc { return h.CreateEmploymentRowsByCode() }
+	replace := func(h *apiControllerHandler) echo.HandlerFunc { return h.ReplaceEmploymentRowsByCode() }
+
+	t.Run("SuccessCreateEmploymentRowsController", func(t *testing.T) {
+		employmentRepository.Mock.On("CreateEmploymentRows", mock.Anything, mock.MatchedBy(func(rows []*models.Employment) bool {
+			return len(rows) == 2 && rows[0].ProfileCode == 96 && rows[0].Employer == "Gojek" && rows[1].Employer == "Tokopedia"
+		})).Return([]int{42, 41}, nil).Once()
+
+		rec, err := serveRows(http.MethodPost, "/employment/:profileCode/batch", `{"data": [{"employer": "Gojek"}, {"employer": "Tokopedia"}]}`, "96", create)
+		if assert.NoError(t, err) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			// the ids come back in the order the rows were sent
+			assert.JSONEq(t, `{"ids": [42, 41]}`, rec.Body.String())
+		}
+	})
+
+	t.Run("FailedCreateEmploymentRowsController_Err400", func(t *testing.T) {
+		_, err := serveRows(http.MethodPost, "/employment/:profileCode/batch", `{"data": []}`, "97", create)
+		var httpErr *echo.HTTPError
+		if assert.ErrorAs(t, err, &httpErr) {
+			assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+			assert.Equal(t, "bad request. failed to validate", httpErr.Message)
+		}
+	})
+
+	t.Run("FailedCreateEmploymentRowsController_InvalidRow", func(t *testing.T) {
+		_, err := serveRows(http.MethodPost, "/employment/:profileCode/batch", `{"data": [{"employer": "Gojek", "employmentType": "volunteer"}]}`, "95", create)
+		var httpErr *echo.HTTPError
+		if assert.ErrorAs(t, err, &httpErr) {
+			assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+		}
+		employmentRepository.Mock.AssertNotCalled(t, "CreateEmploymentRows", mock.Anything, mock.MatchedBy(func(rows []*models.Employment) bool {
+			return len(rows) > 0 && rows[0].ProfileCode == 95
+		}))
+	})
+
+	t.Run("SuccessReplaceEmploymentRowsController", func(t *testing.T) {
+		employmentRepository.Mock.On("ReplaceEmploymentRows", mock.Anything, 98, 1, mock.MatchedBy(func(rows []*models.Employment) bool {
+			return len(rows) == 1 && rows[0].ProfileCode == 98 && rows[0].Employer == "Gojek"
+		})).Return([]*models.EmploymentDTO{}, []int{43}, 2, nil).Once()
+
+		rec, err := serveRows(http.MethodPut, "/employment/:profileCode", `{"data": [{"employer": "Gojek"}]}`, "98", replace)
+		if assert.NoError(t, err) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.JSONEq(t, `{"ids": [43]}`, rec.Body.String())
+		}
+	})
+
+	t.Run("FailedReplaceEmploymentRowsController_Err500", func(t *testing.T) {
+		employmentRepository.Mock.On("ReplaceEmploymentRows", mock.Anything, 99, 1, mock.Anything).Return(nil, nil, 0, errors.New("unexpected")).Once()
+
+		_, err := serveRows(http.MethodPut, "/employment/:profileCode", `{"data": []}`, "99", replace)
+		var httpErr *echo.HTTPError
+		if assert.ErrorAs(t, err, &httpErr) {
+			assert.Equal(t, http.StatusInternalServerError, httpErr.Code)
+		}
+	})
+}
+
+func TestListProfilesController(t *testing.T) {
+	t.Run("SuccessListProfilesController", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = &CustomValidator{validator: validator.New()}
+
+		profileRepository.Mock.On("ListProfiles", mock.Anything, mock.MatchedBy(func(filter *models.ProfileFilter) bool {
+			return filter.City == "Bandung" && filter.Skill == "Golang" && filter.Sort == "updated_at" && !filter.Descending
+		})).Return([]*models.ProfileDTO{{ProfileCode: 87, PublicId: "01JAB3Q8W0RM0ZTRBPN7C2Z1K4", City: "Bandung"}}, nil).Once()
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/profiles?city=Bandung&skill=Golang&sort=updated_at&order=asc", nil)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
+		c.SetPath("/profiles")
+
+		apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
+		controller := apiHandler.ListProfiles()(c)
+		if assert.NoError(t, controller) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Body.String(), `"publicId":"01JAB3Q8W0RM0ZTRBPN7C2Z1K4"`)
+			assert.NotContains(t, rec.Body.String(), "nextCursor")
+		}
+	})
+
+	for _, query := range []string{"sort=first_name", "order=up", "limit=101", "cursor=garbage"} {
+		t.Run("FailedListProfilesController_Err400_"+query, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = &CustomValidator{validator: validator.New()}
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/profiles?"+query, nil)
+			c := e.NewContext(req.WithContext(ownerCtx), rec)
+			c.SetPath("/profiles")
+
+			apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
+			controller := apiHandler.ListProfiles()(c)
+			var httpErr *echo.HTTPError
+			if assert.ErrorAs(t, controller, &httpErr) {
+				assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+			}
+		})
+	}
+}
+
+func TestSuggestSkillsController(t *testing.T) {
+	t.Run("SuccessSuggestSkillsController", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = &CustomValidator{validator: validator.New()}
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/skills/suggest?q=k8&limit=5", nil)
+		c := e.NewContext(req.WithContext(ownerCtx), rec)
+		c.SetPath("/skills/suggest")
+
+		apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
+		controller := apiHandler.SuggestSkills()(c)
+		if assert.NoError(t, controller) {
+			var response response.SkillSuggestionList
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "Kubernetes", response.Data[0].Name)
+			assert.Equal(t, "k8s", response.Data[0].Alias)
+		}
+	})
+
+	for _, query := range []string{"", "q=go&limit=51"} {
+		t.Run("FailedSuggestSkillsController_Err400_"+query, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = &CustomValidator{validator: validator.New()}
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/skills/suggest?"+query, nil)
+			c := e.NewContext(req.WithContext(ownerCtx), rec)
+			c.SetPath("/skills/suggest")
+
+			apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
+			controller := apiHandler.SuggestSkills()(c)
+			var httpErr *echo.HTTPError
+			if assert.ErrorAs(t, controller, &httpErr) {
+				assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+			}
+		})
+	}
+}
+
+func TestCreateSkillInvalidLevelController(t *testing.T) {
+	e := echo.New()
+	e.Validator = &CustomValidator{validator: validator.New()}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api", strings.NewReader(`{"skill": "Golang", "level": "Guru"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c := e.NewContext(req.WithContext(ownerCtx), rec)
+	c.SetPath("/skill")
+	c.SetParamNames("profileCode")
+	c.SetParamValues("1")
+
+	apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
+	controller := apiHandler.AddSkillByCode()(c)
+	var httpErr *echo.HTTPError
+	if assert.ErrorAs(t, controller, &httpErr) {
+		assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+	}
+}
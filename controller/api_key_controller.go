@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"net/http"
+	"test-bpjs/v2/models/request"
+	apiKeyService "test-bpjs/v2/service/apikey"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type apiKeyControllerHandler struct {
+	group         *echo.Group
+	apiKeyService apiKeyService.ApiKeyService
+}
+
+func NewApiKeyControllerHandler(
+	group *echo.Group,
+	apiKeyService apiKeyService.ApiKeyService,
+) *apiKeyControllerHandler {
+	return &apiKeyControllerHandler{
+		group:         group,
+		apiKeyService: apiKeyService,
+	}
+}
+
+func (h *apiKeyControllerHandler) MapRoutes() {
+	h.group.GET("/api-keys", h.GetApiKeys())
+	h.group.POST("/api-keys", h.CreateApiKey())
+	h.group.DELETE("/api-keys/:id", h.RevokeApiKey())
+}
+
+func (h *apiKeyControllerHandler) GetApiKeys() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "GetApiKeys", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		res, err := h.apiKeyService.GetApiKeys(ctx)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+func (h *apiKeyControllerHandler) CreateApiKey() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "CreateApiKey", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.CreateApiKeyRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		res, err := h.apiKeyService.CreateApiKey(ctx, request)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		return c.JSON(http.StatusCreated, res)
+	}
+}
+
+func (h *apiKeyControllerHandler) RevokeApiKey() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "RevokeApiKey", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.RevokeApiKeyRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		if err := h.apiKeyService.RevokeApiKey(ctx, request.Id); err != nil {
+			return serviceError(err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
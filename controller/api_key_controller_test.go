@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/response"
+	dataRepository "test-bpjs/v2/repository"
+	repository "test-bpjs/v2/repository/mocks"
+	apiKeyService "test-bpjs/v2/service/apikey"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var apiKeyRepository = &repository.ApiKeyRepository{Mock: mock.Mock{}}
+var apiKeyServiceTest = apiKeyService.NewApiKeyService(apiKeyRepository, authorizer)
+
+func TestCreateApiKeyController(t *testing.T) {
+	serve := func(body map[string]interface{}) (*httptest.ResponseRecorder, error) {
+		requestBody, _ := json.Marshal(body)
+		e, c, rec := newTestContext(ownerCtx, http.MethodPost, "/api/api-keys", bytes.NewBuffer(requestBody))
+
+		apiKeyHandler := NewApiKeyControllerHandler(e.Group("api"), apiKeyServiceTest)
+		return rec, apiKeyHandler.CreateApiKey()(c)
+	}
+
+	t.Run("SuccessCreateApiKeyController", func(t *testing.T) {
+		apiKeyRepository.Mock.On("CreateApiKey", mock.Anything, mock.AnythingOfType("*models.ApiKey")).Return(&models.ApiKeyDTO{
+			Id:        1,
+			CreatedAt: time.Now(),
+		}, nil).Once()
+
+		rec, err := serve(map[string]interface{}{
+			"name":   "ci",
+			"scopes": []string{auth.ScopeProfileRead, auth.ScopeResumeExport},
+		})
+		if assert.NoError(t, err) {
+			var response response.CreateApiKeyResponse
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			assert.Equal(t, 1, response.Id)
+			assert.NotEmpty(t, response.Key)
+		}
+	})
+
+	t.Run("FailedCreateApiKeyController_UnknownScope", func(t *testing.T) {
+		_, err := serve(map[string]interface{}{
+			"name":   "ci",
+			"scopes": []string{"admin:all"},
+		})
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+		}
+	})
+}
+
+func TestGetApiKeysController(t *testing.T) {
+	t.Run("SuccessGetApiKeysController", func(t *testing.T) {
+		apiKeyRepository.Mock.On("GetApiKeysByUser", mock.Anything, 1).Return([]*models.ApiKeyDTO{
+			{Id: 1, Name: "ci", Prefix: "bpjs_abcdefgh", Scopes: []string{auth.ScopeProfileRead}},
+		}, nil).Once()
+
+		e, c, rec := newTestContext(ownerCtx, http.MethodGet, "/api/api-keys", nil)
+
+		apiKeyHandler := NewApiKeyControllerHandler(e.Group("api"), apiKeyServiceTest)
+		if assert.NoError(t, apiKeyHandler.GetApiKeys()(c)) {
+			var response response.ApiKeyList
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Len(t, response.Data, 1)
+		}
+	})
+}
+
+func TestRevokeApiKeyController(t *testing.T) {
+	serve := func(id string) (*httptest.ResponseRecorder, error) {
+		e, c, rec := newTestContext(ownerCtx, http.MethodDelete, "/api/api-keys/"+id, nil)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+
+		apiKeyHandler := NewApiKeyControllerHandler(e.Group("api"), apiKeyServiceTest)
+		return rec, apiKeyHandler.RevokeApiKey()(c)
+	}
+
+	t.Run("SuccessRevokeApiKeyController", func(t *testing.T) {
+		apiKeyRepository.Mock.On("RevokeApiKey", mock.Anything, 1, 4).Return(nil).Once()
+
+		rec, err := serve("4")
+		if assert.NoError(t, err) {
+			assert.Equal(t, http.StatusNoContent, rec.Code)
+		}
+	})
+
+	t.Run("FailedRevokeApiKeyController_Err404", func(t *testing.T) {
+		apiKeyRepository.Mock.On("RevokeApiKey", mock.Anything, 1, 5).Return(dataRepository.ErrNotFound).Once()
+
+		_, err := serve("5")
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+		}
+	})
+
+	t.Run("FailedRevokeApiKeyController_Err400", func(t *testing.T) {
+		_, err := serve("abc")
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+		}
+	})
+}
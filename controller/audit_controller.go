@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"net/http"
+	"test-bpjs/v2/models/request"
+	auditService "test-bpjs/v2/service/audit"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type auditControllerHandler struct {
+	group        *echo.Group
+	auditService auditService.AuditService
+}
+
+func NewAuditControllerHandler(
+	group *echo.Group,
+	auditService auditService.AuditService,
+) *auditControllerHandler {
+	return &auditControllerHandler{
+		group:        group,
+		auditService: auditService,
+	}
+}
+
+func (h *auditControllerHandler) MapRoutes() {
+	h.group.GET("/profile/:profileCode/audit", h.GetAuditLog())
+}
+
+func (h *auditControllerHandler) GetAuditLog() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "GetAuditLog", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.GetAuditLogRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		res, err := h.auditService.GetAuditLog(ctx, request)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/response"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetAuditLogController(t *testing.T) {
+	serve := func(target string) (*httptest.ResponseRecorder, error) {
+		e, c, rec := newTestContext(ownerCtx, http.MethodGet, target, nil)
+		c.SetParamNames("profileCode")
+		c.SetParamValues("90")
+
+		auditHandler := NewAuditControllerHandler(e.Group("api"), auditor)
+		return rec, auditHandler.GetAuditLog()(c)
+	}
+
+	t.Run("SuccessGetAuditLogController", func(t *testing.T) {
+		auditRepository.Mock.On("GetAuditLogsByProfileCode", mock.Anything, 90, 5, 5).Return([]*models.AuditLogDTO{
+			{Id: 6, ActorId: 1, Action: models.AuditActionDelete, Entity: models.AuditEntitySkill, EntityId: 3},
+			{Id: 5, ActorId: 1, Action: models.AuditActionUpdate, Entity: models.AuditEntityProfile, EntityId: 90, ProfilePublicId: "01JAB3Q8W0RM0ZTRBPN7C2Z1K4"},
+		}, 6, nil).Once()
+
+		rec, err := serve("/api/profile/90/audit?page=2&pageSize=5")
+		if assert.NoError(t, err) {
+			var response response.AuditLogList
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, 6, response.Total)
+			assert.Equal(t, models.AuditEntitySkill, response.Data[0].Entity)
+			assert.Equal(t, 3, response.Data[0].EntityId)
+			// profile entries name the profile by its public id only
+			assert.Zero(t, response.Data[1].EntityId)
+			assert.Equal(t, "01JAB3Q8W0RM0ZTRBPN7C2Z1K4", response.Data[1].EntityPublicId)
+			assert.NotContains(t, rec.Body.String(), `"entityId":90`)
+		}
+	})
+
+	t.Run("FailedGetAuditLogController_Err400", func(t *testing.T) {
+		_, err := serve("/api/profile/90/audit?pageSize=1000")
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+		}
+	})
+}
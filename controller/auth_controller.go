@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"test-bpjs/v2/models/request"
+	authService "test-bpjs/v2/service/auth"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type authControllerHandler struct {
+	group       *echo.Group
+	authService authService.AuthService
+}
+
+func NewAuthControllerHandler(
+	group *echo.Group,
+	authService authService.AuthService,
+) *authControllerHandler {
+	return &authControllerHandler{
+		group:       group,
+		authService: authService,
+	}
+}
+
+func (h *authControllerHandler) MapRoutes() {
+	h.group.POST("/token", h.IssueToken())
+}
+
+func (h *authControllerHandler) IssueToken() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "IssueToken", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.LoginRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		res, err := h.authService.Login(ctx, request)
+		if errors.Is(err, authService.ErrInvalidCredentials) {
+			return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+		}
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
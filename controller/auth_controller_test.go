@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"test-bpjs/v2/config"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/response"
+	repository "test-bpjs/v2/repository/mocks"
+	authService "test-bpjs/v2/service/auth"
+	"testing"
+
+	"github.com/go-playground/validator"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var userRepository = &repository.UserRepository{Mock: mock.Mock{}}
+var tokenManager, _ = auth.NewTokenManager(config.Config{JWTSecret: "test-secret"})
+var authServiceTest = authService.NewAuthService(userRepository, tokenManager)
+
+func TestIssueTokenController(t *testing.T) {
+	passwordHash, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	userRepository.Mock.On("GetUserByUsername", mock.Anything, "john").Return(&models.UserDTO{
+		Id:           1,
+		Username:     "john",
+		PasswordHash: string(passwordHash),
+	}, nil)
+
+	t.Run("SuccessIssueTokenController", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = &CustomValidator{validator: validator.New()}
+		requestBody, _ := json.Marshal(map[string]interface{}{
+			"username": "john",
+			"password": "secret",
+		})
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/auth/token", bytes.NewBuffer(requestBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		c := e.NewContext(req, rec)
+
+		authHandler := NewAuthControllerHandler(e.Group("auth"), authServiceTest)
+
+		controller := authHandler.IssueToken()(c)
+		if assert.NoError(t, controller) {
+			var response response.TokenResponse
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.NotEmpty(t, response.AccessToken)
+		}
+	})
+
+	t.Run("FailedIssueTokenController_Err401", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = &CustomValidator{validator: validator.New()}
+		requestBody, _ := json.Marshal(map[string]interface{}{
+			"username": "john",
+			"password": "wrong",
+		})
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/auth/token", bytes.NewBuffer(requestBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		c := e.NewContext(req, rec)
+
+		authHandler := NewAuthControllerHandler(e.Group("auth"), authServiceTest)
+
+		controller := authHandler.IssueToken()(c)
+		if assert.Error(t, controller) {
+			re := regexp.MustCompile(`code=(\d+)`)
+			match := re.FindStringSubmatch(controller.Error())
+			errCode, _ := strconv.Atoi(match[1])
+			assert.Equal(t, http.StatusUnauthorized, errCode)
+		}
+	})
+
+	t.Run("FailedIssueTokenController_ErrValidate", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = &CustomValidator{validator: validator.New()}
+		requestBody, _ := json.Marshal(map[string]interface{}{
+			"username": "john",
+		})
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/auth/token", bytes.NewBuffer(requestBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		c := e.NewContext(req, rec)
+
+		authHandler := NewAuthControllerHandler(e.Group("auth"), authServiceTest)
+
+		controller := authHandler.IssueToken()(c)
+		if assert.Error(t, controller) {
+			re := regexp.MustCompile(`code=(\d+), message=(.+)`)
+			match := re.FindStringSubmatch(controller.Error())
+			errCode, _ := strconv.Atoi(match[1])
+			assert.Equal(t, http.StatusBadRequest, errCode)
+			assert.Equal(t, "bad request. failed to validate", match[2])
+		}
+	})
+}
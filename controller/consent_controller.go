@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"test-bpjs/v2/models/request"
+	consentService "test-bpjs/v2/service/consent"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type consentControllerHandler struct {
+	group          *echo.Group
+	consentService consentService.ConsentService
+}
+
+func NewConsentControllerHandler(
+	group *echo.Group,
+	consentService consentService.ConsentService,
+) *consentControllerHandler {
+	return &consentControllerHandler{
+		group:          group,
+		consentService: consentService,
+	}
+}
+
+func (h *consentControllerHandler) MapRoutes() {
+	h.group.GET("/profile/:profileCode/consents", h.GetConsents())
+	h.group.POST("/profile/:profileCode/consents", h.GrantConsent())
+	h.group.DELETE("/profile/:profileCode/consents/:purpose", h.RevokeConsent())
+}
+
+func (h *consentControllerHandler) GetConsents() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "GetConsents", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.GetConsentsRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		res, err := h.consentService.GetConsents(ctx, request.ProfileCode)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+func (h *consentControllerHandler) GrantConsent() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "GrantConsent", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.GrantConsentRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		res, err := h.consentService.GrantConsent(ctx, request)
+		if errors.Is(err, consentService.ErrOutdatedTerms) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
+		if err != nil {
+			return serviceError(err)
+		}
+
+		return c.JSON(http.StatusCreated, res)
+	}
+}
+
+func (h *consentControllerHandler) RevokeConsent() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "RevokeConsent", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.RevokeConsentRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		if err := h.consentService.RevokeConsent(ctx, request); err != nil {
+			return serviceError(err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/response"
+	consentService "test-bpjs/v2/service/consent"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var consentServiceTest = consentService.NewConsentService(consentRepository, authorizer, "2024-11")
+
+func TestGrantConsentController(t *testing.T) {
+	serve := func(body map[string]interface{}) (*httptest.ResponseRecorder, error) {
+		requestBody, _ := json.Marshal(body)
+		e, c, rec := newTestContext(ownerCtx, http.MethodPost, "/api/profile/80/consents", bytes.NewBuffer(requestBody))
+		c.SetParamNames("profileCode")
+		c.SetParamValues(strconv.Itoa(80))
+
+		consentHandler := NewConsentControllerHandler(e.Group("api"), consentServiceTest)
+		return rec, consentHandler.GrantConsent()(c)
+	}
+
+	t.Run("SuccessGrantConsentController", func(t *testing.T) {
+		consentRepository.Mock.On("GrantConsent", mock.Anything, mock.AnythingOfType("*models.Consent")).Return(&models.ConsentDTO{Id: 1}, nil).Once()
+
+		rec, err := serve(map[string]interface{}{"purpose": "recruiter_access", "termsVersion": "2024-11"})
+		if assert.NoError(t, err) {
+			var response response.ConsentResponse
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			assert.True(t, response.Active)
+		}
+	})
+
+	t.Run("FailedGrantConsentController_Err409", func(t *testing.T) {
+		_, err := serve(map[string]interface{}{"purpose": "recruiter_access", "termsVersion": "2023-01"})
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusConflict, err.(*echo.HTTPError).Code)
+		}
+	})
+
+	t.Run("FailedGrantConsentController_Err400", func(t *testing.T) {
+		_, err := serve(map[string]interface{}{"purpose": "marketing", "termsVersion": "2024-11"})
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+		}
+	})
+}
+
+func TestRevokeConsentController(t *testing.T) {
+	t.Run("SuccessRevokeConsentController", func(t *testing.T) {
+		consentRepository.Mock.On("RevokeConsent", mock.Anything, 81, "recruiter_access", mock.Anything).Return(nil).Once()
+
+		e, c, rec := newTestContext(ownerCtx, http.MethodDelete, "/api/profile/81/consents/recruiter_access", nil)
+		c.SetParamNames("profileCode", "purpose")
+		c.SetParamValues(strconv.Itoa(81), "recruiter_access")
+
+		consentHandler := NewConsentControllerHandler(e.Group("api"), consentServiceTest)
+		if assert.NoError(t, consentHandler.RevokeConsent()(c)) {
+			assert.Equal(t, http.StatusNoContent, rec.Code)
+		}
+	})
+}
+
+func TestRecruiterConsentController(t *testing.T) {
+	recruiterCtx := auth.WithClaims(context.Background(), &auth.Claims{UserId: 2, Role: auth.RoleRecruiter})
+	consentRepository.Mock.On("HasActiveConsent", mock.Anything, 82, models.ConsentPurposeRecruiterAccess, "").Return(false, nil).Once()
+
+	e, c, _ := newTestContext(recruiterCtx, http.MethodGet, "/api/profile/82", nil)
+	c.SetParamNames("profileCode")
+	c.SetParamValues(strconv.Itoa(82))
+
+	apiHandler := NewApiControllerHandler(e.Group("api"), profileServiceTest, skillServiceTest, educationServiceTest, employmentServiceTest)
+	err := apiHandler.GetProfileByCode()(c)
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusForbidden, err.(*echo.HTTPError).Code)
+	}
+}
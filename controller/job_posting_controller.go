@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"net/http"
+	"test-bpjs/v2/models/request"
+	jobService "test-bpjs/v2/service/job"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type jobPostingControllerHandler struct {
+	group             *echo.Group
+	jobPostingService jobService.JobPostingService
+}
+
+func NewJobPostingControllerHandler(
+	group *echo.Group,
+	jobPostingService jobService.JobPostingService,
+) *jobPostingControllerHandler {
+	return &jobPostingControllerHandler{
+		group:             group,
+		jobPostingService: jobPostingService,
+	}
+}
+
+func (h *jobPostingControllerHandler) MapRoutes() {
+	h.group.GET("/jobs", h.GetJobPostings())
+	h.group.POST("/jobs", h.CreateJobPosting())
+	h.group.GET("/jobs/:id", h.GetJobPosting())
+	h.group.PUT("/jobs/:id", h.UpdateJobPosting())
+	h.group.DELETE("/jobs/:id", h.DeleteJobPosting())
+	h.group.GET("/jobs/:id/matches", h.MatchCandidates())
+}
+
+func (h *jobPostingControllerHandler) GetJobPostings() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "GetJobPostings", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		res, err := h.jobPostingService.GetJobPostings(ctx)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+func (h *jobPostingControllerHandler) GetJobPosting() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "GetJobPosting", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.GetJobPostingRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		res, err := h.jobPostingService.GetJobPosting(ctx, request.Id)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+func (h *jobPostingControllerHandler) CreateJobPosting() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "CreateJobPosting", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.CreateJobPostingRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		res, err := h.jobPostingService.CreateJobPosting(ctx, request)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		return c.JSON(http.StatusCreated, res)
+	}
+}
+
+func (h *jobPostingControllerHandler) UpdateJobPosting() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "UpdateJobPosting", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.UpdateJobPostingRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		res, err := h.jobPostingService.UpdateJobPosting(ctx, request)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+func (h *jobPostingControllerHandler) DeleteJobPosting() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "DeleteJobPosting", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.DeleteJobPostingRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		if err := h.jobPostingService.DeleteJobPosting(ctx, request.Id); err != nil {
+			return serviceError(err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func (h *jobPostingControllerHandler) MatchCandidates() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "MatchCandidates", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.MatchJobPostingRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		res, err := h.jobPostingService.MatchCandidates(ctx, request)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/response"
+	repository "test-bpjs/v2/repository/mocks"
+	jobService "test-bpjs/v2/service/job"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var jobPostingRepository = &repository.JobPostingRepository{Mock: mock.Mock{}}
+var jobPostingServiceTest = jobService.NewJobPostingService(jobPostingRepository, authorizer)
+var recruiterCtx = auth.WithClaims(context.Background(), &auth.Claims{UserId: 5, Role: auth.RoleRecruiter})
+
+func TestCreateJobPostingController(t *testing.T) {
+	serve := func(body string) (*httptest.ResponseRecorder, error) {
+		e, c, rec := newTestContext(recruiterCtx, http.MethodPost, "/api/jobs", strings.NewReader(body))
+
+		jobPostingHandler := NewJobPostingControllerHandler(e.Group("api"), jobPostingServiceTest)
+		return rec, jobPostingHandler.CreateJobPosting()(c)
+	}
+
+	t.Run("SuccessCreateJobPostingController", func(t *testing.T) {
+		jobPostingRepository.Mock.On("CreateJobPosting", mock.Anything, mock.MatchedBy(func(jobPosting *models.JobPosting) bool {
+			return jobPosting.Title == "Backend Engineer" && jobPosting.OwnerId == 5
+		})).Return(&models.JobPostingDTO{Id: 1}, nil).Once()
+
+		rec, err := serve(`{"title":"Backend Engineer","skills":[{"skill":"Golang","level":"Advanced","required":true}],"minYearsExperience":3,"city":"Jakarta"}`)
+		if assert.NoError(t, err) {
+			var response response.JobPostingResponse
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			assert.Equal(t, 1, response.Id)
+			assert.Equal(t, 3, response.MinYearsExperience)
+		}
+	})
+
+	t.Run("FailedCreateJobPostingController_NoSkills", func(t *testing.T) {
+		_, err := serve(`{"title":"Backend Engineer","skills":[]}`)
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+		}
+	})
+
+	t.Run("FailedCreateJobPostingController_BlankSkill", func(t *testing.T) {
+		_, err := serve(`{"title":"Backend Engineer","skills":[{"skill":""}]}`)
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+		}
+	})
+}
+
+func TestMatchCandidatesController(t *testing.T) {
+	serve := func(ctx context.Context, id string) (*httptest.ResponseRecorder, error) {
+		e, c, rec := newTestContext(ctx, http.MethodGet, "/api/jobs/"+id+"/matches", nil)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+
+		jobPostingHandler := NewJobPostingControllerHandler(e.Group("api"), jobPostingServiceTest)
+		return rec, jobPostingHandler.MatchCandidates()(c)
+	}
+
+	t.Run("SuccessMatchCandidatesController", func(t *testing.T) {
+		jobPostingRepository.Mock.On("GetJobPostingById", mock.Anything, 2).Return(&models.JobPostingDTO{
+			Id: 2, OwnerId: 5, Skills: []models.JobPostingSkill{{Skill: "Golang", Required: true}},
+		}, nil).Once()
+		jobPostingRepository.Mock.On("GetMatchCandidates", mock.Anything, mock.Anything, []string{"go", "golang", "go lang"}).Return([]*models.MatchCandidateDTO{
+			{ProfileCode: 88, PublicId: "01JAB3Q8W0RM0ZTRBPN7C2Z1K8", Skills: []*models.SkillDTO{{Skill: "Golang", Level: "Expert"}}},
+		}, nil).Once()
+
+		rec, err := serve(recruiterCtx, "2")
+		if assert.NoError(t, err) {
+			var response response.JobMatchList
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "01JAB3Q8W0RM0ZTRBPN7C2Z1K8", response.Data[0].PublicId)
+			assert.NotContains(t, rec.Body.String(), "profileCode")
+			assert.Equal(t, float64(100), response.Data[0].Score)
+			assert.Len(t, response.Data[0].Criteria, 3)
+		}
+	})
+
+	t.Run("FailedMatchCandidatesController_Err403", func(t *testing.T) {
+		_, err := serve(ownerCtx, "2")
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusForbidden, err.(*echo.HTTPError).Code)
+		}
+	})
+
+	t.Run("FailedMatchCandidatesController_Err404", func(t *testing.T) {
+		jobPostingRepository.Mock.On("GetJobPostingById", mock.Anything, 3).Return(&models.JobPostingDTO{}, sql.ErrNoRows).Once()
+
+		_, err := serve(recruiterCtx, "3")
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+		}
+	})
+}
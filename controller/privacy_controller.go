@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"test-bpjs/v2/models/request"
+	privacyService "test-bpjs/v2/service/privacy"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type privacyControllerHandler struct {
+	group          *echo.Group
+	privacyService privacyService.PrivacyService
+}
+
+func NewPrivacyControllerHandler(
+	group *echo.Group,
+	privacyService privacyService.PrivacyService,
+) *privacyControllerHandler {
+	return &privacyControllerHandler{
+		group:          group,
+		privacyService: privacyService,
+	}
+}
+
+func (h *privacyControllerHandler) MapRoutes() {
+	h.group.GET("/profile/:profileCode/data-export", h.ExportProfileData())
+	h.group.POST("/profile/:profileCode/erasure", h.EraseProfile())
+}
+
+func (h *privacyControllerHandler) ExportProfileData() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "ExportProfileData", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.DataExportRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		res, err := h.privacyService.ExportProfileData(ctx, request.ProfileCode)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", res.FileName))
+		return c.Blob(http.StatusOK, "application/zip", res.Content)
+	}
+}
+
+func (h *privacyControllerHandler) EraseProfile() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "EraseProfile", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.EraseProfileRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		res, err := h.privacyService.EraseProfile(ctx, request)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
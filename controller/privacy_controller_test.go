@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/response"
+	repository "test-bpjs/v2/repository/mocks"
+	privacyService "test-bpjs/v2/service/privacy"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var privacyRepository = &repository.PrivacyRepository{Mock: mock.Mock{}}
+var resumeRepository = &repository.ResumeRepository{Mock: mock.Mock{}}
+var privacyServiceTest = privacyService.NewPrivacyService(profileRepository, educationRepository, employmentRepository, skillRepository, consentRepository, auditRepository, resumeRepository, privacyRepository, authorizer)
+
+func TestExportProfileDataController(t *testing.T) {
+	t.Run("SuccessExportProfileDataController", func(t *testing.T) {
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 70).Return(&models.ProfileDTO{ProfileCode: 70, PublicId: "01JAB3Q8W0RM0ZTRBPN7C2Z1K4"}, nil).Once()
+		profileRepository.Mock.On("GetWorkingExperienceByCode", mock.Anything, 70).Return(&models.ProfileDTO{}, nil).Once()
+		educationRepository.Mock.On("GetEducationByProfileCode", mock.Anything, 70).Return([]*models.EducationDTO{}, nil).Once()
+		employmentRepository.Mock.On("GetEmploymentByProfileCode", mock.Anything, 70).Return([]*models.EmploymentDTO{}, nil).Once()
+		skillRepository.Mock.On("GetSkillsByProfileCode", mock.Anything, 70).Return([]*models.SkillDTO{}, nil).Once()
+		consentRepository.Mock.On("GetConsentsByProfileCode", mock.Anything, 70).Return([]*models.ConsentDTO{}, nil).Once()
+		auditRepository.Mock.On("GetAuditLogsByProfileCode", mock.Anything, 70, 0, 0).Return([]*models.AuditLogDTO{}, 0, nil).Once()
+		resumeRepository.Mock.On("GetResumeVersions", mock.Anything, 70).Return([]*models.ResumeVersionDTO{}, nil).Once()
+
+		e, c, rec := newTestContext(ownerCtx, http.MethodGet, "/api/profile/70/data-export", nil)
+		c.SetParamNames("profileCode")
+		c.SetParamValues(strconv.Itoa(70))
+
+		privacyHandler := NewPrivacyControllerHandler(e.Group("api"), privacyServiceTest)
+		if assert.NoError(t, privacyHandler.ExportProfileData()(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "application/zip", rec.Header().Get(echo.HeaderContentType))
+			assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "data-export-01JAB3Q8W0RM0ZTRBPN7C2Z1K4.zip")
+			assert.Equal(t, []byte("PK"), rec.Body.Bytes()[:2])
+		}
+	})
+}
+
+func TestEraseProfileController(t *testing.T) {
+	serve := func(code int, body map[string]interface{}) (*httptest.ResponseRecorder, error) {
+		requestBody, _ := json.Marshal(body)
+		e, c, rec := newTestContext(ownerCtx, http.MethodPost, "/api/profile/"+strconv.Itoa(code)+"/erasure", bytes.NewBuffer(requestBody))
+		c.SetParamNames("profileCode")
+		c.SetParamValues(strconv.Itoa(code))
+
+		privacyHandler := NewPrivacyControllerHandler(e.Group("api"), privacyServiceTest)
+		return rec, privacyHandler.EraseProfile()(c)
+	}
+
+	t.Run("SuccessEraseProfileController", func(t *testing.T) {
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 71).Return(&models.ProfileDTO{ProfileCode: 71, PublicId: "01JAB3Q8W0RM0ZTRBPN7C2Z1K6"}, nil).Once()
+		privacyRepository.Mock.On("EraseProfile", mock.Anything, 71, mock.AnythingOfType("*models.ErasureReceipt")).Return(nil).Once()
+
+		rec, err := serve(71, map[string]interface{}{"mode": "anonymise", "reason": "subject request"})
+		if assert.NoError(t, err) {
+			var response response.ErasureReceiptResponse
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "anonymise", response.Mode)
+			assert.NotEmpty(t, response.ReceiptId)
+		}
+	})
+
+	t.Run("FailedEraseProfileController_UnknownMode", func(t *testing.T) {
+		_, err := serve(71, map[string]interface{}{"mode": "shred"})
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+		}
+	})
+}
@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/response"
+	resumeService "test-bpjs/v2/service/resume"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var resumeServiceTest = resumeService.NewResumeService(profileRepository, educationRepository, employmentRepository, skillRepository, resumeRepository, authorizer, auditor, timeline)
+
+func serveResume(method, target, body string, names, values []string, handler func(h *resumeControllerHandler) echo.HandlerFunc) (*httptest.ResponseRecorder, error) {
+	e, c, rec := newTestContext(ownerCtx, method, target, strings.NewReader(body))
+	c.SetParamNames(names...)
+	c.SetParamValues(values...)
+
+	resumeHandler := NewResumeControllerHandler(e.Group("api"), resumeServiceTest)
+	return rec, handler(resumeHandler)(c)
+}
+
+func TestGetResumeController(t *testing.T) {
+	t.Run("SuccessGetResumeController", func(t *testing.T) {
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 100).Return(&models.ProfileDTO{ProfileCode: 100, FirstName: "John"}, nil).Once()
+		profileRepository.Mock.On("GetWorkingExperienceByCode", mock.Anything, 100).Return(&models.ProfileDTO{WorkingExperience: "5 years"}, nil).Once()
+		educationRepository.Mock.On("GetEducationByProfileCode", mock.Anything, 100).Return([]*models.EducationDTO{}, nil).Once()
+		employmentRepository.Mock.On("GetEmploymentByProfileCode", mock.Anything, 100).Return([]*models.EmploymentDTO{{Id: 1, Employer: "BPJS"}}, nil).Once()
+		skillRepository.Mock.On("GetSkillsByProfileCode", mock.Anything, 100).Return([]*models.SkillDTO{}, nil).Once()
+
+		rec, err := serveResume(http.MethodGet, "/api/resume/100", "", []string{"profileCode"}, []string{"100"}, (*resumeControllerHandler).GetResume)
+		if assert.NoError(t, err) {
+			var response response.ResumeResponse
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "John", response.Profile.FirstName)
+			assert.Equal(t, "BPJS", response.Employment[0].Employer)
+		}
+	})
+}
+
+func TestSaveResumeVersionController(t *testing.T) {
+	t.Run("SuccessSaveResumeVersionController", func(t *testing.T) {
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 101).Return(&models.ProfileDTO{ProfileCode: 101}, nil).Once()
+		profileRepository.Mock.On("GetWorkingExperienceByCode", mock.Anything, 101).Return(&models.ProfileDTO{}, nil).Once()
+		educationRepository.Mock.On("GetEducationByProfileCode", mock.Anything, 101).Return([]*models.EducationDTO{}, nil).Once()
+		employmentRepository.Mock.On("GetEmploymentByProfileCode", mock.Anything, 101).Return([]*models.EmploymentDTO{}, nil).Once()
+		skillRepository.Mock.On("GetSkillsByProfileCode", mock.Anything, 101).Return([]*models.SkillDTO{}, nil).Once()
+		resumeRepository.Mock.On("CreateResumeVersion", mock.Anything, mock.MatchedBy(func(v *models.ResumeVersion) bool {
+			return v.ProfileCode == 101 && v.Label == "march"
+		})).Return(&models.ResumeVersionDTO{ProfileCode: 101, Version: 1, Label: "march"}, nil).Once()
+
+		rec, err := serveResume(http.MethodPost, "/api/resume/101/versions", `{"label":"march"}`, []string{"profileCode"}, []string{"101"}, (*resumeControllerHandler).SaveResumeVersion)
+		if assert.NoError(t, err) {
+			var response response.ResumeVersionResponse
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			assert.Equal(t, 1, response.Version)
+			assert.Equal(t, "march", response.Label)
+		}
+	})
+}
+
+func TestGetResumeVersionController(t *testing.T) {
+	t.Run("FailedGetResumeVersionController_Err404", func(t *testing.T) {
+		resumeRepository.Mock.On("GetResumeVersion", mock.Anything, 102, 7).Return(nil, sql.ErrNoRows).Once()
+
+		_, err := serveResume(http.MethodGet, "/api/resume/102/versions/7", "", []string{"profileCode", "version"}, []string{"102", "7"}, (*resumeControllerHandler).GetResumeVersion)
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+		}
+	})
+}
+
+func TestDiffResumeVersionsController(t *testing.T) {
+	t.Run("SuccessDiffResumeVersionsController", func(t *testing.T) {
+		resumeRepository.Mock.On("GetResumeVersion", mock.Anything, 103, 1).Return(&models.ResumeVersionDTO{Version: 1, Snapshot: &models.ResumeSnapshot{
+			Profile: &models.ProfileDTO{ProfileCode: 103, City: "Bandung"},
+		}}, nil).Once()
+		resumeRepository.Mock.On("GetResumeVersion", mock.Anything, 103, 2).Return(&models.ResumeVersionDTO{Version: 2, Snapshot: &models.ResumeSnapshot{
+			Profile: &models.ProfileDTO{ProfileCode: 103, City: "Jakarta"},
+			Skills:  []*models.SkillDTO{{Id: 1, Skill: "Golang"}},
+		}}, nil).Once()
+
+		rec, err := serveResume(http.MethodGet, "/api/resume/103/versions/diff?from=1&to=2", "", []string{"profileCode"}, []string{"103"}, (*resumeControllerHandler).DiffResumeVersions)
+		if assert.NoError(t, err) {
+			var response response.ResumeDiffResponse
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, json.RawMessage(`"Jakarta"`), response.Profile["city"].After)
+			assert.Equal(t, resumeService.RowAdded, response.Skills[0].Change)
+		}
+	})
+
+	t.Run("FailedDiffResumeVersionsController_Err400", func(t *testing.T) {
+		_, err := serveResume(http.MethodGet, "/api/resume/103/versions/diff?from=1", "", []string{"profileCode"}, []string{"103"}, (*resumeControllerHandler).DiffResumeVersions)
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+		}
+	})
+}
+
+func TestRestoreResumeVersionController(t *testing.T) {
+	t.Run("FailedRestoreResumeVersionController_Err404", func(t *testing.T) {
+		resumeRepository.Mock.On("GetResumeVersion", mock.Anything, 104, 3).Return(nil, sql.ErrNoRows).Once()
+
+		_, err := serveResume(http.MethodPost, "/api/resume/104/versions/3/restore", "", []string{"profileCode", "version"}, []string{"104", "3"}, (*resumeControllerHandler).RestoreResumeVersion)
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+		}
+	})
+}
+
+func TestCheckAtsController(t *testing.T) {
+	t.Run("SuccessCheckAtsController", func(t *testing.T) {
+		skillRepository.Mock.On("GetSkillsByProfileCode", mock.Anything, 110).Return([]*models.SkillDTO{{Skill: "PostgreSQL"}}, nil).Once()
+		employmentRepository.Mock.On("GetEmploymentByProfileCode", mock.Anything, 110).Return([]*models.EmploymentDTO{}, nil).Once()
+		educationRepository.Mock.On("GetEducationByProfileCode", mock.Anything, 110).Return([]*models.EducationDTO{}, nil).Once()
+
+		rec, err := serveResume(http.MethodPost, "/api/resume/110/ats-check", `{"jobDescription":"PostgreSQL and Redis"}`, []string{"profileCode"}, []string{"110"}, (*resumeControllerHandler).CheckAts)
+		if assert.NoError(t, err) {
+			var response response.AtsCheckResponse
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "postgresql", response.Found[0].Keyword)
+			assert.Equal(t, "redis", response.Missing[0].Keyword)
+			assert.Equal(t, 50.0, response.Coverage)
+		}
+	})
+
+	t.Run("FailedCheckAtsController_Err400", func(t *testing.T) {
+		_, err := serveResume(http.MethodPost, "/api/resume/110/ats-check", `{"jobDescription":""}`, []string{"profileCode"}, []string{"110"}, (*resumeControllerHandler).CheckAts)
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+		}
+	})
+}
+
+func TestGetResumeScoreController(t *testing.T) {
+	t.Run("SuccessGetResumeScoreController", func(t *testing.T) {
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 111).Return(&models.ProfileDTO{ProfileCode: 111, PhotoUrl: "photo.jpg"}, nil).Once()
+		profileRepository.Mock.On("GetWorkingExperienceByCode", mock.Anything, 111).Return(&models.ProfileDTO{}, nil).Once()
+		educationRepository.Mock.On("GetEducationByProfileCode", mock.Anything, 111).Return([]*models.EducationDTO{}, nil).Once()
+		employmentRepository.Mock.On("GetEmploymentByProfileCode", mock.Anything, 111).Return([]*models.EmploymentDTO{}, nil).Once()
+		skillRepository.Mock.On("GetSkillsByProfileCode", mock.Anything, 111).Return([]*models.SkillDTO{}, nil).Once()
+
+		rec, err := serveResume(http.MethodGet, "/api/profile/111/score", "", []string{"profileCode"}, []string{"111"}, (*resumeControllerHandler).GetResumeScore)
+		if assert.NoError(t, err) {
+			var response response.ResumeScoreResponse
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, 10, response.Score)
+			assert.Equal(t, "Write a summary of your working experience", response.Suggestions[0].Message)
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"net/http"
+	"test-bpjs/v2/models/request"
+	searchService "test-bpjs/v2/service/search"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type searchControllerHandler struct {
+	group         *echo.Group
+	searchService searchService.SearchService
+}
+
+func NewSearchControllerHandler(
+	group *echo.Group,
+	searchService searchService.SearchService,
+) *searchControllerHandler {
+	return &searchControllerHandler{
+		group:         group,
+		searchService: searchService,
+	}
+}
+
+func (h *searchControllerHandler) MapRoutes() {
+	h.group.GET("/search", h.Search())
+}
+
+func (h *searchControllerHandler) Search() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "Search", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.SearchRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		res, err := h.searchService.Search(ctx, request)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
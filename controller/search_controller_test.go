@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/response"
+	repository "test-bpjs/v2/repository/mocks"
+	searchService "test-bpjs/v2/service/search"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var searchRepository = &repository.SearchRepository{Mock: mock.Mock{}}
+var searchServiceTest = searchService.NewSearchService(searchRepository, authorizer)
+
+func TestSearchController(t *testing.T) {
+	serve := func(target string) (*httptest.ResponseRecorder, error) {
+		e, c, rec := newTestContext(ownerCtx, http.MethodGet, target, nil)
+
+		searchHandler := NewSearchControllerHandler(e.Group("api"), searchServiceTest)
+		return rec, searchHandler.Search()(c)
+	}
+
+	t.Run("SuccessSearchController", func(t *testing.T) {
+		searchRepository.Mock.On("SearchProfiles", mock.Anything, mock.MatchedBy(func(search *models.ProfileSearch) bool {
+			return search.Query == "backend golang" && search.Language == models.SearchLanguageIndonesian && search.Limit == 5
+		})).Return([]*models.SearchResultDTO{
+			{ProfileCode: 88, PublicId: "01JAB3Q8W0RM0ZTRBPN7C2Z1K8", FirstName: "Siti", Rank: 0.8, Snippet: "<mark>Backend</mark> <mark>Golang</mark>"},
+		}, nil).Once()
+
+		rec, err := serve("/api/search?q=backend+golang&lang=id&pageSize=5")
+		if assert.NoError(t, err) {
+			var response response.SearchResultList
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "01JAB3Q8W0RM0ZTRBPN7C2Z1K8", response.Data[0].PublicId)
+			assert.NotContains(t, rec.Body.String(), "profileCode")
+			assert.Equal(t, "<mark>Backend</mark> <mark>Golang</mark>", response.Data[0].Snippet)
+		}
+	})
+
+	t.Run("FailedSearchController_MissingQuery", func(t *testing.T) {
+		_, err := serve("/api/search")
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+		}
+	})
+
+	t.Run("FailedSearchController_UnknownLanguage", func(t *testing.T) {
+		_, err := serve("/api/search?q=golang&lang=fr")
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+		}
+	})
+}
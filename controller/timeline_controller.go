@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"net/http"
+	"test-bpjs/v2/models/request"
+	timelineService "test-bpjs/v2/service/timeline"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type timelineControllerHandler struct {
+	group           *echo.Group
+	timelineService timelineService.TimelineService
+}
+
+func NewTimelineControllerHandler(
+	group *echo.Group,
+	timelineService timelineService.TimelineService,
+) *timelineControllerHandler {
+	return &timelineControllerHandler{
+		group:           group,
+		timelineService: timelineService,
+	}
+}
+
+func (h *timelineControllerHandler) MapRoutes() {
+	h.group.GET("/employment/:profileCode/timeline", h.GetEmploymentTimeline())
+}
+
+func (h *timelineControllerHandler) GetEmploymentTimeline() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		ctx, span := apiTracer.Start(c.Request().Context(), "GetEmploymentTimeline", trace.WithTimestamp(time.Now()), trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End(trace.WithStackTrace(true))
+
+		var request request.GetProfileRequest
+		if err := c.Bind(&request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to bind")
+		}
+
+		if err := c.Validate(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to validate")
+		}
+
+		res, err := h.timelineService.GetTimeline(ctx, request.ProfileCode)
+		if err != nil {
+			return serviceError(err)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/response"
+	timelineService "test-bpjs/v2/service/timeline"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var timeline = timelineService.NewTimelineService(employmentRepository, authorizer, 0)
+
+func TestGetEmploymentTimelineController(t *testing.T) {
+	serve := func(code string) (*httptest.ResponseRecorder, error) {
+		e, c, rec := newTestContext(ownerCtx, http.MethodGet, "/api/employment/"+code+"/timeline", nil)
+		c.SetParamNames("profileCode")
+		c.SetParamValues(code)
+
+		timelineHandler := NewTimelineControllerHandler(e.Group("api"), timeline)
+		return rec, timelineHandler.GetEmploymentTimeline()(c)
+	}
+
+	t.Run("SuccessGetEmploymentTimelineController", func(t *testing.T) {
+		employmentRepository.Mock.On("GetEmploymentByProfileCode", mock.Anything, 120).Return([]*models.EmploymentDTO{
+			{Id: 1, StartDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), EmploymentType: models.EmploymentTypeFullTime},
+		}, nil).Once()
+
+		rec, err := serve("120")
+		if assert.NoError(t, err) {
+			var response response.EmploymentTimelineResponse
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, 366, response.TotalExperience.Days)
+			assert.Equal(t, models.EmploymentTypeFullTime, response.ByType[0].EmploymentType)
+		}
+	})
+
+	t.Run("FailedGetEmploymentTimelineController_Err400", func(t *testing.T) {
+		_, err := serve("abc")
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+		}
+	})
+
+	t.Run("FailedGetEmploymentTimelineController_Err500", func(t *testing.T) {
+		employmentRepository.Mock.On("GetEmploymentByProfileCode", mock.Anything, 121).Return(nil, errors.New("connection refused")).Once()
+
+		_, err := serve("121")
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusInternalServerError, err.(*echo.HTTPError).Code)
+		}
+	})
+}
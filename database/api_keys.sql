@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS api_keys(
+id SERIAL PRIMARY KEY NOT NULL,
+user_id int NOT NULL,
+name varchar(255) NOT NULL,
+prefix varchar(16) NOT NULL,
+key_hash varchar(64) NOT NULL,
+scopes varchar[] NOT NULL DEFAULT '{}',
+created_at timestamptz NULL DEFAULT CURRENT_TIMESTAMP,
+last_used_at timestamptz NULL,
+revoked_at timestamptz NULL,
+CONSTRAINT api_keys_key_hash_un UNIQUE (key_hash),
+CONSTRAINT api_keys_user_fk FOREIGN KEY (user_id) REFERENCES users(id));
+CREATE INDEX IF NOT EXISTS api_keys_user_id_idx ON api_keys(user_id);
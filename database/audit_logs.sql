@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS audit_logs(
+id SERIAL PRIMARY KEY NOT NULL,
+profile_code int NOT NULL,
+actor_id int NULL,
+api_key_id int NULL,
+action varchar(16) NOT NULL,
+entity varchar(32) NOT NULL,
+entity_id int NOT NULL,
+changes text NULL,
+request_id varchar(64) NULL,
+created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP);
+-- no foreign key to profile: entries outlive the rows they describe
+CREATE INDEX IF NOT EXISTS audit_logs_profile_code_idx ON audit_logs(profile_code, id DESC);
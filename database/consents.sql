@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS consents(
+id SERIAL PRIMARY KEY NOT NULL,
+profile_code int NOT NULL,
+purpose varchar(64) NOT NULL,
+terms_version varchar(32) NOT NULL,
+granted_by int NOT NULL,
+granted_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+revoked_at timestamptz NULL,
+CONSTRAINT consents_profile_fk FOREIGN KEY (profile_code) REFERENCES profile(profile_code));
+-- at most one active consent per profile and purpose
+CREATE UNIQUE INDEX IF NOT EXISTS consents_active_un ON consents(profile_code, purpose) WHERE revoked_at IS NULL;
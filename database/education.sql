@@ -8,4 +8,13 @@ end_date DATE,
 city varchar,
 description varchar,
 created_at timestamptz NULL DEFAULT CURRENT_TIMESTAMP,
-CONSTRAINT education_fk FOREIGN KEY (profile_code) REFERENCES profile(profile_code));
\ No newline at end of file
+version int NOT NULL DEFAULT 1,
+deleted_at timestamptz NULL,
+CONSTRAINT education_fk FOREIGN KEY (profile_code) REFERENCES profile(profile_code));
+-- bring tables created before these columns up to date; on a new table they
+-- change nothing
+ALTER TABLE education ADD COLUMN IF NOT EXISTS version int NOT NULL DEFAULT 1;
+ALTER TABLE education ADD COLUMN IF NOT EXISTS deleted_at timestamptz NULL;
+-- most queries only look at rows that are not in the trash
+CREATE INDEX IF NOT EXISTS education_profile_code_idx ON education(profile_code) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS education_deleted_at_idx ON education(deleted_at) WHERE deleted_at IS NOT NULL;
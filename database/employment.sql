@@ -7,5 +7,17 @@ start_date DATE,
 end_date DATE,
 city varchar,
 description varchar,
+-- full_time, part_time, contract, internship or freelance
+employment_type varchar(32),
 created_at timestamptz NULL DEFAULT CURRENT_TIMESTAMP,
-CONSTRAINT employment_fk FOREIGN KEY (profile_code) REFERENCES profile(profile_code));
\ No newline at end of file
+version int NOT NULL DEFAULT 1,
+deleted_at timestamptz NULL,
+CONSTRAINT employment_fk FOREIGN KEY (profile_code) REFERENCES profile(profile_code));
+-- bring tables created before these columns up to date; on a new table they
+-- change nothing
+ALTER TABLE employment ADD COLUMN IF NOT EXISTS version int NOT NULL DEFAULT 1;
+ALTER TABLE employment ADD COLUMN IF NOT EXISTS deleted_at timestamptz NULL;
+ALTER TABLE employment ADD COLUMN IF NOT EXISTS employment_type varchar(32);
+-- most queries only look at rows that are not in the trash
+CREATE INDEX IF NOT EXISTS employment_profile_code_idx ON employment(profile_code) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS employment_deleted_at_idx ON employment(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS erasure_receipts(
+id SERIAL PRIMARY KEY NOT NULL,
+receipt_id varchar(26) NOT NULL,
+profile_public_id varchar(26) NOT NULL,
+mode varchar(16) NOT NULL,
+reason varchar,
+requested_by int NOT NULL,
+profile_rows int NOT NULL DEFAULT 0,
+education_rows int NOT NULL DEFAULT 0,
+employment_rows int NOT NULL DEFAULT 0,
+skill_rows int NOT NULL DEFAULT 0,
+consent_rows int NOT NULL DEFAULT 0,
+audit_rows int NOT NULL DEFAULT 0,
+resume_version_rows int NOT NULL DEFAULT 0,
+photo_removed boolean NOT NULL DEFAULT false,
+erased_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+CONSTRAINT erasure_receipts_receipt_id_un UNIQUE (receipt_id));
+CREATE INDEX IF NOT EXISTS erasure_receipts_profile_public_id_idx ON erasure_receipts(profile_public_id);
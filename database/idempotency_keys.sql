@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys(
+scope varchar(64) NOT NULL,
+idempotency_key varchar(255) NOT NULL,
+request_hash char(64) NOT NULL,
+status_code int NULL,
+content_type varchar(255) NULL,
+body text NULL,
+created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+expires_at timestamptz NOT NULL,
+CONSTRAINT idempotency_keys_pk PRIMARY KEY (scope, idempotency_key));
+CREATE INDEX IF NOT EXISTS idempotency_keys_expires_at_idx ON idempotency_keys(expires_at);
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS headers jsonb NULL;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS job_postings(
+id SERIAL PRIMARY KEY NOT NULL,
+owner_id int NOT NULL,
+title varchar(255) NOT NULL,
+-- [{"skill": "Golang", "level": "Advanced", "required": true}, ...]
+skills jsonb NOT NULL DEFAULT '[]',
+min_years_experience int NOT NULL DEFAULT 0,
+city varchar,
+country varchar,
+created_at timestamptz NULL DEFAULT CURRENT_TIMESTAMP,
+updated_at timestamptz NULL,
+CONSTRAINT job_postings_owner_fk FOREIGN KEY (owner_id) REFERENCES users(id));
+CREATE INDEX IF NOT EXISTS job_postings_owner_id_idx ON job_postings(owner_id);
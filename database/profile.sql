@@ -1,19 +1,66 @@
 CREATE TABLE IF NOT EXISTS profile(
 profile_code SERIAL PRIMARY KEY NOT NULL,
+public_id varchar(26) NOT NULL,
 wanted_job_title varchar(255) NOT NULL,
 first_name varchar(255) NOT NULL,
 last_name varchar(255),
-email varchar(255) NOT NULL,
-phone varchar(15) NOT NULL,
+email text NOT NULL,
+email_bidx varchar(64),
+phone text NOT NULL,
 country varchar(255) NOT NULL,
 city varchar(255) NOT NULL,
-address varchar(255) NOT NULL,
+address text NOT NULL,
 postal_code int4 NOT NULL,
 driving_license varchar(255),
 nationality varchar,
 place_of_birth varchar NOT NULL,
-date_of_birth DATE NOT NULL,
+date_of_birth DATE,
+date_of_birth_enc text,
 photo_url varchar,
+photo_hash varchar(64),
 working_experiences varchar,
 created_at timestamptz NULL DEFAULT CURRENT_TIMESTAMP,
-updated_at timestamp NULL DEFAULT CURRENT_TIMESTAMP);
\ No newline at end of file
+updated_at timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+owner_id int,
+version int NOT NULL DEFAULT 1,
+deleted_at timestamptz NULL,
+CONSTRAINT profile_public_id_un UNIQUE (public_id),
+CONSTRAINT profile_owner_fk FOREIGN KEY (owner_id) REFERENCES users(id));
+-- bring tables created before these columns up to date; on a new table they
+-- change nothing
+ALTER TABLE profile ADD COLUMN IF NOT EXISTS owner_id int CONSTRAINT profile_owner_fk REFERENCES users(id);
+ALTER TABLE profile ADD COLUMN IF NOT EXISTS version int NOT NULL DEFAULT 1;
+ALTER TABLE profile ADD COLUMN IF NOT EXISTS deleted_at timestamptz NULL;
+ALTER TABLE profile ADD COLUMN IF NOT EXISTS photo_hash varchar(64);
+-- encrypted values do not fit the old column sizes; cmd/rotatekeys encrypts
+-- the existing rows and fills email_bidx and date_of_birth_enc
+ALTER TABLE profile ALTER COLUMN email TYPE text, ALTER COLUMN phone TYPE text, ALTER COLUMN address TYPE text;
+ALTER TABLE profile ALTER COLUMN date_of_birth DROP NOT NULL;
+ALTER TABLE profile ADD COLUMN IF NOT EXISTS email_bidx varchar(64);
+ALTER TABLE profile ADD COLUMN IF NOT EXISTS date_of_birth_enc text;
+-- existing profiles get a random id in the ULID alphabet before public_id
+-- becomes required; the subquery refers to the row so it runs once per row
+ALTER TABLE profile ADD COLUMN IF NOT EXISTS public_id varchar(26);
+UPDATE profile SET public_id = (
+  SELECT substr('01234567', 1 + floor(random() * 8)::int, 1) ||
+    string_agg(substr('0123456789ABCDEFGHJKMNPQRSTVWXYZ', 1 + floor(random() * 32)::int, 1), '')
+  FROM generate_series(1, 25)
+  WHERE profile.profile_code IS NOT NULL)
+WHERE public_id IS NULL;
+ALTER TABLE profile ALTER COLUMN public_id SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS profile_public_id_un ON profile(public_id);
+CREATE INDEX IF NOT EXISTS profile_email_bidx_idx ON profile(email_bidx);
+CREATE INDEX IF NOT EXISTS profile_photo_hash_idx ON profile(photo_hash);
+CREATE INDEX IF NOT EXISTS profile_owner_id_idx ON profile(owner_id);
+CREATE INDEX IF NOT EXISTS profile_deleted_at_idx ON profile(deleted_at) WHERE deleted_at IS NOT NULL;
+-- GET /api/profiles pages through live profiles by (sort key, public_id);
+-- the expressions must match the ones in ListProfiles to be used
+DROP INDEX IF EXISTS profile_list_created_at_idx;
+DROP INDEX IF EXISTS profile_list_updated_at_idx;
+CREATE INDEX IF NOT EXISTS profile_list_created_at_public_id_idx ON profile((COALESCE(created_at, '0001-01-01 00:00:00+00')), public_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS profile_list_updated_at_public_id_idx ON profile((COALESCE(updated_at, '0001-01-01 00:00:00+00')), public_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS profile_country_city_idx ON profile(lower(country), lower(city)) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS profile_nationality_idx ON profile(lower(nationality)) WHERE deleted_at IS NULL;
+-- wanted_job_title is matched anywhere in the title, which needs trigrams
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX IF NOT EXISTS profile_wanted_job_title_trgm_idx ON profile USING gin (wanted_job_title gin_trgm_ops) WHERE deleted_at IS NULL;
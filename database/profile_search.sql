@@ -0,0 +1,82 @@
+-- full text search over a profile and its live education, employment and
+-- skill rows, kept up to date by the triggers below. Run after the files of
+-- those tables.
+CREATE TABLE IF NOT EXISTS profile_search(
+profile_code int PRIMARY KEY NOT NULL,
+document text NOT NULL,
+search_en tsvector NOT NULL,
+search_id tsvector NOT NULL,
+updated_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+CONSTRAINT profile_search_profile_fk FOREIGN KEY (profile_code) REFERENCES profile(profile_code) ON DELETE CASCADE);
+CREATE INDEX IF NOT EXISTS profile_search_en_idx ON profile_search USING gin (search_en);
+CREATE INDEX IF NOT EXISTS profile_search_id_idx ON profile_search USING gin (search_id);
+
+-- titles and skills weigh most, then employers and schools, then places,
+-- then descriptions. Profiles in the trash are left out.
+CREATE OR REPLACE FUNCTION refresh_profile_search(code int) RETURNS void AS $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM profile WHERE profile_code = code AND deleted_at IS NULL) THEN
+    DELETE FROM profile_search WHERE profile_code = code;
+    RETURN;
+  END IF;
+
+  WITH parts AS (
+    SELECT
+      concat_ws(' ', p.wanted_job_title, p.first_name, p.last_name,
+        (SELECT string_agg(job_title, ' ') FROM employment WHERE profile_code = code AND deleted_at IS NULL),
+        (SELECT string_agg(skill, ' ') FROM skill WHERE profile_code = code AND deleted_at IS NULL)) AS a,
+      concat_ws(' ',
+        (SELECT string_agg(employer, ' ') FROM employment WHERE profile_code = code AND deleted_at IS NULL),
+        (SELECT string_agg(concat_ws(' ', school, degree), ' ') FROM education WHERE profile_code = code AND deleted_at IS NULL)) AS b,
+      concat_ws(' ', p.city, p.country,
+        (SELECT string_agg(city, ' ') FROM employment WHERE profile_code = code AND deleted_at IS NULL),
+        (SELECT string_agg(city, ' ') FROM education WHERE profile_code = code AND deleted_at IS NULL)) AS c,
+      concat_ws(' ',
+        (SELECT string_agg(description, ' ') FROM employment WHERE profile_code = code AND deleted_at IS NULL),
+        (SELECT string_agg(description, ' ') FROM education WHERE profile_code = code AND deleted_at IS NULL)) AS d
+    FROM profile p
+    WHERE p.profile_code = code
+  )
+  INSERT INTO profile_search(profile_code, document, search_en, search_id, updated_at)
+  SELECT code,
+    concat_ws(E'\n', a, b, c, d),
+    setweight(to_tsvector('english', a), 'A') || setweight(to_tsvector('english', b), 'B') ||
+      setweight(to_tsvector('english', c), 'C') || setweight(to_tsvector('english', d), 'D'),
+    setweight(to_tsvector('indonesian', a), 'A') || setweight(to_tsvector('indonesian', b), 'B') ||
+      setweight(to_tsvector('indonesian', c), 'C') || setweight(to_tsvector('indonesian', d), 'D'),
+    CURRENT_TIMESTAMP
+  FROM parts
+  ON CONFLICT (profile_code) DO UPDATE SET
+    document = EXCLUDED.document,
+    search_en = EXCLUDED.search_en,
+    search_id = EXCLUDED.search_id,
+    updated_at = EXCLUDED.updated_at;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION profile_search_trigger() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    PERFORM refresh_profile_search(OLD.profile_code);
+  ELSE
+    PERFORM refresh_profile_search(NEW.profile_code);
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS profile_search_refresh ON profile;
+CREATE TRIGGER profile_search_refresh AFTER INSERT OR DELETE OR UPDATE OF wanted_job_title, first_name, last_name, city, country, deleted_at ON profile
+FOR EACH ROW EXECUTE FUNCTION profile_search_trigger();
+DROP TRIGGER IF EXISTS profile_search_refresh ON employment;
+CREATE TRIGGER profile_search_refresh AFTER INSERT OR DELETE OR UPDATE OF job_title, employer, city, description, deleted_at ON employment
+FOR EACH ROW EXECUTE FUNCTION profile_search_trigger();
+DROP TRIGGER IF EXISTS profile_search_refresh ON education;
+CREATE TRIGGER profile_search_refresh AFTER INSERT OR DELETE OR UPDATE OF school, degree, city, description, deleted_at ON education
+FOR EACH ROW EXECUTE FUNCTION profile_search_trigger();
+DROP TRIGGER IF EXISTS profile_search_refresh ON skill;
+CREATE TRIGGER profile_search_refresh AFTER INSERT OR DELETE OR UPDATE OF skill, deleted_at ON skill
+FOR EACH ROW EXECUTE FUNCTION profile_search_trigger();
+
+-- index the profiles that existed before the triggers
+SELECT refresh_profile_search(profile_code) FROM profile WHERE deleted_at IS NULL;
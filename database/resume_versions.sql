@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS resume_versions(
+id SERIAL PRIMARY KEY NOT NULL,
+profile_code int NOT NULL,
+version int NOT NULL,
+label varchar(255),
+automatic boolean NOT NULL DEFAULT false,
+checksum varchar(64) NOT NULL,
+snapshot text NOT NULL,
+created_by int NULL,
+created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+CONSTRAINT resume_versions_profile_fk FOREIGN KEY (profile_code) REFERENCES profile(profile_code),
+CONSTRAINT resume_versions_version_un UNIQUE (profile_code, version));
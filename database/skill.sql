@@ -4,4 +4,15 @@ id SERIAL PRIMARY KEY NOT NULL,
 skill varchar,
 level varchar,
 created_at timestamptz NULL DEFAULT CURRENT_TIMESTAMP,
-CONSTRAINT education_fk FOREIGN KEY (profile_code) REFERENCES profile(profile_code));
\ No newline at end of file
+version int NOT NULL DEFAULT 1,
+deleted_at timestamptz NULL,
+CONSTRAINT education_fk FOREIGN KEY (profile_code) REFERENCES profile(profile_code));
+-- bring tables created before these columns up to date; on a new table they
+-- change nothing
+ALTER TABLE skill ADD COLUMN IF NOT EXISTS version int NOT NULL DEFAULT 1;
+ALTER TABLE skill ADD COLUMN IF NOT EXISTS deleted_at timestamptz NULL;
+-- most queries only look at rows that are not in the trash
+CREATE INDEX IF NOT EXISTS skill_profile_code_idx ON skill(profile_code) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS skill_deleted_at_idx ON skill(deleted_at) WHERE deleted_at IS NOT NULL;
+-- the skill and level filters of GET /api/profiles
+CREATE INDEX IF NOT EXISTS skill_name_level_idx ON skill(lower(skill), lower(level), profile_code) WHERE deleted_at IS NULL;
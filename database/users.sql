@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS users(
+id SERIAL PRIMARY KEY NOT NULL,
+username varchar(255) NOT NULL,
+password_hash varchar(255) NOT NULL,
+role varchar(32) NOT NULL DEFAULT 'user',
+created_at timestamptz NULL DEFAULT CURRENT_TIMESTAMP,
+CONSTRAINT users_username_un UNIQUE (username));
@@ -0,0 +1,27 @@
+package auth
+
+const (
+	ScopeProfileRead  = "profile:read"
+	ScopeProfileWrite = "profile:write"
+	ScopeResumeExport = "resume:export"
+	ScopeJobRead      = "job:read"
+	ScopeJobWrite     = "job:write"
+)
+
+// Scopes lists every scope an API key can be granted.
+var Scopes = []string{ScopeProfileRead, ScopeProfileWrite, ScopeResumeExport, ScopeJobRead, ScopeJobWrite}
+
+// HasScope reports whether the caller may use the given scope. Callers that
+// signed in with a password get every scope their role allows; only API keys
+// are restricted to the scopes they were created with.
+func (c *Claims) HasScope(scope string) bool {
+	if c.ApiKeyId == 0 {
+		return true
+	}
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"test-bpjs/v2/config"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const defaultTokenExpiry = time.Hour
+
+var ErrInvalidToken = errors.New("invalid token")
+
+const (
+	RoleUser      = "user"
+	RoleAdmin     = "admin"
+	RoleRecruiter = "recruiter"
+)
+
+type Claims struct {
+	UserId   int    `json:"uid"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+	// ApiKeyId and Scopes are only set when the caller used an API key.
+	ApiKeyId int      `json:"-"`
+	Scopes   []string `json:"-"`
+	jwt.StandardClaims
+}
+
+// TokenManager issues and verifies the access tokens used on the /api routes.
+type TokenManager struct {
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+	issuer    string
+	expiry    time.Duration
+}
+
+func NewTokenManager(cfg config.Config) (*TokenManager, error) {
+	tokens := &TokenManager{
+		issuer: cfg.JWTIssuer,
+		expiry: cfg.JWTExpiry,
+	}
+	if tokens.expiry <= 0 {
+		tokens.expiry = defaultTokenExpiry
+	}
+
+	switch cfg.JWTSigningMethod {
+	case "", "HS256":
+		if cfg.JWTSecret == "" {
+			return nil, errors.New("jwt secret is required for HS256")
+		}
+		tokens.method = jwt.SigningMethodHS256
+		tokens.signKey = []byte(cfg.JWTSecret)
+		tokens.verifyKey = []byte(cfg.JWTSecret)
+	case "RS256":
+		privateKey, publicKey, err := loadRSAKeys(cfg.JWTPrivateKeyPath, cfg.JWTPublicKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		tokens.method = jwt.SigningMethodRS256
+		tokens.signKey = privateKey
+		tokens.verifyKey = publicKey
+	default:
+		return nil, fmt.Errorf("unsupported jwt signing method: %s", cfg.JWTSigningMethod)
+	}
+
+	return tokens, nil
+}
+
+func (t *TokenManager) Issue(userId int, username, role string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(t.expiry)
+	claims := &Claims{
+		UserId:   userId,
+		Username: username,
+		Role:     role,
+		StandardClaims: jwt.StandardClaims{
+			Subject:   strconv.Itoa(userId),
+			Issuer:    t.issuer,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: expiresAt.Unix(),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(t.method, claims).SignedString(t.signKey)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign token: %v", err)
+	}
+	return token, expiresAt, nil
+}
+
+func (t *TokenManager) Parse(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		// never let the token pick its own algorithm
+		if token.Method.Alg() != t.method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method: %s", token.Method.Alg())
+		}
+		return t.verifyKey, nil
+	})
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+	if t.issuer != "" && !claims.VerifyIssuer(t.issuer, true) {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func loadRSAKeys(privateKeyPath, publicKeyPath string) (*rsa.PrivateKey, *rsa.PublicKey, error) {
+	privatePEM, err := os.ReadFile(privateKeyPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read jwt private key: %v", err)
+	}
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse jwt private key: %v", err)
+	}
+
+	// the public key can be derived from the private one when it isn't given
+	if publicKeyPath == "" {
+		return privateKey, &privateKey.PublicKey, nil
+	}
+	publicPEM, err := os.ReadFile(publicKeyPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read jwt public key: %v", err)
+	}
+	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(publicPEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse jwt public key: %v", err)
+	}
+	return privateKey, publicKey, nil
+}
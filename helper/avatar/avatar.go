@@ -0,0 +1,125 @@
+package avatar
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+	"unicode"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const DefaultSize = 256
+
+// palette holds the background colours an avatar can get. White initials are
+// readable on every one of them.
+var palette = []color.RGBA{
+	{R: 0xE5, G: 0x39, B: 0x35, A: 0xFF},
+	{R: 0xD8, G: 0x1B, B: 0x60, A: 0xFF},
+	{R: 0x8E, G: 0x24, B: 0xAA, A: 0xFF},
+	{R: 0x5E, G: 0x35, B: 0xB1, A: 0xFF},
+	{R: 0x39, G: 0x49, B: 0xAB, A: 0xFF},
+	{R: 0x1E, G: 0x88, B: 0xE5, A: 0xFF},
+	{R: 0x00, G: 0x89, B: 0x7B, A: 0xFF},
+	{R: 0x43, G: 0xA0, B: 0x47, A: 0xFF},
+	{R: 0xF4, G: 0x51, B: 0x1E, A: 0xFF},
+	{R: 0x6D, G: 0x4C, B: 0x41, A: 0xFF},
+	{R: 0x54, G: 0x6E, B: 0x7A, A: 0xFF},
+}
+
+// Initials returns the upper-cased first letter of the first and last name.
+// "?" is returned when neither name has a letter to show.
+func Initials(firstName, lastName string) string {
+	var initials []rune
+	for _, name := range []string{firstName, lastName} {
+		for _, r := range strings.TrimSpace(name) {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				initials = append(initials, unicode.ToUpper(r))
+				break
+			}
+		}
+	}
+	if len(initials) == 0 {
+		return "?"
+	}
+	return string(initials)
+}
+
+// Color picks the background colour for a profile. The same profile code
+// always gets the same colour.
+func Color(code int) color.RGBA {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%d", code)
+	return palette[h.Sum32()%uint32(len(palette))]
+}
+
+// RenderPNG draws the initials in white on a square of the given colour.
+func RenderPNG(initials string, background color.RGBA, size int) ([]byte, error) {
+	face := basicfont.Face7x13
+	text := asciiInitials(initials)
+
+	// draw the text at the font's native size, then scale it up so the
+	// initials fill roughly half of the avatar height
+	d := &font.Drawer{Face: face}
+	textWidth := d.MeasureString(text).Ceil()
+	textHeight := face.Height
+	mask := image.NewAlpha(image.Rect(0, 0, textWidth, textHeight))
+	d.Dst = mask
+	d.Src = image.Opaque
+	d.Dot = fixed.P(0, face.Ascent)
+	d.DrawString(text)
+
+	scale := size / 2 / textHeight
+	if scale < 1 {
+		scale = 1
+	}
+	offsetX := (size - textWidth*scale) / 2
+	offsetY := (size - textHeight*scale) / 2
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: background}, image.Point{}, draw.Src)
+	for y := 0; y < textHeight*scale; y++ {
+		for x := 0; x < textWidth*scale; x++ {
+			if mask.AlphaAt(x/scale, y/scale).A == 0 {
+				continue
+			}
+			img.Set(offsetX+x, offsetY+y, color.White)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderSVG returns the same avatar as RenderPNG as a scalable SVG document.
+func RenderSVG(initials string, background color.RGBA, size int) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, size, size, size, size)
+	fmt.Fprintf(&buf, `<rect width="100%%" height="100%%" fill="#%02X%02X%02X"/>`, background.R, background.G, background.B)
+	fmt.Fprintf(&buf, `<text x="50%%" y="50%%" dy=".35em" text-anchor="middle" fill="#FFFFFF" font-family="Helvetica, Arial, sans-serif" font-size="%d">`, size*2/5)
+	xml.EscapeText(&buf, []byte(initials))
+	buf.WriteString(`</text></svg>`)
+	return buf.Bytes()
+}
+
+// asciiInitials replaces runes the bitmap font cannot draw so the PNG never
+// shows empty boxes.
+func asciiInitials(initials string) string {
+	return strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII {
+			return '?'
+		}
+		return r
+	}, initials)
+}
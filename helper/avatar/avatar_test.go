@@ -0,0 +1,81 @@
+package avatar
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInitials(t *testing.T) {
+	assert.Equal(t, "BS", Initials("budi", "santoso"))
+	assert.Equal(t, "B", Initials("  budi ", ""))
+	assert.Equal(t, "S", Initials("", "santoso"))
+	// leading punctuation is skipped, digits count as initials
+	assert.Equal(t, "J2", Initials("'jo", "-2nd"))
+	// letters outside ASCII are kept and upper-cased
+	assert.Equal(t, "ÉÇ", Initials("élodie", "çelik"))
+	assert.Equal(t, "王", Initials("王", ""))
+
+	for _, names := range [][2]string{{"", ""}, {"   ", "\t"}, {"-", "."}} {
+		assert.Equal(t, "?", Initials(names[0], names[1]), names)
+	}
+}
+
+func TestColor(t *testing.T) {
+	// the same code always gets the same colour
+	assert.Equal(t, Color(7), Color(7))
+	assert.Equal(t, Color(123456), Color(123456))
+
+	for code := 0; code < 50; code++ {
+		assert.Contains(t, palette, Color(code))
+	}
+}
+
+func TestRenderPNG(t *testing.T) {
+	background := Color(7)
+	data, err := RenderPNG("BS", background, DefaultSize)
+	assert.Nil(t, err)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	assert.Nil(t, err)
+	assert.Equal(t, image.Rect(0, 0, DefaultSize, DefaultSize), img.Bounds())
+	// the corner is background, the centre holds white initials
+	assert.Equal(t, background, color.RGBAModel.Convert(img.At(0, 0)))
+	assert.True(t, hasWhite(img))
+
+	// initials the bitmap font cannot draw still render
+	data, err = RenderPNG("王", background, 16)
+	assert.Nil(t, err)
+	img, err = png.Decode(bytes.NewReader(data))
+	assert.Nil(t, err)
+	assert.Equal(t, image.Rect(0, 0, 16, 16), img.Bounds())
+}
+
+func TestRenderSVG(t *testing.T) {
+	svg := string(RenderSVG("BS", color.RGBA{R: 0x1E, G: 0x88, B: 0xE5, A: 0xFF}, 128))
+	assert.Contains(t, svg, `width="128" height="128"`)
+	assert.Contains(t, svg, `fill="#1E88E5"`)
+	assert.Contains(t, svg, `>BS</text>`)
+
+	// initials are escaped and non-ASCII ones are kept as they are
+	svg = string(RenderSVG("<&", palette[0], DefaultSize))
+	assert.Contains(t, svg, `>&lt;&amp;</text>`)
+	svg = string(RenderSVG("ÉÇ", palette[0], DefaultSize))
+	assert.Contains(t, svg, `>ÉÇ</text>`)
+}
+
+func hasWhite(img image.Image) bool {
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if color.RGBAModel.Convert(img.At(x, y)) == (color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}) {
+				return true
+			}
+		}
+	}
+	return false
+}
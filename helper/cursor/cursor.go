@@ -0,0 +1,31 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var ErrMalformed = errors.New("malformed cursor")
+
+// Encode turns the position of the last row of a page into an opaque token
+// that is safe to put in a query string.
+func Encode(position interface{}) (string, error) {
+	encoded, err := json.Marshal(position)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(encoded), nil
+}
+
+// Decode reads a position back from a token made by Encode.
+func Decode(token string, position interface{}) error {
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return ErrMalformed
+	}
+	if err := json.Unmarshal(decoded, position); err != nil {
+		return ErrMalformed
+	}
+	return nil
+}
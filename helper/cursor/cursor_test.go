@@ -0,0 +1,26 @@
+package cursor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeAndDecode(t *testing.T) {
+	type position struct {
+		At   string `json:"t"`
+		Code int    `json:"c"`
+	}
+
+	token, err := Encode(position{At: "2024-11-01T10:00:00Z", Code: 7})
+	assert.Nil(t, err)
+	assert.NotContains(t, token, "=")
+
+	var decoded position
+	assert.Nil(t, Decode(token, &decoded))
+	assert.Equal(t, position{At: "2024-11-01T10:00:00Z", Code: 7}, decoded)
+
+	for _, token := range []string{"not base64!", "bm90IGpzb24"} {
+		assert.ErrorIs(t, Decode(token, &decoded), ErrMalformed, token)
+	}
+}
@@ -0,0 +1,40 @@
+package etag
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	HeaderETag    = "ETag"
+	HeaderIfMatch = "If-Match"
+)
+
+var (
+	ErrMissing   = errors.New("missing If-Match header")
+	ErrMalformed = errors.New("malformed If-Match header")
+)
+
+// Format renders a row version as a strong entity tag, e.g. "3".
+func Format(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// Parse reads the row version from an If-Match header. Only a single strong
+// tag is accepted: weak tags, lists and "*" cannot pin a write to the
+// version it was based on.
+func Parse(header string) (int, error) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0, ErrMissing
+	}
+	if len(header) < 2 || header[0] != '"' || header[len(header)-1] != '"' {
+		return 0, ErrMalformed
+	}
+	version, err := strconv.Atoi(header[1 : len(header)-1])
+	if err != nil || version < 1 {
+		return 0, ErrMalformed
+	}
+	return version, nil
+}
@@ -0,0 +1,25 @@
+package etag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatAndParse(t *testing.T) {
+	version, err := Parse(Format(3))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, version)
+
+	version, err = Parse(` "12" `)
+	assert.Nil(t, err)
+	assert.Equal(t, 12, version)
+
+	_, err = Parse("")
+	assert.ErrorIs(t, err, ErrMissing)
+
+	for _, header := range []string{`*`, `W/"3"`, `"3", "4"`, `3`, `"abc"`, `"0"`, `"`} {
+		_, err = Parse(header)
+		assert.ErrorIs(t, err, ErrMalformed, header)
+	}
+}
@@ -0,0 +1,247 @@
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"test-bpjs/v2/config"
+)
+
+const (
+	// sealed values look like enc:v1:<key id>:<wrapped data key>:<ciphertext>
+	prefix  = "enc:v1:"
+	keySize = 32
+)
+
+var (
+	ErrUnknownKey      = errors.New("unknown encryption key")
+	ErrMalformedSealed = errors.New("malformed encrypted value")
+)
+
+// Cipher seals column values with envelope encryption: every value gets its
+// own random data key, which is itself encrypted with the current master key.
+// Rotating the master key only means re-wrapping the small data keys.
+//
+// A zero Cipher (no keys configured) stores values as they are.
+type Cipher struct {
+	currentKeyId  string
+	masterKeys    map[string]cipher.AEAD
+	blindIndexKey []byte
+	columns       map[string]bool
+}
+
+// NewCipher reads the master keys (base64 encoded, 32 bytes each) and the
+// columns to encrypt from the config. Encryption is off when no key is set.
+func NewCipher(cfg config.Config) (*Cipher, error) {
+	c := &Cipher{
+		masterKeys: map[string]cipher.AEAD{},
+		columns:    map[string]bool{},
+	}
+	if len(cfg.EncryptionKeys) == 0 {
+		return c, nil
+	}
+
+	for keyId, encoded := range cfg.EncryptionKeys {
+		if keyId == "" || strings.Contains(keyId, ":") {
+			return nil, fmt.Errorf("invalid encryption key id: %q", keyId)
+		}
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encryption key %s: %v", keyId, err)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		c.masterKeys[keyId] = aead
+	}
+	if _, ok := c.masterKeys[cfg.EncryptionKeyId]; !ok {
+		return nil, fmt.Errorf("current encryption key %q is not configured", cfg.EncryptionKeyId)
+	}
+	c.currentKeyId = cfg.EncryptionKeyId
+
+	blindIndexKey, err := decodeKey(cfg.BlindIndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid blind index key: %v", err)
+	}
+	c.blindIndexKey = blindIndexKey
+
+	for _, column := range cfg.EncryptedColumns {
+		c.columns[column] = true
+	}
+	return c, nil
+}
+
+// Encrypts reports whether values of the column are stored encrypted.
+func (c *Cipher) Encrypts(column string) bool {
+	return c.currentKeyId != "" && c.columns[column]
+}
+
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	if c.currentKeyId == "" {
+		return plaintext, nil
+	}
+
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("failed to generate data key: %v", err)
+	}
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := seal(dataAEAD, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	wrappedKey, err := seal(c.masterKeys[c.currentKeyId], dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	return prefix + c.currentKeyId + ":" +
+		base64.RawStdEncoding.EncodeToString(wrappedKey) + ":" +
+		base64.RawStdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt opens a sealed value with whichever master key sealed it. Values
+// written before encryption was turned on are returned unchanged.
+func (c *Cipher) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	keyId, wrappedKey, ciphertext, err := split(value)
+	if err != nil {
+		return "", err
+	}
+	dataKey, err := c.unwrap(keyId, wrappedKey)
+	if err != nil {
+		return "", err
+	}
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dataAEAD, ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %v", err)
+	}
+	return string(plaintext), nil
+}
+
+// Rotate re-wraps the data key of a value sealed with an older master key.
+// The value itself is not re-encrypted. It reports whether anything changed.
+func (c *Cipher) Rotate(value string) (string, bool, error) {
+	if !IsEncrypted(value) || c.currentKeyId == "" {
+		return value, false, nil
+	}
+
+	keyId, wrappedKey, ciphertext, err := split(value)
+	if err != nil {
+		return "", false, err
+	}
+	if keyId == c.currentKeyId {
+		return value, false, nil
+	}
+	dataKey, err := c.unwrap(keyId, wrappedKey)
+	if err != nil {
+		return "", false, err
+	}
+	rewrapped, err := seal(c.masterKeys[c.currentKeyId], dataKey)
+	if err != nil {
+		return "", false, err
+	}
+
+	return prefix + c.currentKeyId + ":" +
+		base64.RawStdEncoding.EncodeToString(rewrapped) + ":" +
+		base64.RawStdEncoding.EncodeToString(ciphertext), true, nil
+}
+
+// BlindIndex returns a keyed hash of the normalised value, so equality
+// lookups work without storing or comparing the plain value. It is empty
+// when encryption is off.
+func (c *Cipher) BlindIndex(value string) string {
+	if len(c.blindIndexKey) == 0 {
+		return ""
+	}
+	mac := hmac.New(sha256.New, c.blindIndexKey)
+	mac.Write([]byte(strings.ToLower(strings.TrimSpace(value))))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsEncrypted reports whether the value was sealed by a Cipher.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+func (c *Cipher) unwrap(keyId string, wrappedKey []byte) ([]byte, error) {
+	masterKey, ok := c.masterKeys[keyId]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyId)
+	}
+	dataKey, err := open(masterKey, wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %v", err)
+	}
+	return dataKey, nil
+}
+
+func split(value string) (string, []byte, []byte, error) {
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if len(parts) != 3 {
+		return "", nil, nil, ErrMalformedSealed
+	}
+	wrappedKey, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", nil, nil, ErrMalformedSealed
+	}
+	ciphertext, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", nil, nil, ErrMalformedSealed
+	}
+	return parts[0], wrappedKey, ciphertext, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %v", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal prepends the random nonce to the ciphertext.
+func seal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %v", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(aead cipher.AEAD, sealed []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformedSealed
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != keySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", keySize, len(key))
+	}
+	return key, nil
+}
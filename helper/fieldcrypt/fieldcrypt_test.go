@@ -0,0 +1,144 @@
+package fieldcrypt
+
+import (
+	"encoding/base64"
+	"strings"
+	"test-bpjs/v2/config"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), keySize)))
+}
+
+func testConfig(currentKeyId string, keyIds ...string) config.Config {
+	keys := map[string]string{}
+	for _, keyId := range keyIds {
+		keys[keyId] = testKey(keyId[len(keyId)-1])
+	}
+	return config.Config{
+		EncryptionKeys:   keys,
+		EncryptionKeyId:  currentKeyId,
+		BlindIndexKey:    testKey('z'),
+		EncryptedColumns: []string{"email", "phone"},
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	c, err := NewCipher(testConfig("k1", "k1"))
+	assert.Nil(t, err)
+
+	t.Run("SuccessRoundTrip", func(t *testing.T) {
+		sealed, err := c.Encrypt("john@example.com")
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(sealed, "enc:v1:k1:"))
+		assert.NotContains(t, sealed, "john")
+
+		plain, err := c.Decrypt(sealed)
+		assert.Nil(t, err)
+		assert.Equal(t, "john@example.com", plain)
+	})
+	t.Run("SuccessFreshDataKeyPerValue", func(t *testing.T) {
+		first, _ := c.Encrypt("john@example.com")
+		second, _ := c.Encrypt("john@example.com")
+		assert.NotEqual(t, first, second)
+	})
+	t.Run("SuccessPlainValuePassesThrough", func(t *testing.T) {
+		plain, err := c.Decrypt("john@example.com")
+		assert.Nil(t, err)
+		assert.Equal(t, "john@example.com", plain)
+	})
+	t.Run("FailedTamperedValue", func(t *testing.T) {
+		sealed, _ := c.Encrypt("john@example.com")
+		_, err := c.Decrypt(sealed[:len(sealed)-4] + "AAAA")
+		assert.NotNil(t, err)
+	})
+	t.Run("FailedMalformedValue", func(t *testing.T) {
+		_, err := c.Decrypt("enc:v1:k1:nope")
+		assert.ErrorIs(t, err, ErrMalformedSealed)
+	})
+	t.Run("SuccessConfiguredColumns", func(t *testing.T) {
+		assert.True(t, c.Encrypts("email"))
+		assert.False(t, c.Encrypts("address"))
+	})
+}
+
+func TestRotate(t *testing.T) {
+	oldCipher, err := NewCipher(testConfig("k1", "k1"))
+	assert.Nil(t, err)
+	newCipher, err := NewCipher(testConfig("k2", "k1", "k2"))
+	assert.Nil(t, err)
+	retiredCipher, err := NewCipher(testConfig("k2", "k2"))
+	assert.Nil(t, err)
+
+	sealed, err := oldCipher.Encrypt("0812345678")
+	assert.Nil(t, err)
+
+	t.Run("SuccessDecryptWithOlderKey", func(t *testing.T) {
+		plain, err := newCipher.Decrypt(sealed)
+		assert.Nil(t, err)
+		assert.Equal(t, "0812345678", plain)
+	})
+	t.Run("SuccessRotate", func(t *testing.T) {
+		rotated, changed, err := newCipher.Rotate(sealed)
+		assert.Nil(t, err)
+		assert.True(t, changed)
+		assert.True(t, strings.HasPrefix(rotated, "enc:v1:k2:"))
+
+		plain, err := retiredCipher.Decrypt(rotated)
+		assert.Nil(t, err)
+		assert.Equal(t, "0812345678", plain)
+
+		_, changed, err = newCipher.Rotate(rotated)
+		assert.Nil(t, err)
+		assert.False(t, changed)
+	})
+	t.Run("FailedUnknownKey", func(t *testing.T) {
+		_, err := retiredCipher.Decrypt(sealed)
+		assert.ErrorIs(t, err, ErrUnknownKey)
+	})
+}
+
+func TestBlindIndex(t *testing.T) {
+	c, _ := NewCipher(testConfig("k1", "k1"))
+
+	assert.Len(t, c.BlindIndex("john@example.com"), 64)
+	assert.Equal(t, c.BlindIndex("john@example.com"), c.BlindIndex(" John@Example.com "))
+	assert.NotEqual(t, c.BlindIndex("john@example.com"), c.BlindIndex("jane@example.com"))
+
+	other, _ := NewCipher(config.Config{
+		EncryptionKeys:  map[string]string{"k1": testKey('a')},
+		EncryptionKeyId: "k1",
+		BlindIndexKey:   testKey('y'),
+	})
+	assert.NotEqual(t, c.BlindIndex("john@example.com"), other.BlindIndex("john@example.com"))
+}
+
+func TestNewCipher(t *testing.T) {
+	t.Run("SuccessDisabled", func(t *testing.T) {
+		c, err := NewCipher(config.Config{EncryptedColumns: []string{"email"}})
+		assert.Nil(t, err)
+		assert.False(t, c.Encrypts("email"))
+		assert.Empty(t, c.BlindIndex("john@example.com"))
+
+		value, err := c.Encrypt("john@example.com")
+		assert.Nil(t, err)
+		assert.Equal(t, "john@example.com", value)
+	})
+	t.Run("FailedMissingCurrentKey", func(t *testing.T) {
+		_, err := NewCipher(testConfig("k9", "k1"))
+		assert.NotNil(t, err)
+	})
+	t.Run("FailedShortKey", func(t *testing.T) {
+		cfg := testConfig("k1", "k1")
+		cfg.EncryptionKeys["k1"] = base64.StdEncoding.EncodeToString([]byte("short"))
+		_, err := NewCipher(cfg)
+		assert.NotNil(t, err)
+	})
+	t.Run("FailedKeyIdWithColon", func(t *testing.T) {
+		_, err := NewCipher(testConfig("k:1", "k:1"))
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,60 @@
+package jsondiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"test-bpjs/v2/models"
+)
+
+// Diff compares the JSON fields of two values and returns the ones that
+// differ. Either side may be nil. A field missing on one side counts as
+// changed unless the other side holds a zero value, so empty columns stay out
+// of diffs against nothing.
+func Diff(before, after interface{}) (map[string]models.FieldChange, error) {
+	oldFields, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]models.FieldChange{}
+	for name, value := range newFields {
+		previous, ok := oldFields[name]
+		if ok && bytes.Equal(previous, value) || !ok && isZero(value) {
+			continue
+		}
+		changes[name] = models.FieldChange{Before: previous, After: value}
+	}
+	for name, previous := range oldFields {
+		if _, ok := newFields[name]; !ok && !isZero(previous) {
+			changes[name] = models.FieldChange{Before: previous}
+		}
+	}
+	return changes, nil
+}
+
+func fields(v interface{}) (map[string]json.RawMessage, error) {
+	fields := map[string]json.RawMessage{}
+	if v == nil {
+		return fields, nil
+	}
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func isZero(value json.RawMessage) bool {
+	switch string(value) {
+	case `null`, `""`, `0`, `false`, `"0001-01-01T00:00:00Z"`:
+		return true
+	}
+	return false
+}
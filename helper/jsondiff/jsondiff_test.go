@@ -0,0 +1,42 @@
+package jsondiff
+
+import (
+	"test-bpjs/v2/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	t.Run("Update", func(t *testing.T) {
+		changes, err := Diff(
+			&models.ProfileDTO{FirstName: "John", LastName: "Doe", PostalCode: 12345},
+			&models.ProfileDTO{FirstName: "Jane", LastName: "", PostalCode: 12345},
+		)
+		assert.Nil(t, err)
+		assert.Len(t, changes, 2)
+		assert.JSONEq(t, `"John"`, string(changes["firstName"].Before))
+		assert.JSONEq(t, `"Jane"`, string(changes["firstName"].After))
+		assert.JSONEq(t, `"Doe"`, string(changes["lastName"].Before))
+		assert.JSONEq(t, `""`, string(changes["lastName"].After))
+	})
+	t.Run("Create", func(t *testing.T) {
+		changes, err := Diff(nil, &models.SkillDTO{Id: 1, Skill: "Golang"})
+		assert.Nil(t, err)
+		assert.Len(t, changes, 2)
+		assert.Nil(t, changes["skill"].Before)
+		assert.JSONEq(t, `"Golang"`, string(changes["skill"].After))
+	})
+	t.Run("Delete", func(t *testing.T) {
+		changes, err := Diff(&models.SkillDTO{Id: 1, Level: "Expert"}, (*models.SkillDTO)(nil))
+		assert.Nil(t, err)
+		assert.Len(t, changes, 2)
+		assert.JSONEq(t, `"Expert"`, string(changes["level"].Before))
+		assert.Nil(t, changes["level"].After)
+	})
+	t.Run("Unchanged", func(t *testing.T) {
+		changes, err := Diff(&models.SkillDTO{Id: 1}, &models.SkillDTO{Id: 1})
+		assert.Nil(t, err)
+		assert.Empty(t, changes)
+	})
+}
@@ -0,0 +1,233 @@
+// Package keywords pulls the keywords out of a job description and looks
+// them up in other text, without calling any outside service.
+package keywords
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// maxWords is the length of the longest keyword.
+const maxWords = 3
+
+type Keyword struct {
+	// Term is the keyword in lower case, its words separated by a space.
+	Term  string
+	Count int
+}
+
+type token struct {
+	text  string
+	lower string
+}
+
+type candidate struct {
+	Keyword
+	words    int
+	first    int
+	distinct bool
+}
+
+// Extract returns at most limit keywords of the text, the most frequent and
+// longest first. Every word but a stop word is a keyword. Phrases of up to
+// three words are keywords when they occur more than once, or when each of
+// their words stands out, like the words of "Google Cloud" or "CI/CD".
+// Single words only seen as part of such a phrase are left out.
+func Extract(text string, limit int) []Keyword {
+	candidates := map[string]*candidate{}
+	position := 0
+	for _, run := range runs(text) {
+		for start := range run {
+			for words := 1; words <= maxWords && start+words <= len(run); words++ {
+				window := run[start : start+words]
+				term, distinct := join(window), words == 1
+				if !distinct {
+					distinct = true
+					for _, t := range window {
+						distinct = distinct && standsOut(t.text)
+					}
+				}
+
+				c, ok := candidates[term]
+				if !ok {
+					c = &candidate{Keyword: Keyword{Term: term}, words: words, first: position}
+					candidates[term] = c
+				}
+				c.Count++
+				c.distinct = c.distinct || distinct
+			}
+			position++
+		}
+	}
+
+	var kept []*candidate
+	for _, c := range candidates {
+		if c.words == 1 || c.Count > 1 || c.distinct {
+			kept = append(kept, c)
+		}
+	}
+	// a keyword only seen inside a longer one is left out
+	var result []*candidate
+	for _, c := range kept {
+		covered := false
+		for _, longer := range kept {
+			if longer.words > c.words && longer.Count >= c.Count && strings.Contains(" "+longer.Term+" ", " "+c.Term+" ") {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			result = append(result, c)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if wi, wj := result[i].Count*result[i].words, result[j].Count*result[j].words; wi != wj {
+			return wi > wj
+		}
+		return result[i].first < result[j].first
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+
+	keywords := make([]Keyword, 0, len(result))
+	for _, c := range result {
+		keywords = append(keywords, c.Keyword)
+	}
+	return keywords
+}
+
+// Text is text prepared for looking up keywords.
+type Text struct {
+	words string
+}
+
+// NewText joins the parts into one text. Keywords are never found across
+// two parts.
+func NewText(parts ...string) Text {
+	var words strings.Builder
+	words.WriteString("|")
+	for _, part := range parts {
+		for _, phrase := range phrases(part) {
+			words.WriteString(" " + join(phrase) + " |")
+		}
+	}
+	return Text{words: words.String()}
+}
+
+// Contains reports whether the text holds the words of the keyword next to
+// each other, ignoring case and punctuation.
+func (t Text) Contains(term string) bool {
+	return term != "" && strings.Contains(t.words, " "+term+" ")
+}
+
+// ContainsAny reports whether the text holds any of the spellings, each read
+// the way the text is.
+func (t Text) ContainsAny(spellings ...string) bool {
+	for _, spelling := range spellings {
+		for _, phrase := range phrases(spelling) {
+			if t.Contains(join(phrase)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// runs splits the text into runs of words that may form a keyword together:
+// phrases are cut at punctuation and stop words, and words too short or
+// made of digits only are dropped.
+func runs(text string) [][]token {
+	var runs [][]token
+	for _, phrase := range phrases(text) {
+		var run []token
+		for _, t := range phrase {
+			if stopWords[t.lower] || !meaningful(t.lower) {
+				if len(run) > 0 {
+					runs = append(runs, run)
+				}
+				run = nil
+				continue
+			}
+			run = append(run, t)
+		}
+		if len(run) > 0 {
+			runs = append(runs, run)
+		}
+	}
+	return runs
+}
+
+// phrases tokenises the text. Words may hold the symbols of names like C++,
+// C#, Node.js, CI/CD or full-stack. A phrase ends at a line break, at the
+// end of a sentence and at any other punctuation.
+func phrases(text string) [][]token {
+	var phrases [][]token
+	var phrase []token
+	var word strings.Builder
+	endWord := func() {
+		raw := word.String()
+		word.Reset()
+		trimmed := strings.TrimLeft(strings.TrimRight(raw, ".-/"), "-/")
+		if trimmed != "" {
+			phrase = append(phrase, token{text: trimmed, lower: strings.ToLower(trimmed)})
+		}
+		if strings.HasSuffix(raw, ".") {
+			phrases, phrase = appendPhrase(phrases, phrase), nil
+		}
+	}
+
+	for _, r := range text {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("+#.-/", r):
+			word.WriteRune(r)
+		case r == ' ' || r == '\t':
+			endWord()
+		default:
+			endWord()
+			phrases, phrase = appendPhrase(phrases, phrase), nil
+		}
+	}
+	endWord()
+	return appendPhrase(phrases, phrase)
+}
+
+func appendPhrase(phrases [][]token, phrase []token) [][]token {
+	if len(phrase) == 0 {
+		return phrases
+	}
+	return append(phrases, phrase)
+}
+
+func meaningful(word string) bool {
+	if len([]rune(word)) < 2 {
+		return false
+	}
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// standsOut tells words written differently from ordinary prose: capitalised
+// names, acronyms and words with digits or symbols.
+func standsOut(word string) bool {
+	for _, r := range word {
+		if unicode.IsUpper(r) || unicode.IsDigit(r) || strings.ContainsRune("+#./", r) {
+			return true
+		}
+	}
+	return false
+}
+
+func join(tokens []token) string {
+	words := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		words = append(words, t.lower)
+	}
+	return strings.Join(words, " ")
+}
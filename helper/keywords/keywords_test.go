@@ -0,0 +1,87 @@
+package keywords
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func terms(keywords []Keyword) []string {
+	result := make([]string, 0, len(keywords))
+	for _, keyword := range keywords {
+		result = append(result, keyword.Term)
+	}
+	return result
+}
+
+func TestExtract(t *testing.T) {
+	t.Run("SuccessExtractEnglish", func(t *testing.T) {
+		keywords := Extract(`We are looking for a Backend Engineer with 3+ years of experience.
+Requirements:
+- Golang and PostgreSQL
+- Experience with Google Cloud and CI/CD pipelines
+- Familiar with C++ or C#, Node.js is a plus
+- Backend Engineer mindset: testing, testing, testing.`, 0)
+
+		all := terms(keywords)
+		// repeated phrases and phrases of names are kept whole
+		assert.Contains(t, all, "backend engineer")
+		assert.Contains(t, all, "google cloud")
+		assert.NotContains(t, all, "google")
+		assert.NotContains(t, all, "backend")
+		// symbols belong to the word
+		assert.Contains(t, all, "c++")
+		assert.Contains(t, all, "c#")
+		assert.Contains(t, all, "node.js")
+		assert.Contains(t, all, "ci/cd")
+		// stop words, filler and numbers are no keywords
+		assert.NotContains(t, all, "with")
+		assert.NotContains(t, all, "experience")
+		assert.NotContains(t, all, "familiar")
+		assert.NotContains(t, all, "3+")
+		// phrases of ordinary words seen once are split
+		assert.Contains(t, all, "pipelines")
+		assert.NotContains(t, all, "ci/cd pipelines")
+		// lines are phrases of their own
+		assert.NotContains(t, all, "postgresql experience")
+
+		assert.Equal(t, "backend engineer", keywords[0].Term)
+		assert.Equal(t, 2, keywords[0].Count)
+		assert.Equal(t, "testing", keywords[1].Term)
+		assert.Equal(t, 3, keywords[1].Count)
+	})
+
+	t.Run("SuccessExtractIndonesian", func(t *testing.T) {
+		all := terms(Extract("Kami mencari pengembang yang memiliki pengalaman minimal 2 tahun dengan Laravel dan MySQL, serta mampu bekerja dalam tim.", 0))
+		assert.ElementsMatch(t, []string{"pengembang", "laravel", "mysql", "tim"}, all)
+	})
+
+	t.Run("SuccessExtractLimit", func(t *testing.T) {
+		keywords := Extract("docker docker kubernetes terraform", 2)
+		assert.Equal(t, []string{"docker", "kubernetes"}, terms(keywords))
+	})
+}
+
+func TestTextContains(t *testing.T) {
+	text := NewText("Senior Golang Engineer", "Built CI/CD pipelines on Google Cloud.", "C++")
+
+	assert.True(t, text.Contains("golang"))
+	assert.True(t, text.Contains("google cloud"))
+	assert.True(t, text.Contains("ci/cd"))
+	assert.True(t, text.Contains("c++"))
+	// whole words only
+	assert.False(t, text.Contains("go"))
+	assert.False(t, text.Contains("c"))
+	// never across two parts
+	assert.False(t, text.Contains("engineer built"))
+	assert.False(t, text.Contains(""))
+}
+
+func TestTextContainsAny(t *testing.T) {
+	text := NewText("Go", "Built services in Node.js")
+
+	assert.True(t, text.ContainsAny("golang", "go"))
+	assert.True(t, text.ContainsAny("nodejs", "Node.js"))
+	assert.False(t, text.ContainsAny("golang", "go lang"))
+	assert.False(t, text.ContainsAny())
+}
@@ -0,0 +1,41 @@
+package keywords
+
+// stopWords are English and Indonesian function words, together with the
+// words found in nearly every job description, which say nothing about the
+// job itself.
+var stopWords = toSet(
+	// English
+	"a", "about", "above", "after", "again", "against", "all", "also", "am", "an", "and", "any", "are", "as", "at",
+	"be", "because", "been", "before", "being", "below", "between", "both", "but", "by",
+	"can", "could", "did", "do", "does", "doing", "down", "during", "each", "either", "etc",
+	"few", "for", "from", "further", "had", "has", "have", "having", "he", "her", "here", "hers", "him", "his", "how",
+	"i", "if", "in", "into", "is", "it", "its", "itself", "just", "least", "less", "like",
+	"may", "me", "might", "more", "most", "must", "my", "no", "nor", "not", "of", "off", "on", "once", "one", "only", "or",
+	"other", "our", "ours", "out", "over", "own", "per", "same", "shall", "she", "should", "so", "some", "such",
+	"than", "that", "the", "their", "theirs", "them", "then", "there", "these", "they", "this", "those", "through", "to", "too",
+	"under", "until", "up", "upon", "us", "very", "via", "was", "we", "were", "what", "when", "where", "which", "while",
+	"who", "whom", "why", "will", "with", "within", "without", "would", "you", "your", "yours",
+	// job description filler
+	"ability", "able", "apply", "candidate", "candidates", "excellent", "experience", "experienced", "familiar",
+	"familiarity", "good", "great", "ideal", "including", "join", "knowledge", "looking", "minimum", "need", "needed",
+	"new", "plus", "preferred", "proficiency", "proficient", "proven", "required", "requirements", "responsibilities",
+	"role", "seeking", "skills", "strong", "understanding", "using", "well", "work", "working", "year", "years",
+	// Indonesian
+	"ada", "adalah", "agar", "akan", "anda", "antara", "apa", "atau", "bagi", "bahwa", "baik", "banyak", "beberapa",
+	"belum", "berbagai", "bersama", "bisa", "dalam", "dan", "dapat", "dari", "dengan", "di", "dia", "hal", "hanya",
+	"harus", "hingga", "ia", "ini", "itu", "jika", "juga", "kami", "karena", "ke", "kita", "lain", "lebih", "maupun",
+	"mereka", "namun", "oleh", "pada", "para", "per", "saat", "sangat", "saya", "sebagai", "secara", "sedang", "seluruh",
+	"semua", "sendiri", "seperti", "serta", "setiap", "sudah", "supaya", "tanpa", "telah", "tersebut", "tetapi",
+	"tidak", "untuk", "yaitu", "yang",
+	// Indonesian job description filler
+	"bekerja", "berpengalaman", "bidang", "diutamakan", "kandidat", "kemampuan", "kriteria", "kualifikasi", "maksimal",
+	"mampu", "memiliki", "mencari", "minimal", "pengalaman", "persyaratan", "tahun", "tanggung", "jawab", "wajib",
+)
+
+func toSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}
@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the memory store forgets buckets that have
+// refilled completely, so idle callers don't pile up.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	policy Policy
+}
+
+type memoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewMemoryStore keeps the buckets in the process. Limits are per instance,
+// so use the Redis store when several instances run behind a load balancer.
+func NewMemoryStore() *memoryStore {
+	return &memoryStore{buckets: map[string]*bucket{}}
+}
+
+func (m *memoryStore) Take(ctx context.Context, key string, policy Policy, now time.Time) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if now.Sub(m.lastSweep) >= sweepInterval {
+		m.sweep(now)
+	}
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(policy.Burst), last: now}
+		m.buckets[key] = b
+	}
+	b.tokens = refill(b.tokens, now.Sub(b.last), policy)
+	b.last = now
+	b.policy = policy
+
+	if b.tokens < 1 {
+		return Result{RetryAfter: wait(b.tokens, policy)}, nil
+	}
+	b.tokens--
+	return Result{Allowed: true, Remaining: int(b.tokens)}, nil
+}
+
+func (m *memoryStore) sweep(now time.Time) {
+	for key, b := range m.buckets {
+		if refill(b.tokens, now.Sub(b.last), b.policy) >= float64(b.policy.Burst) {
+			delete(m.buckets, key)
+		}
+	}
+	m.lastSweep = now
+}
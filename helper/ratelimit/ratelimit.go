@@ -0,0 +1,108 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"strings"
+	"test-bpjs/v2/config"
+	"time"
+)
+
+// Policy is a token bucket: it holds at most Burst tokens and refills Rate
+// tokens per second. Every request takes one token.
+type Policy struct {
+	Name  string
+	Rate  float64
+	Burst int
+}
+
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// Store keeps the buckets. Take must be atomic per key, since several
+// requests for the same caller can arrive at once.
+type Store interface {
+	Take(ctx context.Context, key string, policy Policy, now time.Time) (Result, error)
+}
+
+// Limiter picks the policy that applies to a route and takes a token for it.
+type Limiter struct {
+	store    Store
+	policies map[string]Policy
+	now      func() time.Time
+}
+
+// NewLimiter builds one policy per configured route. A policy with the path
+// "*" applies to every route without its own policy, and an empty method
+// matches every method.
+func NewLimiter(store Store, routes []config.RateLimitPolicy) *Limiter {
+	limiter := &Limiter{
+		store:    store,
+		policies: make(map[string]Policy, len(routes)),
+		now:      time.Now,
+	}
+	for _, route := range routes {
+		if route.Requests <= 0 || route.Per <= 0 {
+			continue
+		}
+		burst := route.Burst
+		if burst <= 0 {
+			burst = route.Requests
+		}
+		method := strings.ToUpper(route.Method)
+		limiter.policies[routeKey(method, route.Path)] = Policy{
+			Name:  strings.TrimSpace(method + " " + route.Path),
+			Rate:  float64(route.Requests) / route.Per.Seconds(),
+			Burst: burst,
+		}
+	}
+	return limiter
+}
+
+// Policy returns the most specific policy for the route, if any.
+func (l *Limiter) Policy(method, path string) (Policy, bool) {
+	for _, key := range []string{routeKey(method, path), routeKey("", path), routeKey(method, "*"), routeKey("", "*")} {
+		if policy, ok := l.policies[key]; ok {
+			return policy, true
+		}
+	}
+	return Policy{}, false
+}
+
+// Take spends one token of the policy's bucket for the given caller.
+func (l *Limiter) Take(ctx context.Context, policy Policy, caller string) (Result, error) {
+	return l.store.Take(ctx, policy.Name+"|"+caller, policy, l.now())
+}
+
+// RetryAfterSeconds rounds a wait up to whole seconds, as the Retry-After
+// header wants them.
+func RetryAfterSeconds(wait time.Duration) int {
+	seconds := int(math.Ceil(wait.Seconds()))
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
+
+func routeKey(method, path string) string {
+	if method == "" {
+		method = "*"
+	}
+	return method + " " + path
+}
+
+// refill returns how many tokens a bucket holds after elapsed time.
+func refill(tokens float64, elapsed time.Duration, policy Policy) float64 {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return math.Min(float64(policy.Burst), tokens+elapsed.Seconds()*policy.Rate)
+}
+
+// wait returns how long an empty bucket takes to get its next token.
+func wait(tokens float64, policy Policy) time.Duration {
+	return time.Duration((1 - tokens) / policy.Rate * float64(time.Second))
+}
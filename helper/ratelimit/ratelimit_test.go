@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	"context"
+	"test-bpjs/v2/config"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStores(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	stores := map[string]Store{
+		"Memory": NewMemoryStore(),
+		"Redis":  NewRedisStore(client),
+	}
+
+	// two requests at once, then one every 10 seconds
+	policy := Policy{Name: "PUT /api/photo/:profileCode", Rate: 0.1, Burst: 2}
+	start := time.Unix(1700000000, 0)
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			first, err := store.Take(ctx, "user:1", policy, start)
+			assert.Nil(t, err)
+			assert.True(t, first.Allowed)
+			assert.Equal(t, 1, first.Remaining)
+
+			second, err := store.Take(ctx, "user:1", policy, start)
+			assert.Nil(t, err)
+			assert.True(t, second.Allowed)
+			assert.Equal(t, 0, second.Remaining)
+
+			third, err := store.Take(ctx, "user:1", policy, start.Add(4*time.Second))
+			assert.Nil(t, err)
+			assert.False(t, third.Allowed)
+			assert.Equal(t, 6*time.Second, third.RetryAfter.Round(time.Millisecond))
+
+			// other callers have their own bucket
+			other, err := store.Take(ctx, "user:2", policy, start)
+			assert.Nil(t, err)
+			assert.True(t, other.Allowed)
+
+			refilled, err := store.Take(ctx, "user:1", policy, start.Add(10*time.Second))
+			assert.Nil(t, err)
+			assert.True(t, refilled.Allowed)
+		})
+	}
+
+	t.Run("RedisUnavailable", func(t *testing.T) {
+		broken := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+		t.Cleanup(func() { broken.Close() })
+
+		_, err := NewRedisStore(broken).Take(context.Background(), "user:1", policy, start)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	store := NewMemoryStore()
+	policy := Policy{Name: "GET *", Rate: 1, Burst: 1}
+	start := time.Unix(1700000000, 0)
+
+	_, _ = store.Take(context.Background(), "ip:10.0.0.1", policy, start)
+	_, _ = store.Take(context.Background(), "ip:10.0.0.2", policy, start.Add(sweepInterval))
+	assert.Len(t, store.buckets, 1)
+}
+
+func TestLimiterPolicy(t *testing.T) {
+	limiter := NewLimiter(NewMemoryStore(), []config.RateLimitPolicy{
+		{Method: "put", Path: "/api/photo/:profileCode", Requests: 10, Per: time.Minute, Burst: 3},
+		{Method: "GET", Path: "*", Requests: 120, Per: time.Minute},
+		{Path: "*", Requests: 60, Per: time.Minute},
+		{Path: "/api/skill/:profileCode", Requests: 0, Per: time.Minute},
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		burst  int
+		found  bool
+	}{
+		{"RoutePolicy", "PUT", "/api/photo/:profileCode", 3, true},
+		{"MethodFallback", "GET", "/api/photo/:profileCode", 120, true},
+		{"GlobalFallback", "DELETE", "/api/photo/:profileCode", 60, true},
+		{"InvalidPolicyIgnored", "POST", "/api/skill/:profileCode", 60, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, found := limiter.Policy(tt.method, tt.path)
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.burst, policy.Burst)
+		})
+	}
+
+	t.Run("NoPolicy", func(t *testing.T) {
+		_, found := NewLimiter(NewMemoryStore(), nil).Policy("GET", "/api/profile/:profileCode")
+		assert.False(t, found)
+	})
+	t.Run("RetryAfterSeconds", func(t *testing.T) {
+		assert.Equal(t, 1, RetryAfterSeconds(0))
+		assert.Equal(t, 2, RetryAfterSeconds(1500*time.Millisecond))
+	})
+}
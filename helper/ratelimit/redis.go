@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript runs the token bucket inside Redis so concurrent requests on
+// several instances can't both spend the last token. The bucket expires once
+// it would have refilled completely.
+var takeScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "last")
+local tokens = tonumber(state[1]) or burst
+local last = tonumber(state[2]) or now
+if now > last then
+	tokens = math.min(burst, tokens + (now - last) / 1000 * rate)
+end
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate * 1000)
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "last", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil(burst / rate * 1000))
+return {allowed, math.floor(tokens), retry}
+`)
+
+type redisStore struct {
+	client redis.Scripter
+	prefix string
+}
+
+// NewRedisStore keeps the buckets in Redis, or anything speaking its
+// protocol, so every instance shares the same limits.
+func NewRedisStore(client redis.Scripter) *redisStore {
+	return &redisStore{client: client, prefix: "ratelimit:"}
+}
+
+func (r *redisStore) Take(ctx context.Context, key string, policy Policy, now time.Time) (Result, error) {
+	values, err := takeScript.Run(ctx, r.client, []string{r.prefix + key},
+		strconv.FormatFloat(policy.Rate, 'f', -1, 64),
+		policy.Burst,
+		now.UnixMilli(),
+	).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to take rate limit token: %v", err)
+	}
+	if len(values) != 3 {
+		return Result{}, fmt.Errorf("failed to take rate limit token: unexpected reply %v", values)
+	}
+
+	return Result{
+		Allowed:    values[0] == 1,
+		Remaining:  int(math.Max(0, float64(values[1]))),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,16 @@
+package requestid
+
+import "context"
+
+type requestIdKey struct{}
+
+// WithRequestId returns a copy of ctx carrying the id of the current request.
+func WithRequestId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIdKey{}, id)
+}
+
+// FromContext returns the request id stored by WithRequestId, or "".
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIdKey{}).(string)
+	return id
+}
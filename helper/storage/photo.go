@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// PhotoCounter tells how many profiles still reference a stored photo.
+type PhotoCounter interface {
+	CountProfilesByPhotoHash(ctx context.Context, hash string) (int, error)
+}
+
+// photoMu is held while a photo file is written and referenced, and while it
+// is counted and removed, so a photo is never removed between an upload
+// finding it on disk and the profile row pointing at it.
+var photoMu sync.Mutex
+
+// root is where ResolvePath looks for photos, when set.
+var root string
+
+// SetRoot makes ResolvePath resolve photo paths under dir. An empty dir goes
+// back to finding public/image relative to the working directory. Tests use it
+// to keep the files they write out of public/image.
+func SetRoot(dir string) {
+	root = dir
+}
+
+// StorePhoto writes the photo to imgPath unless a file is already stored
+// there, then calls update to make the profile reference it. When update
+// fails, a file written by this call is removed again, so rejected uploads
+// leave nothing behind.
+func StorePhoto(imgPath string, data []byte, update func() error) error {
+	photoMu.Lock()
+	defer photoMu.Unlock()
+
+	fullPath := ResolvePath(imgPath)
+	written := false
+	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			return fmt.Errorf("failed creating output file: %v", err)
+		}
+		if err := os.WriteFile(fullPath, data, 0644); err != nil {
+			return fmt.Errorf("failed creating output file: %v", err)
+		}
+		written = true
+	}
+
+	if err := update(); err != nil {
+		if written {
+			os.Remove(fullPath)
+		}
+		return err
+	}
+	return nil
+}
+
+// ReleasePhoto removes a stored photo once no profile references its hash
+// anymore. Photos uploaded before hashing was introduced have no hash and are
+// left alone. It reports whether the file was removed.
+func ReleasePhoto(ctx context.Context, counter PhotoCounter, hash, imgPath string) (bool, error) {
+	if hash == "" {
+		return false, nil
+	}
+
+	photoMu.Lock()
+	defer photoMu.Unlock()
+
+	refs, err := counter.CountProfilesByPhotoHash(ctx, hash)
+	if err != nil {
+		return false, fmt.Errorf("failed to count photo references: %v", err)
+	}
+	if refs > 0 {
+		return false, nil
+	}
+
+	if err := os.Remove(ResolvePath(imgPath)); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to remove photo file: %v", err)
+	}
+	return true, nil
+}
+
+// ResolvePath finds the public/image directory, which sits at a different
+// relative location depending on where the binary or the tests are run from.
+func ResolvePath(imgPath string) string {
+	if root != "" {
+		return filepath.Join(root, imgPath)
+	}
+	for _, prefix := range []string{"../../", "../", ""} {
+		fullPath := filepath.Join(prefix, imgPath)
+		if _, err := os.Stat(filepath.Dir(fullPath)); err == nil {
+			return fullPath
+		}
+	}
+	return imgPath
+}
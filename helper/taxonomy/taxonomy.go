@@ -0,0 +1,214 @@
+// Package taxonomy maps the many spellings of a skill to one canonical name,
+// using a versioned list of skills embedded in the binary.
+package taxonomy
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+//go:embed skills.json
+var dataset []byte
+
+type Skill struct {
+	Name     string   `json:"name"`
+	Aliases  []string `json:"aliases"`
+	Category string   `json:"category"`
+}
+
+// Suggestion is a skill matching a query. Alias is the alias the query
+// matched, empty when it matched the name.
+type Suggestion struct {
+	Skill
+	Alias string
+}
+
+type Taxonomy struct {
+	Version string  `json:"version"`
+	Skills  []Skill `json:"skills"`
+	// index maps the key of every name and alias to its skill
+	index map[string]int
+}
+
+var (
+	defaultOnce     sync.Once
+	defaultTaxonomy *Taxonomy
+)
+
+// Default returns the taxonomy embedded in the binary.
+func Default() *Taxonomy {
+	defaultOnce.Do(func() {
+		taxonomy, err := Parse(dataset)
+		if err != nil {
+			panic(fmt.Sprintf("embedded skill taxonomy: %v", err))
+		}
+		defaultTaxonomy = taxonomy
+	})
+	return defaultTaxonomy
+}
+
+// Parse reads a taxonomy. Two skills may not share a name or alias.
+func Parse(data []byte) (*Taxonomy, error) {
+	var taxonomy Taxonomy
+	if err := json.Unmarshal(data, &taxonomy); err != nil {
+		return nil, err
+	}
+	if taxonomy.Version == "" {
+		return nil, fmt.Errorf("taxonomy has no version")
+	}
+
+	taxonomy.index = map[string]int{}
+	for i, skill := range taxonomy.Skills {
+		if skill.Name == "" || skill.Category == "" {
+			return nil, fmt.Errorf("skill %d needs a name and a category", i)
+		}
+		for _, spelling := range append([]string{skill.Name}, skill.Aliases...) {
+			k := key(spelling)
+			if other, ok := taxonomy.index[k]; ok && other != i {
+				return nil, fmt.Errorf("%q of %s is already taken by %s", spelling, skill.Name, taxonomy.Skills[other].Name)
+			}
+			taxonomy.index[k] = i
+		}
+	}
+	return &taxonomy, nil
+}
+
+// Lookup finds the skill spelled name, ignoring case, spaces, dots, dashes
+// and underscores.
+func (t *Taxonomy) Lookup(name string) (Skill, bool) {
+	i, ok := t.index[key(name)]
+	if !ok {
+		return Skill{}, false
+	}
+	return t.Skills[i], true
+}
+
+// Canonical returns the name of the skill spelled name. Skills missing from
+// the taxonomy keep their spelling, without surrounding and repeated spaces.
+func (t *Taxonomy) Canonical(name string) string {
+	if skill, ok := t.Lookup(name); ok {
+		return skill.Name
+	}
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// Spellings returns the lower case name and aliases of the skill spelled
+// name, or just the name in lower case when it is not in the taxonomy.
+func (t *Taxonomy) Spellings(name string) []string {
+	skill, ok := t.Lookup(name)
+	if !ok {
+		return []string{strings.ToLower(t.Canonical(name))}
+	}
+	spellings := []string{strings.ToLower(skill.Name)}
+	for _, alias := range skill.Aliases {
+		spellings = append(spellings, strings.ToLower(alias))
+	}
+	return spellings
+}
+
+// The ranks of a suggestion, best first.
+const (
+	rankExact = iota
+	rankNamePrefix
+	rankAliasPrefix
+	rankWordPrefix
+	rankContains
+	unmatched
+)
+
+// Suggest returns at most limit skills matching the query: exact matches
+// first, then skills whose name or alias starts with it, then skills with a
+// word starting with it, then skills containing it anywhere.
+func (t *Taxonomy) Suggest(query string, limit int) []Suggestion {
+	q := key(query)
+	if q == "" {
+		return []Suggestion{}
+	}
+	words := strings.Fields(strings.ToLower(query))
+
+	type ranked struct {
+		Suggestion
+		rank int
+	}
+	var matches []ranked
+	for _, skill := range t.Skills {
+		best := ranked{Suggestion: Suggestion{Skill: skill}, rank: unmatched}
+		for i, spelling := range append([]string{skill.Name}, skill.Aliases...) {
+			rank := match(spelling, q, words, i == 0)
+			if rank < best.rank {
+				best.rank = rank
+				best.Alias = ""
+				if i > 0 {
+					best.Alias = spelling
+				}
+			}
+		}
+		if best.rank != unmatched {
+			matches = append(matches, best)
+		}
+	}
+	sort.SliceStable(matches, func(a, b int) bool {
+		if matches[a].rank != matches[b].rank {
+			return matches[a].rank < matches[b].rank
+		}
+		return strings.ToLower(matches[a].Name) < strings.ToLower(matches[b].Name)
+	})
+
+	suggestions := make([]Suggestion, 0, limit)
+	for _, m := range matches {
+		if len(suggestions) == limit {
+			break
+		}
+		suggestions = append(suggestions, m.Suggestion)
+	}
+	return suggestions
+}
+
+func match(spelling, q string, words []string, isName bool) int {
+	k := key(spelling)
+	switch {
+	case k == q:
+		return rankExact
+	case strings.HasPrefix(k, q) && isName:
+		return rankNamePrefix
+	case strings.HasPrefix(k, q):
+		return rankAliasPrefix
+	case startsWord(strings.ToLower(spelling), words):
+		return rankWordPrefix
+	case strings.Contains(k, q):
+		return rankContains
+	}
+	return unmatched
+}
+
+// startsWord reports whether a word of the spelling, after the first, starts
+// with the first word of the query.
+func startsWord(spelling string, words []string) bool {
+	if len(words) == 0 {
+		return false
+	}
+	fields := strings.Fields(spelling)
+	for _, field := range fields[1:] {
+		if strings.HasPrefix(field, words[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+// key folds a spelling so "GoLang", "golang" and "Go-Lang" look the same.
+func key(spelling string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(spelling) {
+		if unicode.IsSpace(r) || r == '.' || r == '-' || r == '_' {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,103 @@
+package taxonomy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func names(suggestions []Suggestion) []string {
+	result := make([]string, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		result = append(result, suggestion.Name)
+	}
+	return result
+}
+
+func TestDefault(t *testing.T) {
+	t.Run("SuccessLoadEmbeddedTaxonomy", func(t *testing.T) {
+		taxonomy := Default()
+		assert.NotEmpty(t, taxonomy.Version)
+		assert.NotEmpty(t, taxonomy.Skills)
+		assert.Same(t, taxonomy, Default())
+	})
+}
+
+func TestParse(t *testing.T) {
+	t.Run("FailedParseSharedAlias", func(t *testing.T) {
+		_, err := Parse([]byte(`{"version": "1", "skills": [
+			{"name": "Go", "aliases": ["golang"], "category": "programming_language"},
+			{"name": "GoLang", "category": "programming_language"}]}`))
+		assert.EqualError(t, err, `"GoLang" of GoLang is already taken by Go`)
+	})
+
+	t.Run("FailedParseWithoutVersion", func(t *testing.T) {
+		_, err := Parse([]byte(`{"skills": []}`))
+		assert.EqualError(t, err, "taxonomy has no version")
+	})
+
+	t.Run("FailedParseWithoutCategory", func(t *testing.T) {
+		_, err := Parse([]byte(`{"version": "1", "skills": [{"name": "Go"}]}`))
+		assert.EqualError(t, err, "skill 0 needs a name and a category")
+	})
+}
+
+func TestCanonical(t *testing.T) {
+	taxonomy := Default()
+
+	t.Run("SuccessCanonicalAliases", func(t *testing.T) {
+		for _, spelling := range []string{"golang", "Go", "GoLang", " go-lang ", "GO"} {
+			assert.Equal(t, "Go", taxonomy.Canonical(spelling), spelling)
+		}
+		assert.Equal(t, "Node.js", taxonomy.Canonical("nodejs"))
+		assert.Equal(t, "Kubernetes", taxonomy.Canonical("k8s"))
+		assert.Equal(t, "Teamwork", taxonomy.Canonical("Kerja Sama Tim"))
+		// symbols tell skills apart
+		assert.Equal(t, "C++", taxonomy.Canonical("c++"))
+		assert.Equal(t, "C#", taxonomy.Canonical("c#"))
+	})
+
+	t.Run("SuccessKeepUnknownSkill", func(t *testing.T) {
+		assert.Equal(t, "Underwater Basket Weaving", taxonomy.Canonical("  Underwater   Basket Weaving "))
+		_, ok := taxonomy.Lookup("Underwater Basket Weaving")
+		assert.False(t, ok)
+	})
+
+	t.Run("SuccessSpellings", func(t *testing.T) {
+		assert.Equal(t, []string{"postgresql", "postgres", "psql", "pgsql"}, taxonomy.Spellings("Postgres"))
+		assert.Equal(t, []string{"cobol"}, taxonomy.Spellings(" COBOL "))
+	})
+}
+
+func TestSuggest(t *testing.T) {
+	taxonomy := Default()
+
+	t.Run("SuccessSuggestExactFirst", func(t *testing.T) {
+		suggestions := taxonomy.Suggest("go", 3)
+		assert.Equal(t, "Go", suggestions[0].Name)
+		assert.Empty(t, suggestions[0].Alias)
+		assert.Len(t, suggestions, 3)
+	})
+
+	t.Run("SuccessSuggestByAlias", func(t *testing.T) {
+		suggestions := taxonomy.Suggest("postgr", 10)
+		assert.Equal(t, []string{"PostgreSQL"}, names(suggestions))
+
+		suggestions = taxonomy.Suggest("k8", 10)
+		assert.Equal(t, "Kubernetes", suggestions[0].Name)
+		assert.Equal(t, "k8s", suggestions[0].Alias)
+	})
+
+	t.Run("SuccessSuggestNamePrefixBeforeWordPrefix", func(t *testing.T) {
+		suggestions := taxonomy.Suggest("react", 10)
+		assert.Equal(t, []string{"React", "React Native"}, names(suggestions))
+
+		suggestions = taxonomy.Suggest("cloud", 10)
+		assert.Equal(t, []string{"Google Cloud Platform"}, names(suggestions))
+	})
+
+	t.Run("SuccessSuggestNothing", func(t *testing.T) {
+		assert.Empty(t, taxonomy.Suggest("  ", 10))
+		assert.Empty(t, taxonomy.Suggest("zzz", 10))
+	})
+}
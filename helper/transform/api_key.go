@@ -0,0 +1,26 @@
+package transform
+
+import (
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/response"
+	"time"
+)
+
+func TransformApiKey(apiKey *models.ApiKeyDTO) *response.ApiKeyResponse {
+	return &response.ApiKeyResponse{
+		Id:         apiKey.Id,
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Scopes:     apiKey.Scopes,
+		CreatedAt:  apiKey.CreatedAt,
+		LastUsedAt: optionalTime(apiKey.LastUsedAt),
+		RevokedAt:  optionalTime(apiKey.RevokedAt),
+	}
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,38 @@
+package transform
+
+import (
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/response"
+)
+
+// TransformAuditLog names profiles by their public id, as the serial profile
+// code never leaves the API.
+func TransformAuditLog(auditLog *models.AuditLogDTO) *response.AuditLogResponse {
+	auditLogResponse := &response.AuditLogResponse{
+		Id:        auditLog.Id,
+		ActorId:   auditLog.ActorId,
+		ApiKeyId:  auditLog.ApiKeyId,
+		Action:    auditLog.Action,
+		Entity:    auditLog.Entity,
+		EntityId:  auditLog.EntityId,
+		Changes:   TransformFieldChanges(auditLog.Changes),
+		RequestId: auditLog.RequestId,
+		CreatedAt: auditLog.CreatedAt,
+	}
+	if auditLog.Entity == models.AuditEntityProfile {
+		auditLogResponse.EntityId = 0
+		auditLogResponse.EntityPublicId = auditLog.ProfilePublicId
+	}
+	return auditLogResponse
+}
+
+func TransformFieldChanges(changes map[string]models.FieldChange) map[string]response.FieldChangeResponse {
+	if changes == nil {
+		return nil
+	}
+	result := make(map[string]response.FieldChangeResponse, len(changes))
+	for field, change := range changes {
+		result[field] = response.FieldChangeResponse{Before: change.Before, After: change.After}
+	}
+	return result
+}
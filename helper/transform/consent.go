@@ -0,0 +1,17 @@
+package transform
+
+import (
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/response"
+)
+
+func TransformConsent(consent *models.ConsentDTO) *response.ConsentResponse {
+	return &response.ConsentResponse{
+		Id:           consent.Id,
+		Purpose:      consent.Purpose,
+		TermsVersion: consent.TermsVersion,
+		GrantedAt:    consent.GrantedAt,
+		RevokedAt:    optionalTime(consent.RevokedAt),
+		Active:       consent.RevokedAt.IsZero(),
+	}
+}
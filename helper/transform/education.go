@@ -14,5 +14,7 @@ func TransformEducation(education *models.EducationDTO) *response.EducationRespo
 		EndDate:     education.EndDate,
 		City:        education.City,
 		Description: education.Description,
+		Version:     education.Version,
+		DeletedAt:   education.DeletedAt,
 	}
 }
@@ -7,12 +7,15 @@ import (
 
 func TransformEmployment(employment *models.EmploymentDTO) *response.EmploymentResponse {
 	return &response.EmploymentResponse{
-		Id:          employment.Id,
-		JobTitle:    employment.JobTitle,
-		Employer:    employment.Employer,
-		StartDate:   employment.StartDate,
-		EndDate:     employment.EndDate,
-		City:        employment.City,
-		Description: employment.Description,
+		Id:             employment.Id,
+		JobTitle:       employment.JobTitle,
+		Employer:       employment.Employer,
+		StartDate:      employment.StartDate,
+		EndDate:        employment.EndDate,
+		City:           employment.City,
+		Description:    employment.Description,
+		EmploymentType: employment.EmploymentType,
+		Version:        employment.Version,
+		DeletedAt:      employment.DeletedAt,
 	}
 }
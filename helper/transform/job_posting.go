@@ -0,0 +1,28 @@
+package transform
+
+import (
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/response"
+)
+
+func TransformJobPosting(jobPosting *models.JobPostingDTO) *response.JobPostingResponse {
+	skills := make([]*response.JobPostingSkillResponse, 0, len(jobPosting.Skills))
+	for _, skill := range jobPosting.Skills {
+		skills = append(skills, &response.JobPostingSkillResponse{
+			Skill:    skill.Skill,
+			Level:    skill.Level,
+			Required: skill.Required,
+		})
+	}
+	return &response.JobPostingResponse{
+		Id:                 jobPosting.Id,
+		OwnerId:            jobPosting.OwnerId,
+		Title:              jobPosting.Title,
+		Skills:             skills,
+		MinYearsExperience: jobPosting.MinYearsExperience,
+		City:               jobPosting.City,
+		Country:            jobPosting.Country,
+		CreatedAt:          jobPosting.CreatedAt,
+		UpdatedAt:          optionalTime(jobPosting.UpdatedAt),
+	}
+}
@@ -0,0 +1,32 @@
+package transform
+
+import (
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/response"
+)
+
+func TransformErasureReceipt(receipt *models.ErasureReceipt) *response.ErasureReceiptResponse {
+	return &response.ErasureReceiptResponse{
+		ReceiptId:         receipt.ReceiptId,
+		ProfilePublicId:   receipt.ProfilePublicId,
+		Mode:              receipt.Mode,
+		Reason:            receipt.Reason,
+		RequestedBy:       receipt.RequestedBy,
+		ProfileRows:       receipt.ProfileRows,
+		EducationRows:     receipt.EducationRows,
+		EmploymentRows:    receipt.EmploymentRows,
+		SkillRows:         receipt.SkillRows,
+		ConsentRows:       receipt.ConsentRows,
+		AuditRows:         receipt.AuditRows,
+		ResumeVersionRows: receipt.ResumeVersionRows,
+		PhotoRemoved:      receipt.PhotoRemoved,
+		ErasedAt:          receipt.ErasedAt,
+	}
+}
+
+func TransformProfileData(profile *models.ProfileDTO) *response.ProfileDataResponse {
+	return &response.ProfileDataResponse{
+		CreateProfileResponse: TransformProfile(profile),
+		WorkingExperience:     profile.WorkingExperience,
+	}
+}
@@ -8,6 +8,7 @@ import (
 func TransformProfile(profile *models.ProfileDTO) *response.CreateProfileResponse {
 	return &response.CreateProfileResponse{
 		ProfileCode:    profile.ProfileCode,
+		PublicId:       profile.PublicId,
 		WantedJobTitle: profile.WantedJobTitle,
 		FirstName:      profile.FirstName,
 		LastName:       profile.LastName,
@@ -22,5 +23,21 @@ func TransformProfile(profile *models.ProfileDTO) *response.CreateProfileRespons
 		PlaceOfBirth:   profile.PlaceOfBirth,
 		DateOfBirth:    profile.DateOfBirth,
 		PhotoUrl:       profile.PhotoUrl,
+		Version:        profile.Version,
+	}
+}
+
+func TransformProfileSummary(profile *models.ProfileDTO) *response.ProfileSummaryResponse {
+	return &response.ProfileSummaryResponse{
+		ProfileCode:    profile.ProfileCode,
+		PublicId:       profile.PublicId,
+		WantedJobTitle: profile.WantedJobTitle,
+		FirstName:      profile.FirstName,
+		LastName:       profile.LastName,
+		Country:        profile.Country,
+		City:           profile.City,
+		Nationality:    profile.Nationality,
+		CreatedAt:      profile.CreatedAt,
+		UpdatedAt:      profile.UpdatedAt,
 	}
 }
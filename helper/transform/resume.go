@@ -0,0 +1,40 @@
+package transform
+
+import (
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/response"
+)
+
+func TransformResume(snapshot *models.ResumeSnapshot) *response.ResumeResponse {
+	resume := &response.ResumeResponse{
+		Profile:           TransformProfile(snapshot.Profile),
+		WorkingExperience: snapshot.Profile.WorkingExperience,
+		Education:         []*response.EducationResponse{},
+		Employment:        []*response.EmploymentResponse{},
+		Skills:            []*response.SkillResponse{},
+	}
+	for _, education := range snapshot.Education {
+		resume.Education = append(resume.Education, TransformEducation(education))
+	}
+	for _, employment := range snapshot.Employment {
+		resume.Employment = append(resume.Employment, TransformEmployment(employment))
+	}
+	for _, skill := range snapshot.Skills {
+		resume.Skills = append(resume.Skills, TransformSkill(skill))
+	}
+	return resume
+}
+
+func TransformResumeVersion(version *models.ResumeVersionDTO) *response.ResumeVersionResponse {
+	resumeVersion := &response.ResumeVersionResponse{
+		Version:   version.Version,
+		Label:     version.Label,
+		Automatic: version.Automatic,
+		CreatedBy: version.CreatedBy,
+		CreatedAt: version.CreatedAt,
+	}
+	if version.Snapshot != nil {
+		resumeVersion.Resume = TransformResume(version.Snapshot)
+	}
+	return resumeVersion
+}
@@ -0,0 +1,20 @@
+package transform
+
+import (
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/response"
+)
+
+func TransformSearchResult(result *models.SearchResultDTO) *response.SearchResultResponse {
+	return &response.SearchResultResponse{
+		ProfileCode:    result.ProfileCode,
+		PublicId:       result.PublicId,
+		WantedJobTitle: result.WantedJobTitle,
+		FirstName:      result.FirstName,
+		LastName:       result.LastName,
+		City:           result.City,
+		Country:        result.Country,
+		Rank:           result.Rank,
+		Snippet:        result.Snippet,
+	}
+}
@@ -7,8 +7,10 @@ import (
 
 func TransformSkill(skill *models.SkillDTO) *response.SkillResponse {
 	return &response.SkillResponse{
-		Id:    skill.Id,
-		Skill: skill.Skill,
-		Level: skill.Level,
+		Id:        skill.Id,
+		Skill:     skill.Skill,
+		Level:     skill.Level,
+		Version:   skill.Version,
+		DeletedAt: skill.DeletedAt,
 	}
 }
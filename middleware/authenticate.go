@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"test-bpjs/v2/helper/auth"
+	apiKeyService "test-bpjs/v2/service/apikey"
+
+	"github.com/labstack/echo/v4"
+)
+
+const ClaimsContextKey = "claims"
+
+// Authenticate rejects requests without a valid bearer token or API key and
+// stores the caller on both the echo context and the request context, so
+// services can read it through auth.ClaimsFromContext.
+func Authenticate(tokens *auth.TokenManager, apiKeys apiKeyService.ApiKeyService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Request().Header.Get(echo.HeaderAuthorization)
+			scheme, credential, _ := strings.Cut(header, " ")
+			credential = strings.TrimSpace(credential)
+			if credential == "" {
+				return echo.NewHTTPError(http.StatusUnauthorized, "missing bearer token or api key")
+			}
+
+			var claims *auth.Claims
+			switch {
+			case strings.EqualFold(scheme, "Bearer"):
+				parsed, err := tokens.Parse(credential)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired token")
+				}
+				claims = parsed
+			case strings.EqualFold(scheme, "ApiKey"):
+				resolved, err := apiKeys.Authenticate(c.Request().Context(), credential)
+				if errors.Is(err, apiKeyService.ErrInvalidApiKey) {
+					return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+				}
+				if err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, err)
+				}
+				claims = resolved
+			default:
+				return echo.NewHTTPError(http.StatusUnauthorized, "missing bearer token or api key")
+			}
+
+			c.Set(ClaimsContextKey, claims)
+			c.SetRequest(c.Request().WithContext(auth.WithClaims(c.Request().Context(), claims)))
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"test-bpjs/v2/config"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/models"
+	repository "test-bpjs/v2/repository/mocks"
+	apiKeyService "test-bpjs/v2/service/apikey"
+	authorizationService "test-bpjs/v2/service/authorization"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newTestContext wraps req in an echo context that records the response.
+func newTestContext(req *http.Request) (echo.Context, *httptest.ResponseRecorder) {
+	rec := httptest.NewRecorder()
+	return echo.New().NewContext(req, rec), rec
+}
+
+func TestAuthenticateMiddleware(t *testing.T) {
+	tokens, err := auth.NewTokenManager(config.Config{JWTSecret: "test-secret"})
+	assert.Nil(t, err)
+	otherTokens, err := auth.NewTokenManager(config.Config{JWTSecret: "other-secret"})
+	assert.Nil(t, err)
+
+	const validKey = "bpjs_validkeyvalidkeyvalidkey"
+	sum := sha256.Sum256([]byte(validKey))
+	apiKeyRepository := &repository.ApiKeyRepository{Mock: mock.Mock{}}
+	apiKeyRepository.Mock.On("GetActiveApiKeyByHash", mock.Anything, hex.EncodeToString(sum[:])).Return(&models.ApiKeyDTO{
+		Id:     5,
+		UserId: 3,
+		Role:   auth.RoleRecruiter,
+		Scopes: []string{auth.ScopeProfileRead},
+	}, nil)
+	apiKeyRepository.Mock.On("GetActiveApiKeyByHash", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+	apiKeyRepository.Mock.On("TouchApiKey", mock.Anything, 5, mock.Anything).Return(nil)
+	apiKeys := apiKeyService.NewApiKeyService(apiKeyRepository, authorizationService.NewAuthorizer(&repository.ProfileRepository{}, &repository.ConsentRepository{}, ""))
+
+	var seen *auth.Claims
+	handler := Authenticate(tokens, apiKeys)(func(c echo.Context) error {
+		seen, _ = auth.ClaimsFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	serve := func(header string) error {
+		req := httptest.NewRequest(http.MethodGet, "/api/profile/1", nil)
+		if header != "" {
+			req.Header.Set(echo.HeaderAuthorization, header)
+		}
+		c, _ := newTestContext(req)
+		return handler(c)
+	}
+
+	t.Run("SuccessValidToken", func(t *testing.T) {
+		token, _, err := tokens.Issue(3, "john", auth.RoleUser)
+		assert.Nil(t, err)
+
+		seen = nil
+		assert.NoError(t, serve("Bearer "+token))
+		if assert.NotNil(t, seen) {
+			assert.Equal(t, 3, seen.UserId)
+			assert.Equal(t, "john", seen.Username)
+			assert.Equal(t, auth.RoleUser, seen.Role)
+		}
+	})
+
+	t.Run("FailedMissingToken", func(t *testing.T) {
+		err := serve("")
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusUnauthorized, err.(*echo.HTTPError).Code)
+		}
+	})
+
+	t.Run("FailedWrongScheme", func(t *testing.T) {
+		token, _, _ := tokens.Issue(3, "john", auth.RoleUser)
+		err := serve("Basic " + token)
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusUnauthorized, err.(*echo.HTTPError).Code)
+		}
+	})
+
+	t.Run("FailedForeignSignature", func(t *testing.T) {
+		token, _, _ := otherTokens.Issue(3, "john", auth.RoleUser)
+		err := serve("Bearer " + token)
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusUnauthorized, err.(*echo.HTTPError).Code)
+		}
+	})
+	t.Run("SuccessValidApiKey", func(t *testing.T) {
+		seen = nil
+		assert.NoError(t, serve("ApiKey "+validKey))
+		if assert.NotNil(t, seen) {
+			assert.Equal(t, 3, seen.UserId)
+			assert.Equal(t, 5, seen.ApiKeyId)
+			assert.Equal(t, auth.RoleRecruiter, seen.Role)
+			assert.True(t, seen.HasScope(auth.ScopeProfileRead))
+			assert.False(t, seen.HasScope(auth.ScopeResumeExport))
+		}
+		apiKeyRepository.AssertCalled(t, "TouchApiKey", mock.Anything, 5, mock.Anything)
+	})
+
+	t.Run("FailedUnknownApiKey", func(t *testing.T) {
+		err := serve("ApiKey bpjs_revoked")
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusUnauthorized, err.(*echo.HTTPError).Code)
+		}
+	})
+}
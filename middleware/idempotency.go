@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"test-bpjs/v2/helper/etag"
+	idempotencyService "test-bpjs/v2/service/idempotency"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed tells clients the response is a replay of the
+	// first request with the key.
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// replayedHeaders are the response headers stored with the response besides
+// its content type, so a retry gets the same ETag and Location as the first
+// request.
+var replayedHeaders = []string{etag.HeaderETag, echo.HeaderLocation}
+
+// Idempotency replays the stored response when a POST to one of the given
+// routes is retried with the same Idempotency-Key header, instead of creating
+// the row a second time. Requests without the header run as usual. Keys are
+// kept per caller, so it must run after Authenticate.
+func Idempotency(service idempotencyService.IdempotencyService, paths ...string) echo.MiddlewareFunc {
+	routes := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		routes[path] = true
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get(HeaderIdempotencyKey)
+			if c.Request().Method != http.MethodPost || !routes[c.Path()] || key == "" {
+				return next(c)
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				return echo.NewHTTPError(http.StatusBadRequest, "bad request. invalid idempotency key")
+			}
+
+			body, err := io.ReadAll(c.Request().Body)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "bad request. failed to read body")
+			}
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := c.Request().Context()
+			scope := caller(c)
+			stored, err := service.Begin(ctx, scope, key, requestHash(c.Request(), body))
+			if err != nil {
+				switch {
+				case errors.Is(err, idempotencyService.ErrKeyReused):
+					return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+				case errors.Is(err, idempotencyService.ErrKeyInProgress):
+					return echo.NewHTTPError(http.StatusConflict, err.Error())
+				}
+				return echo.NewHTTPError(http.StatusInternalServerError, err)
+			}
+			if stored != nil {
+				for name, value := range stored.Headers {
+					c.Response().Header().Set(name, value)
+				}
+				c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+				return c.Blob(stored.StatusCode, stored.ContentType, stored.Body)
+			}
+
+			recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+			err = next(c)
+
+			// only successful responses are kept; a failed request frees the
+			// key so the client can retry it
+			status := c.Response().Status
+			if err != nil || status < http.StatusOK || status >= http.StatusMultipleChoices {
+				if releaseErr := service.Release(ctx, scope, key); releaseErr != nil {
+					log.WithContext(ctx).Warnf("idempotency key not released: %v", releaseErr)
+				}
+				return err
+			}
+			contentType := c.Response().Header().Get(echo.HeaderContentType)
+			headers := make(map[string]string)
+			for _, name := range replayedHeaders {
+				if value := c.Response().Header().Get(name); value != "" {
+					headers[name] = value
+				}
+			}
+			// a key left claimed without a response would answer every retry
+			// with 409 until it expires, so it is freed instead
+			if completeErr := service.Complete(ctx, scope, key, status, contentType, headers, recorder.body.Bytes()); completeErr != nil {
+				log.WithContext(ctx).Warnf("idempotent response not stored: %v", completeErr)
+				if releaseErr := service.Release(ctx, scope, key); releaseErr != nil {
+					log.WithContext(ctx).Warnf("idempotency key not released: %v", releaseErr)
+				}
+			}
+			return nil
+		}
+	}
+}
+
+// requestHash tells a retry apart from a different request reusing the key.
+func requestHash(req *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(req.Method + " " + req.URL.RequestURI() + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body while writing it out.
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
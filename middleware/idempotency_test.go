@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/helper/etag"
+	"test-bpjs/v2/models"
+	repository "test-bpjs/v2/repository/mocks"
+	idempotencyService "test-bpjs/v2/service/idempotency"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	idempotencyRepository := &repository.IdempotencyRepository{Mock: mock.Mock{}}
+	idempotency := idempotencyService.NewIdempotencyService(idempotencyRepository, time.Hour)
+
+	created := 0
+	handler := Idempotency(idempotency, "/api/skill/:profileCode")(func(c echo.Context) error {
+		created++
+		if c.Request().Header.Get("X-Fail") != "" {
+			return echo.NewHTTPError(http.StatusInternalServerError)
+		}
+		c.Response().Header().Set(etag.HeaderETag, etag.Format(1))
+		c.Response().Header().Set(echo.HeaderLocation, "/api/skill/1")
+		return c.JSON(http.StatusOK, map[string]int{"id": created})
+	})
+
+	serve := func(key, body string, header ...string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodPost, "/api/skill/1", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if key != "" {
+			req.Header.Set(HeaderIdempotencyKey, key)
+		}
+		if len(header) > 0 {
+			req.Header.Set("X-Fail", header[0])
+		}
+		req = req.WithContext(auth.WithClaims(req.Context(), &auth.Claims{UserId: 1}))
+		c, rec := newTestContext(req)
+		c.SetPath("/api/skill/:profileCode")
+		return rec, handler(c)
+	}
+
+	t.Run("SuccessWithoutKey", func(t *testing.T) {
+		created = 0
+		_, err := serve("", `{"skill":"Go"}`)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, created)
+		idempotencyRepository.Mock.AssertNotCalled(t, "ClaimIdempotencyKey", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("SuccessFirstRequest_Stored", func(t *testing.T) {
+		created = 0
+		idempotencyRepository.Mock.On("ClaimIdempotencyKey", mock.Anything, mock.MatchedBy(func(payload *models.IdempotencyKey) bool {
+			return payload.Scope == "user:1" && payload.Key == "first"
+		}), mock.Anything).Return(true, nil).Once()
+		headers := map[string]string{etag.HeaderETag: etag.Format(1), echo.HeaderLocation: "/api/skill/1"}
+		idempotencyRepository.Mock.On("CompleteIdempotencyKey", mock.Anything, "user:1", "first", http.StatusOK, echo.MIMEApplicationJSON, headers, []byte("{\"id\":1}\n")).Return(nil).Once()
+
+		rec, err := serve("first", `{"skill":"Go"}`)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, created)
+		assert.Empty(t, rec.Header().Get(HeaderIdempotentReplayed))
+		idempotencyRepository.Mock.AssertCalled(t, "CompleteIdempotencyKey", mock.Anything, "user:1", "first", http.StatusOK, echo.MIMEApplicationJSON, headers, []byte("{\"id\":1}\n"))
+	})
+
+	t.Run("FailedStoreResponse_Released", func(t *testing.T) {
+		created = 0
+		idempotencyRepository.Mock.On("ClaimIdempotencyKey", mock.Anything, mock.MatchedBy(func(payload *models.IdempotencyKey) bool {
+			return payload.Key == "unstored"
+		}), mock.Anything).Return(true, nil).Once()
+		idempotencyRepository.Mock.On("CompleteIdempotencyKey", mock.Anything, "user:1", "unstored", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("connection reset")).Once()
+		idempotencyRepository.Mock.On("DeleteIdempotencyKey", mock.Anything, "user:1", "unstored").Return(nil).Once()
+
+		rec, err := serve("unstored", `{"skill":"Go"}`)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, created)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		idempotencyRepository.Mock.AssertCalled(t, "DeleteIdempotencyKey", mock.Anything, "user:1", "unstored")
+	})
+
+	t.Run("SuccessRetry_Replayed", func(t *testing.T) {
+		created = 0
+		var hash string
+		idempotencyRepository.Mock.On("ClaimIdempotencyKey", mock.Anything, mock.MatchedBy(func(payload *models.IdempotencyKey) bool {
+			hash = payload.RequestHash
+			return payload.Key == "retry"
+		}), mock.Anything).Return(false, nil).Once()
+		idempotencyRepository.Mock.On("GetIdempotencyKey", mock.Anything, "user:1", "retry").Return(func(ctx context.Context, scope, key string) *models.IdempotencyKeyDTO {
+			return &models.IdempotencyKeyDTO{
+				RequestHash: hash,
+				StatusCode:  http.StatusCreated,
+				ContentType: echo.MIMEApplicationJSON,
+				Headers:     map[string]string{etag.HeaderETag: etag.Format(3), echo.HeaderLocation: "/api/skill/7"},
+				Body:        []byte(`{"id":7}`),
+			}
+		}, nil).Once()
+
+		rec, err := serve("retry", `{"skill":"Go"}`)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, created)
+		assert.Equal(t, "true", rec.Header().Get(HeaderIdempotentReplayed))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, etag.Format(3), rec.Header().Get(etag.HeaderETag))
+		assert.Equal(t, "/api/skill/7", rec.Header().Get(echo.HeaderLocation))
+		assert.JSONEq(t, `{"id":7}`, rec.Body.String())
+	})
+
+	t.Run("FailedReusedKey_Err422", func(t *testing.T) {
+		created = 0
+		idempotencyRepository.Mock.On("ClaimIdempotencyKey", mock.Anything, mock.MatchedBy(func(payload *models.IdempotencyKey) bool {
+			return payload.Key == "reused"
+		}), mock.Anything).Return(false, nil).Once()
+		idempotencyRepository.Mock.On("GetIdempotencyKey", mock.Anything, "user:1", "reused").Return(&models.IdempotencyKeyDTO{
+			RequestHash: "hash of another body",
+			StatusCode:  http.StatusOK,
+		}, nil).Once()
+
+		_, err := serve("reused", `{"skill":"Rust"}`)
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusUnprocessableEntity, err.(*echo.HTTPError).Code)
+		}
+		assert.Equal(t, 0, created)
+	})
+
+	t.Run("FailedHandler_Released", func(t *testing.T) {
+		idempotencyRepository.Mock.On("ClaimIdempotencyKey", mock.Anything, mock.MatchedBy(func(payload *models.IdempotencyKey) bool {
+			return payload.Key == "failing"
+		}), mock.Anything).Return(true, nil).Once()
+		idempotencyRepository.Mock.On("DeleteIdempotencyKey", mock.Anything, "user:1", "failing").Return(nil).Once()
+
+		_, err := serve("failing", `{"skill":"Go"}`, "1")
+		assert.Error(t, err)
+		idempotencyRepository.Mock.AssertCalled(t, "DeleteIdempotencyKey", mock.Anything, "user:1", "failing")
+		idempotencyRepository.Mock.AssertNotCalled(t, "CompleteIdempotencyKey", mock.Anything, "user:1", "failing", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("FailedLongKey_Err400", func(t *testing.T) {
+		_, err := serve(strings.Repeat("k", 256), `{"skill":"Go"}`)
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+		}
+	})
+}
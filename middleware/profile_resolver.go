@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"test-bpjs/v2/repository"
+	profileService "test-bpjs/v2/service/profile"
+
+	"github.com/labstack/echo/v4"
+)
+
+const profileCodeParam = "profileCode"
+
+// trashedProfileRoutes are the routes that work on profiles in the trash too:
+// listing and restoring trashed rows, and erasing a profile, which a subject
+// may ask for after trashing it.
+var trashedProfileRoutes = map[string]bool{
+	"/api/profile/:profileCode/restore":    true,
+	"/api/profile/:profileCode/erasure":    true,
+	"/api/education/:profileCode/trash":    true,
+	"/api/education/:profileCode/restore":  true,
+	"/api/employment/:profileCode/trash":   true,
+	"/api/employment/:profileCode/restore": true,
+	"/api/skill/:profileCode/trash":        true,
+	"/api/skill/:profileCode/restore":      true,
+}
+
+// ResolveProfileCode replaces the public profile id found in the :profileCode
+// path parameter with the internal profile code, so handlers and services
+// keep working with integers while URLs never expose them. Profiles in the
+// trash are only found on trashedProfileRoutes.
+func ResolveProfileCode(resolver profileService.ProfileService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if trashedProfileRoutes[c.Path()] {
+				c.SetRequest(c.Request().WithContext(repository.WithTrashedProfiles(c.Request().Context())))
+			}
+
+			names := c.ParamNames()
+			values := c.ParamValues()
+			for i, name := range names {
+				if name != profileCodeParam || i >= len(values) {
+					continue
+				}
+
+				code, err := resolver.ResolvePublicId(c.Request().Context(), values[i])
+				if err != nil {
+					if errors.Is(err, profileService.ErrProfileNotFound) {
+						return echo.NewHTTPError(http.StatusNotFound, err.Error())
+					}
+					return echo.NewHTTPError(http.StatusInternalServerError, err)
+				}
+
+				resolved := make([]string, len(values))
+				copy(resolved, values)
+				resolved[i] = strconv.Itoa(code)
+				c.SetParamValues(resolved...)
+				break
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"test-bpjs/v2/repository"
+	mocks "test-bpjs/v2/repository/mocks"
+	authorizationService "test-bpjs/v2/service/authorization"
+	profileService "test-bpjs/v2/service/profile"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestResolveProfileCodeMiddleware(t *testing.T) {
+	profileRepository := &mocks.ProfileRepository{Mock: mock.Mock{}}
+	profileRepository.Mock.On("GetProfileCodeByPublicId", mock.Anything, "01JAB3Q8W0RM0ZTRBPN7C2Z1K4").Return(42, nil)
+	profileRepository.Mock.On("GetProfileCodeByPublicId", mock.Anything, "42").Return(0, sql.ErrNoRows)
+	profileRepository.Mock.On("GetProfileCodeByPublicId", mock.Anything, "broken").Return(0, errors.New("connection refused"))
+	resolver := profileService.NewProfileService(profileRepository, authorizationService.NewAuthorizer(profileRepository, &mocks.ConsentRepository{}, ""), nil, nil)
+
+	var seen string
+	handler := ResolveProfileCode(resolver)(func(c echo.Context) error {
+		seen = c.Param("profileCode")
+		return c.NoContent(http.StatusOK)
+	})
+
+	serve := func(names []string, values []string) error {
+		c, _ := newTestContext(httptest.NewRequest(http.MethodGet, "/api", nil).WithContext(context.Background()))
+		c.SetParamNames(names...)
+		c.SetParamValues(values...)
+		return handler(c)
+	}
+
+	t.Run("SuccessResolvePublicId", func(t *testing.T) {
+		seen = ""
+		assert.NoError(t, serve([]string{"profileCode"}, []string{"01JAB3Q8W0RM0ZTRBPN7C2Z1K4"}))
+		assert.Equal(t, "42", seen)
+	})
+
+	t.Run("SuccessRouteWithoutProfileCode", func(t *testing.T) {
+		seen = "untouched"
+		assert.NoError(t, serve(nil, nil))
+		assert.Equal(t, "", seen)
+	})
+
+	t.Run("FailedSequentialCode_Err404", func(t *testing.T) {
+		err := serve([]string{"profileCode"}, []string{"42"})
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+		}
+	})
+
+	t.Run("SuccessTrashRoutesFindTrashedProfiles", func(t *testing.T) {
+		var trashed bool
+		handler := ResolveProfileCode(resolver)(func(c echo.Context) error {
+			trashed = repository.IncludesTrashedProfiles(c.Request().Context())
+			return c.NoContent(http.StatusOK)
+		})
+		for path, want := range map[string]bool{
+			"/api/profile/:profileCode":                          false,
+			"/api/profile/:profileCode/restore":                  true,
+			"/api/profile/:profileCode/erasure":                  true,
+			"/api/profile/:profileCode/data-export":              false,
+			"/api/education/:profileCode/trash":                  true,
+			"/api/education/:profileCode/trashcan":               false,
+			"/api/resume/:profileCode/versions/:version/restore": false,
+		} {
+			c, _ := newTestContext(httptest.NewRequest(http.MethodGet, "/api", nil))
+			c.SetPath(path)
+			c.SetParamNames("profileCode")
+			c.SetParamValues("01JAB3Q8W0RM0ZTRBPN7C2Z1K4")
+			assert.NoError(t, handler(c))
+			assert.Equal(t, want, trashed, path)
+		}
+	})
+
+	t.Run("FailedResolver_Err500", func(t *testing.T) {
+		err := serve([]string{"profileCode"}, []string{"broken"})
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusInternalServerError, err.(*echo.HTTPError).Code)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/helper/ratelimit"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+// RateLimit applies the limiter's policy for the matched route. Callers are
+// told apart by API key, then user, then client IP, so it must run after
+// Authenticate to limit signed-in callers individually.
+func RateLimit(limiter *ratelimit.Limiter) echo.MiddlewareFunc {
+	return rateLimit(limiter, caller)
+}
+
+// RateLimitByIP applies the limiter's policy for the matched route to the
+// client IP alone. It runs before Authenticate, so requests with bad
+// credentials are throttled as well.
+func RateLimitByIP(limiter *ratelimit.Limiter) echo.MiddlewareFunc {
+	return rateLimit(limiter, func(c echo.Context) string {
+		// not "ip:", so a store shared with RateLimit keeps separate buckets
+		return "addr:" + c.RealIP()
+	})
+}
+
+func rateLimit(limiter *ratelimit.Limiter, caller func(c echo.Context) string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			policy, ok := limiter.Policy(c.Request().Method, c.Path())
+			if !ok {
+				return next(c)
+			}
+
+			result, err := limiter.Take(c.Request().Context(), policy, caller(c))
+			if err != nil {
+				// an unavailable store must not take the API down with it
+				log.WithContext(c.Request().Context()).Warnf("rate limit skipped: %v", err)
+				return next(c)
+			}
+
+			header := c.Response().Header()
+			header.Set("X-RateLimit-Limit", strconv.Itoa(policy.Burst))
+			header.Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			if !result.Allowed {
+				header.Set("Retry-After", strconv.Itoa(ratelimit.RetryAfterSeconds(result.RetryAfter)))
+				return echo.NewHTTPError(http.StatusTooManyRequests, "too many requests")
+			}
+			return next(c)
+		}
+	}
+}
+
+func caller(c echo.Context) string {
+	if claims, ok := auth.ClaimsFromContext(c.Request().Context()); ok {
+		if claims.ApiKeyId != 0 {
+			return "key:" + strconv.Itoa(claims.ApiKeyId)
+		}
+		return "user:" + strconv.Itoa(claims.UserId)
+	}
+	return "ip:" + c.RealIP()
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"test-bpjs/v2/config"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/helper/ratelimit"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), []config.RateLimitPolicy{
+		{Method: http.MethodPut, Path: "/api/photo/:profileCode", Requests: 1, Per: time.Minute},
+	})
+	handler := RateLimit(limiter)(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	serve := func(method string, claims *auth.Claims, ip string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(method, "/api/photo/1", nil)
+		req.RemoteAddr = ip + ":1234"
+		if claims != nil {
+			req = req.WithContext(auth.WithClaims(req.Context(), claims))
+		}
+		c, rec := newTestContext(req)
+		c.SetPath("/api/photo/:profileCode")
+		return rec, handler(c)
+	}
+
+	t.Run("SuccessFirstRequest", func(t *testing.T) {
+		rec, err := serve(http.MethodPut, &auth.Claims{UserId: 1}, "10.0.0.1")
+		assert.NoError(t, err)
+		assert.Equal(t, "1", rec.Header().Get("X-RateLimit-Limit"))
+		assert.Equal(t, "0", rec.Header().Get("X-RateLimit-Remaining"))
+	})
+
+	t.Run("FailedSecondRequest_Err429", func(t *testing.T) {
+		rec, err := serve(http.MethodPut, &auth.Claims{UserId: 1}, "10.0.0.1")
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusTooManyRequests, err.(*echo.HTTPError).Code)
+			assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+		}
+	})
+
+	t.Run("SuccessSeparateBuckets", func(t *testing.T) {
+		// same IP, but another user and an API key of the first user
+		_, err := serve(http.MethodPut, &auth.Claims{UserId: 2}, "10.0.0.1")
+		assert.NoError(t, err)
+		_, err = serve(http.MethodPut, &auth.Claims{UserId: 1, ApiKeyId: 7}, "10.0.0.1")
+		assert.NoError(t, err)
+		_, err = serve(http.MethodPut, nil, "10.0.0.1")
+		assert.NoError(t, err)
+		_, err = serve(http.MethodPut, nil, "10.0.0.1")
+		assert.Error(t, err)
+	})
+
+	t.Run("SuccessRouteWithoutPolicy", func(t *testing.T) {
+		rec, err := serve(http.MethodGet, &auth.Claims{UserId: 1}, "10.0.0.1")
+		assert.NoError(t, err)
+		assert.Empty(t, rec.Header().Get("X-RateLimit-Limit"))
+	})
+}
+
+func TestRateLimitByIPMiddleware(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), []config.RateLimitPolicy{
+		{Path: "*", Requests: 2, Per: time.Minute},
+	})
+	// the IP limit runs before Authenticate, which rejects every request here
+	handler := RateLimitByIP(limiter)(func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusUnauthorized, "invalid credentials")
+	})
+
+	serve := func(ip string) error {
+		req := httptest.NewRequest(http.MethodGet, "/api/profile/1", nil)
+		req.RemoteAddr = ip + ":1234"
+		c, _ := newTestContext(req)
+		c.SetPath("/api/profile/:profileCode")
+		return handler(c)
+	}
+
+	t.Run("FailedBadCredentials_Err429", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			err := serve("10.0.0.2")
+			if assert.Error(t, err) {
+				assert.Equal(t, http.StatusUnauthorized, err.(*echo.HTTPError).Code)
+			}
+		}
+		err := serve("10.0.0.2")
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusTooManyRequests, err.(*echo.HTTPError).Code)
+		}
+	})
+
+	t.Run("SuccessSeparateIPs", func(t *testing.T) {
+		err := serve("10.0.0.3")
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusUnauthorized, err.(*echo.HTTPError).Code)
+		}
+	})
+}
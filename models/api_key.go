@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type ApiKey struct {
+	bun.BaseModel `bun:"table:api_keys"`
+
+	Id         int       `bun:"id,pk,type:int,autoincrement"`
+	UserId     int       `bun:"user_id,notnull"`
+	Name       string    `bun:"name,notnull"`
+	Prefix     string    `bun:"prefix,notnull"`
+	KeyHash    string    `bun:"key_hash,notnull"`
+	Scopes     []string  `bun:"scopes,array"`
+	CreatedAt  time.Time `bun:"created_at,default:current_timestamp"`
+	LastUsedAt time.Time `bun:"last_used_at,nullzero"`
+	RevokedAt  time.Time `bun:"revoked_at,nullzero"`
+}
+
+type ApiKeyDTO struct {
+	Id         int       `json:"id"`
+	UserId     int       `json:"userId"`
+	Role       string    `json:"role"`
+	Name       string    `json:"name"`
+	Prefix     string    `json:"prefix"`
+	Scopes     []string  `json:"scopes"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	RevokedAt  time.Time `json:"revokedAt"`
+}
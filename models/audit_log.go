@@ -0,0 +1,63 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+)
+
+const (
+	AuditEntityProfile    = "profile"
+	AuditEntityEducation  = "education"
+	AuditEntityEmployment = "employment"
+	AuditEntitySkill      = "skill"
+	AuditEntityResume     = "resume"
+)
+
+// AuditLog is one mutation of a profile or its child rows. Entries are only
+// ever inserted; the changes are redacted when the profile is erased. The
+// profile's public id is read from the profile along with the entry.
+type AuditLog struct {
+	bun.BaseModel `bun:"table:audit_logs"`
+
+	Id              int       `bun:"id,pk,type:int,autoincrement"`
+	ProfileCode     int       `bun:"profile_code,notnull"`
+	ActorId         int       `bun:"actor_id,nullzero"`
+	ApiKeyId        int       `bun:"api_key_id,nullzero"`
+	Action          string    `bun:"action,notnull"`
+	Entity          string    `bun:"entity,notnull"`
+	EntityId        int       `bun:"entity_id,notnull"`
+	Changes         string    `bun:"changes,nullzero"`
+	RequestId       string    `bun:"request_id,nullzero"`
+	CreatedAt       time.Time `bun:"created_at,notnull"`
+	ProfilePublicId string    `bun:"profile_public_id,scanonly"`
+}
+
+// FieldChange is the before and after value of one field, as JSON. Before is
+// null for created rows and After is null for deleted ones.
+type FieldChange struct {
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+type AuditLogDTO struct {
+	Id              int                    `json:"id"`
+	ProfileCode     int                    `json:"profileCode"`
+	ProfilePublicId string                 `json:"profilePublicId"`
+	ActorId         int                    `json:"actorId"`
+	ApiKeyId        int                    `json:"apiKeyId"`
+	Action          string                 `json:"action"`
+	Entity          string                 `json:"entity"`
+	EntityId        int                    `json:"entityId"`
+	Changes         map[string]FieldChange `json:"changes"`
+	RequestId       string                 `json:"requestId"`
+	CreatedAt       time.Time              `json:"createdAt"`
+}
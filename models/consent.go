@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// ConsentPurposeRecruiterAccess is the candidate's agreement to let
+// recruiters read their profile and CV.
+const ConsentPurposeRecruiterAccess = "recruiter_access"
+
+type Consent struct {
+	bun.BaseModel `bun:"table:consents"`
+
+	Id           int       `bun:"id,pk,type:int,autoincrement"`
+	ProfileCode  int       `bun:"profile_code,notnull"`
+	Purpose      string    `bun:"purpose,notnull"`
+	TermsVersion string    `bun:"terms_version,notnull"`
+	GrantedBy    int       `bun:"granted_by,notnull"`
+	GrantedAt    time.Time `bun:"granted_at,notnull"`
+	RevokedAt    time.Time `bun:"revoked_at,nullzero"`
+}
+
+type ConsentDTO struct {
+	Id           int       `json:"id"`
+	ProfileCode  int       `json:"profileCode"`
+	Purpose      string    `json:"purpose"`
+	TermsVersion string    `json:"termsVersion"`
+	GrantedBy    int       `json:"grantedBy"`
+	GrantedAt    time.Time `json:"grantedAt"`
+	RevokedAt    time.Time `json:"revokedAt"`
+}
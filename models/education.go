@@ -18,16 +18,20 @@ type Education struct {
 	City        string    `bun:"city"`
 	Description string    `bun:"description"`
 	CreatedAt   time.Time `bun:"created_at,default:current_timestamp"`
+	Version     int       `bun:"version,notnull,default:1"`
+	DeletedAt   time.Time `bun:"deleted_at,soft_delete,nullzero"`
 }
 
 type EducationDTO struct {
-	ProfileCode int       `json:"profileCode"`
-	Id          int       `json:"id"`
-	School      string    `json:"school"`
-	Degree      string    `json:"degree"`
-	StartDate   time.Time `json:"startDate"`
-	EndDate     time.Time `json:"endDate"`
-	City        string    `json:"city"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"createdAt"`
+	ProfileCode int        `json:"profileCode"`
+	Id          int        `json:"id"`
+	School      string     `json:"school"`
+	Degree      string     `json:"degree"`
+	StartDate   time.Time  `json:"startDate"`
+	EndDate     time.Time  `json:"endDate"`
+	City        string     `json:"city"`
+	Description string     `json:"description"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	Version     int        `json:"-"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty"`
 }
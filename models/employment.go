@@ -6,28 +6,60 @@ import (
 	"github.com/uptrace/bun"
 )
 
+// The employment types of a row. Rows may also leave it empty.
+const (
+	EmploymentTypeFullTime   = "full_time"
+	EmploymentTypePartTime   = "part_time"
+	EmploymentTypeContract   = "contract"
+	EmploymentTypeInternship = "internship"
+	EmploymentTypeFreelance  = "freelance"
+)
+
+// EmploymentTypes lists every employment type.
+var EmploymentTypes = []string{EmploymentTypeFullTime, EmploymentTypePartTime, EmploymentTypeContract, EmploymentTypeInternship, EmploymentTypeFreelance}
+
+// ValidEmploymentType reports whether the type is empty or one of
+// EmploymentTypes.
+func ValidEmploymentType(employmentType string) bool {
+	if employmentType == "" {
+		return true
+	}
+	for _, t := range EmploymentTypes {
+		if t == employmentType {
+			return true
+		}
+	}
+	return false
+}
+
 type Employment struct {
 	bun.BaseModel `bun:"table:employment"`
 
-	ProfileCode int       `bun:"profile_code"`
-	Id          int       `bun:"id,pk,type:int,autoincrement"`
-	JobTitle    string    `bun:"job_title"`
-	Employer    string    `bun:"employer"`
-	StartDate   time.Time `bun:"start_date"`
-	EndDate     time.Time `bun:"end_date"`
-	City        string    `bun:"city"`
-	Description string    `bun:"description"`
-	CreatedAt   time.Time `bun:"created_at,default:current_timestamp"`
+	ProfileCode    int       `bun:"profile_code"`
+	Id             int       `bun:"id,pk,type:int,autoincrement"`
+	JobTitle       string    `bun:"job_title"`
+	Employer       string    `bun:"employer"`
+	StartDate      time.Time `bun:"start_date"`
+	EndDate        time.Time `bun:"end_date"`
+	City           string    `bun:"city"`
+	Description    string    `bun:"description"`
+	EmploymentType string    `bun:"employment_type"`
+	CreatedAt      time.Time `bun:"created_at,default:current_timestamp"`
+	Version        int       `bun:"version,notnull,default:1"`
+	DeletedAt      time.Time `bun:"deleted_at,soft_delete,nullzero"`
 }
 
 type EmploymentDTO struct {
-	ProfileCode int       `json:"profileCode"`
-	Id          int       `json:"id"`
-	JobTitle    string    `json:"jobTitle"`
-	Employer    string    `json:"employer"`
-	StartDate   time.Time `json:"startDate"`
-	EndDate     time.Time `json:"endDate"`
-	City        string    `json:"city"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"createdAt"`
+	ProfileCode    int        `json:"profileCode"`
+	Id             int        `json:"id"`
+	JobTitle       string     `json:"jobTitle"`
+	Employer       string     `json:"employer"`
+	StartDate      time.Time  `json:"startDate"`
+	EndDate        time.Time  `json:"endDate"`
+	City           string     `json:"city"`
+	Description    string     `json:"description"`
+	EmploymentType string     `json:"employmentType"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	Version        int        `json:"-"`
+	DeletedAt      *time.Time `json:"deletedAt,omitempty"`
 }
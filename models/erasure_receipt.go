@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+const (
+	ErasureModeAnonymise = "anonymise"
+	ErasureModeDelete    = "delete"
+)
+
+// ErasureReceipt records that a profile was erased. It holds no personal
+// data, so it is kept after the profile itself is gone.
+type ErasureReceipt struct {
+	bun.BaseModel `bun:"table:erasure_receipts"`
+
+	Id                int       `bun:"id,pk,type:int,autoincrement"`
+	ReceiptId         string    `bun:"receipt_id,notnull"`
+	ProfilePublicId   string    `bun:"profile_public_id,notnull"`
+	Mode              string    `bun:"mode,notnull"`
+	Reason            string    `bun:"reason"`
+	RequestedBy       int       `bun:"requested_by,notnull"`
+	ProfileRows       int       `bun:"profile_rows"`
+	EducationRows     int       `bun:"education_rows"`
+	EmploymentRows    int       `bun:"employment_rows"`
+	SkillRows         int       `bun:"skill_rows"`
+	ConsentRows       int       `bun:"consent_rows"`
+	AuditRows         int       `bun:"audit_rows"`
+	ResumeVersionRows int       `bun:"resume_version_rows"`
+	PhotoRemoved      bool      `bun:"photo_removed"`
+	ErasedAt          time.Time `bun:"erased_at,notnull"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// IdempotencyKey is a create request a client may retry. The response is
+// empty while the first request is still running.
+type IdempotencyKey struct {
+	bun.BaseModel `bun:"table:idempotency_keys"`
+
+	Scope       string            `bun:"scope,pk"`
+	Key         string            `bun:"idempotency_key,pk"`
+	RequestHash string            `bun:"request_hash,notnull"`
+	StatusCode  int               `bun:"status_code,nullzero"`
+	ContentType string            `bun:"content_type,nullzero"`
+	Headers     map[string]string `bun:"headers,type:jsonb,nullzero"`
+	Body        string            `bun:"body,nullzero"`
+	CreatedAt   time.Time         `bun:"created_at,notnull"`
+	ExpiresAt   time.Time         `bun:"expires_at,notnull"`
+}
+
+type IdempotencyKeyDTO struct {
+	Scope       string
+	Key         string
+	RequestHash string
+	StatusCode  int
+	ContentType string
+	Headers     map[string]string
+	Body        []byte
+	ExpiresAt   time.Time
+}
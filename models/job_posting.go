@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// JobPostingSkill is a skill asked for by a job posting. An empty level
+// accepts any level.
+type JobPostingSkill struct {
+	Skill    string `json:"skill"`
+	Level    string `json:"level"`
+	Required bool   `json:"required"`
+}
+
+type JobPosting struct {
+	bun.BaseModel `bun:"table:job_postings,alias:job_posting"`
+
+	Id                 int               `bun:"id,pk,type:int,autoincrement"`
+	OwnerId            int               `bun:"owner_id,notnull"`
+	Title              string            `bun:"title,notnull"`
+	Skills             []JobPostingSkill `bun:"skills,type:jsonb"`
+	MinYearsExperience int               `bun:"min_years_experience"`
+	City               string            `bun:"city"`
+	Country            string            `bun:"country"`
+	CreatedAt          time.Time         `bun:"created_at,default:current_timestamp"`
+	UpdatedAt          time.Time         `bun:"updated_at,nullzero"`
+}
+
+type JobPostingDTO struct {
+	Id                 int               `json:"id"`
+	OwnerId            int               `json:"ownerId"`
+	Title              string            `json:"title"`
+	Skills             []JobPostingSkill `json:"skills"`
+	MinYearsExperience int               `json:"minYearsExperience"`
+	City               string            `json:"city"`
+	Country            string            `json:"country"`
+	CreatedAt          time.Time         `json:"createdAt"`
+	UpdatedAt          time.Time         `json:"updatedAt"`
+}
+
+// MatchCandidateDTO is a profile together with the rows a job posting is
+// matched against.
+type MatchCandidateDTO struct {
+	ProfileCode    int
+	PublicId       string
+	WantedJobTitle string
+	FirstName      string
+	LastName       string
+	City           string
+	Country        string
+	Skills         []*SkillDTO
+	Employment     []*EmploymentDTO
+}
@@ -10,10 +10,12 @@ type Profile struct {
 	bun.BaseModel `bun:"table:profile"`
 
 	ProfileCode       int       `bun:"profile_code,pk,type:int,autoincrement"`
+	PublicId          string    `bun:"public_id,notnull"`
 	WantedJobTitle    string    `bun:"wanted_job_title,notnull"`
 	FirstName         string    `bun:"first_name,notnull"`
 	LastName          string    `bun:"last_name"`
 	Email             string    `bun:"email,notnull"`
+	EmailIndex        string    `bun:"email_bidx,nullzero"`
 	Phone             string    `bun:"phone,notnull"`
 	Country           string    `bun:"country,notnull"`
 	City              string    `bun:"city,notnull"`
@@ -22,15 +24,49 @@ type Profile struct {
 	DrivingLicense    string    `bun:"driving_license"`
 	Nationality       string    `bun:"nationality"`
 	PlaceOfBirth      string    `bun:"place_of_birth"`
-	DateOfBirth       time.Time `bun:"date_of_birth"`
+	DateOfBirth       time.Time `bun:"date_of_birth,nullzero"`
+	DateOfBirthEnc    string    `bun:"date_of_birth_enc,nullzero"`
 	PhotoUrl          string    `bun:"photo_url"`
+	PhotoHash         string    `bun:"photo_hash"`
 	WorkingExperience string    `bun:"working_experience"`
 	CreatedAt         time.Time `bun:"created_at,default:current_timestamp"`
 	UpdatedAt         time.Time `bun:"updated_at"`
+	OwnerId           int       `bun:"owner_id"`
+	Version           int       `bun:"version,notnull,default:1"`
+	DeletedAt         time.Time `bun:"deleted_at,soft_delete,nullzero"`
 }
 
 type ProfileDTO struct {
-	ProfileCode       int       `json:"profileCode"`
+	ProfileCode       int        `json:"profileCode"`
+	PublicId          string     `json:"publicId"`
+	WantedJobTitle    string     `json:"wantedJobTitle"`
+	FirstName         string     `json:"firstName"`
+	LastName          string     `json:"lastName"`
+	Email             string     `json:"email"`
+	Phone             string     `json:"phone"`
+	Country           string     `json:"country"`
+	City              string     `json:"city"`
+	Address           string     `json:"address"`
+	PostalCode        int        `json:"postalCode"`
+	DrivingLicense    string     `json:"drivingLicense"`
+	Nationality       string     `json:"nationality"`
+	PlaceOfBirth      string     `json:"placeOfBirth"`
+	DateOfBirth       time.Time  `json:"dateOfBirth"`
+	DateOfBirthEnc    string     `json:"-"`
+	PhotoUrl          string     `json:"photoUrl"`
+	PhotoHash         string     `json:"photoHash"`
+	WorkingExperience string     `json:"workingExperience"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	UpdatedAt         time.Time  `json:"updatedAt"`
+	OwnerId           int        `json:"ownerId"`
+	Version           int        `json:"-"`
+	DeletedAt         *time.Time `json:"deletedAt,omitempty"`
+}
+
+// AuditedProfile is what the audit log records of a profile: the fields its
+// owner can see, without internal codes or the version.
+type AuditedProfile struct {
+	PublicId          string    `json:"publicId"`
 	WantedJobTitle    string    `json:"wantedJobTitle"`
 	FirstName         string    `json:"firstName"`
 	LastName          string    `json:"lastName"`
@@ -46,6 +82,43 @@ type ProfileDTO struct {
 	DateOfBirth       time.Time `json:"dateOfBirth"`
 	PhotoUrl          string    `json:"photoUrl"`
 	WorkingExperience string    `json:"workingExperience"`
-	CreatedAt         time.Time `json:"createdAt"`
-	UpdatedAt         time.Time `json:"updatedAt"`
+}
+
+// ProfileFilter narrows down a listing of profiles. Empty fields match every
+// profile; text is compared without regard to case.
+type ProfileFilter struct {
+	Country        string
+	City           string
+	Nationality    string
+	WantedJobTitle string // matches any part of the title
+	Skill          string
+	SkillLevel     string
+	Visibility     ProfileVisibility
+
+	// Sort is created_at or updated_at. Ties are broken by public id, so
+	// the order is stable across pages.
+	Sort       string
+	Descending bool
+	After      *ProfileCursor
+	Limit      int
+}
+
+// ProfileVisibility is what a caller may list: every profile, or the ones
+// they own plus, with Consented, those whose owner consented to recruiter
+// access under TermsVersion (any version when empty).
+type ProfileVisibility struct {
+	All          bool
+	OwnerId      int
+	Consented    bool
+	TermsVersion string
+}
+
+// ProfileCursor is the position of the last profile of a page, in the order
+// the page was sorted by. Profiles sorted at the same time are told apart by
+// their public id, so cursors never carry the internal profile code.
+type ProfileCursor struct {
+	Sort       string    `json:"s"`
+	Descending bool      `json:"d"`
+	At         time.Time `json:"t"`
+	PublicId   string    `json:"p"`
 }
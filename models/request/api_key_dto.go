@@ -0,0 +1,10 @@
+package request
+
+type CreateApiKeyRequest struct {
+	Name   string   `json:"name" validate:"required,max=255"`
+	Scopes []string `json:"scopes" validate:"required,min=1,dive,oneof=profile:read profile:write resume:export job:read job:write"`
+}
+
+type RevokeApiKeyRequest struct {
+	Id int `param:"id" validate:"required"`
+}
@@ -0,0 +1,7 @@
+package request
+
+type GetAuditLogRequest struct {
+	ProfileCode int `param:"profileCode" validate:"required"`
+	Page        int `query:"page" validate:"omitempty,min=1"`
+	PageSize    int `query:"pageSize" validate:"omitempty,min=1,max=100"`
+}
@@ -0,0 +1,16 @@
+package request
+
+type GetConsentsRequest struct {
+	ProfileCode int `param:"profileCode" validate:"required"`
+}
+
+type GrantConsentRequest struct {
+	ProfileCode  int    `param:"profileCode" validate:"required"`
+	Purpose      string `json:"purpose" validate:"required,oneof=recruiter_access"`
+	TermsVersion string `json:"termsVersion" validate:"required,max=32"`
+}
+
+type RevokeConsentRequest struct {
+	ProfileCode int    `param:"profileCode" validate:"required"`
+	Purpose     string `param:"purpose" validate:"required,oneof=recruiter_access"`
+}
@@ -16,3 +16,51 @@ type DefaultGetDataByProfileCodeAndId struct {
 	ProfileCode int `param:"profileCode" validate:"required"`
 	Id          int `query:"id" validate:"required"`
 }
+
+// RestoreDataByProfileCodeAndId names the trashed row to restore in the body,
+// as echo only binds query parameters on GET and DELETE.
+type RestoreDataByProfileCodeAndId struct {
+	ProfileCode int `param:"profileCode" validate:"required"`
+	Id          int `json:"id" validate:"required"`
+}
+
+// EducationRow is one education row of a batch. A row without an end date is
+// still ongoing.
+type EducationRow struct {
+	School      string    `json:"school" validate:"required"`
+	Degree      string    `json:"degree"`
+	StartDate   time.Time `json:"startDate"`
+	EndDate     time.Time `json:"endDate" validate:"omitempty,gtefield=StartDate"`
+	City        string    `json:"city"`
+	Description string    `json:"description"`
+}
+
+// CreateEducationRowsRequest adds several education rows at once.
+type CreateEducationRowsRequest struct {
+	ProfileCode int            `param:"profileCode" validate:"required"`
+	Data        []EducationRow `json:"data" validate:"required,min=1,max=100,dive"`
+}
+
+// ReplaceEducationRowsRequest replaces every education row of the profile. An empty
+// list removes them all.
+type ReplaceEducationRowsRequest struct {
+	ProfileCode int            `param:"profileCode" validate:"required"`
+	Data        []EducationRow `json:"data" validate:"required,max=100,dive"`
+	// Version is the profile's ETag from the If-Match header.
+	Version int `json:"-"`
+}
+
+// PatchEducationRequest is a JSON Merge Patch of an education row. Members
+// left out of the patch keep their value, members set to null are cleared.
+type PatchEducationRequest struct {
+	ProfileCode int                 `param:"profileCode" validate:"required"`
+	Id          int                 `query:"id" validate:"required"`
+	School      Optional[string]    `json:"school"`
+	Degree      Optional[string]    `json:"degree"`
+	StartDate   Optional[time.Time] `json:"startDate"`
+	EndDate     Optional[time.Time] `json:"endDate"`
+	City        Optional[string]    `json:"city"`
+	Description Optional[string]    `json:"description"`
+	// Version is the ETag from the If-Match header.
+	Version int `json:"-"`
+}
@@ -5,11 +5,54 @@ import (
 )
 
 type CreateEmploymentRequest struct {
-	ProfileCode int       `param:"profileCode" validate:"required"`
-	JobTitle    string    `json:"jobTitle"`
-	Employer    string    `json:"employer"`
-	StartDate   time.Time `json:"startDate"`
-	EndDate     time.Time `json:"endDate"`
-	City        string    `json:"city"`
-	Description string    `json:"description"`
+	ProfileCode    int       `param:"profileCode" validate:"required"`
+	JobTitle       string    `json:"jobTitle"`
+	Employer       string    `json:"employer"`
+	StartDate      time.Time `json:"startDate"`
+	EndDate        time.Time `json:"endDate"`
+	City           string    `json:"city"`
+	Description    string    `json:"description"`
+	EmploymentType string    `json:"employmentType" validate:"omitempty,oneof=full_time part_time contract internship freelance"`
+}
+
+// EmploymentRow is one employment row of a batch.
+type EmploymentRow struct {
+	JobTitle       string    `json:"jobTitle"`
+	Employer       string    `json:"employer"`
+	StartDate      time.Time `json:"startDate"`
+	EndDate        time.Time `json:"endDate"`
+	City           string    `json:"city"`
+	Description    string    `json:"description"`
+	EmploymentType string    `json:"employmentType" validate:"omitempty,oneof=full_time part_time contract internship freelance"`
+}
+
+// CreateEmploymentRowsRequest adds several employment rows at once.
+type CreateEmploymentRowsRequest struct {
+	ProfileCode int             `param:"profileCode" validate:"required"`
+	Data        []EmploymentRow `json:"data" validate:"required,min=1,max=100,dive"`
+}
+
+// ReplaceEmploymentRowsRequest replaces every employment row of the profile. An empty
+// list removes them all.
+type ReplaceEmploymentRowsRequest struct {
+	ProfileCode int             `param:"profileCode" validate:"required"`
+	Data        []EmploymentRow `json:"data" validate:"required,max=100,dive"`
+	// Version is the profile's ETag from the If-Match header.
+	Version int `json:"-"`
+}
+
+// PatchEmploymentRequest is a JSON Merge Patch of an employment row. Members
+// left out of the patch keep their value, members set to null are cleared.
+type PatchEmploymentRequest struct {
+	ProfileCode    int                 `param:"profileCode" validate:"required"`
+	Id             int                 `query:"id" validate:"required"`
+	JobTitle       Optional[string]    `json:"jobTitle"`
+	Employer       Optional[string]    `json:"employer"`
+	StartDate      Optional[time.Time] `json:"startDate"`
+	EndDate        Optional[time.Time] `json:"endDate"`
+	City           Optional[string]    `json:"city"`
+	Description    Optional[string]    `json:"description"`
+	EmploymentType Optional[string]    `json:"employmentType"`
+	// Version is the ETag from the If-Match header.
+	Version int `json:"-"`
 }
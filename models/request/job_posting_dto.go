@@ -0,0 +1,41 @@
+package request
+
+// JobPostingSkillRow is a skill asked for by a job posting. An empty level
+// accepts any level.
+type JobPostingSkillRow struct {
+	Skill    string `json:"skill" validate:"required,max=255"`
+	Level    string `json:"level" validate:"max=64"`
+	Required bool   `json:"required"`
+}
+
+type CreateJobPostingRequest struct {
+	Title              string               `json:"title" validate:"required,max=255"`
+	Skills             []JobPostingSkillRow `json:"skills" validate:"required,min=1,max=50,dive"`
+	MinYearsExperience int                  `json:"minYearsExperience" validate:"min=0,max=60"`
+	City               string               `json:"city" validate:"max=255"`
+	Country            string               `json:"country" validate:"max=255"`
+}
+
+// UpdateJobPostingRequest replaces every field of a job posting.
+type UpdateJobPostingRequest struct {
+	Id                 int                  `param:"id" validate:"required"`
+	Title              string               `json:"title" validate:"required,max=255"`
+	Skills             []JobPostingSkillRow `json:"skills" validate:"required,min=1,max=50,dive"`
+	MinYearsExperience int                  `json:"minYearsExperience" validate:"min=0,max=60"`
+	City               string               `json:"city" validate:"max=255"`
+	Country            string               `json:"country" validate:"max=255"`
+}
+
+type GetJobPostingRequest struct {
+	Id int `param:"id" validate:"required"`
+}
+
+type DeleteJobPostingRequest struct {
+	Id int `param:"id" validate:"required"`
+}
+
+type MatchJobPostingRequest struct {
+	Id       int `param:"id" validate:"required"`
+	Page     int `query:"page" validate:"omitempty,min=1"`
+	PageSize int `query:"pageSize" validate:"omitempty,min=1,max=100"`
+}
@@ -0,0 +1,36 @@
+package request
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Optional is a field of a JSON Merge Patch (RFC 7396). It tells a member
+// that is absent, and so left alone, from one that is null, which clears
+// the field, and from one that is set to a value, zero or not.
+type Optional[T any] struct {
+	Value T
+	Set   bool
+	Null  bool
+}
+
+// UnmarshalJSON is only called for members present in the patch.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if bytes.Equal(data, []byte("null")) {
+		o.Null = true
+		var zero T
+		o.Value = zero
+		return nil
+	}
+	return json.Unmarshal(data, &o.Value)
+}
+
+// Apply copies a present member into dst, which is cleared for null, and
+// reports whether it did.
+func (o Optional[T]) Apply(dst *T) bool {
+	if o.Set {
+		*dst = o.Value
+	}
+	return o.Set
+}
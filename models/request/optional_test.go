@@ -0,0 +1,36 @@
+package request
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptional(t *testing.T) {
+	var patch PatchProfileRequest
+	err := json.Unmarshal([]byte(`{"lastName": null, "postalCode": 0, "city": "Bandung"}`), &patch)
+	assert.Nil(t, err)
+
+	assert.False(t, patch.FirstName.Set, "absent members are not set")
+	assert.True(t, patch.LastName.Set)
+	assert.True(t, patch.LastName.Null)
+	assert.True(t, patch.PostalCode.Set)
+	assert.False(t, patch.PostalCode.Null)
+	assert.Equal(t, "Bandung", patch.City.Value)
+
+	lastName, postalCode, firstName := "Doe", 40115, "John"
+	assert.True(t, patch.LastName.Apply(&lastName))
+	assert.Equal(t, "", lastName)
+	assert.True(t, patch.PostalCode.Apply(&postalCode))
+	assert.Equal(t, 0, postalCode)
+	assert.False(t, patch.FirstName.Apply(&firstName))
+	assert.Equal(t, "John", firstName)
+}
+
+func TestNullRequired(t *testing.T) {
+	var patch PatchProfileRequest
+	err := json.Unmarshal([]byte(`{"lastName": null, "phone": null, "email": null}`), &patch)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"email", "phone"}, patch.NullRequired())
+}
@@ -0,0 +1,11 @@
+package request
+
+type DataExportRequest struct {
+	ProfileCode int `param:"profileCode" validate:"required"`
+}
+
+type EraseProfileRequest struct {
+	ProfileCode int    `param:"profileCode" validate:"required"`
+	Mode        string `json:"mode" validate:"required,oneof=anonymise delete"`
+	Reason      string `json:"reason" validate:"max=500"`
+}
@@ -1,16 +1,42 @@
 package request
 
-import "time"
+import (
+	"sort"
+	"time"
+)
 
 type UploadPhotoRequest struct {
 	ProfileCode int    `param:"profileCode" validate:"required"`
 	Base64Img   string `json:"base64img"`
+	// Version is the ETag from the If-Match header.
+	Version int `json:"-"`
 }
 
 type GetProfileRequest struct {
 	ProfileCode int `param:"profileCode" validate:"required"`
 }
 
+type GetAvatarRequest struct {
+	ProfileCode int    `param:"profileCode" validate:"required"`
+	Format      string `query:"format" validate:"omitempty,oneof=png svg"`
+}
+
+// ListProfilesRequest filters, sorts and pages through profiles. Cursor is
+// the nextCursor of the previous page and only works with the same sort
+// and order.
+type ListProfilesRequest struct {
+	Country        string `query:"country"`
+	City           string `query:"city"`
+	Nationality    string `query:"nationality"`
+	WantedJobTitle string `query:"wanted_job_title"`
+	Skill          string `query:"skill"`
+	SkillLevel     string `query:"skill_level"`
+	Sort           string `query:"sort" validate:"omitempty,oneof=created_at updated_at"`
+	Order          string `query:"order" validate:"omitempty,oneof=asc desc"`
+	Cursor         string `query:"cursor"`
+	Limit          int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
 type CreateProfileRequest struct {
 	WantedJobTitle string    `json:"wantedJobTitle"`
 	FirstName      string    `json:"firstName"`
@@ -43,4 +69,49 @@ type UpdateProfileRequest struct {
 	PlaceOfBirth      string    `json:"placeOfBirth"`
 	DateOfBirth       time.Time `json:"dateOfBirth"`
 	WorkingExperience string    `json:"workingExperience"`
+	// Version is the ETag from the If-Match header.
+	Version int `json:"-"`
+}
+
+// PatchProfileRequest is a JSON Merge Patch of a profile. Members left out
+// of the patch keep their value, members set to null are cleared.
+type PatchProfileRequest struct {
+	ProfileCode       int                 `param:"profileCode" validate:"required"`
+	WantedJobTitle    Optional[string]    `json:"wantedJobTitle"`
+	FirstName         Optional[string]    `json:"firstName"`
+	LastName          Optional[string]    `json:"lastName"`
+	Email             Optional[string]    `json:"email"`
+	Phone             Optional[string]    `json:"phone"`
+	Country           Optional[string]    `json:"country"`
+	City              Optional[string]    `json:"city"`
+	Address           Optional[string]    `json:"address"`
+	PostalCode        Optional[int]       `json:"postalCode"`
+	DrivingLicense    Optional[string]    `json:"drivingLicense"`
+	Nationality       Optional[string]    `json:"nationality"`
+	PlaceOfBirth      Optional[string]    `json:"placeOfBirth"`
+	DateOfBirth       Optional[time.Time] `json:"dateOfBirth"`
+	WorkingExperience Optional[string]    `json:"workingExperience"`
+	// Version is the ETag from the If-Match header.
+	Version int `json:"-"`
+}
+
+// NullRequired lists the members set to null that a profile cannot do
+// without, as their columns are NOT NULL.
+func (r PatchProfileRequest) NullRequired() []string {
+	var members []string
+	for member, field := range map[string]Optional[string]{
+		"wantedJobTitle": r.WantedJobTitle,
+		"firstName":      r.FirstName,
+		"email":          r.Email,
+		"phone":          r.Phone,
+		"country":        r.Country,
+		"city":           r.City,
+		"address":        r.Address,
+	} {
+		if field.Null {
+			members = append(members, member)
+		}
+	}
+	sort.Strings(members)
+	return members
 }
@@ -0,0 +1,32 @@
+package request
+
+type GetResumeRequest struct {
+	ProfileCode int `param:"profileCode" validate:"required"`
+}
+
+type SaveResumeVersionRequest struct {
+	ProfileCode int    `param:"profileCode" validate:"required"`
+	Label       string `json:"label" validate:"max=255"`
+}
+
+type GetResumeVersionRequest struct {
+	ProfileCode int `param:"profileCode" validate:"required"`
+	Version     int `param:"version" validate:"required,min=1"`
+}
+
+type DiffResumeVersionsRequest struct {
+	ProfileCode int `param:"profileCode" validate:"required"`
+	From        int `query:"from" validate:"required,min=1"`
+	To          int `query:"to" validate:"required,min=1"`
+}
+
+type RestoreResumeVersionRequest struct {
+	ProfileCode int `param:"profileCode" validate:"required"`
+	Version     int `param:"version" validate:"required,min=1"`
+}
+
+// AtsCheckRequest is a job description pasted by the candidate.
+type AtsCheckRequest struct {
+	ProfileCode    int    `param:"profileCode" validate:"required"`
+	JobDescription string `json:"jobDescription" validate:"required,max=20000"`
+}
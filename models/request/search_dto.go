@@ -0,0 +1,10 @@
+package request
+
+// SearchRequest is a full text search over profiles. Query takes web search
+// syntax: quoted phrases, "or" and a leading - to exclude a word.
+type SearchRequest struct {
+	Query    string `query:"q" validate:"required,max=200"`
+	Language string `query:"lang" validate:"omitempty,oneof=en id"`
+	Page     int    `query:"page" validate:"omitempty,min=1"`
+	PageSize int    `query:"pageSize" validate:"omitempty,min=1,max=100"`
+}
@@ -5,3 +5,41 @@ type CreateSkillRequest struct {
 	Skill       string `json:"skill"`
 	Level       string `json:"level"`
 }
+
+// SkillRow is one skill of a batch.
+type SkillRow struct {
+	Skill string `json:"skill"`
+	Level string `json:"level"`
+}
+
+// CreateSkillsRequest adds several skill rows at once.
+type CreateSkillsRequest struct {
+	ProfileCode int        `param:"profileCode" validate:"required"`
+	Data        []SkillRow `json:"data" validate:"required,min=1,max=100"`
+}
+
+// ReplaceSkillsRequest replaces every skill row of the profile. An empty
+// list removes them all.
+type ReplaceSkillsRequest struct {
+	ProfileCode int        `param:"profileCode" validate:"required"`
+	Data        []SkillRow `json:"data" validate:"required,max=100"`
+	// Version is the profile's ETag from the If-Match header.
+	Version int `json:"-"`
+}
+
+// PatchSkillRequest is a JSON Merge Patch of a skill row. Members left out
+// of the patch keep their value, members set to null are cleared.
+type PatchSkillRequest struct {
+	ProfileCode int              `param:"profileCode" validate:"required"`
+	Id          int              `query:"id" validate:"required"`
+	Skill       Optional[string] `json:"skill"`
+	Level       Optional[string] `json:"level"`
+	// Version is the ETag from the If-Match header.
+	Version int `json:"-"`
+}
+
+// SuggestSkillsRequest asks for at most Limit skills matching Query.
+type SuggestSkillsRequest struct {
+	Query string `query:"q" validate:"required,max=100"`
+	Limit int    `query:"limit" validate:"omitempty,min=1,max=50"`
+}
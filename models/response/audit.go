@@ -0,0 +1,25 @@
+package response
+
+import "time"
+
+// AuditLogResponse names the changed row by EntityId, or by EntityPublicId
+// for profile entries.
+type AuditLogResponse struct {
+	Id             int                            `json:"id"`
+	ActorId        int                            `json:"actorId"`
+	ApiKeyId       int                            `json:"apiKeyId,omitempty"`
+	Action         string                         `json:"action"`
+	Entity         string                         `json:"entity"`
+	EntityId       int                            `json:"entityId,omitempty"`
+	EntityPublicId string                         `json:"entityPublicId,omitempty"`
+	Changes        map[string]FieldChangeResponse `json:"changes"`
+	RequestId      string                         `json:"requestId,omitempty"`
+	CreatedAt      time.Time                      `json:"createdAt"`
+}
+
+type AuditLogList struct {
+	Data     []*AuditLogResponse `json:"data"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"pageSize"`
+	Total    int                 `json:"total"`
+}
@@ -1,10 +1,30 @@
 package response
 
+import "encoding/json"
+
 type DefaultResponse struct {
-	ProfileCode int `json:"profileCode"`
+	ProfileCode int    `json:"-"`
+	PublicId    string `json:"publicId,omitempty"`
+	Version     int    `json:"version,omitempty"`
 }
 
 type DefaultResponseWithId struct {
-	ProfileCode int `json:"profileCode"`
+	ProfileCode int `json:"-"`
 	Id          int `json:"id"`
+	Version     int `json:"version,omitempty"`
+}
+
+// DefaultResponseWithIds lists the ids of rows created together, in the
+// order they were sent.
+type DefaultResponseWithIds struct {
+	ProfileCode int   `json:"-"`
+	Ids         []int `json:"ids"`
+	// Version is the profile's version after a replace, sent as the ETag.
+	Version int `json:"-"`
+}
+
+// FieldChangeResponse is the before and after value of one field, as JSON.
+type FieldChangeResponse struct {
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
 }
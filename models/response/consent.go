@@ -0,0 +1,16 @@
+package response
+
+import "time"
+
+type ConsentResponse struct {
+	Id           int        `json:"id"`
+	Purpose      string     `json:"purpose"`
+	TermsVersion string     `json:"termsVersion"`
+	GrantedAt    time.Time  `json:"grantedAt"`
+	RevokedAt    *time.Time `json:"revokedAt"`
+	Active       bool       `json:"active"`
+}
+
+type ConsentList struct {
+	Data []*ConsentResponse `json:"data"`
+}
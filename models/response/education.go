@@ -5,13 +5,15 @@ import (
 )
 
 type EducationResponse struct {
-	Id          int       `json:"id"`
-	School      string    `json:"school"`
-	Degree      string    `json:"degree"`
-	StartDate   time.Time `json:"startDate"`
-	EndDate     time.Time `json:"endDate"`
-	City        string    `json:"city"`
-	Description string    `json:"description"`
+	Id          int        `json:"id"`
+	School      string     `json:"school"`
+	Degree      string     `json:"degree"`
+	StartDate   time.Time  `json:"startDate"`
+	EndDate     time.Time  `json:"endDate"`
+	City        string     `json:"city"`
+	Description string     `json:"description"`
+	Version     int        `json:"version"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty"`
 }
 type EducationList struct {
 	Data []*EducationResponse `json:"data"`
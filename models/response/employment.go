@@ -5,15 +5,56 @@ import (
 )
 
 type EmploymentResponse struct {
-	Id          int       `json:"id"`
-	JobTitle    string    `json:"jobTitle"`
-	Employer    string    `json:"employer"`
-	StartDate   time.Time `json:"startDate"`
-	EndDate     time.Time `json:"endDate"`
-	City        string    `json:"city"`
-	Description string    `json:"description"`
+	Id             int        `json:"id"`
+	JobTitle       string     `json:"jobTitle"`
+	Employer       string     `json:"employer"`
+	StartDate      time.Time  `json:"startDate"`
+	EndDate        time.Time  `json:"endDate"`
+	City           string     `json:"city"`
+	Description    string     `json:"description"`
+	EmploymentType string     `json:"employmentType"`
+	Version        int        `json:"version"`
+	DeletedAt      *time.Time `json:"deletedAt,omitempty"`
 }
 
 type EmploymentList struct {
 	Data []*EmploymentResponse `json:"data"`
 }
+
+// EmploymentTimelineResponse sums up the working history of a profile. Time
+// spent in jobs held at once is only counted once.
+type EmploymentTimelineResponse struct {
+	ProfileCode     int                          `json:"-"`
+	TotalExperience ExperienceResponse           `json:"totalExperience"`
+	ByType          []*TypeExperienceResponse    `json:"byType"`
+	Gaps            []*EmploymentGapResponse     `json:"gaps"`
+	Overlaps        []*EmploymentOverlapResponse `json:"overlaps"`
+}
+
+type ExperienceResponse struct {
+	Days  int     `json:"days"`
+	Years float64 `json:"years"`
+}
+
+type TypeExperienceResponse struct {
+	EmploymentType string  `json:"employmentType"`
+	Days           int     `json:"days"`
+	Years          float64 `json:"years"`
+}
+
+// EmploymentGapResponse is a break between jobs longer than the gap
+// threshold. Current is set when the break lasts until today.
+type EmploymentGapResponse struct {
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	Days    int       `json:"days"`
+	Current bool      `json:"current"`
+}
+
+// EmploymentOverlapResponse is the time two jobs were held at once.
+type EmploymentOverlapResponse struct {
+	EmploymentIds []int     `json:"employmentIds"`
+	From          time.Time `json:"from"`
+	To            time.Time `json:"to"`
+	Days          int       `json:"days"`
+}
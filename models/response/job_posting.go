@@ -0,0 +1,58 @@
+package response
+
+import "time"
+
+type JobPostingSkillResponse struct {
+	Skill    string `json:"skill"`
+	Level    string `json:"level"`
+	Required bool   `json:"required"`
+}
+
+type JobPostingResponse struct {
+	Id                 int                        `json:"id"`
+	OwnerId            int                        `json:"ownerId"`
+	Title              string                     `json:"title"`
+	Skills             []*JobPostingSkillResponse `json:"skills"`
+	MinYearsExperience int                        `json:"minYearsExperience"`
+	City               string                     `json:"city"`
+	Country            string                     `json:"country"`
+	CreatedAt          time.Time                  `json:"createdAt"`
+	UpdatedAt          *time.Time                 `json:"updatedAt"`
+}
+
+type JobPostingList struct {
+	Data []*JobPostingResponse `json:"data"`
+}
+
+// MatchCriterionResponse explains how a candidate did on one criterion of a
+// job posting. Matched is only true when it earned every point.
+type MatchCriterionResponse struct {
+	Criterion string  `json:"criterion"`
+	Name      string  `json:"name"`
+	Matched   bool    `json:"matched"`
+	Points    float64 `json:"points"`
+	MaxPoints float64 `json:"maxPoints"`
+	Detail    string  `json:"detail"`
+}
+
+// JobMatchResponse is a candidate scored against a job posting, out of 100.
+type JobMatchResponse struct {
+	ProfileCode       int                       `json:"-"`
+	PublicId          string                    `json:"publicId"`
+	WantedJobTitle    string                    `json:"wantedJobTitle"`
+	FirstName         string                    `json:"firstName"`
+	LastName          string                    `json:"lastName"`
+	City              string                    `json:"city"`
+	Country           string                    `json:"country"`
+	Score             float64                   `json:"score"`
+	YearsOfExperience float64                   `json:"yearsOfExperience"`
+	Criteria          []*MatchCriterionResponse `json:"criteria"`
+}
+
+type JobMatchList struct {
+	JobPostingId int                 `json:"jobPostingId"`
+	Data         []*JobMatchResponse `json:"data"`
+	Page         int                 `json:"page"`
+	PageSize     int                 `json:"pageSize"`
+	Total        int                 `json:"total"`
+}
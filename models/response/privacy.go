@@ -0,0 +1,33 @@
+package response
+
+import "time"
+
+// DataExport is the zip archive handed to a data subject.
+type DataExport struct {
+	FileName string
+	Content  []byte
+}
+
+// ProfileDataResponse is the profile as exported to its data subject, with
+// the working experience the profile response leaves out.
+type ProfileDataResponse struct {
+	*CreateProfileResponse
+	WorkingExperience string `json:"workingExperience"`
+}
+
+type ErasureReceiptResponse struct {
+	ReceiptId         string    `json:"receiptId"`
+	ProfilePublicId   string    `json:"profilePublicId"`
+	Mode              string    `json:"mode"`
+	Reason            string    `json:"reason"`
+	RequestedBy       int       `json:"requestedBy"`
+	ProfileRows       int       `json:"profileRows"`
+	EducationRows     int       `json:"educationRows"`
+	EmploymentRows    int       `json:"employmentRows"`
+	SkillRows         int       `json:"skillRows"`
+	ConsentRows       int       `json:"consentRows"`
+	AuditRows         int       `json:"auditRows"`
+	ResumeVersionRows int       `json:"resumeVersionRows"`
+	PhotoRemoved      bool      `json:"photoRemoved"`
+	ErasedAt          time.Time `json:"erasedAt"`
+}
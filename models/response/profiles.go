@@ -5,7 +5,8 @@ import (
 )
 
 type CreateProfileResponse struct {
-	ProfileCode    int       `json:"profileCode"`
+	ProfileCode    int       `json:"-"`
+	PublicId       string    `json:"publicId"`
 	WantedJobTitle string    `json:"wantedJobTitle"`
 	FirstName      string    `json:"firstName"`
 	LastName       string    `json:"lastName"`
@@ -20,13 +21,36 @@ type CreateProfileResponse struct {
 	PlaceOfBirth   string    `json:"placeOfBirth"`
 	DateOfBirth    time.Time `json:"dateOfBirth"`
 	PhotoUrl       string    `json:"photoUrl"`
+	Version        int       `json:"version"`
+}
+
+// ProfileSummaryResponse is a profile in a listing, without its contact
+// details.
+type ProfileSummaryResponse struct {
+	ProfileCode    int       `json:"-"`
+	PublicId       string    `json:"publicId"`
+	WantedJobTitle string    `json:"wantedJobTitle"`
+	FirstName      string    `json:"firstName"`
+	LastName       string    `json:"lastName"`
+	Country        string    `json:"country"`
+	City           string    `json:"city"`
+	Nationality    string    `json:"nationality"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// ProfileList is a page of profiles. NextCursor is empty on the last page.
+type ProfileList struct {
+	Data       []*ProfileSummaryResponse `json:"data"`
+	NextCursor string                    `json:"nextCursor,omitempty"`
 }
 
 type WorkingExperiencesResponse struct {
 	WorkingExperience string `json:"workingExperience"`
+	Version           int    `json:"version"`
 }
 
 type UploadPhotoResponse struct {
-	ProfileCode int    `json:"profileCode"`
+	ProfileCode int    `json:"-"`
 	PhotoUrl    string `json:"photoUrl"`
 }
@@ -0,0 +1,84 @@
+package response
+
+import "time"
+
+type ResumeResponse struct {
+	Profile           *CreateProfileResponse      `json:"profile"`
+	WorkingExperience string                      `json:"workingExperience"`
+	Education         []*EducationResponse        `json:"education"`
+	Employment        []*EmploymentResponse       `json:"employment"`
+	Skills            []*SkillResponse            `json:"skills"`
+	Timeline          *EmploymentTimelineResponse `json:"timeline,omitempty"`
+}
+
+type ResumeVersionResponse struct {
+	Version   int             `json:"version"`
+	Label     string          `json:"label"`
+	Automatic bool            `json:"automatic"`
+	CreatedBy int             `json:"createdBy"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Resume    *ResumeResponse `json:"resume,omitempty"`
+}
+
+type ResumeVersionList struct {
+	Data []*ResumeVersionResponse `json:"data"`
+}
+
+// ResumeDiffResponse lists what changed between two versions. Child rows are
+// matched by id and reported as added, removed or modified.
+type ResumeDiffResponse struct {
+	From       int                            `json:"from"`
+	To         int                            `json:"to"`
+	Profile    map[string]FieldChangeResponse `json:"profile"`
+	Education  []*RowDiffResponse             `json:"education"`
+	Employment []*RowDiffResponse             `json:"employment"`
+	Skills     []*RowDiffResponse             `json:"skills"`
+}
+
+type RowDiffResponse struct {
+	Id     int                            `json:"id"`
+	Change string                         `json:"change"`
+	Fields map[string]FieldChangeResponse `json:"fields"`
+}
+
+// AtsKeywordResponse is a keyword of the job description. FoundIn names the
+// parts of the resume holding it: skills, employment or education.
+type AtsKeywordResponse struct {
+	Keyword     string   `json:"keyword"`
+	Occurrences int      `json:"occurrences"`
+	FoundIn     []string `json:"foundIn"`
+}
+
+// AtsCheckResponse tells which keywords of a job description the resume
+// covers. Coverage is the percentage of keywords found.
+type AtsCheckResponse struct {
+	ProfileCode int                   `json:"-"`
+	Coverage    float64               `json:"coverage"`
+	Found       []*AtsKeywordResponse `json:"found"`
+	Missing     []*AtsKeywordResponse `json:"missing"`
+}
+
+// ResumeScoreResponse rates how complete a resume is, out of 100, and lists
+// what would raise the score most first.
+type ResumeScoreResponse struct {
+	ProfileCode int                         `json:"-"`
+	Score       int                         `json:"score"`
+	MaxScore    int                         `json:"maxScore"`
+	Sections    []*ResumeSectionScore       `json:"sections"`
+	Suggestions []*ResumeSuggestionResponse `json:"suggestions"`
+}
+
+type ResumeSectionScore struct {
+	Section  string  `json:"section"`
+	Score    float64 `json:"score"`
+	MaxScore float64 `json:"maxScore"`
+}
+
+// ResumeSuggestionResponse is a change to the resume and the points it is
+// worth.
+type ResumeSuggestionResponse struct {
+	Priority string  `json:"priority"`
+	Section  string  `json:"section"`
+	Message  string  `json:"message"`
+	Points   float64 `json:"points"`
+}
@@ -0,0 +1,21 @@
+package response
+
+// SearchResultResponse is a matching profile. Snippet is HTML: the matched
+// words are wrapped in <mark> and everything else is escaped.
+type SearchResultResponse struct {
+	ProfileCode    int     `json:"-"`
+	PublicId       string  `json:"publicId"`
+	WantedJobTitle string  `json:"wantedJobTitle"`
+	FirstName      string  `json:"firstName"`
+	LastName       string  `json:"lastName"`
+	City           string  `json:"city"`
+	Country        string  `json:"country"`
+	Rank           float64 `json:"rank"`
+	Snippet        string  `json:"snippet"`
+}
+
+type SearchResultList struct {
+	Data     []*SearchResultResponse `json:"data"`
+	Page     int                     `json:"page"`
+	PageSize int                     `json:"pageSize"`
+}
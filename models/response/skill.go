@@ -1,11 +1,30 @@
 package response
 
+import (
+	"time"
+)
+
 type SkillResponse struct {
-	Id    int    `json:"id"`
-	Skill string `json:"skill"`
-	Level string `json:"level"`
+	Id        int        `json:"id"`
+	Skill     string     `json:"skill"`
+	Level     string     `json:"level"`
+	Version   int        `json:"version"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
 }
 
 type SkillList struct {
 	Data []*SkillResponse `json:"data"`
 }
+
+// SkillSuggestionResponse is a skill of the taxonomy. Alias is the spelling
+// the query matched when it was not the name.
+type SkillSuggestionResponse struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Alias    string `json:"alias,omitempty"`
+}
+
+type SkillSuggestionList struct {
+	TaxonomyVersion string                     `json:"taxonomyVersion"`
+	Data            []*SkillSuggestionResponse `json:"data"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// ResumeSnapshot is a whole resume at one point in time: the profile with
+// its working experience and every education, employment and skill row.
+type ResumeSnapshot struct {
+	Profile    *ProfileDTO      `json:"profile"`
+	Education  []*EducationDTO  `json:"education"`
+	Employment []*EmploymentDTO `json:"employment"`
+	Skills     []*SkillDTO      `json:"skills"`
+}
+
+// ResumeVersion is a stored snapshot. Versions are numbered per profile,
+// starting at 1. Automatic versions are taken after every change; the others
+// were saved explicitly and may carry a label.
+type ResumeVersion struct {
+	bun.BaseModel `bun:"table:resume_versions"`
+
+	Id          int       `bun:"id,pk,type:int,autoincrement"`
+	ProfileCode int       `bun:"profile_code,notnull"`
+	Version     int       `bun:"version,notnull"`
+	Label       string    `bun:"label"`
+	Automatic   bool      `bun:"automatic,notnull"`
+	Checksum    string    `bun:"checksum,notnull"`
+	Snapshot    string    `bun:"snapshot,notnull"`
+	CreatedBy   int       `bun:"created_by,nullzero"`
+	CreatedAt   time.Time `bun:"created_at,notnull"`
+}
+
+type ResumeVersionDTO struct {
+	Id          int             `json:"id"`
+	ProfileCode int             `json:"profileCode"`
+	Version     int             `json:"version"`
+	Label       string          `json:"label"`
+	Automatic   bool            `json:"automatic"`
+	Checksum    string          `json:"-"`
+	Snapshot    *ResumeSnapshot `json:"snapshot,omitempty"`
+	CreatedBy   int             `json:"createdBy"`
+	CreatedAt   time.Time       `json:"createdAt"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import "github.com/uptrace/bun"
+
+const (
+	SearchLanguageEnglish    = "en"
+	SearchLanguageIndonesian = "id"
+)
+
+// ProfileSearchIndex is the full text search row of a profile. The database
+// keeps it up to date with triggers, so it is only ever read.
+type ProfileSearchIndex struct {
+	bun.BaseModel `bun:"table:profile_search,alias:ps"`
+
+	ProfileCode int    `bun:"profile_code,pk"`
+	Document    string `bun:"document"`
+}
+
+// ProfileSearch is a full text query over the profiles a caller may read.
+type ProfileSearch struct {
+	Query      string
+	Language   string
+	Visibility ProfileVisibility
+	Limit      int
+	Offset     int
+}
+
+type SearchResultDTO struct {
+	ProfileCode    int
+	PublicId       string
+	WantedJobTitle string
+	FirstName      string
+	LastName       string
+	City           string
+	Country        string
+	Rank           float64
+	Snippet        string
+}
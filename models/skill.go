@@ -1,11 +1,40 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"github.com/uptrace/bun"
 )
 
+// The levels of a skill, from lowest to highest. Rows may also leave it
+// empty.
+const (
+	SkillLevelBeginner     = "beginner"
+	SkillLevelIntermediate = "intermediate"
+	SkillLevelAdvanced     = "advanced"
+	SkillLevelExpert       = "expert"
+)
+
+// SkillLevels lists every skill level, lowest first.
+var SkillLevels = []string{SkillLevelBeginner, SkillLevelIntermediate, SkillLevelAdvanced, SkillLevelExpert}
+
+// NormalizeSkillLevel returns the level as listed in SkillLevels, ignoring
+// case and surrounding spaces, and false when it is not a skill level. Empty
+// levels stay empty.
+func NormalizeSkillLevel(level string) (string, bool) {
+	level = strings.ToLower(strings.TrimSpace(level))
+	if level == "" {
+		return "", true
+	}
+	for _, l := range SkillLevels {
+		if l == level {
+			return l, true
+		}
+	}
+	return level, false
+}
+
 type Skill struct {
 	bun.BaseModel `bun:"table:skill"`
 
@@ -14,12 +43,29 @@ type Skill struct {
 	Skill       string    `bun:"skill"`
 	Level       string    `bun:"level"`
 	CreatedAt   time.Time `bun:"created_at,default:current_timestamp"`
+	Version     int       `bun:"version,notnull,default:1"`
+	DeletedAt   time.Time `bun:"deleted_at,soft_delete,nullzero"`
 }
 
 type SkillDTO struct {
-	ProfileCode int       `json:"profileCode"`
-	Id          int       `json:"id"`
-	Skill       string    `json:"skill"`
-	Level       string    `json:"level"`
-	CreatedAt   time.Time `json:"createdAt"`
+	ProfileCode int        `json:"profileCode"`
+	Id          int        `json:"id"`
+	Skill       string     `json:"skill"`
+	Level       string     `json:"level"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	Version     int        `json:"-"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty"`
+}
+
+// SkillBackfill counts what rewriting the stored skill rows to the taxonomy
+// did. Unrecognized rows have a skill missing from the taxonomy, rows with an
+// invalid level keep it, and skipped rows were changed or purged while the
+// backfill ran.
+type SkillBackfill struct {
+	TaxonomyVersion string
+	Checked         int
+	Updated         int
+	Skipped         int
+	Unrecognized    int
+	InvalidLevels   int
 }
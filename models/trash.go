@@ -0,0 +1,11 @@
+package models
+
+// TrashPurge counts what a purge of the trash removed for good. Profiles are
+// erased like on a data subject request, so each one also has a receipt.
+type TrashPurge struct {
+	Profiles   int
+	Education  int
+	Employment int
+	Skills     int
+	ReceiptIds []string
+}
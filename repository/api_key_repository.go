@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"test-bpjs/v2/models"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type ApiKeyRepository interface {
+	GetApiKeysByUser(ctx context.Context, userId int) ([]*models.ApiKeyDTO, error)
+	GetActiveApiKeyByHash(ctx context.Context, hash string) (*models.ApiKeyDTO, error)
+	CreateApiKey(ctx context.Context, payload *models.ApiKey) (*models.ApiKeyDTO, error)
+	RevokeApiKey(ctx context.Context, userId, id int) error
+	TouchApiKey(ctx context.Context, id int, usedAt time.Time) error
+}
+
+type apiKeyRepository struct {
+	DB bun.IDB
+}
+
+func NewApiKeyRepository(db bun.IDB) *apiKeyRepository {
+	return &apiKeyRepository{
+		DB: db,
+	}
+}
+
+func (a *apiKeyRepository) GetApiKeysByUser(ctx context.Context, userId int) ([]*models.ApiKeyDTO, error) {
+	var apiKeys []*models.ApiKeyDTO
+	err := a.DB.NewSelect().
+		Model((*models.ApiKey)(nil)).
+		Column("id", "user_id", "name", "prefix", "scopes", "created_at", "last_used_at", "revoked_at").
+		Where("user_id = ?", userId).
+		Order("id").
+		Scan(ctx, &apiKeys)
+	return apiKeys, err
+}
+
+// GetActiveApiKeyByHash returns a key that has not been revoked, together with
+// the role of the user it belongs to.
+func (a *apiKeyRepository) GetActiveApiKeyByHash(ctx context.Context, hash string) (*models.ApiKeyDTO, error) {
+	var apiKey models.ApiKeyDTO
+	err := a.DB.NewSelect().
+		Model((*models.ApiKey)(nil)).
+		Column("api_key.id", "api_key.user_id", "api_key.name", "api_key.prefix", "api_key.scopes").
+		ColumnExpr("u.role").
+		Join("JOIN users AS u ON u.id = api_key.user_id").
+		Where("api_key.key_hash = ?", hash).
+		Where("api_key.revoked_at IS NULL").
+		Scan(ctx, &apiKey)
+	return &apiKey, err
+}
+
+func (a *apiKeyRepository) CreateApiKey(ctx context.Context, payload *models.ApiKey) (*models.ApiKeyDTO, error) {
+	var apiKey models.ApiKeyDTO
+	_, err := a.DB.NewInsert().
+		Model(payload).
+		Returning("id, created_at").
+		Exec(ctx, &apiKey)
+	return &apiKey, err
+}
+
+// RevokeApiKey returns ErrNotFound when the user has no such key, or it is
+// already revoked.
+func (a *apiKeyRepository) RevokeApiKey(ctx context.Context, userId, id int) error {
+	res, err := a.DB.NewUpdate().
+		Model((*models.ApiKey)(nil)).
+		Set("revoked_at = ?", time.Now()).
+		Where("user_id = ?", userId).
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	return affected(res, err)
+}
+
+func (a *apiKeyRepository) TouchApiKey(ctx context.Context, id int, usedAt time.Time) error {
+	_, err := a.DB.NewUpdate().
+		Model((*models.ApiKey)(nil)).
+		Set("last_used_at = ?", usedAt).
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
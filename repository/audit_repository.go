@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"test-bpjs/v2/helper/fieldcrypt"
+	"test-bpjs/v2/models"
+
+	"github.com/uptrace/bun"
+)
+
+type AuditRepository interface {
+	CreateAuditLog(ctx context.Context, entry *models.AuditLog) error
+	GetAuditLogsByProfileCode(ctx context.Context, code, limit, offset int) ([]*models.AuditLogDTO, int, error)
+	RotateAuditLogKeys(ctx context.Context) (int, error)
+}
+
+// auditRepository encrypts the changes of every entry, since they hold the
+// same personal data as the rows they describe.
+type auditRepository struct {
+	DB     bun.IDB
+	cipher *fieldcrypt.Cipher
+}
+
+func NewAuditRepository(db bun.IDB, cipher *fieldcrypt.Cipher) *auditRepository {
+	return &auditRepository{
+		DB:     db,
+		cipher: cipher,
+	}
+}
+
+func (a *auditRepository) CreateAuditLog(ctx context.Context, entry *models.AuditLog) error {
+	sealed := *entry
+	if sealed.Changes != "" {
+		changes, err := a.cipher.Encrypt(sealed.Changes)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt changes: %v", err)
+		}
+		sealed.Changes = changes
+	}
+	_, err := a.DB.NewInsert().
+		Model(&sealed).
+		Returning("id").
+		Exec(ctx, &entry.Id)
+	return err
+}
+
+// GetAuditLogsByProfileCode returns a page of entries, newest first, and the
+// total number of entries. A limit of 0 returns every entry.
+func (a *auditRepository) GetAuditLogsByProfileCode(ctx context.Context, code, limit, offset int) ([]*models.AuditLogDTO, int, error) {
+	var entries []models.AuditLog
+	total, err := a.DB.NewSelect().
+		Model(&entries).
+		Column("id", "profile_code", "actor_id", "api_key_id", "action", "entity", "entity_id", "changes", "request_id", "created_at").
+		// the raw subquery also finds profiles in the trash
+		ColumnExpr("(SELECT public_id FROM profile WHERE profile.profile_code = audit_log.profile_code) AS profile_public_id").
+		Where("profile_code = ?", code).
+		Order("id DESC").
+		Limit(limit).
+		Offset(offset).
+		ScanAndCount(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	auditLogs := make([]*models.AuditLogDTO, 0, len(entries))
+	for _, entry := range entries {
+		auditLog, err := a.open(entry)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read audit log %d: %v", entry.Id, err)
+		}
+		auditLogs = append(auditLogs, auditLog)
+	}
+	return auditLogs, total, nil
+}
+
+// RotateAuditLogKeys re-wraps the changes sealed with an older key. It
+// returns how many entries were updated.
+func (a *auditRepository) RotateAuditLogKeys(ctx context.Context) (int, error) {
+	updated, lastId := 0, 0
+	for {
+		var entries []models.AuditLog
+		err := a.DB.NewSelect().
+			Model(&entries).
+			Column("id", "changes").
+			Where("id > ?", lastId).
+			Where("changes IS NOT NULL").
+			Order("id").
+			Limit(rotateBatchSize).
+			Scan(ctx)
+		if err != nil {
+			return updated, err
+		}
+		if len(entries) == 0 {
+			return updated, nil
+		}
+
+		for i := range entries {
+			entry := &entries[i]
+			lastId = entry.Id
+
+			changed, err := a.rotate(entry)
+			if err != nil {
+				return updated, fmt.Errorf("failed to rotate audit log %d: %v", entry.Id, err)
+			}
+			if !changed {
+				continue
+			}
+			_, err = a.DB.NewUpdate().
+				Model(entry).
+				Column("changes").
+				WherePK().
+				Exec(ctx)
+			if err != nil {
+				return updated, err
+			}
+			updated++
+		}
+	}
+}
+
+func (a *auditRepository) rotate(entry *models.AuditLog) (bool, error) {
+	changes, changed, err := a.cipher.Rotate(entry.Changes)
+	if err != nil {
+		return false, err
+	}
+	entry.Changes = changes
+	return changed, nil
+}
+
+func (a *auditRepository) open(entry models.AuditLog) (*models.AuditLogDTO, error) {
+	auditLog := &models.AuditLogDTO{
+		Id:              entry.Id,
+		ProfileCode:     entry.ProfileCode,
+		ProfilePublicId: entry.ProfilePublicId,
+		ActorId:         entry.ActorId,
+		ApiKeyId:        entry.ApiKeyId,
+		Action:          entry.Action,
+		Entity:          entry.Entity,
+		EntityId:        entry.EntityId,
+		RequestId:       entry.RequestId,
+		CreatedAt:       entry.CreatedAt,
+	}
+	// redacted entries have no changes left
+	if entry.Changes == "" {
+		return auditLog, nil
+	}
+	changes, err := a.cipher.Decrypt(entry.Changes)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(changes), &auditLog.Changes); err != nil {
+		return nil, fmt.Errorf("failed to decode changes: %v", err)
+	}
+	return auditLog, nil
+}
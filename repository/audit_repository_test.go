@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"test-bpjs/v2/helper/fieldcrypt"
+	"test-bpjs/v2/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAuditLog(t *testing.T) {
+	repo := NewAuditRepository(nil, testCipher(t, "k1", "k1"))
+
+	sealed, err := repo.cipher.Encrypt(`{"email":{"before":"old@example.com","after":"new@example.com"}}`)
+	assert.Nil(t, err)
+	assert.True(t, fieldcrypt.IsEncrypted(sealed))
+
+	auditLog, err := repo.open(models.AuditLog{Id: 1, ProfileCode: 2, Action: models.AuditActionUpdate, Changes: sealed})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, auditLog.ProfileCode)
+	assert.JSONEq(t, `"old@example.com"`, string(auditLog.Changes["email"].Before))
+	assert.JSONEq(t, `"new@example.com"`, string(auditLog.Changes["email"].After))
+
+	// entries of erased profiles keep everything but the changes
+	auditLog, err = repo.open(models.AuditLog{Id: 2, ProfileCode: 2, Action: models.AuditActionDelete})
+	assert.Nil(t, err)
+	assert.Nil(t, auditLog.Changes)
+	assert.Equal(t, models.AuditActionDelete, auditLog.Action)
+}
+
+func TestRotateAuditLog(t *testing.T) {
+	oldRepo := NewAuditRepository(nil, testCipher(t, "k1", "k1"))
+	newRepo := NewAuditRepository(nil, testCipher(t, "k2", "k1", "k2"))
+	// what is left once the old key was removed from the config
+	onlyNewRepo := NewAuditRepository(nil, testCipher(t, "k2", "k2"))
+
+	sealed, err := oldRepo.cipher.Encrypt(`{"email":{"before":"old@example.com","after":"new@example.com"}}`)
+	assert.Nil(t, err)
+	entry := &models.AuditLog{Id: 1, ProfileCode: 2, Action: models.AuditActionUpdate, Changes: sealed}
+
+	_, err = onlyNewRepo.open(*entry)
+	assert.ErrorIs(t, err, fieldcrypt.ErrUnknownKey)
+
+	changed, err := newRepo.rotate(entry)
+	assert.Nil(t, err)
+	assert.True(t, changed)
+
+	auditLog, err := onlyNewRepo.open(*entry)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `"new@example.com"`, string(auditLog.Changes["email"].After))
+
+	changed, err = newRepo.rotate(entry)
+	assert.Nil(t, err)
+	assert.False(t, changed)
+}
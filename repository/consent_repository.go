@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"test-bpjs/v2/models"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type ConsentRepository interface {
+	GetConsentsByProfileCode(ctx context.Context, code int) ([]*models.ConsentDTO, error)
+	HasActiveConsent(ctx context.Context, code int, purpose, termsVersion string) (bool, error)
+	GrantConsent(ctx context.Context, payload *models.Consent) (*models.ConsentDTO, error)
+	RevokeConsent(ctx context.Context, code int, purpose string, revokedAt time.Time) error
+}
+
+type consentRepository struct {
+	DB bun.IDB
+}
+
+func NewConsentRepository(db bun.IDB) *consentRepository {
+	return &consentRepository{
+		DB: db,
+	}
+}
+
+func (c *consentRepository) GetConsentsByProfileCode(ctx context.Context, code int) ([]*models.ConsentDTO, error) {
+	var consents []*models.ConsentDTO
+	err := c.DB.NewSelect().
+		Model((*models.Consent)(nil)).
+		Column("id", "profile_code", "purpose", "terms_version", "granted_by", "granted_at", "revoked_at").
+		Where("profile_code = ?", code).
+		Order("granted_at", "id").
+		Scan(ctx, &consents)
+	return consents, err
+}
+
+// HasActiveConsent reports whether the profile has an unrevoked consent for
+// the purpose. An empty terms version accepts consent to any version.
+func (c *consentRepository) HasActiveConsent(ctx context.Context, code int, purpose, termsVersion string) (bool, error) {
+	query := c.DB.NewSelect().
+		Model((*models.Consent)(nil)).
+		Where("profile_code = ?", code).
+		Where("purpose = ?", purpose).
+		Where("revoked_at IS NULL")
+	if termsVersion != "" {
+		query.Where("terms_version = ?", termsVersion)
+	}
+	return query.Exists(ctx)
+}
+
+// GrantConsent stores a new consent and revokes the one it replaces, so the
+// history shows every version of the terms the candidate agreed to.
+func (c *consentRepository) GrantConsent(ctx context.Context, payload *models.Consent) (*models.ConsentDTO, error) {
+	var consent models.ConsentDTO
+	err := c.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewUpdate().
+			Model((*models.Consent)(nil)).
+			Set("revoked_at = ?", payload.GrantedAt).
+			Where("profile_code = ?", payload.ProfileCode).
+			Where("purpose = ?", payload.Purpose).
+			Where("revoked_at IS NULL").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = tx.NewInsert().
+			Model(payload).
+			Returning("id").
+			Exec(ctx, &consent)
+		return err
+	})
+	return &consent, err
+}
+
+func (c *consentRepository) RevokeConsent(ctx context.Context, code int, purpose string, revokedAt time.Time) error {
+	_, err := c.DB.NewUpdate().
+		Model((*models.Consent)(nil)).
+		Set("revoked_at = ?", revokedAt).
+		Where("profile_code = ?", code).
+		Where("purpose = ?", purpose).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	return err
+}
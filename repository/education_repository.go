@@ -9,8 +9,13 @@ import (
 
 type EducationRepository interface {
 	GetEducationByProfileCode(ctx context.Context, code int) ([]*models.EducationDTO, error)
+	GetDeletedEducationByProfileCode(ctx context.Context, code int) ([]*models.EducationDTO, error)
 	CreateEducation(ctx context.Context, payload *models.Education) (*models.EducationDTO, error)
-	DeleteEducation(ctx context.Context, code, id int) error
+	CreateEducationRows(ctx context.Context, payload []*models.Education) ([]int, error)
+	ReplaceEducationRows(ctx context.Context, code, version int, payload []*models.Education) ([]*models.EducationDTO, []int, int, error)
+	PatchEducation(ctx context.Context, code, id, version int, payload *models.Education, columns []string) (*models.EducationDTO, error)
+	DeleteEducation(ctx context.Context, code, id, version int) (*models.EducationDTO, error)
+	RestoreEducation(ctx context.Context, code, id int) (*models.EducationDTO, error)
 }
 
 type educationRepository struct {
@@ -27,12 +32,26 @@ func (e *educationRepository) GetEducationByProfileCode(ctx context.Context, cod
 	var education []*models.EducationDTO
 	err := e.DB.NewSelect().
 		Model((*models.Education)(nil)).
-		Column("id", "school", "degree", "start_date", "end_date", "city", "description").
+		Column("id", "school", "degree", "start_date", "end_date", "city", "description", "version").
 		Where("profile_code = ?", code).
 		Scan(ctx, &education)
 	return education, err
 }
 
+// GetDeletedEducationByProfileCode lists the rows in the trash, most
+// recently deleted first.
+func (e *educationRepository) GetDeletedEducationByProfileCode(ctx context.Context, code int) ([]*models.EducationDTO, error) {
+	var education []*models.EducationDTO
+	err := e.DB.NewSelect().
+		Model((*models.Education)(nil)).
+		Column("id", "school", "degree", "start_date", "end_date", "city", "description", "deleted_at").
+		Where("profile_code = ?", code).
+		WhereDeleted().
+		Order("deleted_at DESC").
+		Scan(ctx, &education)
+	return education, err
+}
+
 func (e *educationRepository) CreateEducation(ctx context.Context, payload *models.Education) (*models.EducationDTO, error) {
 	var education models.EducationDTO
 	_, err := e.DB.NewInsert().
@@ -42,13 +61,110 @@ func (e *educationRepository) CreateEducation(ctx context.Context, payload *mode
 	return &education, err
 }
 
-func (e *educationRepository) DeleteEducation(ctx context.Context, code, id int) error {
+// CreateEducationRows adds the rows with one bulk insert, so either all of
+// them are added or none. It returns their ids in the order of the payload.
+func (e *educationRepository) CreateEducationRows(ctx context.Context, payload []*models.Education) ([]int, error) {
+	err := e.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return insertEducationRows(ctx, tx, payload)
+	})
+	return educationIds(payload), err
+}
+
+// ReplaceEducationRows moves every row of the profile to the trash and adds
+// the payload in their place, in one transaction, unless the profile has
+// been changed since the given version. It returns the rows it removed, the
+// new ids in the order of the payload and the new version of the profile.
+func (e *educationRepository) ReplaceEducationRows(ctx context.Context, code, version int, payload []*models.Education) ([]*models.EducationDTO, []int, int, error) {
+	var removed []*models.EducationDTO
+	err := e.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		if version, err = bumpProfileVersion(ctx, tx, code, version); err != nil {
+			return err
+		}
+		_, err = tx.NewDelete().
+			Model((*models.Education)(nil)).
+			Where("profile_code = ?", code).
+			Returning("profile_code, id, school, degree, start_date, end_date, city, description, created_at").
+			Exec(ctx, &removed)
+		if err != nil {
+			return err
+		}
+		return insertEducationRows(ctx, tx, payload)
+	})
+	return removed, educationIds(payload), version, err
+}
+
+// insertEducationRows relies on Postgres returning the ids of a multi-row
+// insert in the order of its rows, which bun scans back into the payload.
+func insertEducationRows(ctx context.Context, tx bun.Tx, payload []*models.Education) error {
+	if len(payload) == 0 {
+		return nil
+	}
+	_, err := tx.NewInsert().
+		Model(&payload).
+		Returning("id").
+		Exec(ctx)
+	return err
+}
+
+func educationIds(payload []*models.Education) []int {
+	ids := make([]int, 0, len(payload))
+	for _, row := range payload {
+		ids = append(ids, row.Id)
+	}
+	return ids
+}
+
+// PatchEducation writes the given columns of the payload, empty or not, unless
+// the row has been changed since the given version. It returns the same
+// columns as the list of rows, for comparing the row before and after.
+func (e *educationRepository) PatchEducation(ctx context.Context, code, id, version int, payload *models.Education, columns []string) (*models.EducationDTO, error) {
 	var education models.EducationDTO
-	_, err := e.DB.NewDelete().
+	res, err := e.DB.NewUpdate().
+		Model(payload).
+		Column(append(columns, "version")...).
+		Value("version", "version + 1").
+		Where("profile_code = ?", code).
+		Where("id = ?", id).
+		Where("version = ?", version).
+		Returning("id, school, degree, start_date, end_date, city, description, version").
+		Exec(ctx, &education)
+	row := e.DB.NewSelect().
+		Model((*models.Education)(nil)).
+		Where("profile_code = ?", code).
+		Where("id = ?", id)
+	return &education, guarded(ctx, row, res, err)
+}
+
+// DeleteEducation moves the row to the trash and returns it, unless it has been
+// changed since the given version.
+func (e *educationRepository) DeleteEducation(ctx context.Context, code, id, version int) (*models.EducationDTO, error) {
+	var education models.EducationDTO
+	res, err := e.DB.NewDelete().
 		Model((*models.Education)(nil)).
 		Where("profile_code = ?", code).
 		Where("id = ?", id).
-		Returning("profile_code").
+		Where("version = ?", version).
+		Returning("profile_code, id, school, degree, start_date, end_date, city, description, created_at").
 		Exec(ctx, &education)
-	return err
+	row := e.DB.NewSelect().
+		Model((*models.Education)(nil)).
+		Where("profile_code = ?", code).
+		Where("id = ?", id)
+	return &education, guarded(ctx, row, res, err)
+}
+
+// RestoreEducation takes the row out of the trash and returns it.
+func (e *educationRepository) RestoreEducation(ctx context.Context, code, id int) (*models.EducationDTO, error) {
+	var education models.EducationDTO
+	res, err := e.DB.NewUpdate().
+		Model((*models.Education)(nil)).
+		Set("deleted_at = NULL").
+		Set("version = version + 1").
+		Where("profile_code = ?", code).
+		Where("id = ?", id).
+		WhereDeleted().
+		Returning("profile_code, id, school, degree, start_date, end_date, city, description, created_at").
+		Exec(ctx, &education)
+	return &education, affected(res, err)
 }
@@ -9,8 +9,13 @@ import (
 
 type EmploymentRepository interface {
 	GetEmploymentByProfileCode(ctx context.Context, code int) ([]*models.EmploymentDTO, error)
+	GetDeletedEmploymentByProfileCode(ctx context.Context, code int) ([]*models.EmploymentDTO, error)
 	CreateEmployment(ctx context.Context, payload *models.Employment) (*models.EmploymentDTO, error)
-	DeleteEmployment(ctx context.Context, id, code int) error
+	CreateEmploymentRows(ctx context.Context, payload []*models.Employment) ([]int, error)
+	ReplaceEmploymentRows(ctx context.Context, code, version int, payload []*models.Employment) ([]*models.EmploymentDTO, []int, int, error)
+	PatchEmployment(ctx context.Context, code, id, version int, payload *models.Employment, columns []string) (*models.EmploymentDTO, error)
+	DeleteEmployment(ctx context.Context, code, id, version int) (*models.EmploymentDTO, error)
+	RestoreEmployment(ctx context.Context, code, id int) (*models.EmploymentDTO, error)
 }
 
 type employmentRepository struct {
@@ -27,12 +32,26 @@ func (e *employmentRepository) GetEmploymentByProfileCode(ctx context.Context, c
 	var employment []*models.EmploymentDTO
 	err := e.DB.NewSelect().
 		Model((*models.Employment)(nil)).
-		Column("id", "job_title", "employer", "start_date", "end_date", "city", "description").
+		Column("id", "job_title", "employer", "start_date", "end_date", "city", "description", "employment_type", "version").
 		Where("profile_code = ?", code).
 		Scan(ctx, &employment)
 	return employment, err
 }
 
+// GetDeletedEmploymentByProfileCode lists the rows in the trash, most
+// recently deleted first.
+func (e *employmentRepository) GetDeletedEmploymentByProfileCode(ctx context.Context, code int) ([]*models.EmploymentDTO, error) {
+	var employment []*models.EmploymentDTO
+	err := e.DB.NewSelect().
+		Model((*models.Employment)(nil)).
+		Column("id", "job_title", "employer", "start_date", "end_date", "city", "description", "employment_type", "deleted_at").
+		Where("profile_code = ?", code).
+		WhereDeleted().
+		Order("deleted_at DESC").
+		Scan(ctx, &employment)
+	return employment, err
+}
+
 func (e *employmentRepository) CreateEmployment(ctx context.Context, payload *models.Employment) (*models.EmploymentDTO, error) {
 	var employment models.EmploymentDTO
 	_, err := e.DB.NewInsert().
@@ -42,13 +61,110 @@ func (e *employmentRepository) CreateEmployment(ctx context.Context, payload *mo
 	return &employment, err
 }
 
-func (e *employmentRepository) DeleteEmployment(ctx context.Context, code, id int) error {
+// CreateEmploymentRows adds the rows with one bulk insert, so either all of
+// them are added or none. It returns their ids in the order of the payload.
+func (e *employmentRepository) CreateEmploymentRows(ctx context.Context, payload []*models.Employment) ([]int, error) {
+	err := e.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return insertEmploymentRows(ctx, tx, payload)
+	})
+	return employmentIds(payload), err
+}
+
+// ReplaceEmploymentRows moves every row of the profile to the trash and adds
+// the payload in their place, in one transaction, unless the profile has
+// been changed since the given version. It returns the rows it removed, the
+// new ids in the order of the payload and the new version of the profile.
+func (e *employmentRepository) ReplaceEmploymentRows(ctx context.Context, code, version int, payload []*models.Employment) ([]*models.EmploymentDTO, []int, int, error) {
+	var removed []*models.EmploymentDTO
+	err := e.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		if version, err = bumpProfileVersion(ctx, tx, code, version); err != nil {
+			return err
+		}
+		_, err = tx.NewDelete().
+			Model((*models.Employment)(nil)).
+			Where("profile_code = ?", code).
+			Returning("profile_code, id, job_title, employer, start_date, end_date, city, description, employment_type, created_at").
+			Exec(ctx, &removed)
+		if err != nil {
+			return err
+		}
+		return insertEmploymentRows(ctx, tx, payload)
+	})
+	return removed, employmentIds(payload), version, err
+}
+
+// insertEmploymentRows relies on Postgres returning the ids of a multi-row
+// insert in the order of its rows, which bun scans back into the payload.
+func insertEmploymentRows(ctx context.Context, tx bun.Tx, payload []*models.Employment) error {
+	if len(payload) == 0 {
+		return nil
+	}
+	_, err := tx.NewInsert().
+		Model(&payload).
+		Returning("id").
+		Exec(ctx)
+	return err
+}
+
+func employmentIds(payload []*models.Employment) []int {
+	ids := make([]int, 0, len(payload))
+	for _, row := range payload {
+		ids = append(ids, row.Id)
+	}
+	return ids
+}
+
+// PatchEmployment writes the given columns of the payload, empty or not, unless
+// the row has been changed since the given version. It returns the same
+// columns as the list of rows, for comparing the row before and after.
+func (e *employmentRepository) PatchEmployment(ctx context.Context, code, id, version int, payload *models.Employment, columns []string) (*models.EmploymentDTO, error) {
 	var employment models.EmploymentDTO
-	_, err := e.DB.NewDelete().
+	res, err := e.DB.NewUpdate().
+		Model(payload).
+		Column(append(columns, "version")...).
+		Value("version", "version + 1").
+		Where("profile_code = ?", code).
+		Where("id = ?", id).
+		Where("version = ?", version).
+		Returning("id, job_title, employer, start_date, end_date, city, description, employment_type, version").
+		Exec(ctx, &employment)
+	row := e.DB.NewSelect().
+		Model((*models.Employment)(nil)).
+		Where("profile_code = ?", code).
+		Where("id = ?", id)
+	return &employment, guarded(ctx, row, res, err)
+}
+
+// DeleteEmployment moves the row to the trash and returns it, unless it has been
+// changed since the given version.
+func (e *employmentRepository) DeleteEmployment(ctx context.Context, code, id, version int) (*models.EmploymentDTO, error) {
+	var employment models.EmploymentDTO
+	res, err := e.DB.NewDelete().
 		Model((*models.Employment)(nil)).
 		Where("profile_code = ?", code).
 		Where("id = ?", id).
-		Returning("profile_code").
+		Where("version = ?", version).
+		Returning("profile_code, id, job_title, employer, start_date, end_date, city, description, employment_type, created_at").
 		Exec(ctx, &employment)
-	return err
+	row := e.DB.NewSelect().
+		Model((*models.Employment)(nil)).
+		Where("profile_code = ?", code).
+		Where("id = ?", id)
+	return &employment, guarded(ctx, row, res, err)
+}
+
+// RestoreEmployment takes the row out of the trash and returns it.
+func (e *employmentRepository) RestoreEmployment(ctx context.Context, code, id int) (*models.EmploymentDTO, error) {
+	var employment models.EmploymentDTO
+	res, err := e.DB.NewUpdate().
+		Model((*models.Employment)(nil)).
+		Set("deleted_at = NULL").
+		Set("version = version + 1").
+		Where("profile_code = ?", code).
+		Where("id = ?", id).
+		WhereDeleted().
+		Returning("profile_code, id, job_title, employer, start_date, end_date, city, description, employment_type, created_at").
+		Exec(ctx, &employment)
+	return &employment, affected(res, err)
 }
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"test-bpjs/v2/helper/fieldcrypt"
+	"test-bpjs/v2/models"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type IdempotencyRepository interface {
+	ClaimIdempotencyKey(ctx context.Context, payload *models.IdempotencyKey, now time.Time) (bool, error)
+	GetIdempotencyKey(ctx context.Context, scope, key string) (*models.IdempotencyKeyDTO, error)
+	CompleteIdempotencyKey(ctx context.Context, scope, key string, statusCode int, contentType string, headers map[string]string, body []byte) error
+	DeleteIdempotencyKey(ctx context.Context, scope, key string) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int, error)
+	RotateIdempotencyKeys(ctx context.Context) (int, error)
+}
+
+// idempotencyRepository encrypts the stored responses, since they echo the
+// personal data of the rows that were created.
+type idempotencyRepository struct {
+	DB     bun.IDB
+	cipher *fieldcrypt.Cipher
+}
+
+func NewIdempotencyRepository(db bun.IDB, cipher *fieldcrypt.Cipher) *idempotencyRepository {
+	return &idempotencyRepository{
+		DB:     db,
+		cipher: cipher,
+	}
+}
+
+// ClaimIdempotencyKey stores the key for a new request and reports whether it
+// was free. A key that expired before now is taken over in the same
+// statement, so two retries can't both claim it.
+func (i *idempotencyRepository) ClaimIdempotencyKey(ctx context.Context, payload *models.IdempotencyKey, now time.Time) (bool, error) {
+	res, err := i.DB.NewInsert().
+		Model(payload).
+		On("CONFLICT (scope, idempotency_key) DO UPDATE").
+		Set("request_hash = EXCLUDED.request_hash").
+		Set("status_code = NULL").
+		Set("content_type = NULL").
+		Set("headers = NULL").
+		Set("body = NULL").
+		Set("created_at = EXCLUDED.created_at").
+		Set("expires_at = EXCLUDED.expires_at").
+		Where("?TableAlias.expires_at <= ?", now).
+		Returning("NULL").
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	rows, err := res.RowsAffected()
+	return rows > 0, err
+}
+
+func (i *idempotencyRepository) GetIdempotencyKey(ctx context.Context, scope, key string) (*models.IdempotencyKeyDTO, error) {
+	var stored models.IdempotencyKey
+	err := i.DB.NewSelect().
+		Model(&stored).
+		Where("scope = ?", scope).
+		Where("idempotency_key = ?", key).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return i.open(stored)
+}
+
+// CompleteIdempotencyKey stores the response of the request holding the key,
+// so retries get it replayed. Headers hold the response headers besides the
+// content type that retries need too, such as ETag and Location.
+func (i *idempotencyRepository) CompleteIdempotencyKey(ctx context.Context, scope, key string, statusCode int, contentType string, headers map[string]string, body []byte) error {
+	sealed, err := i.cipher.Encrypt(string(body))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt response: %v", err)
+	}
+	res, err := i.DB.NewUpdate().
+		Model((*models.IdempotencyKey)(nil)).
+		Set("status_code = ?", statusCode).
+		Set("content_type = ?", contentType).
+		Set("headers = ?", headers).
+		Set("body = ?", sealed).
+		Where("scope = ?", scope).
+		Where("idempotency_key = ?", key).
+		Exec(ctx)
+	return affected(res, err)
+}
+
+// DeleteIdempotencyKey frees the key of a request that failed, so the client
+// can retry it.
+func (i *idempotencyRepository) DeleteIdempotencyKey(ctx context.Context, scope, key string) error {
+	_, err := i.DB.NewDelete().
+		Model((*models.IdempotencyKey)(nil)).
+		Where("scope = ?", scope).
+		Where("idempotency_key = ?", key).
+		Exec(ctx)
+	return err
+}
+
+func (i *idempotencyRepository) DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int, error) {
+	res, err := i.DB.NewDelete().
+		Model((*models.IdempotencyKey)(nil)).
+		Where("expires_at <= ?", now).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := res.RowsAffected()
+	return int(rows), err
+}
+
+// RotateIdempotencyKeys re-wraps the stored responses sealed with an older
+// encryption key. It returns how many responses were updated.
+func (i *idempotencyRepository) RotateIdempotencyKeys(ctx context.Context) (int, error) {
+	updated := 0
+	var lastScope, lastKey string
+	for {
+		var stored []models.IdempotencyKey
+		err := i.DB.NewSelect().
+			Model(&stored).
+			Column("scope", "idempotency_key", "body").
+			Where("(scope, idempotency_key) > (?, ?)", lastScope, lastKey).
+			Where("body IS NOT NULL").
+			Order("scope", "idempotency_key").
+			Limit(rotateBatchSize).
+			Scan(ctx)
+		if err != nil {
+			return updated, err
+		}
+		if len(stored) == 0 {
+			return updated, nil
+		}
+
+		for j := range stored {
+			response := &stored[j]
+			lastScope, lastKey = response.Scope, response.Key
+
+			changed, err := i.rotate(response)
+			if err != nil {
+				return updated, fmt.Errorf("failed to rotate idempotency key %s: %v", response.Key, err)
+			}
+			if !changed {
+				continue
+			}
+			_, err = i.DB.NewUpdate().
+				Model(response).
+				Column("body").
+				WherePK().
+				Exec(ctx)
+			if err != nil {
+				return updated, err
+			}
+			updated++
+		}
+	}
+}
+
+func (i *idempotencyRepository) rotate(stored *models.IdempotencyKey) (bool, error) {
+	body, changed, err := i.cipher.Rotate(stored.Body)
+	if err != nil {
+		return false, err
+	}
+	stored.Body = body
+	return changed, nil
+}
+
+func (i *idempotencyRepository) open(stored models.IdempotencyKey) (*models.IdempotencyKeyDTO, error) {
+	body := stored.Body
+	if body != "" {
+		plaintext, err := i.cipher.Decrypt(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt response: %v", err)
+		}
+		body = plaintext
+	}
+	return &models.IdempotencyKeyDTO{
+		Scope:       stored.Scope,
+		Key:         stored.Key,
+		RequestHash: stored.RequestHash,
+		StatusCode:  stored.StatusCode,
+		ContentType: stored.ContentType,
+		Headers:     stored.Headers,
+		Body:        []byte(body),
+		ExpiresAt:   stored.ExpiresAt,
+	}, nil
+}
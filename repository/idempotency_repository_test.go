@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"test-bpjs/v2/helper/fieldcrypt"
+	"test-bpjs/v2/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenIdempotencyKey(t *testing.T) {
+	repo := NewIdempotencyRepository(nil, testCipher(t, "k1", "k1"))
+
+	sealed, err := repo.cipher.Encrypt(`{"profileCode":1}`)
+	assert.Nil(t, err)
+	assert.True(t, fieldcrypt.IsEncrypted(sealed))
+
+	stored, err := repo.open(models.IdempotencyKey{Scope: "user:1", Key: "abc", StatusCode: 200, Headers: map[string]string{"ETag": `"1"`}, Body: sealed})
+	assert.Nil(t, err)
+	assert.Equal(t, 200, stored.StatusCode)
+	assert.Equal(t, `"1"`, stored.Headers["ETag"])
+	assert.JSONEq(t, `{"profileCode":1}`, string(stored.Body))
+
+	// a claimed key has no response until the request holding it completes
+	stored, err = repo.open(models.IdempotencyKey{Scope: "user:1", Key: "abc"})
+	assert.Nil(t, err)
+	assert.Zero(t, stored.StatusCode)
+	assert.Empty(t, stored.Body)
+}
+
+func TestRotateIdempotencyKey(t *testing.T) {
+	oldRepo := NewIdempotencyRepository(nil, testCipher(t, "k1", "k1"))
+	newRepo := NewIdempotencyRepository(nil, testCipher(t, "k2", "k1", "k2"))
+	// what is left once the old key was removed from the config
+	onlyNewRepo := NewIdempotencyRepository(nil, testCipher(t, "k2", "k2"))
+
+	sealed, err := oldRepo.cipher.Encrypt(`{"profileCode":1}`)
+	assert.Nil(t, err)
+	stored := &models.IdempotencyKey{Scope: "user:1", Key: "abc", StatusCode: 200, Body: sealed}
+
+	_, err = onlyNewRepo.open(*stored)
+	assert.ErrorContains(t, err, fieldcrypt.ErrUnknownKey.Error())
+
+	changed, err := newRepo.rotate(stored)
+	assert.Nil(t, err)
+	assert.True(t, changed)
+
+	response, err := onlyNewRepo.open(*stored)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"profileCode":1}`, string(response.Body))
+
+	changed, err = newRepo.rotate(stored)
+	assert.Nil(t, err)
+	assert.False(t, changed)
+}
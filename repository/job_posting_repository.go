@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"test-bpjs/v2/models"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type JobPostingRepository interface {
+	GetJobPostings(ctx context.Context, ownerId int) ([]*models.JobPostingDTO, error)
+	GetJobPostingById(ctx context.Context, id int) (*models.JobPostingDTO, error)
+	CreateJobPosting(ctx context.Context, payload *models.JobPosting) (*models.JobPostingDTO, error)
+	UpdateJobPosting(ctx context.Context, payload *models.JobPosting) error
+	DeleteJobPosting(ctx context.Context, id int) error
+	GetMatchCandidates(ctx context.Context, visibility models.ProfileVisibility, skills []string) ([]*models.MatchCandidateDTO, error)
+}
+
+type jobPostingRepository struct {
+	DB bun.IDB
+}
+
+func NewJobPostingRepository(db bun.IDB) *jobPostingRepository {
+	return &jobPostingRepository{
+		DB: db,
+	}
+}
+
+// GetJobPostings lists the postings of an owner, or every posting when the
+// owner is 0.
+func (j *jobPostingRepository) GetJobPostings(ctx context.Context, ownerId int) ([]*models.JobPostingDTO, error) {
+	var jobPostings []*models.JobPostingDTO
+	query := j.DB.NewSelect().
+		Model((*models.JobPosting)(nil)).
+		Column("id", "owner_id", "title", "skills", "min_years_experience", "city", "country", "created_at", "updated_at")
+	if ownerId != 0 {
+		query.Where("owner_id = ?", ownerId)
+	}
+	err := query.Order("id").Scan(ctx, &jobPostings)
+	return jobPostings, err
+}
+
+func (j *jobPostingRepository) GetJobPostingById(ctx context.Context, id int) (*models.JobPostingDTO, error) {
+	var jobPosting models.JobPostingDTO
+	err := j.DB.NewSelect().
+		Model((*models.JobPosting)(nil)).
+		Column("id", "owner_id", "title", "skills", "min_years_experience", "city", "country", "created_at", "updated_at").
+		Where("id = ?", id).
+		Scan(ctx, &jobPosting)
+	return &jobPosting, err
+}
+
+func (j *jobPostingRepository) CreateJobPosting(ctx context.Context, payload *models.JobPosting) (*models.JobPostingDTO, error) {
+	var jobPosting models.JobPostingDTO
+	_, err := j.DB.NewInsert().
+		Model(payload).
+		Returning("id, created_at").
+		Exec(ctx, &jobPosting)
+	return &jobPosting, err
+}
+
+// UpdateJobPosting replaces every field of the posting but its owner.
+func (j *jobPostingRepository) UpdateJobPosting(ctx context.Context, payload *models.JobPosting) error {
+	payload.UpdatedAt = time.Now()
+	res, err := j.DB.NewUpdate().
+		Model(payload).
+		Column("title", "skills", "min_years_experience", "city", "country", "updated_at").
+		WherePK().
+		Exec(ctx)
+	return affected(res, err)
+}
+
+func (j *jobPostingRepository) DeleteJobPosting(ctx context.Context, id int) error {
+	res, err := j.DB.NewDelete().
+		Model((*models.JobPosting)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+	return affected(res, err)
+}
+
+// GetMatchCandidates returns the visible profiles having at least one of the
+// skills, with their skill and employment rows.
+func (j *jobPostingRepository) GetMatchCandidates(ctx context.Context, visibility models.ProfileVisibility, skills []string) ([]*models.MatchCandidateDTO, error) {
+	var candidates []*models.MatchCandidateDTO
+	if err := j.candidateQuery(visibility, skills).Scan(ctx, &candidates); err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	codes := make([]int, 0, len(candidates))
+	byCode := make(map[int]*models.MatchCandidateDTO, len(candidates))
+	for _, candidate := range candidates {
+		codes = append(codes, candidate.ProfileCode)
+		byCode[candidate.ProfileCode] = candidate
+	}
+
+	var skillRows []*models.SkillDTO
+	err := j.DB.NewSelect().
+		Model((*models.Skill)(nil)).
+		Column("profile_code", "id", "skill", "level").
+		Where("profile_code IN (?)", bun.In(codes)).
+		Order("id").
+		Scan(ctx, &skillRows)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range skillRows {
+		byCode[row.ProfileCode].Skills = append(byCode[row.ProfileCode].Skills, row)
+	}
+
+	var employmentRows []*models.EmploymentDTO
+	err = j.DB.NewSelect().
+		Model((*models.Employment)(nil)).
+		Column("profile_code", "id", "job_title", "employer", "start_date", "end_date").
+		Where("profile_code IN (?)", bun.In(codes)).
+		Order("start_date").
+		Scan(ctx, &employmentRows)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range employmentRows {
+		byCode[row.ProfileCode].Employment = append(byCode[row.ProfileCode].Employment, row)
+	}
+	return candidates, nil
+}
+
+func (j *jobPostingRepository) candidateQuery(visibility models.ProfileVisibility, skills []string) *bun.SelectQuery {
+	query := j.DB.NewSelect().
+		Model((*models.Profile)(nil)).
+		Column("profile_code", "public_id", "wanted_job_title", "first_name", "last_name", "city", "country").
+		Where("EXISTS (?)", j.DB.NewSelect().
+			Model((*models.Skill)(nil)).
+			ColumnExpr("1").
+			Where("?TableAlias.profile_code = profile.profile_code").
+			Where("lower(?TableAlias.skill) IN (?)", bun.In(skills)))
+	whereVisible(j.DB, query, visibility)
+	return query.Order("profile.profile_code")
+}
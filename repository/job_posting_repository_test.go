@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"database/sql"
+	"test-bpjs/v2/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+func TestMatchCandidateQuery(t *testing.T) {
+	repo := NewJobPostingRepository(bun.NewDB(&sql.DB{}, pgdialect.New()))
+
+	candidates := repo.candidateQuery(models.ProfileVisibility{OwnerId: 3, Consented: true}, []string{"golang", "postgresql"}).String()
+	assert.Contains(t, candidates, `lower("skill".skill) IN ('golang', 'postgresql')`)
+	assert.Contains(t, candidates, `"skill"."deleted_at" IS NULL`)
+	assert.Contains(t, candidates, `(profile.owner_id = 3) OR (EXISTS (SELECT 1 FROM "consents"`)
+	assert.Contains(t, candidates, `"profile"."deleted_at" IS NULL`)
+
+	candidates = repo.candidateQuery(models.ProfileVisibility{All: true}, []string{"golang"}).String()
+	assert.NotContains(t, candidates, "owner_id")
+}
@@ -40,18 +40,82 @@ func (_m *EducationRepository) CreateEducation(ctx context.Context, payload *mod
 	return r0, r1
 }
 
-// DeleteEducation provides a mock function with given fields: ctx, code, id
-func (_m *EducationRepository) DeleteEducation(ctx context.Context, code int, id int) error {
-	ret := _m.Called(ctx, code, id)
+// CreateEducationRows provides a mock function with given fields: ctx, payload
+func (_m *EducationRepository) CreateEducationRows(ctx context.Context, payload []*models.Education) ([]int, error) {
+	ret := _m.Called(ctx, payload)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
-		r0 = rf(ctx, code, id)
+	var r0 []int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*models.Education) ([]int, error)); ok {
+		return rf(ctx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []*models.Education) []int); ok {
+		r0 = rf(ctx, payload)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, []*models.Education) error); ok {
+		r1 = rf(ctx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteEducation provides a mock function with given fields: ctx, code, id, version
+func (_m *EducationRepository) DeleteEducation(ctx context.Context, code int, id int, version int) (*models.EducationDTO, error) {
+	ret := _m.Called(ctx, code, id, version)
+
+	var r0 *models.EducationDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (*models.EducationDTO, error)); ok {
+		return rf(ctx, code, id, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) *models.EducationDTO); ok {
+		r0 = rf(ctx, code, id, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EducationDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, code, id, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeletedEducationByProfileCode provides a mock function with given fields: ctx, code
+func (_m *EducationRepository) GetDeletedEducationByProfileCode(ctx context.Context, code int) ([]*models.EducationDTO, error) {
+	ret := _m.Called(ctx, code)
+
+	var r0 []*models.EducationDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]*models.EducationDTO, error)); ok {
+		return rf(ctx, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []*models.EducationDTO); ok {
+		r0 = rf(ctx, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.EducationDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // GetEducationByProfileCode provides a mock function with given fields: ctx, code
@@ -80,6 +144,100 @@ func (_m *EducationRepository) GetEducationByProfileCode(ctx context.Context, co
 	return r0, r1
 }
 
+// PatchEducation provides a mock function with given fields: ctx, code, id, version, payload, columns
+func (_m *EducationRepository) PatchEducation(ctx context.Context, code int, id int, version int, payload *models.Education, columns []string) (*models.EducationDTO, error) {
+	ret := _m.Called(ctx, code, id, version, payload, columns)
+
+	var r0 *models.EducationDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int, *models.Education, []string) (*models.EducationDTO, error)); ok {
+		return rf(ctx, code, id, version, payload, columns)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int, *models.Education, []string) *models.EducationDTO); ok {
+		r0 = rf(ctx, code, id, version, payload, columns)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EducationDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int, *models.Education, []string) error); ok {
+		r1 = rf(ctx, code, id, version, payload, columns)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplaceEducationRows provides a mock function with given fields: ctx, code, version, payload
+func (_m *EducationRepository) ReplaceEducationRows(ctx context.Context, code int, version int, payload []*models.Education) ([]*models.EducationDTO, []int, int, error) {
+	ret := _m.Called(ctx, code, version, payload)
+
+	var r0 []*models.EducationDTO
+	var r1 []int
+	var r2 int
+	var r3 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, []*models.Education) ([]*models.EducationDTO, []int, int, error)); ok {
+		return rf(ctx, code, version, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, []*models.Education) []*models.EducationDTO); ok {
+		r0 = rf(ctx, code, version, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.EducationDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, []*models.Education) []int); ok {
+		r1 = rf(ctx, code, version, payload)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]int)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, int, []*models.Education) int); ok {
+		r2 = rf(ctx, code, version, payload)
+	} else {
+		r2 = ret.Get(2).(int)
+	}
+
+	if rf, ok := ret.Get(3).(func(context.Context, int, int, []*models.Education) error); ok {
+		r3 = rf(ctx, code, version, payload)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// RestoreEducation provides a mock function with given fields: ctx, code, id
+func (_m *EducationRepository) RestoreEducation(ctx context.Context, code int, id int) (*models.EducationDTO, error) {
+	ret := _m.Called(ctx, code, id)
+
+	var r0 *models.EducationDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*models.EducationDTO, error)); ok {
+		return rf(ctx, code, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *models.EducationDTO); ok {
+		r0 = rf(ctx, code, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EducationDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, code, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewEducationRepository interface {
 	mock.TestingT
 	Cleanup(func())
@@ -40,18 +40,82 @@ func (_m *EmploymentRepository) CreateEmployment(ctx context.Context, payload *m
 	return r0, r1
 }
 
-// DeleteEmployment provides a mock function with given fields: ctx, id, code
-func (_m *EmploymentRepository) DeleteEmployment(ctx context.Context, id int, code int) error {
-	ret := _m.Called(ctx, id, code)
+// CreateEmploymentRows provides a mock function with given fields: ctx, payload
+func (_m *EmploymentRepository) CreateEmploymentRows(ctx context.Context, payload []*models.Employment) ([]int, error) {
+	ret := _m.Called(ctx, payload)
+
+	var r0 []int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*models.Employment) ([]int, error)); ok {
+		return rf(ctx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []*models.Employment) []int); ok {
+		r0 = rf(ctx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
-		r0 = rf(ctx, id, code)
+	if rf, ok := ret.Get(1).(func(context.Context, []*models.Employment) error); ok {
+		r1 = rf(ctx, payload)
 	} else {
-		r0 = ret.Error(0)
+		r1 = ret.Error(1)
 	}
 
-	return r0
+	return r0, r1
+}
+
+// DeleteEmployment provides a mock function with given fields: ctx, code, id, version
+func (_m *EmploymentRepository) DeleteEmployment(ctx context.Context, code int, id int, version int) (*models.EmploymentDTO, error) {
+	ret := _m.Called(ctx, code, id, version)
+
+	var r0 *models.EmploymentDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (*models.EmploymentDTO, error)); ok {
+		return rf(ctx, code, id, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) *models.EmploymentDTO); ok {
+		r0 = rf(ctx, code, id, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EmploymentDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, code, id, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeletedEmploymentByProfileCode provides a mock function with given fields: ctx, code
+func (_m *EmploymentRepository) GetDeletedEmploymentByProfileCode(ctx context.Context, code int) ([]*models.EmploymentDTO, error) {
+	ret := _m.Called(ctx, code)
+
+	var r0 []*models.EmploymentDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]*models.EmploymentDTO, error)); ok {
+		return rf(ctx, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []*models.EmploymentDTO); ok {
+		r0 = rf(ctx, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.EmploymentDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // GetEmploymentByProfileCode provides a mock function with given fields: ctx, code
@@ -80,6 +144,100 @@ func (_m *EmploymentRepository) GetEmploymentByProfileCode(ctx context.Context,
 	return r0, r1
 }
 
+// PatchEmployment provides a mock function with given fields: ctx, code, id, version, payload, columns
+func (_m *EmploymentRepository) PatchEmployment(ctx context.Context, code int, id int, version int, payload *models.Employment, columns []string) (*models.EmploymentDTO, error) {
+	ret := _m.Called(ctx, code, id, version, payload, columns)
+
+	var r0 *models.EmploymentDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int, *models.Employment, []string) (*models.EmploymentDTO, error)); ok {
+		return rf(ctx, code, id, version, payload, columns)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int, *models.Employment, []string) *models.EmploymentDTO); ok {
+		r0 = rf(ctx, code, id, version, payload, columns)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EmploymentDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int, *models.Employment, []string) error); ok {
+		r1 = rf(ctx, code, id, version, payload, columns)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplaceEmploymentRows provides a mock function with given fields: ctx, code, version, payload
+func (_m *EmploymentRepository) ReplaceEmploymentRows(ctx context.Context, code int, version int, payload []*models.Employment) ([]*models.EmploymentDTO, []int, int, error) {
+	ret := _m.Called(ctx, code, version, payload)
+
+	var r0 []*models.EmploymentDTO
+	var r1 []int
+	var r2 int
+	var r3 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, []*models.Employment) ([]*models.EmploymentDTO, []int, int, error)); ok {
+		return rf(ctx, code, version, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, []*models.Employment) []*models.EmploymentDTO); ok {
+		r0 = rf(ctx, code, version, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.EmploymentDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, []*models.Employment) []int); ok {
+		r1 = rf(ctx, code, version, payload)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]int)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, int, []*models.Employment) int); ok {
+		r2 = rf(ctx, code, version, payload)
+	} else {
+		r2 = ret.Get(2).(int)
+	}
+
+	if rf, ok := ret.Get(3).(func(context.Context, int, int, []*models.Employment) error); ok {
+		r3 = rf(ctx, code, version, payload)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// RestoreEmployment provides a mock function with given fields: ctx, code, id
+func (_m *EmploymentRepository) RestoreEmployment(ctx context.Context, code int, id int) (*models.EmploymentDTO, error) {
+	ret := _m.Called(ctx, code, id)
+
+	var r0 *models.EmploymentDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*models.EmploymentDTO, error)); ok {
+		return rf(ctx, code, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *models.EmploymentDTO); ok {
+		r0 = rf(ctx, code, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EmploymentDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, code, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewEmploymentRepository interface {
 	mock.TestingT
 	Cleanup(func())
@@ -14,6 +14,30 @@ type ProfileRepository struct {
 	mock.Mock
 }
 
+// CountProfilesByPhotoHash provides a mock function with given fields: ctx, hash
+func (_m *ProfileRepository) CountProfilesByPhotoHash(ctx context.Context, hash string) (int, error) {
+	ret := _m.Called(ctx, hash)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int, error)); ok {
+		return rf(ctx, hash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, hash)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateProfile provides a mock function with given fields: ctx, payload
 func (_m *ProfileRepository) CreateProfile(ctx context.Context, payload *models.Profile) (*models.ProfileDTO, error) {
 	ret := _m.Called(ctx, payload)
@@ -40,25 +64,51 @@ func (_m *ProfileRepository) CreateProfile(ctx context.Context, payload *models.
 	return r0, r1
 }
 
-// DeletePhotoByCode provides a mock function with given fields: ctx, code
-func (_m *ProfileRepository) DeletePhotoByCode(ctx context.Context, code int) (*models.DefaultResponse, error) {
-	ret := _m.Called(ctx, code)
+// DeletePhotoByCode provides a mock function with given fields: ctx, code, version
+func (_m *ProfileRepository) DeletePhotoByCode(ctx context.Context, code int, version int) (*models.DefaultResponse, error) {
+	ret := _m.Called(ctx, code, version)
 
 	var r0 *models.DefaultResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.DefaultResponse, error)); ok {
-		return rf(ctx, code)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*models.DefaultResponse, error)); ok {
+		return rf(ctx, code, version)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) *models.DefaultResponse); ok {
-		r0 = rf(ctx, code)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *models.DefaultResponse); ok {
+		r0 = rf(ctx, code, version)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.DefaultResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = rf(ctx, code)
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, code, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteProfile provides a mock function with given fields: ctx, code, version
+func (_m *ProfileRepository) DeleteProfile(ctx context.Context, code int, version int) (*models.ProfileDTO, error) {
+	ret := _m.Called(ctx, code, version)
+
+	var r0 *models.ProfileDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*models.ProfileDTO, error)); ok {
+		return rf(ctx, code, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *models.ProfileDTO); ok {
+		r0 = rf(ctx, code, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ProfileDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, code, version)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -92,6 +142,80 @@ func (_m *ProfileRepository) GetProfileByCode(ctx context.Context, code int) (*m
 	return r0, r1
 }
 
+// GetProfileCodeByPublicId provides a mock function with given fields: ctx, publicId
+func (_m *ProfileRepository) GetProfileCodeByPublicId(ctx context.Context, publicId string) (int, error) {
+	ret := _m.Called(ctx, publicId)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int, error)); ok {
+		return rf(ctx, publicId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, publicId)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, publicId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProfileCodesByEmail provides a mock function with given fields: ctx, email
+func (_m *ProfileRepository) GetProfileCodesByEmail(ctx context.Context, email string) ([]int, error) {
+	ret := _m.Called(ctx, email)
+
+	var r0 []int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]int, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []int); ok {
+		r0 = rf(ctx, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProfileOwner provides a mock function with given fields: ctx, code
+func (_m *ProfileRepository) GetProfileOwner(ctx context.Context, code int) (int, error) {
+	ret := _m.Called(ctx, code)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, code)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetWorkingExperienceByCode provides a mock function with given fields: ctx, code
 func (_m *ProfileRepository) GetWorkingExperienceByCode(ctx context.Context, code int) (*models.ProfileDTO, error) {
 	ret := _m.Called(ctx, code)
@@ -118,25 +242,127 @@ func (_m *ProfileRepository) GetWorkingExperienceByCode(ctx context.Context, cod
 	return r0, r1
 }
 
-// UpdateProfile provides a mock function with given fields: ctx, code, payload
-func (_m *ProfileRepository) UpdateProfile(ctx context.Context, code int, payload *models.Profile) (*models.ProfileDTO, error) {
-	ret := _m.Called(ctx, code, payload)
+// ListProfiles provides a mock function with given fields: ctx, filter
+func (_m *ProfileRepository) ListProfiles(ctx context.Context, filter *models.ProfileFilter) ([]*models.ProfileDTO, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*models.ProfileDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ProfileFilter) ([]*models.ProfileDTO, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ProfileFilter) []*models.ProfileDTO); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.ProfileDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.ProfileFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PatchProfile provides a mock function with given fields: ctx, code, version, payload, columns
+func (_m *ProfileRepository) PatchProfile(ctx context.Context, code int, version int, payload *models.Profile, columns []string) (*models.ProfileDTO, error) {
+	ret := _m.Called(ctx, code, version, payload, columns)
+
+	var r0 *models.ProfileDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *models.Profile, []string) (*models.ProfileDTO, error)); ok {
+		return rf(ctx, code, version, payload, columns)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *models.Profile, []string) *models.ProfileDTO); ok {
+		r0 = rf(ctx, code, version, payload, columns)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ProfileDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, *models.Profile, []string) error); ok {
+		r1 = rf(ctx, code, version, payload, columns)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreProfile provides a mock function with given fields: ctx, code
+func (_m *ProfileRepository) RestoreProfile(ctx context.Context, code int) (*models.ProfileDTO, error) {
+	ret := _m.Called(ctx, code)
+
+	var r0 *models.ProfileDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*models.ProfileDTO, error)); ok {
+		return rf(ctx, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.ProfileDTO); ok {
+		r0 = rf(ctx, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ProfileDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RotateProfileKeys provides a mock function with given fields: ctx
+func (_m *ProfileRepository) RotateProfileKeys(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateProfile provides a mock function with given fields: ctx, code, version, payload
+func (_m *ProfileRepository) UpdateProfile(ctx context.Context, code int, version int, payload *models.Profile) (*models.ProfileDTO, error) {
+	ret := _m.Called(ctx, code, version, payload)
 
 	var r0 *models.ProfileDTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, *models.Profile) (*models.ProfileDTO, error)); ok {
-		return rf(ctx, code, payload)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *models.Profile) (*models.ProfileDTO, error)); ok {
+		return rf(ctx, code, version, payload)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int, *models.Profile) *models.ProfileDTO); ok {
-		r0 = rf(ctx, code, payload)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *models.Profile) *models.ProfileDTO); ok {
+		r0 = rf(ctx, code, version, payload)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.ProfileDTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int, *models.Profile) error); ok {
-		r1 = rf(ctx, code, payload)
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, *models.Profile) error); ok {
+		r1 = rf(ctx, code, version, payload)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -40,18 +40,108 @@ func (_m *SkillRepository) CreateSkill(ctx context.Context, payload *models.Skil
 	return r0, r1
 }
 
-// DeleteSkill provides a mock function with given fields: ctx, code, id
-func (_m *SkillRepository) DeleteSkill(ctx context.Context, code int, id int) error {
-	ret := _m.Called(ctx, code, id)
+// CreateSkills provides a mock function with given fields: ctx, payload
+func (_m *SkillRepository) CreateSkills(ctx context.Context, payload []*models.Skill) ([]int, error) {
+	ret := _m.Called(ctx, payload)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
-		r0 = rf(ctx, code, id)
+	var r0 []int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*models.Skill) ([]int, error)); ok {
+		return rf(ctx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []*models.Skill) []int); ok {
+		r0 = rf(ctx, payload)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, []*models.Skill) error); ok {
+		r1 = rf(ctx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteSkill provides a mock function with given fields: ctx, code, id, version
+func (_m *SkillRepository) DeleteSkill(ctx context.Context, code int, id int, version int) (*models.SkillDTO, error) {
+	ret := _m.Called(ctx, code, id, version)
+
+	var r0 *models.SkillDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (*models.SkillDTO, error)); ok {
+		return rf(ctx, code, id, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) *models.SkillDTO); ok {
+		r0 = rf(ctx, code, id, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.SkillDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, code, id, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeletedSkillsByProfileCode provides a mock function with given fields: ctx, code
+func (_m *SkillRepository) GetDeletedSkillsByProfileCode(ctx context.Context, code int) ([]*models.SkillDTO, error) {
+	ret := _m.Called(ctx, code)
+
+	var r0 []*models.SkillDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]*models.SkillDTO, error)); ok {
+		return rf(ctx, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []*models.SkillDTO); ok {
+		r0 = rf(ctx, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.SkillDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSkillsAfter provides a mock function with given fields: ctx, id, limit
+func (_m *SkillRepository) GetSkillsAfter(ctx context.Context, id int, limit int) ([]*models.SkillDTO, error) {
+	ret := _m.Called(ctx, id, limit)
+
+	var r0 []*models.SkillDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]*models.SkillDTO, error)); ok {
+		return rf(ctx, id, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []*models.SkillDTO); ok {
+		r0 = rf(ctx, id, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.SkillDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, id, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // GetSkillsByProfileCode provides a mock function with given fields: ctx, code
@@ -80,6 +170,114 @@ func (_m *SkillRepository) GetSkillsByProfileCode(ctx context.Context, code int)
 	return r0, r1
 }
 
+// NormalizeSkill provides a mock function with given fields: ctx, id, version, skill, level
+func (_m *SkillRepository) NormalizeSkill(ctx context.Context, id int, version int, skill string, level string) error {
+	ret := _m.Called(ctx, id, version, skill, level)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, string, string) error); ok {
+		r0 = rf(ctx, id, version, skill, level)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PatchSkill provides a mock function with given fields: ctx, code, id, version, payload, columns
+func (_m *SkillRepository) PatchSkill(ctx context.Context, code int, id int, version int, payload *models.Skill, columns []string) (*models.SkillDTO, error) {
+	ret := _m.Called(ctx, code, id, version, payload, columns)
+
+	var r0 *models.SkillDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int, *models.Skill, []string) (*models.SkillDTO, error)); ok {
+		return rf(ctx, code, id, version, payload, columns)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int, *models.Skill, []string) *models.SkillDTO); ok {
+		r0 = rf(ctx, code, id, version, payload, columns)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.SkillDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int, *models.Skill, []string) error); ok {
+		r1 = rf(ctx, code, id, version, payload, columns)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplaceSkills provides a mock function with given fields: ctx, code, version, payload
+func (_m *SkillRepository) ReplaceSkills(ctx context.Context, code int, version int, payload []*models.Skill) ([]*models.SkillDTO, []int, int, error) {
+	ret := _m.Called(ctx, code, version, payload)
+
+	var r0 []*models.SkillDTO
+	var r1 []int
+	var r2 int
+	var r3 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, []*models.Skill) ([]*models.SkillDTO, []int, int, error)); ok {
+		return rf(ctx, code, version, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, []*models.Skill) []*models.SkillDTO); ok {
+		r0 = rf(ctx, code, version, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.SkillDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, []*models.Skill) []int); ok {
+		r1 = rf(ctx, code, version, payload)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]int)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, int, []*models.Skill) int); ok {
+		r2 = rf(ctx, code, version, payload)
+	} else {
+		r2 = ret.Get(2).(int)
+	}
+
+	if rf, ok := ret.Get(3).(func(context.Context, int, int, []*models.Skill) error); ok {
+		r3 = rf(ctx, code, version, payload)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// RestoreSkill provides a mock function with given fields: ctx, code, id
+func (_m *SkillRepository) RestoreSkill(ctx context.Context, code int, id int) (*models.SkillDTO, error) {
+	ret := _m.Called(ctx, code, id)
+
+	var r0 *models.SkillDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (*models.SkillDTO, error)); ok {
+		return rf(ctx, code, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) *models.SkillDTO); ok {
+		r0 = rf(ctx, code, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.SkillDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, code, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewSkillRepository interface {
 	mock.TestingT
 	Cleanup(func())
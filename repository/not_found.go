@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/uptrace/bun"
+)
+
+// ErrNotFound is returned by updates and deletes that match no row.
+var ErrNotFound = errors.New("record not found")
+
+// ErrVersionMismatch is returned by updates and deletes guarded by a row
+// version when the row has been changed since that version was read.
+var ErrVersionMismatch = errors.New("version mismatch")
+
+// affected turns the result of an update or delete into ErrNotFound when no
+// row matched. Queries scanning their Returning columns into a struct
+// report that as sql.ErrNoRows instead, so both are checked.
+func affected(res sql.Result, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// guarded is affected for writes that also match on the row version. When
+// nothing matched, the row is looked up without the version to tell a stale
+// version from a missing row.
+func guarded(ctx context.Context, row *bun.SelectQuery, res sql.Result, err error) error {
+	err = affected(res, err)
+	if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	exists, err := row.Exists(ctx)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrVersionMismatch
+	}
+	return ErrNotFound
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAffected(t *testing.T) {
+	assert.Nil(t, affected(driver.RowsAffected(1), nil))
+	assert.ErrorIs(t, affected(driver.RowsAffected(0), nil), ErrNotFound)
+	assert.ErrorIs(t, affected(nil, sql.ErrNoRows), ErrNotFound)
+
+	err := errors.New("connection reset")
+	assert.Equal(t, err, affected(nil, err))
+}
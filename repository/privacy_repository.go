@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"test-bpjs/v2/models"
+
+	"github.com/uptrace/bun"
+)
+
+type PrivacyRepository interface {
+	EraseProfile(ctx context.Context, code int, receipt *models.ErasureReceipt) error
+}
+
+type privacyRepository struct {
+	DB bun.IDB
+}
+
+func NewPrivacyRepository(db bun.IDB) *privacyRepository {
+	return &privacyRepository{
+		DB: db,
+	}
+}
+
+// EraseProfile removes the education, employment, skill, consent and resume
+// version rows of a profile, strips the changes from its audit entries, drops
+// the responses stored for its owner's retries and then anonymises or deletes
+// the profile itself, depending on the receipt's mode. The row counts
+// are written to the receipt, which is stored in the same transaction so there
+// is never an erasure without a receipt.
+func (p *privacyRepository) EraseProfile(ctx context.Context, code int, receipt *models.ErasureReceipt) error {
+	return p.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		if receipt.EducationRows, err = deleteByProfileCode(ctx, tx, (*models.Education)(nil), code); err != nil {
+			return err
+		}
+		if receipt.EmploymentRows, err = deleteByProfileCode(ctx, tx, (*models.Employment)(nil), code); err != nil {
+			return err
+		}
+		if receipt.SkillRows, err = deleteByProfileCode(ctx, tx, (*models.Skill)(nil), code); err != nil {
+			return err
+		}
+		if receipt.ConsentRows, err = deleteByProfileCode(ctx, tx, (*models.Consent)(nil), code); err != nil {
+			return err
+		}
+		if receipt.ResumeVersionRows, err = deleteByProfileCode(ctx, tx, (*models.ResumeVersion)(nil), code); err != nil {
+			return err
+		}
+
+		// who changed what and when stays on record, the values don't
+		res, err := tx.NewUpdate().
+			Model((*models.AuditLog)(nil)).
+			Set("changes = NULL").
+			Where("profile_code = ?", code).
+			Where("changes IS NOT NULL").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		receipt.AuditRows = int(rows)
+
+		// this has to run while the profile still points at its owner
+		if _, err = deleteOwnerIdempotencyKeys(tx, code).Exec(ctx); err != nil {
+			return err
+		}
+
+		if receipt.Mode == models.ErasureModeDelete {
+			res, err = tx.NewDelete().
+				Model((*models.Profile)(nil)).
+				Where("profile_code = ?", code).
+				ForceDelete().
+				Exec(ctx)
+		} else {
+			// the row stays so counts and foreign keys keep working, but
+			// nothing in it points back to the person anymore
+			res, err = tx.NewUpdate().
+				Model((*models.Profile)(nil)).
+				Set("first_name = ''").
+				Set("last_name = NULL").
+				Set("email = ''").
+				Set("email_bidx = NULL").
+				Set("phone = ''").
+				Set("country = ''").
+				Set("city = ''").
+				Set("address = ''").
+				Set("postal_code = 0").
+				Set("driving_license = NULL").
+				Set("nationality = NULL").
+				Set("place_of_birth = ''").
+				Set("date_of_birth = NULL").
+				Set("date_of_birth_enc = NULL").
+				Set("photo_url = NULL").
+				Set("photo_hash = NULL").
+				Set("working_experience = NULL").
+				Set("owner_id = NULL").
+				Set("updated_at = ?", receipt.ErasedAt).
+				Where("profile_code = ?", code).
+				WhereAllWithDeleted().
+				Exec(ctx)
+		}
+		if err != nil {
+			return err
+		}
+		rows, err = res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		receipt.ProfileRows = int(rows)
+
+		_, err = tx.NewInsert().
+			Model(receipt).
+			Returning("id").
+			Exec(ctx)
+		return err
+	})
+}
+
+// deleteOwnerIdempotencyKeys removes the idempotency keys of the profile's
+// owner and of the owner's API keys. Their stored responses echo the rows
+// that were created, and they are kept per caller rather than per profile.
+func deleteOwnerIdempotencyKeys(db bun.IDB, code int) *bun.DeleteQuery {
+	owner := db.NewSelect().
+		TableExpr("profile").
+		ColumnExpr("owner_id").
+		Where("profile_code = ?", code)
+	return db.NewDelete().
+		Model((*models.IdempotencyKey)(nil)).
+		Where("scope = 'user:' || (?)", owner).
+		WhereOr("scope IN (?)", db.NewSelect().
+			TableExpr("api_keys").
+			ColumnExpr("'key:' || id").
+			Where("user_id = (?)", owner))
+}
+
+// deleteByProfileCode removes the rows for good, including those in the
+// trash.
+func deleteByProfileCode(ctx context.Context, tx bun.Tx, model interface{}, code int) (int, error) {
+	res, err := tx.NewDelete().
+		Model(model).
+		Where("profile_code = ?", code).
+		ForceDelete().
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := res.RowsAffected()
+	return int(rows), err
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+func TestDeleteOwnerIdempotencyKeys(t *testing.T) {
+	db := bun.NewDB(&sql.DB{}, pgdialect.New())
+	query := deleteOwnerIdempotencyKeys(db, 7).String()
+
+	assert.Contains(t, query, `DELETE FROM "idempotency_keys"`)
+	assert.Contains(t, query, `scope = 'user:' || (SELECT owner_id FROM profile WHERE (profile_code = 7))`)
+	assert.Contains(t, query, `scope IN (SELECT 'key:' || id FROM api_keys WHERE (user_id = (SELECT owner_id FROM profile WHERE (profile_code = 7))))`)
+}
@@ -2,6 +2,10 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"test-bpjs/v2/helper/fieldcrypt"
+	"test-bpjs/v2/helper/taxonomy"
 	"test-bpjs/v2/models"
 	"time"
 
@@ -11,71 +15,511 @@ import (
 type ProfileRepository interface {
 	GetProfileByCode(ctx context.Context, code int) (*models.ProfileDTO, error)
 	GetWorkingExperienceByCode(ctx context.Context, code int) (*models.ProfileDTO, error)
+	ListProfiles(ctx context.Context, filter *models.ProfileFilter) ([]*models.ProfileDTO, error)
 	CreateProfile(ctx context.Context, payload *models.Profile) (*models.ProfileDTO, error)
-	UpdateProfile(ctx context.Context, code int, payload *models.Profile) (*models.ProfileDTO, error)
-	DeletePhotoByCode(ctx context.Context, code int) (*models.DefaultResponse, error)
+	UpdateProfile(ctx context.Context, code, version int, payload *models.Profile) (*models.ProfileDTO, error)
+	PatchProfile(ctx context.Context, code, version int, payload *models.Profile, columns []string) (*models.ProfileDTO, error)
+	DeleteProfile(ctx context.Context, code, version int) (*models.ProfileDTO, error)
+	RestoreProfile(ctx context.Context, code int) (*models.ProfileDTO, error)
+	DeletePhotoByCode(ctx context.Context, code, version int) (*models.DefaultResponse, error)
+	CountProfilesByPhotoHash(ctx context.Context, hash string) (int, error)
+	GetProfileOwner(ctx context.Context, code int) (int, error)
+	GetProfileCodeByPublicId(ctx context.Context, publicId string) (int, error)
+	GetProfileCodesByEmail(ctx context.Context, email string) ([]int, error)
+	RotateProfileKeys(ctx context.Context) (int, error)
 }
 
+// profileSortKeys are the expressions ListProfiles can sort by. Rows with no
+// timestamp sort as the zero time, which is what their cursor holds.
+var profileSortKeys = map[string]string{
+	"created_at": "COALESCE(profile.created_at, '0001-01-01 00:00:00+00')",
+	"updated_at": "COALESCE(profile.updated_at, '0001-01-01 00:00:00+00')",
+}
+
+// rotateBatchSize is how many profiles RotateProfileKeys loads at a time.
+const rotateBatchSize = 100
+
+// profileRepository encrypts the configured personal data columns on the
+// way in and decrypts them on the way out, so services only see plain values.
 type profileRepository struct {
-	DB bun.IDB
+	DB     bun.IDB
+	cipher *fieldcrypt.Cipher
 }
 
-func NewProfileRepository(db bun.IDB) *profileRepository {
+func NewProfileRepository(db bun.IDB, cipher *fieldcrypt.Cipher) *profileRepository {
 	return &profileRepository{
-		DB: db,
+		DB:     db,
+		cipher: cipher,
 	}
 }
 
 func (p *profileRepository) GetProfileByCode(ctx context.Context, code int) (*models.ProfileDTO, error) {
 	var profile models.ProfileDTO
-	err := p.DB.NewSelect().
+	err := profileLookup(ctx, p.DB.NewSelect().
 		Model((*models.Profile)(nil)).
-		Column("profile_code", "wanted_job_title", "first_name", "last_name", "email", "phone", "country", "city", "address", "postal_code", "driving_license", "nationality", "place_of_birth", "date_of_birth", "photo_url").
-		Where("profile_code = ?", code).
+		Column("profile_code", "public_id", "wanted_job_title", "first_name", "last_name", "email", "phone", "country", "city", "address", "postal_code", "driving_license", "nationality", "place_of_birth", "date_of_birth", "date_of_birth_enc", "photo_url", "photo_hash", "version").
+		Where("profile_code = ?", code)).
 		Scan(ctx, &profile)
-	return &profile, err
+	if err != nil {
+		return &profile, err
+	}
+	return &profile, p.open(&profile)
 }
 
 func (p *profileRepository) GetWorkingExperienceByCode(ctx context.Context, code int) (*models.ProfileDTO, error) {
 	var profile models.ProfileDTO
 	err := p.DB.NewSelect().
 		Model((*models.Profile)(nil)).
-		Column("working_experience").
+		Column("working_experience", "version").
 		Where("profile_code = ?", code).
 		Scan(ctx, &profile)
 	return &profile, err
 }
 
+// ListProfiles returns a page of the profiles the filter matches, starting
+// after its cursor. It only reads columns that are never encrypted, so the
+// filters can be evaluated by the database.
+func (p *profileRepository) ListProfiles(ctx context.Context, filter *models.ProfileFilter) ([]*models.ProfileDTO, error) {
+	query, err := p.listQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+	var profiles []*models.ProfileDTO
+	err = query.Scan(ctx, &profiles)
+	return profiles, err
+}
+
+func (p *profileRepository) listQuery(filter *models.ProfileFilter) (*bun.SelectQuery, error) {
+	key, ok := profileSortKeys[filter.Sort]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sort: %q", filter.Sort)
+	}
+
+	query := p.DB.NewSelect().
+		Model((*models.Profile)(nil)).
+		Column("profile_code", "public_id", "wanted_job_title", "first_name", "last_name", "country", "city", "nationality", "created_at", "updated_at")
+	whereVisible(p.DB, query, filter.Visibility)
+	for _, field := range []struct{ column, value string }{
+		{"country", filter.Country},
+		{"city", filter.City},
+		{"nationality", filter.Nationality},
+	} {
+		if field.value != "" {
+			query.Where("lower(?) = lower(?)", bun.Ident("profile."+field.column), field.value)
+		}
+	}
+	if filter.WantedJobTitle != "" {
+		query.Where("profile.wanted_job_title ILIKE ?", "%"+escapeLike(filter.WantedJobTitle)+"%")
+	}
+	if filter.Skill != "" || filter.SkillLevel != "" {
+		skills := p.DB.NewSelect().
+			Model((*models.Skill)(nil)).
+			ColumnExpr("1").
+			Where("?TableAlias.profile_code = profile.profile_code")
+		if filter.Skill != "" {
+			// rows stored before the taxonomy may still use an alias
+			skills.Where("lower(?TableAlias.skill) IN (?)", bun.In(taxonomy.Default().Spellings(filter.Skill)))
+		}
+		if filter.SkillLevel != "" {
+			skills.Where("lower(?TableAlias.level) = lower(?)", filter.SkillLevel)
+		}
+		query.Where("EXISTS (?)", skills)
+	}
+
+	direction, compare := "ASC", ">"
+	if filter.Descending {
+		direction, compare = "DESC", "<"
+	}
+	if filter.After != nil {
+		query.Where("("+key+", profile.public_id) "+compare+" (?, ?)", filter.After.At, filter.After.PublicId)
+	}
+	return query.
+		OrderExpr(key + " " + direction).
+		OrderExpr("profile.public_id " + direction).
+		Limit(filter.Limit), nil
+}
+
+// whereVisible limits a query joining the profile table to the profiles the
+// visibility allows.
+func whereVisible(db bun.IDB, query *bun.SelectQuery, visibility models.ProfileVisibility) {
+	if visibility.All {
+		return
+	}
+	query.WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+		q.Where("profile.owner_id = ?", visibility.OwnerId)
+		if visibility.Consented {
+			consents := db.NewSelect().
+				Model((*models.Consent)(nil)).
+				ColumnExpr("1").
+				Where("?TableAlias.profile_code = profile.profile_code").
+				Where("purpose = ?", models.ConsentPurposeRecruiterAccess).
+				Where("revoked_at IS NULL")
+			if visibility.TermsVersion != "" {
+				consents.Where("terms_version = ?", visibility.TermsVersion)
+			}
+			q.WhereOr("EXISTS (?)", consents)
+		}
+		return q
+	})
+}
+
+// escapeLike makes wildcards in a search term match themselves.
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(term)
+}
+
 func (p *profileRepository) CreateProfile(ctx context.Context, payload *models.Profile) (*models.ProfileDTO, error) {
 	var profile models.ProfileDTO
-	_, err := p.DB.NewInsert().
-		Model(payload).
-		Returning("profile_code").
+	sealed, err := p.seal(payload)
+	if err != nil {
+		return &profile, err
+	}
+	_, err = p.DB.NewInsert().
+		Model(sealed).
+		Returning("profile_code, public_id").
 		Exec(ctx, &profile)
 	return &profile, err
 }
 
-func (p *profileRepository) UpdateProfile(ctx context.Context, code int, payload *models.Profile) (*models.ProfileDTO, error) {
+// UpdateProfile writes the non-empty fields of the payload, unless the profile
+// has been changed since the given version, and returns the new version.
+func (p *profileRepository) UpdateProfile(ctx context.Context, code, version int, payload *models.Profile) (*models.ProfileDTO, error) {
 	var profile models.ProfileDTO
 	payload.UpdatedAt = time.Now()
-	_, err := p.DB.NewUpdate().
-		Model(payload).
+	sealed, err := p.seal(payload)
+	if err != nil {
+		return &profile, err
+	}
+	res, err := p.DB.NewUpdate().
+		Model(sealed).
 		OmitZero().
+		Value("version", "version + 1").
 		Where("profile_code = ?", code).
-		Returning("profile_code").
+		Where("version = ?", version).
+		Returning("profile_code, version").
 		Exec(ctx, &profile)
-	return &profile, err
+	return &profile, guarded(ctx, p.row(code), res, err)
+}
+
+// PatchProfile writes the given columns of the payload, empty or not, unless
+// the profile has been changed since the given version, and returns the new
+// version.
+func (p *profileRepository) PatchProfile(ctx context.Context, code, version int, payload *models.Profile, columns []string) (*models.ProfileDTO, error) {
+	var profile models.ProfileDTO
+	payload.UpdatedAt = time.Now()
+	sealed, err := p.seal(payload)
+	if err != nil {
+		return &profile, err
+	}
+	columns = append(columns, "updated_at", "version")
+	for _, column := range columns {
+		// the sealed values live in companion columns, which have to be
+		// written, or cleared, along with the plain ones
+		switch column {
+		case "email":
+			columns = append(columns, "email_bidx")
+		case "date_of_birth":
+			columns = append(columns, "date_of_birth_enc")
+		}
+	}
+	res, err := p.DB.NewUpdate().
+		Model(sealed).
+		Column(columns...).
+		Value("version", "version + 1").
+		Where("profile_code = ?", code).
+		Where("version = ?", version).
+		Returning("profile_code, version").
+		Exec(ctx, &profile)
+	return &profile, guarded(ctx, p.row(code), res, err)
+}
+
+// DeleteProfile moves the profile to the trash. Its education, employment
+// and skill rows are left alone, so restoring the profile brings them back.
+func (p *profileRepository) DeleteProfile(ctx context.Context, code, version int) (*models.ProfileDTO, error) {
+	var profile models.ProfileDTO
+	res, err := p.DB.NewDelete().
+		Model((*models.Profile)(nil)).
+		Where("profile_code = ?", code).
+		Where("version = ?", version).
+		Returning("profile_code, public_id, deleted_at").
+		Exec(ctx, &profile)
+	return &profile, guarded(ctx, p.row(code), res, err)
 }
 
-func (p *profileRepository) DeletePhotoByCode(ctx context.Context, code int) (*models.DefaultResponse, error) {
+func (p *profileRepository) RestoreProfile(ctx context.Context, code int) (*models.ProfileDTO, error) {
 	var profile models.ProfileDTO
-	_, err := p.DB.NewUpdate().
+	res, err := p.DB.NewUpdate().
+		Model((*models.Profile)(nil)).
+		Set("deleted_at = NULL").
+		Set("version = version + 1").
+		Where("profile_code = ?", code).
+		WhereDeleted().
+		Returning("profile_code, public_id").
+		Exec(ctx, &profile)
+	return &profile, affected(res, err)
+}
+
+func (p *profileRepository) DeletePhotoByCode(ctx context.Context, code, version int) (*models.DefaultResponse, error) {
+	var profile models.ProfileDTO
+	res, err := p.DB.NewUpdate().
 		Model((*models.Profile)(nil)).
 		Set("photo_url = NULL").
+		Set("photo_hash = NULL").
+		Set("version = version + 1").
 		Where("profile_code = ?", code).
+		Where("version = ?", version).
 		Returning("profile_code").
 		Exec(ctx, &profile)
 	return &models.DefaultResponse{
 		ProfileCode: profile.ProfileCode,
-	}, err
+	}, guarded(ctx, p.row(code), res, err)
+}
+
+// row selects the profile for telling a stale version from a missing profile.
+func (p *profileRepository) row(code int) *bun.SelectQuery {
+	return p.DB.NewSelect().
+		Model((*models.Profile)(nil)).
+		Where("profile_code = ?", code)
+}
+
+// CountProfilesByPhotoHash counts profiles in the trash too, as restoring
+// them brings their photo back.
+func (p *profileRepository) CountProfilesByPhotoHash(ctx context.Context, hash string) (int, error) {
+	return p.DB.NewSelect().
+		Model((*models.Profile)(nil)).
+		Where("photo_hash = ?", hash).
+		WhereAllWithDeleted().
+		Count(ctx)
+}
+
+// GetProfileOwner finds profiles in the trash only with WithTrashedProfiles,
+// so their owner can still restore them.
+func (p *profileRepository) GetProfileOwner(ctx context.Context, code int) (int, error) {
+	var ownerId int
+	err := profileLookup(ctx, p.DB.NewSelect().
+		Model((*models.Profile)(nil)).
+		ColumnExpr("COALESCE(owner_id, 0)").
+		Where("profile_code = ?", code)).
+		Scan(ctx, &ownerId)
+	return ownerId, err
+}
+
+func (p *profileRepository) GetProfileCodeByPublicId(ctx context.Context, publicId string) (int, error) {
+	var code int
+	err := profileLookup(ctx, p.DB.NewSelect().
+		Model((*models.Profile)(nil)).
+		Column("profile_code").
+		Where("public_id = ?", publicId)).
+		Scan(ctx, &code)
+	return code, err
+}
+
+// GetProfileCodesByEmail looks profiles up through the email blind index, so
+// it works whether or not the email column is encrypted.
+func (p *profileRepository) GetProfileCodesByEmail(ctx context.Context, email string) ([]int, error) {
+	var codes []int
+	query := p.DB.NewSelect().
+		Model((*models.Profile)(nil)).
+		Column("profile_code")
+	if index := p.cipher.BlindIndex(email); index != "" {
+		query.Where("email_bidx = ?", index)
+	} else {
+		query.Where("lower(email) = lower(?)", email)
+	}
+	err := query.Order("profile_code").Scan(ctx, &codes)
+	return codes, err
+}
+
+// RotateProfileKeys re-wraps values sealed with an older key, encrypts
+// columns that were stored before encryption was turned on and fills missing
+// blind indexes, including those of profiles in the trash. It returns how many
+// profiles were updated.
+func (p *profileRepository) RotateProfileKeys(ctx context.Context) (int, error) {
+	updated, lastCode := 0, 0
+	for {
+		var profiles []models.Profile
+		err := p.DB.NewSelect().
+			Model(&profiles).
+			Column("profile_code", "email", "email_bidx", "phone", "address", "date_of_birth", "date_of_birth_enc").
+			Where("profile_code > ?", lastCode).
+			WhereAllWithDeleted().
+			Order("profile_code").
+			Limit(rotateBatchSize).
+			Scan(ctx)
+		if err != nil {
+			return updated, err
+		}
+		if len(profiles) == 0 {
+			return updated, nil
+		}
+
+		for i := range profiles {
+			profile := &profiles[i]
+			lastCode = profile.ProfileCode
+
+			changed, err := p.rotate(profile)
+			if err != nil {
+				return updated, fmt.Errorf("failed to rotate profile %d: %v", profile.ProfileCode, err)
+			}
+			if !changed {
+				continue
+			}
+			_, err = p.DB.NewUpdate().
+				Model(profile).
+				Column("email", "email_bidx", "phone", "address", "date_of_birth", "date_of_birth_enc").
+				WherePK().
+				WhereAllWithDeleted().
+				Exec(ctx)
+			if err != nil {
+				return updated, err
+			}
+			updated++
+		}
+	}
+}
+
+// bumpProfileVersion moves the profile to the next version unless it has been
+// changed since the given one, and returns the new version. Writes replacing
+// a whole list of child rows take it first, so two of them based on the same
+// version can't both go through.
+func bumpProfileVersion(ctx context.Context, tx bun.Tx, code, version int) (int, error) {
+	var next int
+	res, err := tx.NewUpdate().
+		Model((*models.Profile)(nil)).
+		Set("version = version + 1").
+		Where("profile_code = ?", code).
+		Where("version = ?", version).
+		Returning("version").
+		Exec(ctx, &next)
+	row := tx.NewSelect().
+		Model((*models.Profile)(nil)).
+		Where("profile_code = ?", code)
+	return next, guarded(ctx, row, res, err)
+}
+
+// seal returns a copy of the payload with the configured columns encrypted
+// and the email blind index set. Zero values are left alone so OmitZero
+// updates keep working.
+func (p *profileRepository) seal(payload *models.Profile) (*models.Profile, error) {
+	sealed := *payload
+	if sealed.Email != "" {
+		sealed.EmailIndex = p.cipher.BlindIndex(sealed.Email)
+	}
+	for _, field := range textColumns(&sealed) {
+		if *field.value == "" || !p.cipher.Encrypts(field.column) {
+			continue
+		}
+		encrypted, err := p.cipher.Encrypt(*field.value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt %s: %v", field.column, err)
+		}
+		*field.value = encrypted
+	}
+
+	// a date column can't hold ciphertext, so the sealed date goes to its
+	// own column and the plain one is left empty
+	if !sealed.DateOfBirth.IsZero() && p.cipher.Encrypts("date_of_birth") {
+		encrypted, err := p.cipher.Encrypt(sealed.DateOfBirth.Format(time.DateOnly))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt date_of_birth: %v", err)
+		}
+		sealed.DateOfBirthEnc = encrypted
+		sealed.DateOfBirth = time.Time{}
+	}
+	return &sealed, nil
+}
+
+func (p *profileRepository) open(profile *models.ProfileDTO) error {
+	for _, field := range []textColumn{
+		{"email", &profile.Email},
+		{"phone", &profile.Phone},
+		{"address", &profile.Address},
+	} {
+		plain, err := p.cipher.Decrypt(*field.value)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt %s: %v", field.column, err)
+		}
+		*field.value = plain
+	}
+
+	if profile.DateOfBirthEnc != "" {
+		plain, err := p.cipher.Decrypt(profile.DateOfBirthEnc)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt date_of_birth: %v", err)
+		}
+		profile.DateOfBirth, err = time.Parse(time.DateOnly, plain)
+		if err != nil {
+			return fmt.Errorf("failed to parse date_of_birth: %v", err)
+		}
+		profile.DateOfBirthEnc = ""
+	}
+	return nil
+}
+
+// rotate brings a stored profile up to date with the current key and column
+// configuration. It reports whether anything changed.
+func (p *profileRepository) rotate(profile *models.Profile) (bool, error) {
+	email, err := p.cipher.Decrypt(profile.Email)
+	if err != nil {
+		return false, err
+	}
+	changed := false
+	if index := p.cipher.BlindIndex(email); index != "" && index != profile.EmailIndex {
+		profile.EmailIndex, changed = index, true
+	}
+
+	for _, field := range textColumns(profile) {
+		value, ok, err := p.reseal(field.column, *field.value)
+		if err != nil {
+			return false, err
+		}
+		*field.value, changed = value, changed || ok
+	}
+
+	if profile.DateOfBirthEnc == "" && !profile.DateOfBirth.IsZero() {
+		value, ok, err := p.reseal("date_of_birth", profile.DateOfBirth.Format(time.DateOnly))
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			profile.DateOfBirthEnc, profile.DateOfBirth, changed = value, time.Time{}, true
+		}
+	} else if profile.DateOfBirthEnc != "" {
+		value, ok, err := p.reseal("date_of_birth", profile.DateOfBirthEnc)
+		if err != nil {
+			return false, err
+		}
+		profile.DateOfBirthEnc, changed = value, changed || ok
+	}
+	return changed, nil
+}
+
+// reseal re-wraps an encrypted value with the current key, or encrypts a
+// plain one if its column is configured.
+func (p *profileRepository) reseal(column, value string) (string, bool, error) {
+	if fieldcrypt.IsEncrypted(value) {
+		return p.cipher.Rotate(value)
+	}
+	if value == "" || !p.cipher.Encrypts(column) {
+		return value, false, nil
+	}
+	encrypted, err := p.cipher.Encrypt(value)
+	if err != nil {
+		return "", false, err
+	}
+	return encrypted, true, nil
+}
+
+type textColumn struct {
+	column string
+	value  *string
+}
+
+// textColumns lists the text columns of a profile that can be encrypted.
+func textColumns(profile *models.Profile) []textColumn {
+	return []textColumn{
+		{"email", &profile.Email},
+		{"phone", &profile.Phone},
+		{"address", &profile.Address},
+	}
 }
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"strings"
+	"test-bpjs/v2/config"
+	"test-bpjs/v2/helper/fieldcrypt"
+	"test-bpjs/v2/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+func testCipher(t *testing.T, currentKeyId string, keyIds ...string) *fieldcrypt.Cipher {
+	keys := map[string]string{}
+	for _, keyId := range keyIds {
+		keys[keyId] = base64.StdEncoding.EncodeToString([]byte(strings.Repeat(keyId, 16)))
+	}
+	cipher, err := fieldcrypt.NewCipher(config.Config{
+		EncryptionKeys:   keys,
+		EncryptionKeyId:  currentKeyId,
+		BlindIndexKey:    base64.StdEncoding.EncodeToString([]byte(strings.Repeat("bi", 16))),
+		EncryptedColumns: []string{"email", "phone", "address", "date_of_birth"},
+	})
+	assert.Nil(t, err)
+	return cipher
+}
+
+func TestSealAndOpenProfile(t *testing.T) {
+	repo := NewProfileRepository(nil, testCipher(t, "k1", "k1"))
+	dateOfBirth := time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)
+	payload := &models.Profile{
+		FirstName:   "John",
+		Email:       "john@example.com",
+		Phone:       "0812345678",
+		Address:     "Jl. Sudirman 1",
+		DateOfBirth: dateOfBirth,
+	}
+
+	sealed, err := repo.seal(payload)
+	assert.Nil(t, err)
+	assert.Equal(t, "john@example.com", payload.Email, "the caller's payload is left alone")
+	assert.Equal(t, "John", sealed.FirstName)
+	assert.True(t, fieldcrypt.IsEncrypted(sealed.Email))
+	assert.True(t, fieldcrypt.IsEncrypted(sealed.Phone))
+	assert.True(t, fieldcrypt.IsEncrypted(sealed.Address))
+	assert.True(t, sealed.DateOfBirth.IsZero())
+	assert.True(t, fieldcrypt.IsEncrypted(sealed.DateOfBirthEnc))
+	assert.Equal(t, repo.cipher.BlindIndex("JOHN@example.com"), sealed.EmailIndex)
+
+	profile := &models.ProfileDTO{
+		Email:          sealed.Email,
+		Phone:          sealed.Phone,
+		Address:        sealed.Address,
+		DateOfBirthEnc: sealed.DateOfBirthEnc,
+	}
+	assert.Nil(t, repo.open(profile))
+	assert.Equal(t, "john@example.com", profile.Email)
+	assert.Equal(t, "0812345678", profile.Phone)
+	assert.Equal(t, "Jl. Sudirman 1", profile.Address)
+	assert.Equal(t, dateOfBirth, profile.DateOfBirth)
+	assert.Empty(t, profile.DateOfBirthEnc)
+}
+
+func TestSealSkipsZeroValues(t *testing.T) {
+	repo := NewProfileRepository(nil, testCipher(t, "k1", "k1"))
+
+	sealed, err := repo.seal(&models.Profile{PhotoUrl: "public/image/a.png"})
+	assert.Nil(t, err)
+	assert.Empty(t, sealed.Email)
+	assert.Empty(t, sealed.EmailIndex)
+	assert.Empty(t, sealed.DateOfBirthEnc)
+}
+
+func TestRotateProfile(t *testing.T) {
+	oldRepo := NewProfileRepository(nil, testCipher(t, "k1", "k1"))
+	newRepo := NewProfileRepository(nil, testCipher(t, "k2", "k1", "k2"))
+
+	t.Run("SuccessRewrapOldKey", func(t *testing.T) {
+		stored, err := oldRepo.seal(&models.Profile{
+			Email:       "john@example.com",
+			Phone:       "0812345678",
+			DateOfBirth: time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC),
+		})
+		assert.Nil(t, err)
+
+		changed, err := newRepo.rotate(stored)
+		assert.Nil(t, err)
+		assert.True(t, changed)
+		assert.True(t, strings.HasPrefix(stored.Email, "enc:v1:k2:"))
+		assert.True(t, strings.HasPrefix(stored.DateOfBirthEnc, "enc:v1:k2:"))
+
+		changed, err = newRepo.rotate(stored)
+		assert.Nil(t, err)
+		assert.False(t, changed)
+	})
+	t.Run("SuccessEncryptLegacyRow", func(t *testing.T) {
+		stored := &models.Profile{
+			Email:       "jane@example.com",
+			Address:     "Jl. Thamrin 2",
+			DateOfBirth: time.Date(1992, 1, 2, 0, 0, 0, 0, time.UTC),
+		}
+
+		changed, err := newRepo.rotate(stored)
+		assert.Nil(t, err)
+		assert.True(t, changed)
+		assert.True(t, fieldcrypt.IsEncrypted(stored.Email))
+		assert.True(t, fieldcrypt.IsEncrypted(stored.Address))
+		assert.Empty(t, stored.Phone)
+		assert.True(t, stored.DateOfBirth.IsZero())
+		assert.Equal(t, newRepo.cipher.BlindIndex("jane@example.com"), stored.EmailIndex)
+	})
+}
+
+func TestListProfilesQuery(t *testing.T) {
+	repo := NewProfileRepository(bun.NewDB(&sql.DB{}, pgdialect.New()), testCipher(t, "k1", "k1"))
+
+	query, err := repo.listQuery(&models.ProfileFilter{
+		City:           "Jakarta",
+		WantedJobTitle: "100%_go",
+		Skill:          "Golang",
+		Visibility:     models.ProfileVisibility{OwnerId: 3, Consented: true},
+		Sort:           "updated_at",
+		Descending:     true,
+		After:          &models.ProfileCursor{At: time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC), PublicId: "01JAB3Q8W0RM0ZTRBPN7C2Z1K4"},
+		Limit:          21,
+	})
+	assert.Nil(t, err)
+	listing := query.String()
+	assert.Contains(t, listing, `(profile.owner_id = 3) OR (EXISTS (SELECT 1 FROM "consents"`)
+	assert.Contains(t, listing, `lower("profile"."city") = lower('Jakarta')`)
+	assert.Contains(t, listing, `ILIKE '%100\%\_go%'`)
+	assert.Contains(t, listing, `(lower("skill".skill) IN ('go', 'golang', 'go lang')) AND "skill"."deleted_at" IS NULL`)
+	assert.Contains(t, listing, `(COALESCE(profile.updated_at, '0001-01-01 00:00:00+00'), profile.public_id) < ('2024-11-01 00:00:00+00:00', '01JAB3Q8W0RM0ZTRBPN7C2Z1K4')`)
+	assert.Contains(t, listing, `ORDER BY COALESCE(profile.updated_at, '0001-01-01 00:00:00+00') DESC, profile.public_id DESC LIMIT 21`)
+
+	// admins see every profile
+	query, err = repo.listQuery(&models.ProfileFilter{Visibility: models.ProfileVisibility{All: true}, Sort: "created_at", Limit: 21})
+	assert.Nil(t, err)
+	assert.NotContains(t, query.String(), "owner_id")
+
+	_, err = repo.listQuery(&models.ProfileFilter{Sort: "first_name"})
+	assert.Error(t, err)
+}
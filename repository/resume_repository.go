@@ -0,0 +1,327 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"test-bpjs/v2/helper/fieldcrypt"
+	"test-bpjs/v2/models"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type ResumeRepository interface {
+	GetResumeVersions(ctx context.Context, code int) ([]*models.ResumeVersionDTO, error)
+	GetResumeVersion(ctx context.Context, code, version int) (*models.ResumeVersionDTO, error)
+	GetLatestResumeChecksum(ctx context.Context, code int) (string, error)
+	CreateResumeVersion(ctx context.Context, payload *models.ResumeVersion) (*models.ResumeVersionDTO, error)
+	RestoreResume(ctx context.Context, code int, snapshot *models.ResumeSnapshot) error
+	RotateResumeKeys(ctx context.Context) (int, error)
+}
+
+// resumeRepository stores snapshots encrypted as a whole, since they carry
+// every personal data column of the profile.
+type resumeRepository struct {
+	DB     bun.IDB
+	cipher *fieldcrypt.Cipher
+}
+
+func NewResumeRepository(db bun.IDB, cipher *fieldcrypt.Cipher) *resumeRepository {
+	return &resumeRepository{
+		DB:     db,
+		cipher: cipher,
+	}
+}
+
+func (r *resumeRepository) GetResumeVersions(ctx context.Context, code int) ([]*models.ResumeVersionDTO, error) {
+	var versions []*models.ResumeVersionDTO
+	err := r.DB.NewSelect().
+		Model((*models.ResumeVersion)(nil)).
+		Column("id", "profile_code", "version", "label", "automatic", "created_by", "created_at").
+		Where("profile_code = ?", code).
+		Order("version DESC").
+		Scan(ctx, &versions)
+	return versions, err
+}
+
+func (r *resumeRepository) GetResumeVersion(ctx context.Context, code, version int) (*models.ResumeVersionDTO, error) {
+	var stored models.ResumeVersion
+	err := r.DB.NewSelect().
+		Model(&stored).
+		Column("id", "profile_code", "version", "label", "automatic", "checksum", "snapshot", "created_by", "created_at").
+		Where("profile_code = ?", code).
+		Where("version = ?", version).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.open(stored)
+}
+
+func (r *resumeRepository) open(stored models.ResumeVersion) (*models.ResumeVersionDTO, error) {
+	snapshot, err := r.cipher.Decrypt(stored.Snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt snapshot: %v", err)
+	}
+	resumeVersion := &models.ResumeVersionDTO{
+		Id:          stored.Id,
+		ProfileCode: stored.ProfileCode,
+		Version:     stored.Version,
+		Label:       stored.Label,
+		Automatic:   stored.Automatic,
+		Checksum:    stored.Checksum,
+		CreatedBy:   stored.CreatedBy,
+		CreatedAt:   stored.CreatedAt,
+	}
+	if err := json.Unmarshal([]byte(snapshot), &resumeVersion.Snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %v", err)
+	}
+	return resumeVersion, nil
+}
+
+// GetLatestResumeChecksum returns the checksum of the newest version, or ""
+// when the profile has none.
+func (r *resumeRepository) GetLatestResumeChecksum(ctx context.Context, code int) (string, error) {
+	var checksums []string
+	err := r.DB.NewSelect().
+		Model((*models.ResumeVersion)(nil)).
+		Column("checksum").
+		Where("profile_code = ?", code).
+		Order("version DESC").
+		Limit(1).
+		Scan(ctx, &checksums)
+	if err != nil || len(checksums) == 0 {
+		return "", err
+	}
+	return checksums[0], nil
+}
+
+// CreateResumeVersion stores the payload's snapshot, given as plain JSON,
+// under the next version number of the profile.
+func (r *resumeRepository) CreateResumeVersion(ctx context.Context, payload *models.ResumeVersion) (*models.ResumeVersionDTO, error) {
+	var resumeVersion models.ResumeVersionDTO
+	sealed := *payload
+	snapshot, err := r.cipher.Encrypt(sealed.Snapshot)
+	if err != nil {
+		return &resumeVersion, fmt.Errorf("failed to encrypt snapshot: %v", err)
+	}
+	sealed.Snapshot = snapshot
+
+	_, err = r.DB.NewInsert().
+		Model(&sealed).
+		Value("version", "(SELECT COALESCE(MAX(version), 0) + 1 FROM resume_versions WHERE profile_code = ?)", sealed.ProfileCode).
+		Returning("id, profile_code, version, label, automatic, created_by, created_at").
+		Exec(ctx, &resumeVersion)
+	return &resumeVersion, err
+}
+
+// RotateResumeKeys re-wraps the snapshots sealed with an older key. It returns
+// how many versions were updated.
+func (r *resumeRepository) RotateResumeKeys(ctx context.Context) (int, error) {
+	updated, lastId := 0, 0
+	for {
+		var versions []models.ResumeVersion
+		err := r.DB.NewSelect().
+			Model(&versions).
+			Column("id", "snapshot").
+			Where("id > ?", lastId).
+			Order("id").
+			Limit(rotateBatchSize).
+			Scan(ctx)
+		if err != nil {
+			return updated, err
+		}
+		if len(versions) == 0 {
+			return updated, nil
+		}
+
+		for i := range versions {
+			version := &versions[i]
+			lastId = version.Id
+
+			changed, err := r.rotate(version)
+			if err != nil {
+				return updated, fmt.Errorf("failed to rotate resume version %d: %v", version.Id, err)
+			}
+			if !changed {
+				continue
+			}
+			_, err = r.DB.NewUpdate().
+				Model(version).
+				Column("snapshot").
+				WherePK().
+				Exec(ctx)
+			if err != nil {
+				return updated, err
+			}
+			updated++
+		}
+	}
+}
+
+func (r *resumeRepository) rotate(version *models.ResumeVersion) (bool, error) {
+	snapshot, changed, err := r.cipher.Rotate(version.Snapshot)
+	if err != nil {
+		return false, err
+	}
+	version.Snapshot = snapshot
+	return changed, nil
+}
+
+// RestoreResume overwrites the profile and replaces all of its education,
+// employment and skill rows with the ones in the snapshot, in a single
+// transaction. Child rows keep the ids they had in the snapshot, and current
+// rows missing from it go to the trash. The photo is left alone, as the file
+// of an old photo may no longer exist.
+func (r *resumeRepository) RestoreResume(ctx context.Context, code int, snapshot *models.ResumeSnapshot) error {
+	return r.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		profiles := NewProfileRepository(tx, r.cipher)
+		profile := snapshot.Profile
+		sealed, err := profiles.seal(&models.Profile{
+			WantedJobTitle:    profile.WantedJobTitle,
+			FirstName:         profile.FirstName,
+			LastName:          profile.LastName,
+			Email:             profile.Email,
+			Phone:             profile.Phone,
+			Country:           profile.Country,
+			City:              profile.City,
+			Address:           profile.Address,
+			PostalCode:        profile.PostalCode,
+			DrivingLicense:    profile.DrivingLicense,
+			Nationality:       profile.Nationality,
+			PlaceOfBirth:      profile.PlaceOfBirth,
+			DateOfBirth:       profile.DateOfBirth,
+			WorkingExperience: profile.WorkingExperience,
+			UpdatedAt:         time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		_, err = tx.NewUpdate().
+			Model(sealed).
+			Column("wanted_job_title", "first_name", "last_name", "email", "email_bidx", "phone", "country", "city", "address",
+				"postal_code", "driving_license", "nationality", "place_of_birth", "date_of_birth", "date_of_birth_enc",
+				"working_experience", "updated_at", "version").
+			Value("version", "version + 1").
+			Where("profile_code = ?", code).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		education := make([]models.Education, 0, len(snapshot.Education))
+		educationIds := make([]int, 0, len(snapshot.Education))
+		for _, row := range snapshot.Education {
+			educationIds = append(educationIds, row.Id)
+			education = append(education, models.Education{
+				ProfileCode: code,
+				Id:          row.Id,
+				School:      row.School,
+				Degree:      row.Degree,
+				StartDate:   row.StartDate,
+				EndDate:     row.EndDate,
+				City:        row.City,
+				Description: row.Description,
+				CreatedAt:   row.CreatedAt,
+			})
+		}
+		employment := make([]models.Employment, 0, len(snapshot.Employment))
+		employmentIds := make([]int, 0, len(snapshot.Employment))
+		for _, row := range snapshot.Employment {
+			employmentIds = append(employmentIds, row.Id)
+			employment = append(employment, models.Employment{
+				ProfileCode:    code,
+				Id:             row.Id,
+				JobTitle:       row.JobTitle,
+				Employer:       row.Employer,
+				StartDate:      row.StartDate,
+				EndDate:        row.EndDate,
+				City:           row.City,
+				Description:    row.Description,
+				EmploymentType: row.EmploymentType,
+				CreatedAt:      row.CreatedAt,
+			})
+		}
+		skills := make([]models.Skill, 0, len(snapshot.Skills))
+		skillIds := make([]int, 0, len(snapshot.Skills))
+		for _, row := range snapshot.Skills {
+			skillIds = append(skillIds, row.Id)
+			skills = append(skills, models.Skill{
+				ProfileCode: code,
+				Id:          row.Id,
+				Skill:       row.Skill,
+				Level:       row.Level,
+				CreatedAt:   row.CreatedAt,
+			})
+		}
+
+		educationVersion, err := replaceRows(ctx, tx, (*models.Education)(nil), code, educationIds)
+		if err != nil {
+			return err
+		}
+		employmentVersion, err := replaceRows(ctx, tx, (*models.Employment)(nil), code, employmentIds)
+		if err != nil {
+			return err
+		}
+		skillVersion, err := replaceRows(ctx, tx, (*models.Skill)(nil), code, skillIds)
+		if err != nil {
+			return err
+		}
+		for i := range education {
+			education[i].Version = educationVersion
+		}
+		for i := range employment {
+			employment[i].Version = employmentVersion
+		}
+		for i := range skills {
+			skills[i].Version = skillVersion
+		}
+
+		if len(education) > 0 {
+			if _, err := tx.NewInsert().Model(&education).Exec(ctx); err != nil {
+				return err
+			}
+		}
+		if len(employment) > 0 {
+			if _, err := tx.NewInsert().Model(&employment).Exec(ctx); err != nil {
+				return err
+			}
+		}
+		if len(skills) > 0 {
+			if _, err := tx.NewInsert().Model(&skills).Exec(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// replaceRows clears the way for reinserting the rows with the given ids,
+// wherever they are, and moves the other rows of the profile to the trash.
+// It returns a version above any the removed rows had, so the reinserted
+// rows do not match versions read before the restore.
+func replaceRows(ctx context.Context, tx bun.Tx, model interface{}, code int, ids []int) (int, error) {
+	version := 1
+	if len(ids) > 0 {
+		var versions []int
+		_, err := tx.NewDelete().
+			Model(model).
+			Where("profile_code = ?", code).
+			Where("id IN (?)", bun.In(ids)).
+			ForceDelete().
+			Returning("version").
+			Exec(ctx, &versions)
+		if err != nil {
+			return 0, err
+		}
+		for _, v := range versions {
+			version = max(version, v+1)
+		}
+	}
+	_, err := tx.NewDelete().
+		Model(model).
+		Where("profile_code = ?", code).
+		Exec(ctx)
+	return version, err
+}
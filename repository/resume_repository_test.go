@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"test-bpjs/v2/helper/fieldcrypt"
+	"test-bpjs/v2/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotateResumeVersion(t *testing.T) {
+	oldRepo := NewResumeRepository(nil, testCipher(t, "k1", "k1"))
+	newRepo := NewResumeRepository(nil, testCipher(t, "k2", "k1", "k2"))
+	// what is left once the old key was removed from the config
+	onlyNewRepo := NewResumeRepository(nil, testCipher(t, "k2", "k2"))
+
+	sealed, err := oldRepo.cipher.Encrypt(`{"profile":{"firstName":"John"}}`)
+	assert.Nil(t, err)
+	version := &models.ResumeVersion{Id: 1, ProfileCode: 2, Version: 3, Snapshot: sealed}
+
+	_, err = onlyNewRepo.open(*version)
+	assert.ErrorContains(t, err, fieldcrypt.ErrUnknownKey.Error())
+
+	changed, err := newRepo.rotate(version)
+	assert.Nil(t, err)
+	assert.True(t, changed)
+
+	resumeVersion, err := onlyNewRepo.open(*version)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, resumeVersion.Version)
+	assert.Equal(t, "John", resumeVersion.Snapshot.Profile.FirstName)
+
+	changed, err = newRepo.rotate(version)
+	assert.Nil(t, err)
+	assert.False(t, changed)
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"test-bpjs/v2/models"
+
+	"github.com/uptrace/bun"
+)
+
+type SearchRepository interface {
+	SearchProfiles(ctx context.Context, search *models.ProfileSearch) ([]*models.SearchResultDTO, error)
+}
+
+// searchConfigs maps the supported languages to their text search
+// configuration and the column holding the document parsed with it.
+var searchConfigs = map[string]struct{ config, column string }{
+	models.SearchLanguageEnglish:    {"english", "search_en"},
+	models.SearchLanguageIndonesian: {"indonesian", "search_id"},
+}
+
+// headlineOptions cut a couple of short fragments around the matches.
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5"
+
+type searchRepository struct {
+	DB bun.IDB
+}
+
+func NewSearchRepository(db bun.IDB) *searchRepository {
+	return &searchRepository{
+		DB: db,
+	}
+}
+
+// SearchProfiles returns a page of the matching profiles, best match first.
+// Snippets are only cut for the rows of the page, as they are expensive.
+func (s *searchRepository) SearchProfiles(ctx context.Context, search *models.ProfileSearch) ([]*models.SearchResultDTO, error) {
+	query, err := s.searchQuery(search)
+	if err != nil {
+		return nil, err
+	}
+	var results []*models.SearchResultDTO
+	err = query.Scan(ctx, &results)
+	return results, err
+}
+
+func (s *searchRepository) searchQuery(search *models.ProfileSearch) (*bun.SelectQuery, error) {
+	language, ok := searchConfigs[search.Language]
+	if !ok {
+		return nil, fmt.Errorf("unsupported search language: %q", search.Language)
+	}
+
+	hits := s.DB.NewSelect().
+		Model((*models.ProfileSearchIndex)(nil)).
+		Column("ps.profile_code", "ps.document").
+		ColumnExpr("profile.public_id, profile.wanted_job_title, profile.first_name, profile.last_name, profile.city, profile.country").
+		ColumnExpr("ts_rank_cd(?, query) AS rank", bun.Ident("ps."+language.column)).
+		ColumnExpr("query").
+		Join("JOIN profile ON profile.profile_code = ps.profile_code AND profile.deleted_at IS NULL").
+		Join("CROSS JOIN websearch_to_tsquery(?::regconfig, ?) AS query", language.config, search.Query).
+		Where("? @@ query", bun.Ident("ps."+language.column))
+	whereVisible(s.DB, hits, search.Visibility)
+	hits.OrderExpr("rank DESC, ps.profile_code").
+		Limit(search.Limit).
+		Offset(search.Offset)
+
+	// the document is escaped first, so the marks are the only markup in
+	// the snippet
+	return s.DB.NewSelect().
+		TableExpr("(?) AS hit", hits).
+		ColumnExpr("hit.profile_code, hit.public_id, hit.wanted_job_title, hit.first_name, hit.last_name, hit.city, hit.country, hit.rank").
+		ColumnExpr("ts_headline(?::regconfig, replace(replace(replace(hit.document, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), hit.query, ?) AS snippet", language.config, headlineOptions).
+		OrderExpr("hit.rank DESC, hit.profile_code"), nil
+}
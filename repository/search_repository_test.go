@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"database/sql"
+	"test-bpjs/v2/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+func TestSearchQuery(t *testing.T) {
+	repo := NewSearchRepository(bun.NewDB(&sql.DB{}, pgdialect.New()))
+
+	query, err := repo.searchQuery(&models.ProfileSearch{
+		Query:      "golang jakarta",
+		Language:   models.SearchLanguageIndonesian,
+		Visibility: models.ProfileVisibility{OwnerId: 3, Consented: true},
+		Limit:      20,
+		Offset:     40,
+	})
+	assert.Nil(t, err)
+	search := query.String()
+	assert.Contains(t, search, `websearch_to_tsquery('indonesian'::regconfig, 'golang jakarta') AS query WHERE ("ps"."search_id" @@ query)`)
+	assert.Contains(t, search, `(profile.owner_id = 3) OR (EXISTS (SELECT 1 FROM "consents"`)
+	// snippets are only cut for the page
+	assert.Contains(t, search, `LIMIT 20 OFFSET 40) AS hit`)
+	assert.Contains(t, search, `ts_headline('indonesian'::regconfig, replace(replace(replace(hit.document`)
+
+	query, err = repo.searchQuery(&models.ProfileSearch{Query: "golang", Language: models.SearchLanguageEnglish, Visibility: models.ProfileVisibility{All: true}, Limit: 20})
+	assert.Nil(t, err)
+	assert.Contains(t, query.String(), `"ps"."search_en" @@ query`)
+	assert.NotContains(t, query.String(), "owner_id")
+
+	_, err = repo.searchQuery(&models.ProfileSearch{Query: "golang", Language: "fr"})
+	assert.Error(t, err)
+}
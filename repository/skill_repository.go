@@ -9,8 +9,15 @@ import (
 
 type SkillRepository interface {
 	GetSkillsByProfileCode(ctx context.Context, code int) ([]*models.SkillDTO, error)
+	GetDeletedSkillsByProfileCode(ctx context.Context, code int) ([]*models.SkillDTO, error)
 	CreateSkill(ctx context.Context, payload *models.Skill) (*models.SkillDTO, error)
-	DeleteSkill(ctx context.Context, code, id int) error
+	CreateSkills(ctx context.Context, payload []*models.Skill) ([]int, error)
+	ReplaceSkills(ctx context.Context, code, version int, payload []*models.Skill) ([]*models.SkillDTO, []int, int, error)
+	PatchSkill(ctx context.Context, code, id, version int, payload *models.Skill, columns []string) (*models.SkillDTO, error)
+	DeleteSkill(ctx context.Context, code, id, version int) (*models.SkillDTO, error)
+	RestoreSkill(ctx context.Context, code, id int) (*models.SkillDTO, error)
+	GetSkillsAfter(ctx context.Context, id, limit int) ([]*models.SkillDTO, error)
+	NormalizeSkill(ctx context.Context, id, version int, skill, level string) error
 }
 
 type skillRepository struct {
@@ -27,12 +34,26 @@ func (s *skillRepository) GetSkillsByProfileCode(ctx context.Context, code int)
 	var skill []*models.SkillDTO
 	err := s.DB.NewSelect().
 		Model((*models.Skill)(nil)).
-		Column("id", "skill", "level").
+		Column("id", "skill", "level", "version").
 		Where("profile_code = ?", code).
 		Scan(ctx, &skill)
 	return skill, err
 }
 
+// GetDeletedSkillsByProfileCode lists the rows in the trash, most
+// recently deleted first.
+func (s *skillRepository) GetDeletedSkillsByProfileCode(ctx context.Context, code int) ([]*models.SkillDTO, error) {
+	var skill []*models.SkillDTO
+	err := s.DB.NewSelect().
+		Model((*models.Skill)(nil)).
+		Column("id", "skill", "level", "deleted_at").
+		Where("profile_code = ?", code).
+		WhereDeleted().
+		Order("deleted_at DESC").
+		Scan(ctx, &skill)
+	return skill, err
+}
+
 func (s *skillRepository) CreateSkill(ctx context.Context, payload *models.Skill) (*models.SkillDTO, error) {
 	var skill models.SkillDTO
 	_, err := s.DB.NewInsert().
@@ -42,13 +63,145 @@ func (s *skillRepository) CreateSkill(ctx context.Context, payload *models.Skill
 	return &skill, err
 }
 
-func (s *skillRepository) DeleteSkill(ctx context.Context, code, id int) error {
+// CreateSkills adds the rows with one bulk insert, so either all of them are
+// added or none. It returns their ids in the order of the payload.
+func (s *skillRepository) CreateSkills(ctx context.Context, payload []*models.Skill) ([]int, error) {
+	err := s.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return insertSkills(ctx, tx, payload)
+	})
+	return skillIds(payload), err
+}
+
+// ReplaceSkills moves every row of the profile to the trash and adds the
+// payload in their place, in one transaction, unless the profile has been
+// changed since the given version. It returns the rows it removed, the
+// new ids in the order of the payload and the new version of the profile.
+func (s *skillRepository) ReplaceSkills(ctx context.Context, code, version int, payload []*models.Skill) ([]*models.SkillDTO, []int, int, error) {
+	var removed []*models.SkillDTO
+	err := s.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		if version, err = bumpProfileVersion(ctx, tx, code, version); err != nil {
+			return err
+		}
+		_, err = tx.NewDelete().
+			Model((*models.Skill)(nil)).
+			Where("profile_code = ?", code).
+			Returning("profile_code, id, skill, level, created_at").
+			Exec(ctx, &removed)
+		if err != nil {
+			return err
+		}
+		return insertSkills(ctx, tx, payload)
+	})
+	return removed, skillIds(payload), version, err
+}
+
+// insertSkills relies on Postgres returning the ids of a multi-row insert in
+// the order of its rows, which bun scans back into the payload.
+func insertSkills(ctx context.Context, tx bun.Tx, payload []*models.Skill) error {
+	if len(payload) == 0 {
+		return nil
+	}
+	_, err := tx.NewInsert().
+		Model(&payload).
+		Returning("id").
+		Exec(ctx)
+	return err
+}
+
+func skillIds(payload []*models.Skill) []int {
+	ids := make([]int, 0, len(payload))
+	for _, row := range payload {
+		ids = append(ids, row.Id)
+	}
+	return ids
+}
+
+// PatchSkill writes the given columns of the payload, empty or not, unless
+// the row has been changed since the given version. It returns the same
+// columns as the list of rows, for comparing the row before and after.
+func (s *skillRepository) PatchSkill(ctx context.Context, code, id, version int, payload *models.Skill, columns []string) (*models.SkillDTO, error) {
+	var skill models.SkillDTO
+	res, err := s.DB.NewUpdate().
+		Model(payload).
+		Column(append(columns, "version")...).
+		Value("version", "version + 1").
+		Where("profile_code = ?", code).
+		Where("id = ?", id).
+		Where("version = ?", version).
+		Returning("id, skill, level, version").
+		Exec(ctx, &skill)
+	row := s.DB.NewSelect().
+		Model((*models.Skill)(nil)).
+		Where("profile_code = ?", code).
+		Where("id = ?", id)
+	return &skill, guarded(ctx, row, res, err)
+}
+
+// DeleteSkill moves the row to the trash and returns it, unless it has been
+// changed since the given version.
+func (s *skillRepository) DeleteSkill(ctx context.Context, code, id, version int) (*models.SkillDTO, error) {
 	var skill models.SkillDTO
-	_, err := s.DB.NewDelete().
+	res, err := s.DB.NewDelete().
 		Model((*models.Skill)(nil)).
 		Where("profile_code = ?", code).
 		Where("id = ?", id).
-		Returning("profile_code").
+		Where("version = ?", version).
+		Returning("profile_code, id, skill, level, created_at").
 		Exec(ctx, &skill)
-	return err
+	row := s.DB.NewSelect().
+		Model((*models.Skill)(nil)).
+		Where("profile_code = ?", code).
+		Where("id = ?", id)
+	return &skill, guarded(ctx, row, res, err)
+}
+
+// RestoreSkill takes the row out of the trash and returns it.
+func (s *skillRepository) RestoreSkill(ctx context.Context, code, id int) (*models.SkillDTO, error) {
+	var skill models.SkillDTO
+	res, err := s.DB.NewUpdate().
+		Model((*models.Skill)(nil)).
+		Set("deleted_at = NULL").
+		Set("version = version + 1").
+		Where("profile_code = ?", code).
+		Where("id = ?", id).
+		WhereDeleted().
+		Returning("profile_code, id, skill, level, created_at").
+		Exec(ctx, &skill)
+	return &skill, affected(res, err)
+}
+
+// GetSkillsAfter lists up to limit rows with an id above the given one, in id
+// order and including the trash, for walking every row in batches.
+func (s *skillRepository) GetSkillsAfter(ctx context.Context, id, limit int) ([]*models.SkillDTO, error) {
+	var skill []*models.SkillDTO
+	err := s.DB.NewSelect().
+		Model((*models.Skill)(nil)).
+		Column("profile_code", "id", "skill", "level", "version").
+		Where("id > ?", id).
+		WhereAllWithDeleted().
+		Order("id").
+		Limit(limit).
+		Scan(ctx, &skill)
+	return skill, err
+}
+
+// NormalizeSkill rewrites the skill and level of the row, in the trash or
+// not, and bumps its version so clients holding the old one refetch it. It
+// returns ErrVersionMismatch when the row was changed since the given version.
+func (s *skillRepository) NormalizeSkill(ctx context.Context, id, version int, skill, level string) error {
+	res, err := s.DB.NewUpdate().
+		Model((*models.Skill)(nil)).
+		Set("skill = ?", skill).
+		Set("level = ?", level).
+		Set("version = version + 1").
+		Where("id = ?", id).
+		Where("version = ?", version).
+		WhereAllWithDeleted().
+		Exec(ctx)
+	row := s.DB.NewSelect().
+		Model((*models.Skill)(nil)).
+		Where("id = ?", id).
+		WhereAllWithDeleted()
+	return guarded(ctx, row, res, err)
 }
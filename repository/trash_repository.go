@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"test-bpjs/v2/models"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type TrashRepository interface {
+	PurgeDeletedRows(ctx context.Context, before time.Time, purge *models.TrashPurge) error
+	GetDeletedProfiles(ctx context.Context, before time.Time) ([]*models.ProfileDTO, error)
+}
+
+type trashRepository struct {
+	DB bun.IDB
+}
+
+func NewTrashRepository(db bun.IDB) *trashRepository {
+	return &trashRepository{
+		DB: db,
+	}
+}
+
+// PurgeDeletedRows removes education, employment and skill rows that went to
+// the trash before the given time, and adds their counts to the purge.
+func (t *trashRepository) PurgeDeletedRows(ctx context.Context, before time.Time, purge *models.TrashPurge) error {
+	return t.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		if purge.Education, err = purgeDeleted(ctx, tx, (*models.Education)(nil), before); err != nil {
+			return err
+		}
+		if purge.Employment, err = purgeDeleted(ctx, tx, (*models.Employment)(nil), before); err != nil {
+			return err
+		}
+		purge.Skills, err = purgeDeleted(ctx, tx, (*models.Skill)(nil), before)
+		return err
+	})
+}
+
+// GetDeletedProfiles lists the profiles that went to the trash before the
+// given time, with what is needed to erase them.
+func (t *trashRepository) GetDeletedProfiles(ctx context.Context, before time.Time) ([]*models.ProfileDTO, error) {
+	var profiles []*models.ProfileDTO
+	err := t.DB.NewSelect().
+		Model((*models.Profile)(nil)).
+		Column("profile_code", "public_id", "photo_url", "photo_hash", "deleted_at").
+		Where("deleted_at < ?", before).
+		WhereDeleted().
+		Order("profile_code").
+		Scan(ctx, &profiles)
+	return profiles, err
+}
+
+func purgeDeleted(ctx context.Context, tx bun.Tx, model interface{}, before time.Time) (int, error) {
+	res, err := tx.NewDelete().
+		Model(model).
+		Where("deleted_at < ?", before).
+		WhereDeleted().
+		ForceDelete().
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := res.RowsAffected()
+	return int(rows), err
+}
+
+type trashedProfilesKey struct{}
+
+// WithTrashedProfiles returns a copy of ctx in which GetProfileOwner,
+// GetProfileCodeByPublicId and GetProfileByCode also find profiles in the
+// trash. Only the trash, restore and erasure routes use it; everywhere else a
+// trashed profile is not found.
+func WithTrashedProfiles(ctx context.Context) context.Context {
+	return context.WithValue(ctx, trashedProfilesKey{}, true)
+}
+
+// IncludesTrashedProfiles reports whether ctx comes from WithTrashedProfiles.
+func IncludesTrashedProfiles(ctx context.Context) bool {
+	trashed, _ := ctx.Value(trashedProfilesKey{}).(bool)
+	return trashed
+}
+
+// profileLookup leaves profiles in the trash out of q unless ctx comes from
+// WithTrashedProfiles.
+func profileLookup(ctx context.Context, q *bun.SelectQuery) *bun.SelectQuery {
+	if IncludesTrashedProfiles(ctx) {
+		return q.WhereAllWithDeleted()
+	}
+	return q
+}
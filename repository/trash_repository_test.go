@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"test-bpjs/v2/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+// the trash relies on bun turning deletes into updates of deleted_at and
+// leaving deleted rows out of every other query
+func TestSoftDeleteQueries(t *testing.T) {
+	db := bun.NewDB(&sql.DB{}, pgdialect.New())
+
+	for _, model := range []interface{}{(*models.Profile)(nil), (*models.Education)(nil), (*models.Employment)(nil), (*models.Skill)(nil)} {
+		query := db.NewDelete().Model(model).Where("profile_code = ?", 1).String()
+		assert.Contains(t, query, "UPDATE")
+		assert.Contains(t, query, `SET "deleted_at" = `)
+		assert.Contains(t, query, `."deleted_at" IS NULL`)
+
+		query = db.NewSelect().Model(model).Where("profile_code = ?", 1).String()
+		assert.Contains(t, query, `."deleted_at" IS NULL`)
+
+		query = db.NewUpdate().Model(model).Set("deleted_at = NULL").Where("profile_code = ?", 1).WhereDeleted().String()
+		assert.Contains(t, query, `."deleted_at" IS NOT NULL`)
+
+		// erasure and resume restores also remove rows in the trash
+		query = db.NewDelete().Model(model).Where("profile_code = ?", 1).ForceDelete().String()
+		assert.Contains(t, query, "DELETE FROM")
+		assert.NotContains(t, query, "deleted_at")
+
+		// purges only remove rows in the trash
+		query = db.NewDelete().Model(model).WhereDeleted().ForceDelete().String()
+		assert.Contains(t, query, "DELETE FROM")
+		assert.Contains(t, query, `."deleted_at" IS NOT NULL`)
+	}
+}
+
+func TestProfileLookup(t *testing.T) {
+	db := bun.NewDB(&sql.DB{}, pgdialect.New())
+	query := func(ctx context.Context) string {
+		return profileLookup(ctx, db.NewSelect().Model((*models.Profile)(nil)).Column("profile_code").Where("public_id = ?", "x")).String()
+	}
+
+	assert.Contains(t, query(context.Background()), `."deleted_at" IS NULL`)
+	assert.NotContains(t, query(WithTrashedProfiles(context.Background())), `"deleted_at" IS NULL`)
+}
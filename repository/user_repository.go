@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"test-bpjs/v2/models"
+
+	"github.com/uptrace/bun"
+)
+
+type UserRepository interface {
+	GetUserByUsername(ctx context.Context, username string) (*models.UserDTO, error)
+}
+
+type userRepository struct {
+	DB bun.IDB
+}
+
+func NewUserRepository(db bun.IDB) *userRepository {
+	return &userRepository{
+		DB: db,
+	}
+}
+
+func (u *userRepository) GetUserByUsername(ctx context.Context, username string) (*models.UserDTO, error) {
+	var user models.UserDTO
+	err := u.DB.NewSelect().
+		Model((*models.User)(nil)).
+		Column("id", "username", "password_hash", "role").
+		Where("username = ?", username).
+		Scan(ctx, &user)
+	return &user, err
+}
@@ -4,10 +4,25 @@ import (
 	"context"
 	"test-bpjs/v2/config"
 	"test-bpjs/v2/controller"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/helper/etag"
+	"test-bpjs/v2/helper/ratelimit"
+	"test-bpjs/v2/helper/requestid"
+	appMiddleware "test-bpjs/v2/middleware"
+	apiKeyService "test-bpjs/v2/service/apikey"
+	auditService "test-bpjs/v2/service/audit"
+	authService "test-bpjs/v2/service/auth"
+	consentService "test-bpjs/v2/service/consent"
 	educationService "test-bpjs/v2/service/education"
 	employmentService "test-bpjs/v2/service/employment"
+	idempotencyService "test-bpjs/v2/service/idempotency"
+	jobService "test-bpjs/v2/service/job"
+	privacyService "test-bpjs/v2/service/privacy"
 	profileService "test-bpjs/v2/service/profile"
+	resumeService "test-bpjs/v2/service/resume"
+	searchService "test-bpjs/v2/service/search"
 	skillService "test-bpjs/v2/service/skill"
+	timelineService "test-bpjs/v2/service/timeline"
 	"time"
 
 	"github.com/go-playground/validator"
@@ -38,6 +53,19 @@ func RunServer(ctx context.Context,
 	skillService skillService.SkillService,
 	employmentService employmentService.EmploymentService,
 	educationService educationService.EducationService,
+	authService authService.AuthService,
+	apiKeyService apiKeyService.ApiKeyService,
+	privacyService privacyService.PrivacyService,
+	consentService consentService.ConsentService,
+	auditService auditService.AuditService,
+	resumeService resumeService.ResumeService,
+	idempotencyService idempotencyService.IdempotencyService,
+	searchService searchService.SearchService,
+	jobPostingService jobService.JobPostingService,
+	timelineService timelineService.TimelineService,
+	tokens *auth.TokenManager,
+	limiter *ratelimit.Limiter,
+	ipLimiter *ratelimit.Limiter,
 ) {
 	e := echo.New()
 	defer e.Close()
@@ -62,9 +90,20 @@ func RunServer(ctx context.Context,
 		},
 	}))
 
+	// the request id ends up in audit entries, so mutations can be traced
+	// back to the request log
+	e.Use(middleware.RequestIDWithConfig(middleware.RequestIDConfig{
+		RequestIDHandler: func(c echo.Context, id string) {
+			c.SetRequest(c.Request().WithContext(requestid.WithRequestId(c.Request().Context(), id)))
+		},
+	}))
+
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins:     []string{"*"},
 		AllowCredentials: true,
+		// browsers only let scripts read exposed headers, such as the ETag
+		// they need for If-Match
+		ExposeHeaders: []string{etag.HeaderETag, appMiddleware.HeaderIdempotentReplayed},
 	}))
 
 	go func() {
@@ -74,9 +113,49 @@ func RunServer(ctx context.Context,
 	// logger
 	e.Pre(middleware.RemoveTrailingSlash(), middleware.Logger())
 
-	apiController := controller.NewApiControllerHandler(e.Group("/api"), profileService, skillService, educationService, employmentService)
+	authController := controller.NewAuthControllerHandler(e.Group("/auth", appMiddleware.RateLimit(limiter)), authService)
+	authController.MapRoutes()
+
+	// only the create routes: retrying them would otherwise add duplicate rows
+	idempotency := appMiddleware.Idempotency(idempotencyService,
+		"/api/profile",
+		"/api/education/:profileCode",
+		"/api/education/:profileCode/batch",
+		"/api/employment/:profileCode",
+		"/api/employment/:profileCode/batch",
+		"/api/skill/:profileCode",
+		"/api/skill/:profileCode/batch",
+		"/api/jobs",
+	)
+	// the IP limit comes first so bad credentials are throttled as well
+	apiGroup := e.Group("/api", appMiddleware.RateLimitByIP(ipLimiter), appMiddleware.Authenticate(tokens, apiKeyService), appMiddleware.RateLimit(limiter), appMiddleware.ResolveProfileCode(profileService), idempotency)
+	apiController := controller.NewApiControllerHandler(apiGroup, profileService, skillService, educationService, employmentService)
 	apiController.MapRoutes()
 
+	apiKeyController := controller.NewApiKeyControllerHandler(apiGroup, apiKeyService)
+	apiKeyController.MapRoutes()
+
+	privacyController := controller.NewPrivacyControllerHandler(apiGroup, privacyService)
+	privacyController.MapRoutes()
+
+	consentController := controller.NewConsentControllerHandler(apiGroup, consentService)
+	consentController.MapRoutes()
+
+	auditController := controller.NewAuditControllerHandler(apiGroup, auditService)
+	auditController.MapRoutes()
+
+	resumeController := controller.NewResumeControllerHandler(apiGroup, resumeService)
+	resumeController.MapRoutes()
+
+	searchController := controller.NewSearchControllerHandler(apiGroup, searchService)
+	searchController.MapRoutes()
+
+	jobPostingController := controller.NewJobPostingControllerHandler(apiGroup, jobPostingService)
+	jobPostingController.MapRoutes()
+
+	timelineController := controller.NewTimelineControllerHandler(apiGroup, timelineService)
+	timelineController.MapRoutes()
+
 	<-ctx.Done()
 	if err := e.Shutdown(ctx); err != nil {
 		e.Logger.Fatalf("Error when shuting down: %v", err)
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"test-bpjs/v2/helper/auth"
+	transform "test-bpjs/v2/helper/transform"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/request"
+	"test-bpjs/v2/models/response"
+	"test-bpjs/v2/repository"
+	authorizationService "test-bpjs/v2/service/authorization"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	keyPrefix    = "bpjs_"
+	secretLength = 32
+	// prefixLength is how much of the key is kept in plain text so users can
+	// tell their keys apart in the listing.
+	prefixLength = 8
+)
+
+var (
+	ErrInvalidApiKey  = errors.New("invalid or revoked api key")
+	ErrApiKeyNotFound = errors.New("api key not found")
+)
+
+type ApiKeyService interface {
+	GetApiKeys(ctx context.Context) (*response.ApiKeyList, error)
+	CreateApiKey(ctx context.Context, payload request.CreateApiKeyRequest) (*response.CreateApiKeyResponse, error)
+	RevokeApiKey(ctx context.Context, id int) error
+	Authenticate(ctx context.Context, key string) (*auth.Claims, error)
+}
+
+type apiKeyService struct {
+	apiKeyRepo repository.ApiKeyRepository
+	authorizer authorizationService.Authorizer
+}
+
+func NewApiKeyService(apiKeyRepo repository.ApiKeyRepository, authorizer authorizationService.Authorizer) *apiKeyService {
+	return &apiKeyService{apiKeyRepo: apiKeyRepo, authorizer: authorizer}
+}
+
+func (a *apiKeyService) GetApiKeys(ctx context.Context) (*response.ApiKeyList, error) {
+	user, err := a.keyOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKeys, err := a.apiKeyRepo.GetApiKeysByUser(ctx, user.UserId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api keys: %v", err)
+	}
+
+	result := &response.ApiKeyList{Data: []*response.ApiKeyResponse{}}
+	for _, apiKey := range apiKeys {
+		result.Data = append(result.Data, transform.TransformApiKey(apiKey))
+	}
+	return result, nil
+}
+
+func (a *apiKeyService) CreateApiKey(ctx context.Context, payload request.CreateApiKeyRequest) (*response.CreateApiKeyResponse, error) {
+	user, err := a.keyOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, secretLength)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate api key: %v", err)
+	}
+	key := keyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	apiKey, err := a.apiKeyRepo.CreateApiKey(ctx, &models.ApiKey{
+		UserId:  user.UserId,
+		Name:    payload.Name,
+		Prefix:  key[:len(keyPrefix)+prefixLength],
+		KeyHash: hashKey(key),
+		Scopes:  payload.Scopes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api key: %v", err)
+	}
+
+	return &response.CreateApiKeyResponse{
+		ApiKeyResponse: response.ApiKeyResponse{
+			Id:        apiKey.Id,
+			Name:      payload.Name,
+			Prefix:    key[:len(keyPrefix)+prefixLength],
+			Scopes:    payload.Scopes,
+			CreatedAt: apiKey.CreatedAt,
+		},
+		Key: key,
+	}, nil
+}
+
+func (a *apiKeyService) RevokeApiKey(ctx context.Context, id int) error {
+	user, err := a.keyOwner(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = a.apiKeyRepo.RevokeApiKey(ctx, user.UserId, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrApiKeyNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %v", err)
+	}
+	return nil
+}
+
+// Authenticate resolves a plain API key to the claims of the user that owns
+// it and records when the key was last used.
+func (a *apiKeyService) Authenticate(ctx context.Context, key string) (*auth.Claims, error) {
+	if !strings.HasPrefix(key, keyPrefix) {
+		return nil, ErrInvalidApiKey
+	}
+
+	apiKey, err := a.apiKeyRepo.GetActiveApiKeyByHash(ctx, hashKey(key))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalidApiKey
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key: %v", err)
+	}
+
+	// a failed update must not lock the caller out
+	if err := a.apiKeyRepo.TouchApiKey(ctx, apiKey.Id, time.Now()); err != nil {
+		log.WithContext(ctx).Warnf("failed to update api key last use: %v", err)
+	}
+
+	return &auth.Claims{
+		UserId:   apiKey.UserId,
+		Role:     apiKey.Role,
+		ApiKeyId: apiKey.Id,
+		Scopes:   apiKey.Scopes,
+	}, nil
+}
+
+// keyOwner returns the caller, refusing callers that authenticated with an
+// API key so a leaked key cannot be used to mint new ones.
+func (a *apiKeyService) keyOwner(ctx context.Context) (*auth.Claims, error) {
+	user, err := a.authorizer.CurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if user.ApiKeyId != 0 {
+		return nil, authorizationService.ErrForbidden
+	}
+	return user, nil
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
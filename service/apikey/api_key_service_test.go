@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/request"
+	dataRepository "test-bpjs/v2/repository"
+	repository "test-bpjs/v2/repository/mocks"
+	authorizationService "test-bpjs/v2/service/authorization"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var apiKeyRepository = &repository.ApiKeyRepository{Mock: mock.Mock{}}
+var authorizer = authorizationService.NewAuthorizer(&repository.ProfileRepository{Mock: mock.Mock{}}, &repository.ConsentRepository{Mock: mock.Mock{}}, "")
+var apiKeyServiceTest = apiKeyService{apiKeyRepo: apiKeyRepository, authorizer: authorizer}
+
+var userCtx = auth.WithClaims(context.Background(), &auth.Claims{UserId: 1, Role: auth.RoleUser})
+var apiKeyCtx = auth.WithClaims(context.Background(), &auth.Claims{UserId: 1, Role: auth.RoleUser, ApiKeyId: 9, Scopes: auth.Scopes})
+
+func TestInitApiKeyService(t *testing.T) {
+	t.Run("SuccessInitApiKeyService", func(t *testing.T) {
+		assert.NotNil(t, NewApiKeyService(apiKeyRepository, authorizer))
+	})
+}
+
+func TestCreateApiKey(t *testing.T) {
+	var stored *models.ApiKey
+	apiKeyRepository.Mock.On("CreateApiKey", mock.Anything, mock.AnythingOfType("*models.ApiKey")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*models.ApiKey) }).
+		Return(&models.ApiKeyDTO{Id: 3, CreatedAt: time.Now()}, nil).Once()
+
+	t.Run("SuccessCreateApiKey", func(t *testing.T) {
+		result, err := apiKeyServiceTest.CreateApiKey(userCtx, request.CreateApiKeyRequest{
+			Name:   "ci",
+			Scopes: []string{auth.ScopeProfileRead},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 3, result.Id)
+		assert.True(t, strings.HasPrefix(result.Key, keyPrefix))
+		assert.True(t, strings.HasPrefix(result.Key, result.Prefix))
+
+		// only the hash of the key is stored
+		assert.Equal(t, 1, stored.UserId)
+		assert.Equal(t, hashKey(result.Key), stored.KeyHash)
+		assert.NotContains(t, stored.KeyHash, result.Key)
+	})
+	t.Run("FailedCreateApiKey_WithApiKey", func(t *testing.T) {
+		result, err := apiKeyServiceTest.CreateApiKey(apiKeyCtx, request.CreateApiKeyRequest{Name: "ci"})
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, authorizationService.ErrForbidden)
+	})
+	t.Run("FailedCreateApiKey_Anonymous", func(t *testing.T) {
+		result, err := apiKeyServiceTest.CreateApiKey(context.Background(), request.CreateApiKeyRequest{Name: "ci"})
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, authorizationService.ErrUnauthenticated)
+	})
+}
+
+func TestGetApiKeys(t *testing.T) {
+	t.Run("SuccessGetApiKeys", func(t *testing.T) {
+		apiKeyRepository.Mock.On("GetApiKeysByUser", mock.Anything, 1).Return([]*models.ApiKeyDTO{
+			{Id: 3, Name: "ci", Prefix: "bpjs_abcdefgh", Scopes: []string{auth.ScopeProfileRead}},
+		}, nil).Once()
+
+		result, err := apiKeyServiceTest.GetApiKeys(userCtx)
+		assert.Nil(t, err)
+		assert.Len(t, result.Data, 1)
+		assert.Equal(t, "bpjs_abcdefgh", result.Data[0].Prefix)
+		assert.Nil(t, result.Data[0].LastUsedAt)
+	})
+	t.Run("FailedGetApiKeys", func(t *testing.T) {
+		apiKeyRepository.Mock.On("GetApiKeysByUser", mock.Anything, 1).Return(nil, errors.New("connection refused")).Once()
+
+		result, err := apiKeyServiceTest.GetApiKeys(userCtx)
+		assert.Nil(t, result)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestRevokeApiKey(t *testing.T) {
+	t.Run("SuccessRevokeApiKey", func(t *testing.T) {
+		apiKeyRepository.Mock.On("RevokeApiKey", mock.Anything, 1, 3).Return(nil).Once()
+
+		assert.Nil(t, apiKeyServiceTest.RevokeApiKey(userCtx, 3))
+	})
+	t.Run("FailedRevokeApiKey_NotFound", func(t *testing.T) {
+		apiKeyRepository.Mock.On("RevokeApiKey", mock.Anything, 1, 5).Return(dataRepository.ErrNotFound).Once()
+
+		assert.ErrorIs(t, apiKeyServiceTest.RevokeApiKey(userCtx, 5), ErrApiKeyNotFound)
+	})
+	t.Run("FailedRevokeApiKey_WithApiKey", func(t *testing.T) {
+		assert.ErrorIs(t, apiKeyServiceTest.RevokeApiKey(apiKeyCtx, 3), authorizationService.ErrForbidden)
+	})
+}
+
+func TestAuthenticate(t *testing.T) {
+	const key = "bpjs_someverylongsecret"
+
+	t.Run("SuccessAuthenticate", func(t *testing.T) {
+		apiKeyRepository.Mock.On("GetActiveApiKeyByHash", mock.Anything, hashKey(key)).Return(&models.ApiKeyDTO{
+			Id:     3,
+			UserId: 1,
+			Role:   auth.RoleUser,
+			Scopes: []string{auth.ScopeResumeExport},
+		}, nil).Once()
+		apiKeyRepository.Mock.On("TouchApiKey", mock.Anything, 3, mock.AnythingOfType("time.Time")).Return(errors.New("connection refused")).Once()
+
+		claims, err := apiKeyServiceTest.Authenticate(context.Background(), key)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, claims.UserId)
+		assert.Equal(t, 3, claims.ApiKeyId)
+		assert.True(t, claims.HasScope(auth.ScopeResumeExport))
+		assert.False(t, claims.HasScope(auth.ScopeProfileWrite))
+	})
+	t.Run("FailedAuthenticate_Revoked", func(t *testing.T) {
+		apiKeyRepository.Mock.On("GetActiveApiKeyByHash", mock.Anything, hashKey(key)).Return(nil, sql.ErrNoRows).Once()
+
+		claims, err := apiKeyServiceTest.Authenticate(context.Background(), key)
+		assert.Nil(t, claims)
+		assert.ErrorIs(t, err, ErrInvalidApiKey)
+	})
+	t.Run("FailedAuthenticate_Malformed", func(t *testing.T) {
+		claims, err := apiKeyServiceTest.Authenticate(context.Background(), "not-a-key")
+		assert.Nil(t, claims)
+		assert.ErrorIs(t, err, ErrInvalidApiKey)
+	})
+}
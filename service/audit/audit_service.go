@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/helper/jsondiff"
+	"test-bpjs/v2/helper/requestid"
+	transform "test-bpjs/v2/helper/transform"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/request"
+	"test-bpjs/v2/models/response"
+	"test-bpjs/v2/repository"
+	authorizationService "test-bpjs/v2/service/authorization"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultPageSize = 20
+
+type AuditService interface {
+	Record(ctx context.Context, entry Entry)
+	GetAuditLog(ctx context.Context, payload request.GetAuditLogRequest) (*response.AuditLogList, error)
+}
+
+// Entry describes a mutation to record. Before and After are the DTOs of the
+// row around the change; leave Before nil for creates and After nil for
+// deletes.
+type Entry struct {
+	ProfileCode int
+	Action      string
+	Entity      string
+	EntityId    int
+	Before      interface{}
+	After       interface{}
+}
+
+type auditService struct {
+	auditRepo  repository.AuditRepository
+	authorizer authorizationService.Authorizer
+}
+
+func NewAuditService(auditRepo repository.AuditRepository, authorizer authorizationService.Authorizer) *auditService {
+	return &auditService{auditRepo: auditRepo, authorizer: authorizer}
+}
+
+// Record stores an audit entry for a mutation that has already been
+// committed, so a failure is logged instead of failing the request. Updates
+// that changed nothing are not recorded.
+func (a *auditService) Record(ctx context.Context, entry Entry) {
+	changes, err := jsondiff.Diff(entry.Before, entry.After)
+	if err != nil {
+		log.WithContext(ctx).Errorf("failed to diff %s %d: %v", entry.Entity, entry.EntityId, err)
+		return
+	}
+	if entry.Action == models.AuditActionUpdate && len(changes) == 0 {
+		return
+	}
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		log.WithContext(ctx).Errorf("failed to encode changes of %s %d: %v", entry.Entity, entry.EntityId, err)
+		return
+	}
+
+	auditLog := &models.AuditLog{
+		ProfileCode: entry.ProfileCode,
+		Action:      entry.Action,
+		Entity:      entry.Entity,
+		EntityId:    entry.EntityId,
+		Changes:     string(encoded),
+		RequestId:   requestid.FromContext(ctx),
+		CreatedAt:   time.Now().UTC(),
+	}
+	if claims, ok := auth.ClaimsFromContext(ctx); ok {
+		auditLog.ActorId = claims.UserId
+		auditLog.ApiKeyId = claims.ApiKeyId
+	}
+	if err := a.auditRepo.CreateAuditLog(ctx, auditLog); err != nil {
+		log.WithContext(ctx).Errorf("failed to record %s of %s %d: %v", entry.Action, entry.Entity, entry.EntityId, err)
+	}
+}
+
+// GetAuditLog returns a page of the profile's audit log, newest first. Only
+// the owner and admins can read it.
+func (a *auditService) GetAuditLog(ctx context.Context, payload request.GetAuditLogRequest) (*response.AuditLogList, error) {
+	if err := a.authorizer.CanManageProfileData(ctx, payload.ProfileCode, auth.ScopeProfileRead); err != nil {
+		return nil, err
+	}
+
+	page, pageSize := payload.Page, payload.PageSize
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+
+	auditLogs, total, err := a.auditRepo.GetAuditLogsByProfileCode(ctx, payload.ProfileCode, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit log: %v", err)
+	}
+
+	auditLogList := make([]*response.AuditLogResponse, 0, len(auditLogs))
+	for _, auditLog := range auditLogs {
+		auditLogList = append(auditLogList, transform.TransformAuditLog(auditLog))
+	}
+	return &response.AuditLogList{
+		Data:     auditLogList,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	}, nil
+}
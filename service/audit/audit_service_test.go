@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/helper/requestid"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/request"
+	repository "test-bpjs/v2/repository/mocks"
+	authorizationService "test-bpjs/v2/service/authorization"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var auditRepository = &repository.AuditRepository{Mock: mock.Mock{}}
+var profileRepository = &repository.ProfileRepository{Mock: mock.Mock{}}
+var consentRepository = &repository.ConsentRepository{Mock: mock.Mock{}}
+var authorizer = authorizationService.NewAuthorizer(profileRepository, consentRepository, "")
+var auditServiceTest = auditService{auditRepo: auditRepository, authorizer: authorizer}
+
+// the tests run as user 1, who owns every profile
+var ownerCtx = auth.WithClaims(context.Background(), &auth.Claims{UserId: 1, Role: auth.RoleUser})
+var _ = profileRepository.Mock.On("GetProfileOwner", mock.Anything, mock.Anything).Return(1, nil)
+
+func TestInitAuditService(t *testing.T) {
+	t.Run("SuccessInitAuditService", func(t *testing.T) {
+		assert.NotNil(t, NewAuditService(auditRepository, authorizer))
+	})
+}
+
+func TestRecord(t *testing.T) {
+	t.Run("SuccessRecordCreate", func(t *testing.T) {
+		ctx := requestid.WithRequestId(auth.WithClaims(context.Background(), &auth.Claims{UserId: 1, ApiKeyId: 7}), "req-1")
+		auditRepository.Mock.On("CreateAuditLog", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+			return entry.ProfileCode == 1 && entry.ActorId == 1 && entry.ApiKeyId == 7 &&
+				entry.Action == models.AuditActionCreate && entry.Entity == models.AuditEntitySkill && entry.EntityId == 3 &&
+				entry.Changes == `{"id":{"before":null,"after":3},"level":{"before":null,"after":"Expert"},"profileCode":{"before":null,"after":1},"skill":{"before":null,"after":"Golang"}}` &&
+				entry.RequestId == "req-1" && !entry.CreatedAt.IsZero()
+		})).Return(nil).Once()
+
+		auditServiceTest.Record(ctx, Entry{
+			ProfileCode: 1,
+			Action:      models.AuditActionCreate,
+			Entity:      models.AuditEntitySkill,
+			EntityId:    3,
+			After:       &models.SkillDTO{ProfileCode: 1, Id: 3, Skill: "Golang", Level: "Expert"},
+		})
+	})
+	t.Run("SuccessRecordDelete", func(t *testing.T) {
+		auditRepository.Mock.On("CreateAuditLog", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+			return entry.Action == models.AuditActionDelete && entry.EntityId == 4 &&
+				entry.Changes == `{"employer":{"before":"BPJS","after":null},"id":{"before":4,"after":null}}`
+		})).Return(nil).Once()
+
+		auditServiceTest.Record(ownerCtx, Entry{
+			ProfileCode: 1,
+			Action:      models.AuditActionDelete,
+			Entity:      models.AuditEntityEmployment,
+			EntityId:    4,
+			Before:      &models.EmploymentDTO{Id: 4, Employer: "BPJS"},
+		})
+	})
+	t.Run("SuccessRecord_NothingChanged", func(t *testing.T) {
+		auditServiceTest.Record(ownerCtx, Entry{
+			ProfileCode: 1,
+			Action:      models.AuditActionUpdate,
+			Entity:      models.AuditEntityProfile,
+			EntityId:    5,
+			Before:      &models.ProfileDTO{ProfileCode: 5, FirstName: "John"},
+			After:       &models.ProfileDTO{ProfileCode: 5, FirstName: "John"},
+		})
+		auditRepository.Mock.AssertNotCalled(t, "CreateAuditLog", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+			return entry.EntityId == 5
+		}))
+	})
+	t.Run("FailedRecord_RepositoryError", func(t *testing.T) {
+		auditRepository.Mock.On("CreateAuditLog", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+			return entry.EntityId == 6
+		})).Return(errors.New("connection refused")).Once()
+
+		// the mutation already happened, so recording must not panic or fail it
+		auditServiceTest.Record(ownerCtx, Entry{
+			ProfileCode: 1,
+			Action:      models.AuditActionDelete,
+			Entity:      models.AuditEntitySkill,
+			EntityId:    6,
+			Before:      &models.SkillDTO{Id: 6},
+		})
+	})
+}
+
+func TestGetAuditLog(t *testing.T) {
+	t.Run("SuccessGetAuditLog", func(t *testing.T) {
+		auditRepository.Mock.On("GetAuditLogsByProfileCode", mock.Anything, 1, 20, 0).Return([]*models.AuditLogDTO{
+			{Id: 2, Action: models.AuditActionUpdate, Entity: models.AuditEntityProfile, Changes: map[string]models.FieldChange{
+				"email": {Before: []byte(`"old@example.com"`), After: []byte(`"new@example.com"`)},
+			}},
+			{Id: 1, Action: models.AuditActionCreate, Entity: models.AuditEntityProfile},
+		}, 2, nil).Once()
+
+		result, err := auditServiceTest.GetAuditLog(ownerCtx, request.GetAuditLogRequest{ProfileCode: 1})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, result.Page)
+		assert.Equal(t, 20, result.PageSize)
+		assert.Equal(t, 2, result.Total)
+		assert.Len(t, result.Data, 2)
+		assert.Equal(t, `"new@example.com"`, string(result.Data[0].Changes["email"].After))
+		assert.Nil(t, result.Data[1].Changes)
+	})
+	t.Run("SuccessGetAuditLog_Page", func(t *testing.T) {
+		auditRepository.Mock.On("GetAuditLogsByProfileCode", mock.Anything, 1, 10, 20).Return([]*models.AuditLogDTO{}, 25, nil).Once()
+
+		result, err := auditServiceTest.GetAuditLog(ownerCtx, request.GetAuditLogRequest{ProfileCode: 1, Page: 3, PageSize: 10})
+		assert.Nil(t, err)
+		assert.Equal(t, 3, result.Page)
+		assert.Empty(t, result.Data)
+		assert.Equal(t, 25, result.Total)
+	})
+	t.Run("FailedGetAuditLog_Recruiter", func(t *testing.T) {
+		ctx := auth.WithClaims(context.Background(), &auth.Claims{UserId: 2, Role: auth.RoleRecruiter})
+		result, err := auditServiceTest.GetAuditLog(ctx, request.GetAuditLogRequest{ProfileCode: 1})
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, authorizationService.ErrForbidden)
+	})
+	t.Run("FailedGetAuditLog_RepositoryError", func(t *testing.T) {
+		auditRepository.Mock.On("GetAuditLogsByProfileCode", mock.Anything, 2, 20, 0).Return(nil, 0, errors.New("connection refused")).Once()
+
+		result, err := auditServiceTest.GetAuditLog(ownerCtx, request.GetAuditLogRequest{ProfileCode: 2})
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "failed to get audit log:")
+	})
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/models/request"
+	"test-bpjs/v2/models/response"
+	"test-bpjs/v2/repository"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var ErrInvalidCredentials = errors.New("invalid username or password")
+
+// dummyHash is compared against when the username is unknown, so an unknown
+// user takes as long to reject as a wrong password and usernames cannot be
+// probed by timing the response. It has the default cost stored hashes use.
+const dummyHash = "$2a$10$28YN6biDdJ/Zg2jotHm1B.w5ybuG6GUfapeUgfau6wQvS5EYQjaBW"
+
+type AuthService interface {
+	Login(ctx context.Context, payload request.LoginRequest) (*response.TokenResponse, error)
+}
+
+type authService struct {
+	userRepo repository.UserRepository
+	tokens   *auth.TokenManager
+}
+
+func NewAuthService(userRepo repository.UserRepository, tokens *auth.TokenManager) *authService {
+	return &authService{userRepo: userRepo, tokens: tokens}
+}
+
+func (a *authService) Login(ctx context.Context, payload request.LoginRequest) (*response.TokenResponse, error) {
+	user, err := a.userRepo.GetUserByUsername(ctx, payload.Username)
+	if errors.Is(err, sql.ErrNoRows) {
+		bcrypt.CompareHashAndPassword([]byte(dummyHash), []byte(payload.Password))
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %v", err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(payload.Password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	token, expiresAt, err := a.tokens.Issue(user.Id, user.Username, user.Role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue token: %v", err)
+	}
+
+	return &response.TokenResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresAt:   expiresAt,
+	}, nil
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"test-bpjs/v2/config"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/request"
+	repository "test-bpjs/v2/repository/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var userRepository = &repository.UserRepository{Mock: mock.Mock{}}
+var tokenManager, _ = auth.NewTokenManager(config.Config{JWTSecret: "test-secret", JWTIssuer: "test-bpjs"})
+var authServiceTest = authService{userRepo: userRepository, tokens: tokenManager}
+
+func TestInitAuthService(t *testing.T) {
+	t.Run("SuccessInitAuthService", func(t *testing.T) {
+		assert.NotNil(t, NewAuthService(userRepository, tokenManager))
+	})
+}
+
+func TestLogin(t *testing.T) {
+	passwordHash, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	userRepository.Mock.On("GetUserByUsername", mock.Anything, "john").Return(&models.UserDTO{
+		Id:           7,
+		Username:     "john",
+		PasswordHash: string(passwordHash),
+	}, nil)
+
+	t.Run("SuccessLogin", func(t *testing.T) {
+		result, err := authServiceTest.Login(context.Background(), request.LoginRequest{Username: "john", Password: "secret"})
+		assert.Nil(t, err)
+		assert.Equal(t, "Bearer", result.TokenType)
+
+		claims, err := tokenManager.Parse(result.AccessToken)
+		assert.Nil(t, err)
+		assert.Equal(t, 7, claims.UserId)
+		assert.Equal(t, "john", claims.Username)
+		assert.Equal(t, result.ExpiresAt.Unix(), claims.ExpiresAt)
+	})
+	t.Run("FailedLogin_WrongPassword", func(t *testing.T) {
+		result, err := authServiceTest.Login(context.Background(), request.LoginRequest{Username: "john", Password: "wrong"})
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+	t.Run("FailedLogin_UnknownUser", func(t *testing.T) {
+		// the password is still checked, against a hash as costly as a real one
+		cost, err := bcrypt.Cost([]byte(dummyHash))
+		assert.Nil(t, err)
+		assert.Equal(t, bcrypt.DefaultCost, cost)
+
+		userRepository.Mock.On("GetUserByUsername", mock.Anything, "jane").Return(nil, sql.ErrNoRows)
+
+		result, err := authServiceTest.Login(context.Background(), request.LoginRequest{Username: "jane", Password: "secret"})
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+	t.Run("FailedLogin_RepositoryError", func(t *testing.T) {
+		userRepository.Mock.On("GetUserByUsername", mock.Anything, "broken").Return(nil, errors.New("connection refused"))
+
+		result, err := authServiceTest.Login(context.Background(), request.LoginRequest{Username: "broken", Password: "secret"})
+		assert.Nil(t, result)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "failed to get user")
+	})
+}
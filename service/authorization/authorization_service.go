@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/repository"
+)
+
+var (
+	ErrUnauthenticated = errors.New("authentication required")
+	ErrForbidden       = errors.New("not allowed to access this profile")
+	// ErrConsentRequired is a kind of ErrForbidden, so callers only need to
+	// tell them apart when they want to explain why.
+	ErrConsentRequired = fmt.Errorf("%w: the candidate has not consented to recruiter access", ErrForbidden)
+)
+
+// Authorizer decides whether the caller stored in the context may access a
+// profile and the education, employment and skill rows that belong to it.
+type Authorizer interface {
+	CanReadProfile(ctx context.Context, code int) error
+	CanWriteProfile(ctx context.Context, code int) error
+	CanManageProfileData(ctx context.Context, code int, scope string) error
+	ProfileVisibility(ctx context.Context) (*models.ProfileVisibility, error)
+	CurrentUser(ctx context.Context) (*auth.Claims, error)
+}
+
+type authorizer struct {
+	profileRepo  repository.ProfileRepository
+	consentRepo  repository.ConsentRepository
+	termsVersion string
+}
+
+// NewAuthorizer builds an authorizer that lets recruiters read a profile only
+// while its owner consents to the given version of the terms. An empty
+// version accepts consent to any version.
+func NewAuthorizer(profileRepo repository.ProfileRepository, consentRepo repository.ConsentRepository, termsVersion string) *authorizer {
+	return &authorizer{profileRepo: profileRepo, consentRepo: consentRepo, termsVersion: termsVersion}
+}
+
+func (a *authorizer) CurrentUser(ctx context.Context) (*auth.Claims, error) {
+	claims, ok := auth.ClaimsFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	return claims, nil
+}
+
+// CanReadProfile lets owners read their own profile. Admins can read every
+// profile, recruiters every profile whose owner consented to recruiter
+// access. API keys also need the profile:read scope.
+func (a *authorizer) CanReadProfile(ctx context.Context, code int) error {
+	claims, err := a.CurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+	if !claims.HasScope(auth.ScopeProfileRead) {
+		return ErrForbidden
+	}
+
+	switch claims.Role {
+	case auth.RoleAdmin:
+		return nil
+	case auth.RoleRecruiter:
+		return a.checkRecruiter(ctx, claims, code)
+	}
+	return a.checkOwner(ctx, claims, code)
+}
+
+// CanWriteProfile only lets owners change their profile, whatever their role.
+// API keys also need the profile:write scope.
+func (a *authorizer) CanWriteProfile(ctx context.Context, code int) error {
+	claims, err := a.CurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+	if !claims.HasScope(auth.ScopeProfileWrite) {
+		return ErrForbidden
+	}
+	return a.checkOwner(ctx, claims, code)
+}
+
+// CanManageProfileData guards what only the data subject should see or do
+// with a profile: data subject requests (export and erasure), the audit log,
+// the consent history, the resume versions and the rows in the trash. Owners
+// can use them for their own profile and admins for any profile, on the
+// subject's behalf. API keys also need the given scope.
+func (a *authorizer) CanManageProfileData(ctx context.Context, code int, scope string) error {
+	claims, err := a.CurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+	if !claims.HasScope(scope) {
+		return ErrForbidden
+	}
+	if claims.Role == auth.RoleAdmin {
+		return nil
+	}
+	return a.checkOwner(ctx, claims, code)
+}
+
+// ProfileVisibility tells which profiles the caller may list, following the
+// same rules as CanReadProfile.
+func (a *authorizer) ProfileVisibility(ctx context.Context) (*models.ProfileVisibility, error) {
+	claims, err := a.CurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !claims.HasScope(auth.ScopeProfileRead) {
+		return nil, ErrForbidden
+	}
+
+	switch claims.Role {
+	case auth.RoleAdmin:
+		return &models.ProfileVisibility{All: true}, nil
+	case auth.RoleRecruiter:
+		return &models.ProfileVisibility{OwnerId: claims.UserId, Consented: true, TermsVersion: a.termsVersion}, nil
+	}
+	return &models.ProfileVisibility{OwnerId: claims.UserId}, nil
+}
+
+// checkRecruiter lets recruiters read their own profile as usual, and other
+// profiles only with the owner's consent.
+func (a *authorizer) checkRecruiter(ctx context.Context, claims *auth.Claims, code int) error {
+	err := a.checkOwner(ctx, claims, code)
+	if !errors.Is(err, ErrForbidden) {
+		return err
+	}
+
+	consented, err := a.consentRepo.HasActiveConsent(ctx, code, models.ConsentPurposeRecruiterAccess, a.termsVersion)
+	if err != nil {
+		return fmt.Errorf("failed to check consent: %v", err)
+	}
+	if !consented {
+		return ErrConsentRequired
+	}
+	return nil
+}
+
+func (a *authorizer) checkOwner(ctx context.Context, claims *auth.Claims, code int) error {
+	ownerId, err := a.profileRepo.GetProfileOwner(ctx, code)
+	if err != nil {
+		return fmt.Errorf("failed to get profile owner: %v", err)
+	}
+	if ownerId == 0 || ownerId != claims.UserId {
+		return ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/models"
+	repository "test-bpjs/v2/repository/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var profileRepository = &repository.ProfileRepository{Mock: mock.Mock{}}
+var consentRepository = &repository.ConsentRepository{Mock: mock.Mock{}}
+var authorizerTest = authorizer{profileRepo: profileRepository, consentRepo: consentRepository, termsVersion: "2024-11"}
+
+func TestInitAuthorizer(t *testing.T) {
+	t.Run("SuccessInitAuthorizer", func(t *testing.T) {
+		assert.NotNil(t, NewAuthorizer(profileRepository, consentRepository, "2024-11"))
+	})
+}
+
+func TestRoleMatrix(t *testing.T) {
+	// profile 1 is owned by user 10, profile 2 by user 20
+	profileRepository.Mock.On("GetProfileOwner", mock.Anything, 1).Return(10, nil)
+	profileRepository.Mock.On("GetProfileOwner", mock.Anything, 2).Return(20, nil)
+	profileRepository.Mock.On("GetProfileOwner", mock.Anything, 5).Return(10, nil)
+	// only profile 5 shares its CV with recruiters
+	consentRepository.Mock.On("HasActiveConsent", mock.Anything, 1, models.ConsentPurposeRecruiterAccess, "2024-11").Return(false, nil)
+	consentRepository.Mock.On("HasActiveConsent", mock.Anything, 5, models.ConsentPurposeRecruiterAccess, "2024-11").Return(true, nil)
+
+	tests := []struct {
+		name     string
+		claims   *auth.Claims
+		code     int
+		readErr  error
+		writeErr error
+	}{
+		{"Anonymous", nil, 1, ErrUnauthenticated, ErrUnauthenticated},
+		{"UserOwnProfile", &auth.Claims{UserId: 10, Role: auth.RoleUser}, 1, nil, nil},
+		{"UserOtherProfile", &auth.Claims{UserId: 10, Role: auth.RoleUser}, 2, ErrForbidden, ErrForbidden},
+		{"UserWithoutRole", &auth.Claims{UserId: 20}, 2, nil, nil},
+		{"AdminOwnProfile", &auth.Claims{UserId: 10, Role: auth.RoleAdmin}, 1, nil, nil},
+		{"AdminOtherProfile", &auth.Claims{UserId: 10, Role: auth.RoleAdmin}, 2, nil, ErrForbidden},
+		{"RecruiterOwnProfile", &auth.Claims{UserId: 20, Role: auth.RoleRecruiter}, 2, nil, nil},
+		{"RecruiterOtherProfileWithoutConsent", &auth.Claims{UserId: 20, Role: auth.RoleRecruiter}, 1, ErrConsentRequired, ErrForbidden},
+		{"RecruiterOtherProfileWithConsent", &auth.Claims{UserId: 20, Role: auth.RoleRecruiter}, 5, nil, ErrForbidden},
+		{"ApiKeyReadScope", &auth.Claims{UserId: 10, Role: auth.RoleUser, ApiKeyId: 1, Scopes: []string{auth.ScopeProfileRead}}, 1, nil, ErrForbidden},
+		{"ApiKeyWriteScope", &auth.Claims{UserId: 10, Role: auth.RoleUser, ApiKeyId: 1, Scopes: []string{auth.ScopeProfileWrite}}, 1, ErrForbidden, nil},
+		{"ApiKeyNoScope", &auth.Claims{UserId: 20, Role: auth.RoleAdmin, ApiKeyId: 2}, 2, ErrForbidden, ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.claims != nil {
+				ctx = auth.WithClaims(ctx, tt.claims)
+			}
+
+			readErr := authorizerTest.CanReadProfile(ctx, tt.code)
+			writeErr := authorizerTest.CanWriteProfile(ctx, tt.code)
+			if tt.readErr == nil {
+				assert.Nil(t, readErr)
+			} else {
+				assert.ErrorIs(t, readErr, tt.readErr)
+			}
+			if tt.writeErr == nil {
+				assert.Nil(t, writeErr)
+			} else {
+				assert.ErrorIs(t, writeErr, tt.writeErr)
+			}
+		})
+	}
+}
+
+func TestCanManageProfileData(t *testing.T) {
+	profileRepository.Mock.On("GetProfileOwner", mock.Anything, 1).Return(10, nil)
+
+	tests := []struct {
+		name   string
+		claims *auth.Claims
+		err    error
+	}{
+		{"Owner", &auth.Claims{UserId: 10, Role: auth.RoleUser}, nil},
+		{"Admin", &auth.Claims{UserId: 30, Role: auth.RoleAdmin}, nil},
+		{"Recruiter", &auth.Claims{UserId: 20, Role: auth.RoleRecruiter}, ErrForbidden},
+		{"OtherUser", &auth.Claims{UserId: 20, Role: auth.RoleUser}, ErrForbidden},
+		{"OwnerApiKeyWithScope", &auth.Claims{UserId: 10, ApiKeyId: 1, Scopes: []string{auth.ScopeProfileWrite}}, nil},
+		{"OwnerApiKeyWithoutScope", &auth.Claims{UserId: 10, ApiKeyId: 1, Scopes: []string{auth.ScopeProfileRead}}, ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorizerTest.CanManageProfileData(auth.WithClaims(context.Background(), tt.claims), 1, auth.ScopeProfileWrite)
+			if tt.err == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestProfileVisibility(t *testing.T) {
+	tests := []struct {
+		name       string
+		claims     *auth.Claims
+		visibility *models.ProfileVisibility
+		err        error
+	}{
+		{"Admin", &auth.Claims{UserId: 30, Role: auth.RoleAdmin}, &models.ProfileVisibility{All: true}, nil},
+		{"Recruiter", &auth.Claims{UserId: 20, Role: auth.RoleRecruiter}, &models.ProfileVisibility{OwnerId: 20, Consented: true, TermsVersion: "2024-11"}, nil},
+		{"User", &auth.Claims{UserId: 10, Role: auth.RoleUser}, &models.ProfileVisibility{OwnerId: 10}, nil},
+		{"ApiKeyWithoutScope", &auth.Claims{UserId: 10, ApiKeyId: 1, Scopes: []string{auth.ScopeProfileWrite}}, nil, ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			visibility, err := authorizerTest.ProfileVisibility(auth.WithClaims(context.Background(), tt.claims))
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.visibility, visibility)
+		})
+	}
+
+	_, err := authorizerTest.ProfileVisibility(context.Background())
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+}
+
+func TestCheckOwner(t *testing.T) {
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{UserId: 10, Role: auth.RoleUser})
+
+	t.Run("FailedCheckOwner_ProfileWithoutOwner", func(t *testing.T) {
+		profileRepository.Mock.On("GetProfileOwner", mock.Anything, 3).Return(0, nil)
+
+		assert.ErrorIs(t, authorizerTest.CanWriteProfile(ctx, 3), ErrForbidden)
+	})
+	t.Run("FailedCheckOwner_RepositoryError", func(t *testing.T) {
+		profileRepository.Mock.On("GetProfileOwner", mock.Anything, 4).Return(0, errors.New("sql: no rows in result set"))
+
+		err := authorizerTest.CanReadProfile(ctx, 4)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "failed to get profile owner")
+	})
+}
+
+func TestCheckRecruiter(t *testing.T) {
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{UserId: 20, Role: auth.RoleRecruiter})
+
+	t.Run("FailedCheckRecruiter_RepositoryError", func(t *testing.T) {
+		profileRepository.Mock.On("GetProfileOwner", mock.Anything, 6).Return(10, nil)
+		consentRepository.Mock.On("HasActiveConsent", mock.Anything, 6, models.ConsentPurposeRecruiterAccess, "2024-11").Return(false, errors.New("connection refused"))
+
+		err := authorizerTest.CanReadProfile(ctx, 6)
+		assert.NotNil(t, err)
+		assert.NotErrorIs(t, err, ErrForbidden)
+		assert.Contains(t, err.Error(), "failed to check consent")
+	})
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"test-bpjs/v2/helper/auth"
+	transform "test-bpjs/v2/helper/transform"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/request"
+	"test-bpjs/v2/models/response"
+	"test-bpjs/v2/repository"
+	authorizationService "test-bpjs/v2/service/authorization"
+	"time"
+)
+
+var ErrOutdatedTerms = errors.New("consent must be given to the current version of the terms")
+
+type ConsentService interface {
+	GetConsents(ctx context.Context, code int) (*response.ConsentList, error)
+	GrantConsent(ctx context.Context, payload request.GrantConsentRequest) (*response.ConsentResponse, error)
+	RevokeConsent(ctx context.Context, payload request.RevokeConsentRequest) error
+}
+
+type consentService struct {
+	consentRepo  repository.ConsentRepository
+	authorizer   authorizationService.Authorizer
+	termsVersion string
+}
+
+func NewConsentService(consentRepo repository.ConsentRepository, authorizer authorizationService.Authorizer, termsVersion string) *consentService {
+	return &consentService{consentRepo: consentRepo, authorizer: authorizer, termsVersion: termsVersion}
+}
+
+// GetConsents returns the whole consent history of a profile, revoked
+// consents included.
+func (c *consentService) GetConsents(ctx context.Context, code int) (*response.ConsentList, error) {
+	if err := c.authorizer.CanManageProfileData(ctx, code, auth.ScopeProfileRead); err != nil {
+		return nil, err
+	}
+
+	consents, err := c.consentRepo.GetConsentsByProfileCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consents: %v", err)
+	}
+
+	result := &response.ConsentList{Data: []*response.ConsentResponse{}}
+	for _, consent := range consents {
+		result.Data = append(result.Data, transform.TransformConsent(consent))
+	}
+	return result, nil
+}
+
+// GrantConsent records the owner's consent. Only the owner can give it, since
+// it is their agreement to the terms.
+func (c *consentService) GrantConsent(ctx context.Context, payload request.GrantConsentRequest) (*response.ConsentResponse, error) {
+	if err := c.authorizer.CanWriteProfile(ctx, payload.ProfileCode); err != nil {
+		return nil, err
+	}
+	user, err := c.authorizer.CurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if c.termsVersion != "" && payload.TermsVersion != c.termsVersion {
+		return nil, ErrOutdatedTerms
+	}
+
+	consent := &models.Consent{
+		ProfileCode:  payload.ProfileCode,
+		Purpose:      payload.Purpose,
+		TermsVersion: payload.TermsVersion,
+		GrantedBy:    user.UserId,
+		GrantedAt:    time.Now().UTC(),
+	}
+	stored, err := c.consentRepo.GrantConsent(ctx, consent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to grant consent: %v", err)
+	}
+
+	return transform.TransformConsent(&models.ConsentDTO{
+		Id:           stored.Id,
+		ProfileCode:  consent.ProfileCode,
+		Purpose:      consent.Purpose,
+		TermsVersion: consent.TermsVersion,
+		GrantedBy:    consent.GrantedBy,
+		GrantedAt:    consent.GrantedAt,
+	}), nil
+}
+
+func (c *consentService) RevokeConsent(ctx context.Context, payload request.RevokeConsentRequest) error {
+	if err := c.authorizer.CanWriteProfile(ctx, payload.ProfileCode); err != nil {
+		return err
+	}
+
+	if err := c.consentRepo.RevokeConsent(ctx, payload.ProfileCode, payload.Purpose, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to revoke consent: %v", err)
+	}
+	return nil
+}
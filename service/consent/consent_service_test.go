@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/request"
+	repository "test-bpjs/v2/repository/mocks"
+	authorizationService "test-bpjs/v2/service/authorization"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var profileRepository = &repository.ProfileRepository{Mock: mock.Mock{}}
+var consentRepository = &repository.ConsentRepository{Mock: mock.Mock{}}
+var authorizer = authorizationService.NewAuthorizer(profileRepository, consentRepository, "2024-11")
+var consentServiceTest = consentService{consentRepo: consentRepository, authorizer: authorizer, termsVersion: "2024-11"}
+
+// the tests run as user 1, who owns every profile
+var ownerCtx = auth.WithClaims(context.Background(), &auth.Claims{UserId: 1, Role: auth.RoleUser})
+var _ = profileRepository.Mock.On("GetProfileOwner", mock.Anything, mock.Anything).Return(1, nil)
+
+func TestInitConsentService(t *testing.T) {
+	t.Run("SuccessInitConsentService", func(t *testing.T) {
+		assert.NotNil(t, NewConsentService(consentRepository, authorizer, "2024-11"))
+	})
+}
+
+func TestGetConsents(t *testing.T) {
+	t.Run("SuccessGetConsents", func(t *testing.T) {
+		consentRepository.Mock.On("GetConsentsByProfileCode", mock.Anything, 1).Return([]*models.ConsentDTO{
+			{Id: 1, Purpose: models.ConsentPurposeRecruiterAccess, TermsVersion: "2024-01", RevokedAt: time.Now()},
+			{Id: 2, Purpose: models.ConsentPurposeRecruiterAccess, TermsVersion: "2024-11"},
+		}, nil).Once()
+
+		result, err := consentServiceTest.GetConsents(ownerCtx, 1)
+		assert.Nil(t, err)
+		assert.Len(t, result.Data, 2)
+		assert.False(t, result.Data[0].Active)
+		assert.NotNil(t, result.Data[0].RevokedAt)
+		assert.True(t, result.Data[1].Active)
+	})
+	t.Run("FailedGetConsents_Recruiter", func(t *testing.T) {
+		ctx := auth.WithClaims(context.Background(), &auth.Claims{UserId: 2, Role: auth.RoleRecruiter})
+		result, err := consentServiceTest.GetConsents(ctx, 1)
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, authorizationService.ErrForbidden)
+	})
+}
+
+func TestGrantConsent(t *testing.T) {
+	t.Run("SuccessGrantConsent", func(t *testing.T) {
+		consentRepository.Mock.On("GrantConsent", mock.Anything, mock.MatchedBy(func(consent *models.Consent) bool {
+			return consent.ProfileCode == 1 && consent.GrantedBy == 1 && consent.TermsVersion == "2024-11"
+		})).Return(&models.ConsentDTO{Id: 3}, nil).Once()
+
+		result, err := consentServiceTest.GrantConsent(ownerCtx, request.GrantConsentRequest{
+			ProfileCode:  1,
+			Purpose:      models.ConsentPurposeRecruiterAccess,
+			TermsVersion: "2024-11",
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 3, result.Id)
+		assert.True(t, result.Active)
+	})
+	t.Run("FailedGrantConsent_OutdatedTerms", func(t *testing.T) {
+		result, err := consentServiceTest.GrantConsent(ownerCtx, request.GrantConsentRequest{
+			ProfileCode:  1,
+			Purpose:      models.ConsentPurposeRecruiterAccess,
+			TermsVersion: "2024-01",
+		})
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrOutdatedTerms)
+	})
+	t.Run("FailedGrantConsent_RepositoryError", func(t *testing.T) {
+		consentRepository.Mock.On("GrantConsent", mock.Anything, mock.Anything).Return(nil, errors.New("connection refused")).Once()
+
+		result, err := consentServiceTest.GrantConsent(ownerCtx, request.GrantConsentRequest{
+			ProfileCode:  1,
+			Purpose:      models.ConsentPurposeRecruiterAccess,
+			TermsVersion: "2024-11",
+		})
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "failed to grant consent:")
+	})
+}
+
+func TestRevokeConsent(t *testing.T) {
+	t.Run("SuccessRevokeConsent", func(t *testing.T) {
+		consentRepository.Mock.On("RevokeConsent", mock.Anything, 1, models.ConsentPurposeRecruiterAccess, mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+		err := consentServiceTest.RevokeConsent(ownerCtx, request.RevokeConsentRequest{ProfileCode: 1, Purpose: models.ConsentPurposeRecruiterAccess})
+		assert.Nil(t, err)
+	})
+	t.Run("FailedRevokeConsent_Unauthenticated", func(t *testing.T) {
+		err := consentServiceTest.RevokeConsent(context.Background(), request.RevokeConsentRequest{ProfileCode: 1, Purpose: models.ConsentPurposeRecruiterAccess})
+		assert.ErrorIs(t, err, authorizationService.ErrUnauthenticated)
+	})
+}
@@ -2,29 +2,51 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"test-bpjs/v2/helper/auth"
 	transform "test-bpjs/v2/helper/transform"
 	"test-bpjs/v2/models"
 	"test-bpjs/v2/models/request"
 	"test-bpjs/v2/models/response"
 	"test-bpjs/v2/repository"
+	auditService "test-bpjs/v2/service/audit"
+	authorizationService "test-bpjs/v2/service/authorization"
+	resumeService "test-bpjs/v2/service/resume"
 )
 
 type EducationService interface {
 	GetEducationByCode(ctx context.Context, code int) (*response.EducationList, error)
+	GetDeletedEducationByCode(ctx context.Context, code int) (*response.EducationList, error)
 	CreateEducation(ctx context.Context, payload request.CreateEducationRequest) (*response.DefaultResponseWithId, error)
-	DeleteEducation(ctx context.Context, code, id int) (*response.DefaultResponse, error)
+	CreateEducationRows(ctx context.Context, payload request.CreateEducationRowsRequest) (*response.DefaultResponseWithIds, error)
+	ReplaceEducationRows(ctx context.Context, payload request.ReplaceEducationRowsRequest) (*response.DefaultResponseWithIds, error)
+	PatchEducation(ctx context.Context, payload request.PatchEducationRequest) (*response.DefaultResponseWithId, error)
+	DeleteEducation(ctx context.Context, code, id, version int) (*response.DefaultResponse, error)
+	RestoreEducation(ctx context.Context, code, id int) (*response.DefaultResponseWithId, error)
 }
 
+var (
+	ErrEducationNotFound = errors.New("education not found")
+	ErrEducationModified = errors.New("education was changed by another request")
+)
+
 type educationService struct {
 	educationRepo repository.EducationRepository
+	authorizer    authorizationService.Authorizer
+	auditor       auditService.AuditService
+	versioner     resumeService.Versioner
 }
 
-func NewEducationService(educationRepo repository.EducationRepository) *educationService {
-	return &educationService{educationRepo: educationRepo}
+func NewEducationService(educationRepo repository.EducationRepository, authorizer authorizationService.Authorizer, auditor auditService.AuditService, versioner resumeService.Versioner) *educationService {
+	return &educationService{educationRepo: educationRepo, authorizer: authorizer, auditor: auditor, versioner: versioner}
 }
 
 func (s *educationService) GetEducationByCode(ctx context.Context, code int) (*response.EducationList, error) {
+	if err := s.authorizer.CanReadProfile(ctx, code); err != nil {
+		return nil, err
+	}
+
 	var educationList []*response.EducationResponse
 
 	educations, err := s.educationRepo.GetEducationByProfileCode(ctx, code)
@@ -40,7 +62,32 @@ func (s *educationService) GetEducationByCode(ctx context.Context, code int) (*r
 	}, nil
 }
 
+// GetDeletedEducationByCode lists the education rows in the trash.
+func (s *educationService) GetDeletedEducationByCode(ctx context.Context, code int) (*response.EducationList, error) {
+	if err := s.authorizer.CanManageProfileData(ctx, code, auth.ScopeProfileRead); err != nil {
+		return nil, err
+	}
+
+	var educationList []*response.EducationResponse
+
+	education, err := s.educationRepo.GetDeletedEducationByProfileCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted education: %v", err)
+	}
+
+	for _, row := range education {
+		educationList = append(educationList, transform.TransformEducation(row))
+	}
+	return &response.EducationList{
+		Data: educationList,
+	}, nil
+}
+
 func (s *educationService) CreateEducation(ctx context.Context, payload request.CreateEducationRequest) (*response.DefaultResponseWithId, error) {
+	if err := s.authorizer.CanWriteProfile(ctx, payload.ProfileCode); err != nil {
+		return nil, err
+	}
+
 	education, err := s.educationRepo.CreateEducation(ctx, &models.Education{
 		ProfileCode: payload.ProfileCode,
 		School:      payload.School,
@@ -53,18 +100,234 @@ func (s *educationService) CreateEducation(ctx context.Context, payload request.
 	if err != nil {
 		return nil, fmt.Errorf("failed to create education: %v", err)
 	}
+	s.auditor.Record(ctx, auditService.Entry{
+		ProfileCode: payload.ProfileCode,
+		Action:      models.AuditActionCreate,
+		Entity:      models.AuditEntityEducation,
+		EntityId:    education.Id,
+		After: &models.EducationDTO{
+			ProfileCode: payload.ProfileCode,
+			Id:          education.Id,
+			School:      payload.School,
+			Degree:      payload.Degree,
+			StartDate:   payload.StartDate,
+			EndDate:     payload.EndDate,
+			City:        payload.City,
+			Description: payload.Description,
+		},
+	})
+	s.versioner.Snapshot(ctx, payload.ProfileCode)
 	return &response.DefaultResponseWithId{
 		ProfileCode: payload.ProfileCode,
 		Id:          education.Id,
 	}, nil
 }
 
-func (s *educationService) DeleteEducation(ctx context.Context, code, id int) (*response.DefaultResponse, error) {
-	err := s.educationRepo.DeleteEducation(ctx, code, id)
+// CreateEducationRows adds several education rows at once. The ids come back in the
+// order the rows were sent.
+func (s *educationService) CreateEducationRows(ctx context.Context, payload request.CreateEducationRowsRequest) (*response.DefaultResponseWithIds, error) {
+	if err := s.authorizer.CanWriteProfile(ctx, payload.ProfileCode); err != nil {
+		return nil, err
+	}
+
+	rows := educationRows(payload.ProfileCode, payload.Data)
+	ids, err := s.educationRepo.CreateEducationRows(ctx, rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create education: %v", err)
+	}
+	s.recordCreated(ctx, rows)
+	s.versioner.Snapshot(ctx, payload.ProfileCode)
+	return &response.DefaultResponseWithIds{
+		ProfileCode: payload.ProfileCode,
+		Ids:         ids,
+	}, nil
+}
+
+// ReplaceEducationRows replaces every education row of the profile with the given rows.
+// The replaced rows go to the trash, where they can be restored from. The
+// version the client read is the profile's, since the rows are replaced as a
+// whole; ErrEducationModified means the profile has been changed since.
+func (s *educationService) ReplaceEducationRows(ctx context.Context, payload request.ReplaceEducationRowsRequest) (*response.DefaultResponseWithIds, error) {
+	if err := s.authorizer.CanWriteProfile(ctx, payload.ProfileCode); err != nil {
+		return nil, err
+	}
+
+	rows := educationRows(payload.ProfileCode, payload.Data)
+	removed, ids, version, err := s.educationRepo.ReplaceEducationRows(ctx, payload.ProfileCode, payload.Version, rows)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrEducationNotFound
+	}
+	if errors.Is(err, repository.ErrVersionMismatch) {
+		return nil, ErrEducationModified
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to replace education: %v", err)
+	}
+	for _, row := range removed {
+		s.auditor.Record(ctx, auditService.Entry{
+			ProfileCode: payload.ProfileCode,
+			Action:      models.AuditActionDelete,
+			Entity:      models.AuditEntityEducation,
+			EntityId:    row.Id,
+			Before:      row,
+		})
+	}
+	s.recordCreated(ctx, rows)
+	s.versioner.Snapshot(ctx, payload.ProfileCode)
+	return &response.DefaultResponseWithIds{
+		ProfileCode: payload.ProfileCode,
+		Ids:         ids,
+		Version:     version,
+	}, nil
+}
+
+func (s *educationService) recordCreated(ctx context.Context, rows []*models.Education) {
+	for _, row := range rows {
+		s.auditor.Record(ctx, auditService.Entry{
+			ProfileCode: row.ProfileCode,
+			Action:      models.AuditActionCreate,
+			Entity:      models.AuditEntityEducation,
+			EntityId:    row.Id,
+			After: &models.EducationDTO{
+				ProfileCode: row.ProfileCode,
+				Id:          row.Id,
+				School:      row.School,
+				Degree:      row.Degree,
+				StartDate:   row.StartDate,
+				EndDate:     row.EndDate,
+				City:        row.City,
+				Description: row.Description,
+			},
+		})
+	}
+}
+
+func educationRows(code int, data []request.EducationRow) []*models.Education {
+	rows := make([]*models.Education, 0, len(data))
+	for _, row := range data {
+		rows = append(rows, &models.Education{
+			ProfileCode: code,
+			School:      row.School,
+			Degree:      row.Degree,
+			StartDate:   row.StartDate,
+			EndDate:     row.EndDate,
+			City:        row.City,
+			Description: row.Description,
+		})
+	}
+	return rows
+}
+
+// PatchEducation applies a JSON Merge Patch to a education row.
+func (s *educationService) PatchEducation(ctx context.Context, payload request.PatchEducationRequest) (*response.DefaultResponseWithId, error) {
+	if err := s.authorizer.CanWriteProfile(ctx, payload.ProfileCode); err != nil {
+		return nil, err
+	}
+
+	educations, err := s.educationRepo.GetEducationByProfileCode(ctx, payload.ProfileCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch education: %v", err)
+	}
+	var before *models.EducationDTO
+	for _, row := range educations {
+		if row.Id == payload.Id {
+			before = row
+		}
+	}
+	if before == nil {
+		return nil, ErrEducationNotFound
+	}
+
+	education := &models.Education{}
+	var columns []string
+	set := func(column string, ok bool) {
+		if ok {
+			columns = append(columns, column)
+		}
+	}
+	set("school", payload.School.Apply(&education.School))
+	set("degree", payload.Degree.Apply(&education.Degree))
+	set("start_date", payload.StartDate.Apply(&education.StartDate))
+	set("end_date", payload.EndDate.Apply(&education.EndDate))
+	set("city", payload.City.Apply(&education.City))
+	set("description", payload.Description.Apply(&education.Description))
+
+	after, err := s.educationRepo.PatchEducation(ctx, payload.ProfileCode, payload.Id, payload.Version, education, columns)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrEducationNotFound
+	}
+	if errors.Is(err, repository.ErrVersionMismatch) {
+		return nil, ErrEducationModified
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch education: %v", err)
+	}
+	s.auditor.Record(ctx, auditService.Entry{
+		ProfileCode: payload.ProfileCode,
+		Action:      models.AuditActionUpdate,
+		Entity:      models.AuditEntityEducation,
+		EntityId:    payload.Id,
+		Before:      before,
+		After:       after,
+	})
+	s.versioner.Snapshot(ctx, payload.ProfileCode)
+	return &response.DefaultResponseWithId{
+		ProfileCode: payload.ProfileCode,
+		Id:          payload.Id,
+		Version:     after.Version,
+	}, nil
+}
+
+func (s *educationService) DeleteEducation(ctx context.Context, code, id, version int) (*response.DefaultResponse, error) {
+	if err := s.authorizer.CanWriteProfile(ctx, code); err != nil {
+		return nil, err
+	}
+
+	education, err := s.educationRepo.DeleteEducation(ctx, code, id, version)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrEducationNotFound
+	}
+	if errors.Is(err, repository.ErrVersionMismatch) {
+		return nil, ErrEducationModified
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to delete education: %v", err)
 	}
+	s.auditor.Record(ctx, auditService.Entry{
+		ProfileCode: code,
+		Action:      models.AuditActionDelete,
+		Entity:      models.AuditEntityEducation,
+		EntityId:    id,
+		Before:      education,
+	})
+	s.versioner.Snapshot(ctx, code)
 	return &response.DefaultResponse{
 		ProfileCode: code,
 	}, nil
 }
+
+func (s *educationService) RestoreEducation(ctx context.Context, code, id int) (*response.DefaultResponseWithId, error) {
+	if err := s.authorizer.CanWriteProfile(ctx, code); err != nil {
+		return nil, err
+	}
+
+	education, err := s.educationRepo.RestoreEducation(ctx, code, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrEducationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore education: %v", err)
+	}
+	s.auditor.Record(ctx, auditService.Entry{
+		ProfileCode: code,
+		Action:      models.AuditActionRestore,
+		Entity:      models.AuditEntityEducation,
+		EntityId:    id,
+		After:       education,
+	})
+	s.versioner.Snapshot(ctx, code)
+	return &response.DefaultResponseWithId{
+		ProfileCode: code,
+		Id:          id,
+	}, nil
+}
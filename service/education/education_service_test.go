@@ -3,9 +3,13 @@ package service
 import (
 	"context"
 	"errors"
+	"test-bpjs/v2/helper/auth"
 	"test-bpjs/v2/models"
 	"test-bpjs/v2/models/request"
+	dataRepository "test-bpjs/v2/repository"
 	repository "test-bpjs/v2/repository/mocks"
+	auditService "test-bpjs/v2/service/audit"
+	authorizationService "test-bpjs/v2/service/authorization"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,11 +17,32 @@ import (
 )
 
 var educationRepository = &repository.EducationRepository{Mock: mock.Mock{}}
-var educationServiceTest = educationService{educationRepo: educationRepository}
+var profileRepository = &repository.ProfileRepository{Mock: mock.Mock{}}
+var consentRepository = &repository.ConsentRepository{Mock: mock.Mock{}}
+var authorizer = authorizationService.NewAuthorizer(profileRepository, consentRepository, "")
+var auditRepository = &repository.AuditRepository{Mock: mock.Mock{}}
+var auditor = auditService.NewAuditService(auditRepository, authorizer)
+var versioner = &versionRecorder{}
+var educationServiceTest = educationService{educationRepo: educationRepository, authorizer: authorizer, auditor: auditor, versioner: versioner}
+
+// the tests run as user 1, who owns every profile
+var ownerCtx = auth.WithClaims(context.Background(), &auth.Claims{UserId: 1, Role: auth.RoleUser})
+var _ = profileRepository.Mock.On("GetProfileOwner", mock.Anything, mock.Anything).Return(1, nil)
+var _ = auditRepository.Mock.On("CreateAuditLog", mock.Anything, mock.Anything).Return(nil)
+
+// versionRecorder stands in for the resume service and remembers which
+// profiles were versioned
+type versionRecorder struct {
+	codes []int
+}
+
+func (v *versionRecorder) Snapshot(ctx context.Context, code int) {
+	v.codes = append(v.codes, code)
+}
 
 func TestInitEducationService(t *testing.T) {
 	t.Run("SuccessInitEducationService", func(t *testing.T) {
-		assert.NotNil(t, NewEducationService(educationRepository))
+		assert.NotNil(t, NewEducationService(educationRepository, authorizer, auditor, versioner))
 	})
 }
 
@@ -34,7 +59,7 @@ func TestGetEducation(t *testing.T) {
 		response = append(response, result)
 		educationRepository.Mock.On("GetEducationByProfileCode", mock.Anything, 1).Return(response, nil)
 
-		education, err := educationServiceTest.GetEducationByCode(context.Background(), 1)
+		education, err := educationServiceTest.GetEducationByCode(ownerCtx, 1)
 		assert.Nil(t, err)
 		assert.NotNil(t, education)
 	})
@@ -42,7 +67,7 @@ func TestGetEducation(t *testing.T) {
 		// program mock
 		educationRepository.Mock.On("GetEducationByProfileCode", mock.Anything, 2).Return(nil, errors.New("sql: no rows in result set"))
 
-		education, err := educationServiceTest.GetEducationByCode(context.Background(), 2)
+		education, err := educationServiceTest.GetEducationByCode(ownerCtx, 2)
 		assert.Nil(t, education)
 		assert.NotNil(t, err)
 		// assert.Equal(t, "failed to get profile: sql: no rows in result set", err.Error())
@@ -67,7 +92,7 @@ func TestCreateEducation(t *testing.T) {
 			Description: "I'm Programmer",
 		}).Return(result, nil)
 
-		education, err := educationServiceTest.CreateEducation(context.Background(), request.CreateEducationRequest{
+		education, err := educationServiceTest.CreateEducation(ownerCtx, request.CreateEducationRequest{
 			ProfileCode: 1,
 			School:      "UGM",
 			Degree:      "S1",
@@ -87,7 +112,7 @@ func TestCreateEducation(t *testing.T) {
 			Description: "I'm Programmer",
 		}).Return(nil, errors.New(""))
 
-		education, err := educationServiceTest.CreateEducation(context.Background(), request.CreateEducationRequest{
+		education, err := educationServiceTest.CreateEducation(ownerCtx, request.CreateEducationRequest{
 			ProfileCode: 2,
 			School:      "UGM",
 			Degree:      "S1",
@@ -103,20 +128,111 @@ func TestCreateEducation(t *testing.T) {
 
 func TestDeleteEducation(t *testing.T) {
 	t.Run("SuccessDeleteEducation", func(t *testing.T) {
-		educationRepository.Mock.On("DeleteEducation", mock.Anything, 1, 1).Return(nil)
+		educationRepository.Mock.On("DeleteEducation", mock.Anything, 1, 1, 1).Return(&models.EducationDTO{ProfileCode: 1, Id: 1}, nil)
 
-		education, err := educationServiceTest.DeleteEducation(context.Background(), 1, 1)
+		education, err := educationServiceTest.DeleteEducation(ownerCtx, 1, 1, 1)
 		assert.Nil(t, err)
 		assert.NotNil(t, education)
 	})
 	t.Run("FailedDeleteEducation", func(t *testing.T) {
 		// program mock
-		educationRepository.Mock.On("DeleteEducation", mock.Anything, 1, 2).Return(errors.New(""))
+		educationRepository.Mock.On("DeleteEducation", mock.Anything, 1, 2, 1).Return(nil, errors.New(""))
 
-		education, err := educationServiceTest.DeleteEducation(context.Background(), 1, 2)
+		education, err := educationServiceTest.DeleteEducation(ownerCtx, 1, 2, 1)
 		assert.Nil(t, education)
 		assert.NotNil(t, err)
 		// assert.Equal(t, "failed to get profile: sql: no rows in result set", err.Error())
 		assert.Contains(t, err.Error(), "failed to delete education")
 	})
 }
+
+func TestCreateEducationRows(t *testing.T) {
+	t.Run("SuccessCreateEducationRows", func(t *testing.T) {
+		educationRepository.Mock.On("CreateEducationRows", mock.Anything, mock.MatchedBy(func(rows []*models.Education) bool {
+			return len(rows) == 3 && rows[0].ProfileCode == 70 && rows[0].School == "UI" && rows[2].School == "UGM"
+		})).Run(func(args mock.Arguments) {
+			rows := args.Get(1).([]*models.Education)
+			rows[0].Id, rows[1].Id, rows[2].Id = 13, 11, 12
+		}).Return([]int{13, 11, 12}, nil).Once()
+
+		result, err := educationServiceTest.CreateEducationRows(ownerCtx, request.CreateEducationRowsRequest{
+			ProfileCode: 70,
+			Data:        []request.EducationRow{{School: "UI"}, {School: "ITB"}, {School: "UGM"}},
+		})
+		assert.Nil(t, err)
+		// the ids follow the order the rows were sent in, not their value
+		assert.Equal(t, []int{13, 11, 12}, result.Ids)
+		assert.Equal(t, 70, result.ProfileCode)
+		auditRepository.Mock.AssertCalled(t, "CreateAuditLog", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+			return entry.Action == models.AuditActionCreate && entry.ProfileCode == 70 && entry.EntityId == 11
+		}))
+		assert.Contains(t, versioner.codes, 70)
+	})
+	t.Run("FailedCreateEducationRows", func(t *testing.T) {
+		educationRepository.Mock.On("CreateEducationRows", mock.Anything, mock.MatchedBy(func(rows []*models.Education) bool {
+			return len(rows) == 1 && rows[0].ProfileCode == 71
+		})).Return(nil, errors.New("unexpected")).Once()
+
+		result, err := educationServiceTest.CreateEducationRows(ownerCtx, request.CreateEducationRowsRequest{
+			ProfileCode: 71,
+			Data:        []request.EducationRow{{School: "UI"}},
+		})
+		assert.Nil(t, result)
+		assert.Equal(t, "failed to create education: unexpected", err.Error())
+	})
+	t.Run("FailedCreateEducationRows_Unauthenticated", func(t *testing.T) {
+		result, err := educationServiceTest.CreateEducationRows(context.Background(), request.CreateEducationRowsRequest{
+			ProfileCode: 72,
+			Data:        []request.EducationRow{{School: "UI"}},
+		})
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, authorizationService.ErrUnauthenticated)
+	})
+}
+
+func TestReplaceEducationRows(t *testing.T) {
+	t.Run("SuccessReplaceEducationRows", func(t *testing.T) {
+		educationRepository.Mock.On("ReplaceEducationRows", mock.Anything, 73, 1, mock.MatchedBy(func(rows []*models.Education) bool {
+			return len(rows) == 2 && rows[0].School == "ITB" && rows[1].School == "UI"
+		})).Run(func(args mock.Arguments) {
+			rows := args.Get(3).([]*models.Education)
+			rows[0].Id, rows[1].Id = 22, 21
+		}).Return([]*models.EducationDTO{{ProfileCode: 73, Id: 20, School: "UGM"}}, []int{22, 21}, 2, nil).Once()
+
+		result, err := educationServiceTest.ReplaceEducationRows(ownerCtx, request.ReplaceEducationRowsRequest{
+			ProfileCode: 73,
+			Version:     1,
+			Data:        []request.EducationRow{{School: "ITB"}, {School: "UI"}},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, result.Version)
+		assert.Equal(t, []int{22, 21}, result.Ids)
+		auditRepository.Mock.AssertCalled(t, "CreateAuditLog", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+			return entry.Action == models.AuditActionDelete && entry.ProfileCode == 73 && entry.EntityId == 20
+		}))
+		auditRepository.Mock.AssertCalled(t, "CreateAuditLog", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+			return entry.Action == models.AuditActionCreate && entry.ProfileCode == 73 && entry.EntityId == 22
+		}))
+	})
+	t.Run("SuccessReplaceEducationRows_Empty", func(t *testing.T) {
+		educationRepository.Mock.On("ReplaceEducationRows", mock.Anything, 74, 1, []*models.Education{}).Return([]*models.EducationDTO{}, []int{}, 2, nil).Once()
+
+		result, err := educationServiceTest.ReplaceEducationRows(ownerCtx, request.ReplaceEducationRowsRequest{ProfileCode: 74, Version: 1, Data: []request.EducationRow{}})
+		assert.Nil(t, err)
+		assert.Empty(t, result.Ids)
+	})
+	t.Run("FailedReplaceEducationRows", func(t *testing.T) {
+		educationRepository.Mock.On("ReplaceEducationRows", mock.Anything, 75, 1, mock.Anything).Return(nil, nil, 0, errors.New("unexpected")).Once()
+
+		result, err := educationServiceTest.ReplaceEducationRows(ownerCtx, request.ReplaceEducationRowsRequest{ProfileCode: 75, Version: 1, Data: []request.EducationRow{{School: "UI"}}})
+		assert.Nil(t, result)
+		assert.Equal(t, "failed to replace education: unexpected", err.Error())
+	})
+	t.Run("FailedReplaceEducationRows_Modified", func(t *testing.T) {
+		educationRepository.Mock.On("ReplaceEducationRows", mock.Anything, 76, 1, mock.Anything).Return(nil, nil, 0, dataRepository.ErrVersionMismatch).Once()
+
+		result, err := educationServiceTest.ReplaceEducationRows(ownerCtx, request.ReplaceEducationRowsRequest{ProfileCode: 76, Version: 1, Data: []request.EducationRow{{School: "UI"}}})
+		assert.Nil(t, result)
+		assert.True(t, errors.Is(err, ErrEducationModified))
+	})
+}
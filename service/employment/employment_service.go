@@ -2,29 +2,51 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"test-bpjs/v2/helper/auth"
 	transform "test-bpjs/v2/helper/transform"
 	"test-bpjs/v2/models"
 	"test-bpjs/v2/models/request"
 	"test-bpjs/v2/models/response"
 	"test-bpjs/v2/repository"
+	auditService "test-bpjs/v2/service/audit"
+	authorizationService "test-bpjs/v2/service/authorization"
+	resumeService "test-bpjs/v2/service/resume"
 )
 
 type EmploymentService interface {
 	GetEmploymentByCode(ctx context.Context, code int) (*response.EmploymentList, error)
+	GetDeletedEmploymentByCode(ctx context.Context, code int) (*response.EmploymentList, error)
 	CreateEmployment(ctx context.Context, payload request.CreateEmploymentRequest) (*response.DefaultResponseWithId, error)
-	DeleteEmployment(ctx context.Context, code, id int) (*response.DefaultResponse, error)
+	CreateEmploymentRows(ctx context.Context, payload request.CreateEmploymentRowsRequest) (*response.DefaultResponseWithIds, error)
+	ReplaceEmploymentRows(ctx context.Context, payload request.ReplaceEmploymentRowsRequest) (*response.DefaultResponseWithIds, error)
+	PatchEmployment(ctx context.Context, payload request.PatchEmploymentRequest) (*response.DefaultResponseWithId, error)
+	DeleteEmployment(ctx context.Context, code, id, version int) (*response.DefaultResponse, error)
+	RestoreEmployment(ctx context.Context, code, id int) (*response.DefaultResponseWithId, error)
 }
 
+var (
+	ErrEmploymentNotFound = errors.New("employment not found")
+	ErrEmploymentModified = errors.New("employment was changed by another request")
+)
+
 type employmentService struct {
 	employmentRepo repository.EmploymentRepository
+	authorizer     authorizationService.Authorizer
+	auditor        auditService.AuditService
+	versioner      resumeService.Versioner
 }
 
-func NewEmploymentService(employmentRepo repository.EmploymentRepository) *employmentService {
-	return &employmentService{employmentRepo: employmentRepo}
+func NewEmploymentService(employmentRepo repository.EmploymentRepository, authorizer authorizationService.Authorizer, auditor auditService.AuditService, versioner resumeService.Versioner) *employmentService {
+	return &employmentService{employmentRepo: employmentRepo, authorizer: authorizer, auditor: auditor, versioner: versioner}
 }
 
 func (e *employmentService) GetEmploymentByCode(ctx context.Context, code int) (*response.EmploymentList, error) {
+	if err := e.authorizer.CanReadProfile(ctx, code); err != nil {
+		return nil, err
+	}
+
 	var employmentList []*response.EmploymentResponse
 
 	employments, err := e.employmentRepo.GetEmploymentByProfileCode(ctx, code)
@@ -40,31 +62,277 @@ func (e *employmentService) GetEmploymentByCode(ctx context.Context, code int) (
 	}, nil
 }
 
+// GetDeletedEmploymentByCode lists the employment rows in the trash.
+func (s *employmentService) GetDeletedEmploymentByCode(ctx context.Context, code int) (*response.EmploymentList, error) {
+	if err := s.authorizer.CanManageProfileData(ctx, code, auth.ScopeProfileRead); err != nil {
+		return nil, err
+	}
+
+	var employmentList []*response.EmploymentResponse
+
+	employment, err := s.employmentRepo.GetDeletedEmploymentByProfileCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted employment: %v", err)
+	}
+
+	for _, row := range employment {
+		employmentList = append(employmentList, transform.TransformEmployment(row))
+	}
+	return &response.EmploymentList{
+		Data: employmentList,
+	}, nil
+}
+
 func (e *employmentService) CreateEmployment(ctx context.Context, payload request.CreateEmploymentRequest) (*response.DefaultResponseWithId, error) {
+	if err := e.authorizer.CanWriteProfile(ctx, payload.ProfileCode); err != nil {
+		return nil, err
+	}
+
 	employment, err := e.employmentRepo.CreateEmployment(ctx, &models.Employment{
-		ProfileCode: payload.ProfileCode,
-		JobTitle:    payload.JobTitle,
-		Employer:    payload.Employer,
-		StartDate:   payload.StartDate,
-		EndDate:     payload.EndDate,
-		City:        payload.City,
-		Description: payload.Description,
+		ProfileCode:    payload.ProfileCode,
+		JobTitle:       payload.JobTitle,
+		Employer:       payload.Employer,
+		StartDate:      payload.StartDate,
+		EndDate:        payload.EndDate,
+		City:           payload.City,
+		Description:    payload.Description,
+		EmploymentType: payload.EmploymentType,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create employment: %v", err)
 	}
+	e.auditor.Record(ctx, auditService.Entry{
+		ProfileCode: payload.ProfileCode,
+		Action:      models.AuditActionCreate,
+		Entity:      models.AuditEntityEmployment,
+		EntityId:    employment.Id,
+		After: &models.EmploymentDTO{
+			ProfileCode:    payload.ProfileCode,
+			Id:             employment.Id,
+			JobTitle:       payload.JobTitle,
+			Employer:       payload.Employer,
+			StartDate:      payload.StartDate,
+			EndDate:        payload.EndDate,
+			City:           payload.City,
+			Description:    payload.Description,
+			EmploymentType: payload.EmploymentType,
+		},
+	})
+	e.versioner.Snapshot(ctx, payload.ProfileCode)
 	return &response.DefaultResponseWithId{
 		ProfileCode: payload.ProfileCode,
 		Id:          employment.Id,
 	}, nil
 }
 
-func (s *employmentService) DeleteEmployment(ctx context.Context, code, id int) (*response.DefaultResponse, error) {
-	err := s.employmentRepo.DeleteEmployment(ctx, code, id)
+// CreateEmploymentRows adds several employment rows at once. The ids come back in the
+// order the rows were sent.
+func (s *employmentService) CreateEmploymentRows(ctx context.Context, payload request.CreateEmploymentRowsRequest) (*response.DefaultResponseWithIds, error) {
+	if err := s.authorizer.CanWriteProfile(ctx, payload.ProfileCode); err != nil {
+		return nil, err
+	}
+
+	rows := employmentRows(payload.ProfileCode, payload.Data)
+	ids, err := s.employmentRepo.CreateEmploymentRows(ctx, rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create employment: %v", err)
+	}
+	s.recordCreated(ctx, rows)
+	s.versioner.Snapshot(ctx, payload.ProfileCode)
+	return &response.DefaultResponseWithIds{
+		ProfileCode: payload.ProfileCode,
+		Ids:         ids,
+	}, nil
+}
+
+// ReplaceEmploymentRows replaces every employment row of the profile with the given rows.
+// The replaced rows go to the trash, where they can be restored from. The
+// version the client read is the profile's, since the rows are replaced as a
+// whole; ErrEmploymentModified means the profile has been changed since.
+func (s *employmentService) ReplaceEmploymentRows(ctx context.Context, payload request.ReplaceEmploymentRowsRequest) (*response.DefaultResponseWithIds, error) {
+	if err := s.authorizer.CanWriteProfile(ctx, payload.ProfileCode); err != nil {
+		return nil, err
+	}
+
+	rows := employmentRows(payload.ProfileCode, payload.Data)
+	removed, ids, version, err := s.employmentRepo.ReplaceEmploymentRows(ctx, payload.ProfileCode, payload.Version, rows)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrEmploymentNotFound
+	}
+	if errors.Is(err, repository.ErrVersionMismatch) {
+		return nil, ErrEmploymentModified
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to replace employment: %v", err)
+	}
+	for _, row := range removed {
+		s.auditor.Record(ctx, auditService.Entry{
+			ProfileCode: payload.ProfileCode,
+			Action:      models.AuditActionDelete,
+			Entity:      models.AuditEntityEmployment,
+			EntityId:    row.Id,
+			Before:      row,
+		})
+	}
+	s.recordCreated(ctx, rows)
+	s.versioner.Snapshot(ctx, payload.ProfileCode)
+	return &response.DefaultResponseWithIds{
+		ProfileCode: payload.ProfileCode,
+		Ids:         ids,
+		Version:     version,
+	}, nil
+}
+
+func (s *employmentService) recordCreated(ctx context.Context, rows []*models.Employment) {
+	for _, row := range rows {
+		s.auditor.Record(ctx, auditService.Entry{
+			ProfileCode: row.ProfileCode,
+			Action:      models.AuditActionCreate,
+			Entity:      models.AuditEntityEmployment,
+			EntityId:    row.Id,
+			After: &models.EmploymentDTO{
+				ProfileCode:    row.ProfileCode,
+				Id:             row.Id,
+				JobTitle:       row.JobTitle,
+				Employer:       row.Employer,
+				StartDate:      row.StartDate,
+				EndDate:        row.EndDate,
+				City:           row.City,
+				Description:    row.Description,
+				EmploymentType: row.EmploymentType,
+			},
+		})
+	}
+}
+
+func employmentRows(code int, data []request.EmploymentRow) []*models.Employment {
+	rows := make([]*models.Employment, 0, len(data))
+	for _, row := range data {
+		rows = append(rows, &models.Employment{
+			ProfileCode:    code,
+			JobTitle:       row.JobTitle,
+			Employer:       row.Employer,
+			StartDate:      row.StartDate,
+			EndDate:        row.EndDate,
+			City:           row.City,
+			Description:    row.Description,
+			EmploymentType: row.EmploymentType,
+		})
+	}
+	return rows
+}
+
+// PatchEmployment applies a JSON Merge Patch to a employment row.
+func (s *employmentService) PatchEmployment(ctx context.Context, payload request.PatchEmploymentRequest) (*response.DefaultResponseWithId, error) {
+	if err := s.authorizer.CanWriteProfile(ctx, payload.ProfileCode); err != nil {
+		return nil, err
+	}
+
+	employments, err := s.employmentRepo.GetEmploymentByProfileCode(ctx, payload.ProfileCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch employment: %v", err)
+	}
+	var before *models.EmploymentDTO
+	for _, row := range employments {
+		if row.Id == payload.Id {
+			before = row
+		}
+	}
+	if before == nil {
+		return nil, ErrEmploymentNotFound
+	}
+
+	employment := &models.Employment{}
+	var columns []string
+	set := func(column string, ok bool) {
+		if ok {
+			columns = append(columns, column)
+		}
+	}
+	set("job_title", payload.JobTitle.Apply(&employment.JobTitle))
+	set("employer", payload.Employer.Apply(&employment.Employer))
+	set("start_date", payload.StartDate.Apply(&employment.StartDate))
+	set("end_date", payload.EndDate.Apply(&employment.EndDate))
+	set("city", payload.City.Apply(&employment.City))
+	set("description", payload.Description.Apply(&employment.Description))
+	set("employment_type", payload.EmploymentType.Apply(&employment.EmploymentType))
+
+	after, err := s.employmentRepo.PatchEmployment(ctx, payload.ProfileCode, payload.Id, payload.Version, employment, columns)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrEmploymentNotFound
+	}
+	if errors.Is(err, repository.ErrVersionMismatch) {
+		return nil, ErrEmploymentModified
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch employment: %v", err)
+	}
+	s.auditor.Record(ctx, auditService.Entry{
+		ProfileCode: payload.ProfileCode,
+		Action:      models.AuditActionUpdate,
+		Entity:      models.AuditEntityEmployment,
+		EntityId:    payload.Id,
+		Before:      before,
+		After:       after,
+	})
+	s.versioner.Snapshot(ctx, payload.ProfileCode)
+	return &response.DefaultResponseWithId{
+		ProfileCode: payload.ProfileCode,
+		Id:          payload.Id,
+		Version:     after.Version,
+	}, nil
+}
+
+func (s *employmentService) DeleteEmployment(ctx context.Context, code, id, version int) (*response.DefaultResponse, error) {
+	if err := s.authorizer.CanWriteProfile(ctx, code); err != nil {
+		return nil, err
+	}
+
+	employment, err := s.employmentRepo.DeleteEmployment(ctx, code, id, version)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrEmploymentNotFound
+	}
+	if errors.Is(err, repository.ErrVersionMismatch) {
+		return nil, ErrEmploymentModified
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to delete employment: %v", err)
 	}
+	s.auditor.Record(ctx, auditService.Entry{
+		ProfileCode: code,
+		Action:      models.AuditActionDelete,
+		Entity:      models.AuditEntityEmployment,
+		EntityId:    id,
+		Before:      employment,
+	})
+	s.versioner.Snapshot(ctx, code)
 	return &response.DefaultResponse{
 		ProfileCode: code,
 	}, nil
 }
+
+func (s *employmentService) RestoreEmployment(ctx context.Context, code, id int) (*response.DefaultResponseWithId, error) {
+	if err := s.authorizer.CanWriteProfile(ctx, code); err != nil {
+		return nil, err
+	}
+
+	employment, err := s.employmentRepo.RestoreEmployment(ctx, code, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrEmploymentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore employment: %v", err)
+	}
+	s.auditor.Record(ctx, auditService.Entry{
+		ProfileCode: code,
+		Action:      models.AuditActionRestore,
+		Entity:      models.AuditEntityEmployment,
+		EntityId:    id,
+		After:       employment,
+	})
+	s.versioner.Snapshot(ctx, code)
+	return &response.DefaultResponseWithId{
+		ProfileCode: code,
+		Id:          id,
+	}, nil
+}
@@ -3,9 +3,13 @@ package service
 import (
 	"context"
 	"errors"
+	"test-bpjs/v2/helper/auth"
 	"test-bpjs/v2/models"
 	"test-bpjs/v2/models/request"
+	dataRepository "test-bpjs/v2/repository"
 	repository "test-bpjs/v2/repository/mocks"
+	auditService "test-bpjs/v2/service/audit"
+	authorizationService "test-bpjs/v2/service/authorization"
 	"testing"
 	"time"
 
@@ -14,11 +18,32 @@ import (
 )
 
 var employmentRepository = &repository.EmploymentRepository{Mock: mock.Mock{}}
-var employmentServiceTest = employmentService{employmentRepo: employmentRepository}
+var profileRepository = &repository.ProfileRepository{Mock: mock.Mock{}}
+var consentRepository = &repository.ConsentRepository{Mock: mock.Mock{}}
+var authorizer = authorizationService.NewAuthorizer(profileRepository, consentRepository, "")
+var auditRepository = &repository.AuditRepository{Mock: mock.Mock{}}
+var auditor = auditService.NewAuditService(auditRepository, authorizer)
+var versioner = &versionRecorder{}
+var employmentServiceTest = employmentService{employmentRepo: employmentRepository, authorizer: authorizer, auditor: auditor, versioner: versioner}
+
+// the tests run as user 1, who owns every profile
+var ownerCtx = auth.WithClaims(context.Background(), &auth.Claims{UserId: 1, Role: auth.RoleUser})
+var _ = profileRepository.Mock.On("GetProfileOwner", mock.Anything, mock.Anything).Return(1, nil)
+var _ = auditRepository.Mock.On("CreateAuditLog", mock.Anything, mock.Anything).Return(nil)
+
+// versionRecorder stands in for the resume service and remembers which
+// profiles were versioned
+type versionRecorder struct {
+	codes []int
+}
+
+func (v *versionRecorder) Snapshot(ctx context.Context, code int) {
+	v.codes = append(v.codes, code)
+}
 
 func TestInitEmploymentService(t *testing.T) {
 	t.Run("SuccessInitEmploymentService", func(t *testing.T) {
-		assert.NotNil(t, NewEmploymentService(employmentRepository))
+		assert.NotNil(t, NewEmploymentService(employmentRepository, authorizer, auditor, versioner))
 	})
 }
 
@@ -37,7 +62,7 @@ func TestGetEmployment(t *testing.T) {
 		response = append(response, result)
 		employmentRepository.Mock.On("GetEmploymentByProfileCode", mock.Anything, 1).Return(response, nil)
 
-		employment, err := employmentServiceTest.GetEmploymentByCode(context.Background(), 1)
+		employment, err := employmentServiceTest.GetEmploymentByCode(ownerCtx, 1)
 		assert.Nil(t, err)
 		assert.NotNil(t, employment)
 	})
@@ -45,7 +70,7 @@ func TestGetEmployment(t *testing.T) {
 		// program mock
 		employmentRepository.Mock.On("GetEmploymentByProfileCode", mock.Anything, 2).Return(nil, errors.New("sql: no rows in result set"))
 
-		skill, err := employmentServiceTest.GetEmploymentByCode(context.Background(), 2)
+		skill, err := employmentServiceTest.GetEmploymentByCode(ownerCtx, 2)
 		assert.Nil(t, skill)
 		assert.NotNil(t, err)
 		// assert.Equal(t, "failed to get profile: sql: no rows in result set", err.Error())
@@ -70,7 +95,7 @@ func TestCreateEmployment(t *testing.T) {
 			Description: "I'm Programmer",
 		}).Return(result, nil)
 
-		skills, err := employmentServiceTest.CreateEmployment(context.Background(), request.CreateEmploymentRequest{
+		skills, err := employmentServiceTest.CreateEmployment(ownerCtx, request.CreateEmploymentRequest{
 			ProfileCode: 1,
 			JobTitle:    "Programmer",
 			Employer:    "PT. ABC",
@@ -90,7 +115,7 @@ func TestCreateEmployment(t *testing.T) {
 			Description: "I'm Programmer",
 		}).Return(nil, errors.New(""))
 
-		skill, err := employmentServiceTest.CreateEmployment(context.Background(), request.CreateEmploymentRequest{
+		skill, err := employmentServiceTest.CreateEmployment(ownerCtx, request.CreateEmploymentRequest{
 			ProfileCode: 2,
 			JobTitle:    "Programmer",
 			Employer:    "PT. ABC",
@@ -106,20 +131,111 @@ func TestCreateEmployment(t *testing.T) {
 
 func TestDeleteEmployment(t *testing.T) {
 	t.Run("SuccessDeleteEmployment", func(t *testing.T) {
-		employmentRepository.Mock.On("DeleteEmployment", mock.Anything, 1, 1).Return(nil)
+		employmentRepository.Mock.On("DeleteEmployment", mock.Anything, 1, 1, 1).Return(&models.EmploymentDTO{ProfileCode: 1, Id: 1}, nil)
 
-		skills, err := employmentServiceTest.DeleteEmployment(context.Background(), 1, 1)
+		skills, err := employmentServiceTest.DeleteEmployment(ownerCtx, 1, 1, 1)
 		assert.Nil(t, err)
 		assert.NotNil(t, skills)
 	})
 	t.Run("FailedCreateEmployment", func(t *testing.T) {
 		// program mock
-		employmentRepository.Mock.On("DeleteEmployment", mock.Anything, 1, 2).Return(errors.New(""))
+		employmentRepository.Mock.On("DeleteEmployment", mock.Anything, 1, 2, 1).Return(nil, errors.New(""))
 
-		skill, err := employmentServiceTest.DeleteEmployment(context.Background(), 1, 2)
+		skill, err := employmentServiceTest.DeleteEmployment(ownerCtx, 1, 2, 1)
 		assert.Nil(t, skill)
 		assert.NotNil(t, err)
 		// assert.Equal(t, "failed to get profile: sql: no rows in result set", err.Error())
 		assert.Contains(t, err.Error(), "failed to delete employment")
 	})
 }
+
+func TestCreateEmploymentRows(t *testing.T) {
+	t.Run("SuccessCreateEmploymentRows", func(t *testing.T) {
+		employmentRepository.Mock.On("CreateEmploymentRows", mock.Anything, mock.MatchedBy(func(rows []*models.Employment) bool {
+			return len(rows) == 3 && rows[0].ProfileCode == 70 && rows[0].Employer == "Gojek" && rows[2].Employer == "Traveloka"
+		})).Run(func(args mock.Arguments) {
+			rows := args.Get(1).([]*models.Employment)
+			rows[0].Id, rows[1].Id, rows[2].Id = 13, 11, 12
+		}).Return([]int{13, 11, 12}, nil).Once()
+
+		result, err := employmentServiceTest.CreateEmploymentRows(ownerCtx, request.CreateEmploymentRowsRequest{
+			ProfileCode: 70,
+			Data:        []request.EmploymentRow{{Employer: "Gojek"}, {Employer: "Tokopedia"}, {Employer: "Traveloka"}},
+		})
+		assert.Nil(t, err)
+		// the ids follow the order the rows were sent in, not their value
+		assert.Equal(t, []int{13, 11, 12}, result.Ids)
+		assert.Equal(t, 70, result.ProfileCode)
+		auditRepository.Mock.AssertCalled(t, "CreateAuditLog", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+			return entry.Action == models.AuditActionCreate && entry.ProfileCode == 70 && entry.EntityId == 11
+		}))
+		assert.Contains(t, versioner.codes, 70)
+	})
+	t.Run("FailedCreateEmploymentRows", func(t *testing.T) {
+		employmentRepository.Mock.On("CreateEmploymentRows", mock.Anything, mock.MatchedBy(func(rows []*models.Employment) bool {
+			return len(rows) == 1 && rows[0].ProfileCode == 71
+		})).Return(nil, errors.New("unexpected")).Once()
+
+		result, err := employmentServiceTest.CreateEmploymentRows(ownerCtx, request.CreateEmploymentRowsRequest{
+			ProfileCode: 71,
+			Data:        []request.EmploymentRow{{Employer: "Gojek"}},
+		})
+		assert.Nil(t, result)
+		assert.Equal(t, "failed to create employment: unexpected", err.Error())
+	})
+	t.Run("FailedCreateEmploymentRows_Unauthenticated", func(t *testing.T) {
+		result, err := employmentServiceTest.CreateEmploymentRows(context.Background(), request.CreateEmploymentRowsRequest{
+			ProfileCode: 72,
+			Data:        []request.EmploymentRow{{Employer: "Gojek"}},
+		})
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, authorizationService.ErrUnauthenticated)
+	})
+}
+
+func TestReplaceEmploymentRows(t *testing.T) {
+	t.Run("SuccessReplaceEmploymentRows", func(t *testing.T) {
+		employmentRepository.Mock.On("ReplaceEmploymentRows", mock.Anything, 73, 1, mock.MatchedBy(func(rows []*models.Employment) bool {
+			return len(rows) == 2 && rows[0].Employer == "Tokopedia" && rows[1].Employer == "Gojek"
+		})).Run(func(args mock.Arguments) {
+			rows := args.Get(3).([]*models.Employment)
+			rows[0].Id, rows[1].Id = 22, 21
+		}).Return([]*models.EmploymentDTO{{ProfileCode: 73, Id: 20, Employer: "Traveloka"}}, []int{22, 21}, 2, nil).Once()
+
+		result, err := employmentServiceTest.ReplaceEmploymentRows(ownerCtx, request.ReplaceEmploymentRowsRequest{
+			ProfileCode: 73,
+			Version:     1,
+			Data:        []request.EmploymentRow{{Employer: "Tokopedia"}, {Employer: "Gojek"}},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, result.Version)
+		assert.Equal(t, []int{22, 21}, result.Ids)
+		auditRepository.Mock.AssertCalled(t, "CreateAuditLog", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+			return entry.Action == models.AuditActionDelete && entry.ProfileCode == 73 && entry.EntityId == 20
+		}))
+		auditRepository.Mock.AssertCalled(t, "CreateAuditLog", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+			return entry.Action == models.AuditActionCreate && entry.ProfileCode == 73 && entry.EntityId == 22
+		}))
+	})
+	t.Run("SuccessReplaceEmploymentRows_Empty", func(t *testing.T) {
+		employmentRepository.Mock.On("ReplaceEmploymentRows", mock.Anything, 74, 1, []*models.Employment{}).Return([]*models.EmploymentDTO{}, []int{}, 2, nil).Once()
+
+		result, err := employmentServiceTest.ReplaceEmploymentRows(ownerCtx, request.ReplaceEmploymentRowsRequest{ProfileCode: 74, Version: 1, Data: []request.EmploymentRow{}})
+		assert.Nil(t, err)
+		assert.Empty(t, result.Ids)
+	})
+	t.Run("FailedReplaceEmploymentRows", func(t *testing.T) {
+		employmentRepository.Mock.On("ReplaceEmploymentRows", mock.Anything, 75, 1, mock.Anything).Return(nil, nil, 0, errors.New("unexpected")).Once()
+
+		result, err := employmentServiceTest.ReplaceEmploymentRows(ownerCtx, request.ReplaceEmploymentRowsRequest{ProfileCode: 75, Version: 1, Data: []request.EmploymentRow{{Employer: "Gojek"}}})
+		assert.Nil(t, result)
+		assert.Equal(t, "failed to replace employment: unexpected", err.Error())
+	})
+	t.Run("FailedReplaceEmploymentRows_Modified", func(t *testing.T) {
+		employmentRepository.Mock.On("ReplaceEmploymentRows", mock.Anything, 76, 1, mock.Anything).Return(nil, nil, 0, dataRepository.ErrVersionMismatch).Once()
+
+		result, err := employmentServiceTest.ReplaceEmploymentRows(ownerCtx, request.ReplaceEmploymentRowsRequest{ProfileCode: 76, Version: 1, Data: []request.EmploymentRow{{Employer: "Gojek"}}})
+		assert.Nil(t, result)
+		assert.True(t, errors.Is(err, ErrEmploymentModified))
+	})
+}
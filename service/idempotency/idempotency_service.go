@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/repository"
+	"time"
+)
+
+// DefaultTTL is how long responses are kept for retries when no TTL is
+// configured.
+const DefaultTTL = 24 * time.Hour
+
+var (
+	ErrKeyReused     = errors.New("idempotency key was already used for a different request")
+	ErrKeyInProgress = errors.New("a request with this idempotency key is still in progress")
+)
+
+// IdempotencyService lets clients retry create requests safely: the first
+// request with a key claims it, and retries with the same key and request get
+// its response replayed instead of creating the row again.
+type IdempotencyService interface {
+	Begin(ctx context.Context, scope, key, requestHash string) (*models.IdempotencyKeyDTO, error)
+	Complete(ctx context.Context, scope, key string, statusCode int, contentType string, headers map[string]string, body []byte) error
+	Release(ctx context.Context, scope, key string) error
+	PurgeExpired(ctx context.Context, now time.Time) (int, error)
+}
+
+type idempotencyService struct {
+	idempotencyRepo repository.IdempotencyRepository
+	ttl             time.Duration
+	now             func() time.Time
+}
+
+func NewIdempotencyService(idempotencyRepo repository.IdempotencyRepository, ttl time.Duration) *idempotencyService {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &idempotencyService{idempotencyRepo: idempotencyRepo, ttl: ttl, now: time.Now}
+}
+
+// Begin claims the key for the request. It returns nil when the request should
+// run, and the stored response when the same request already ran with the
+// key. Scope keeps the keys of different callers apart.
+func (i *idempotencyService) Begin(ctx context.Context, scope, key, requestHash string) (*models.IdempotencyKeyDTO, error) {
+	now := i.now()
+	claimed, err := i.idempotencyRepo.ClaimIdempotencyKey(ctx, &models.IdempotencyKey{
+		Scope:       scope,
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(i.ttl),
+	}, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim idempotency key: %v", err)
+	}
+	if claimed {
+		return nil, nil
+	}
+
+	stored, err := i.idempotencyRepo.GetIdempotencyKey(ctx, scope, key)
+	if err != nil {
+		// the request holding the key failed and released it in between
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrKeyInProgress
+		}
+		return nil, fmt.Errorf("failed to get idempotency key: %v", err)
+	}
+	if stored.RequestHash != requestHash {
+		return nil, ErrKeyReused
+	}
+	if stored.StatusCode == 0 {
+		return nil, ErrKeyInProgress
+	}
+	return stored, nil
+}
+
+// Complete stores the response of the request that claimed the key.
+func (i *idempotencyService) Complete(ctx context.Context, scope, key string, statusCode int, contentType string, headers map[string]string, body []byte) error {
+	if err := i.idempotencyRepo.CompleteIdempotencyKey(ctx, scope, key, statusCode, contentType, headers, body); err != nil {
+		return fmt.Errorf("failed to store idempotent response: %v", err)
+	}
+	return nil
+}
+
+// Release frees the key of a request that failed, so retrying it runs the
+// request again rather than replaying the failure.
+func (i *idempotencyService) Release(ctx context.Context, scope, key string) error {
+	if err := i.idempotencyRepo.DeleteIdempotencyKey(ctx, scope, key); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %v", err)
+	}
+	return nil
+}
+
+// PurgeExpired removes the keys whose TTL has run out by now.
+func (i *idempotencyService) PurgeExpired(ctx context.Context, now time.Time) (int, error) {
+	purged, err := i.idempotencyRepo.DeleteExpiredIdempotencyKeys(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge idempotency keys: %v", err)
+	}
+	return purged, nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"test-bpjs/v2/models"
+	repository "test-bpjs/v2/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var idempotencyRepository = &repository.IdempotencyRepository{Mock: mock.Mock{}}
+var idempotencyServiceTest = NewIdempotencyService(idempotencyRepository, time.Hour)
+
+func TestBegin(t *testing.T) {
+	now := time.Date(2024, 11, 1, 10, 0, 0, 0, time.UTC)
+	idempotencyServiceTest.now = func() time.Time { return now }
+
+	t.Run("SuccessBegin_Claimed", func(t *testing.T) {
+		idempotencyRepository.Mock.On("ClaimIdempotencyKey", mock.Anything, mock.MatchedBy(func(payload *models.IdempotencyKey) bool {
+			return payload.Key == "claimed" && payload.ExpiresAt.Equal(now.Add(time.Hour))
+		}), now).Return(true, nil).Once()
+
+		stored, err := idempotencyServiceTest.Begin(context.Background(), "user:1", "claimed", "hash")
+		assert.Nil(t, err)
+		assert.Nil(t, stored)
+	})
+
+	t.Run("SuccessBegin_Replay", func(t *testing.T) {
+		idempotencyRepository.Mock.On("ClaimIdempotencyKey", mock.Anything, mock.Anything, now).Return(false, nil).Once()
+		idempotencyRepository.Mock.On("GetIdempotencyKey", mock.Anything, "user:1", "replay").Return(&models.IdempotencyKeyDTO{
+			RequestHash: "hash",
+			StatusCode:  200,
+			Body:        []byte(`{"profileCode":1}`),
+		}, nil).Once()
+
+		stored, err := idempotencyServiceTest.Begin(context.Background(), "user:1", "replay", "hash")
+		assert.Nil(t, err)
+		if assert.NotNil(t, stored) {
+			assert.Equal(t, 200, stored.StatusCode)
+		}
+	})
+
+	t.Run("FailedBegin_Reused", func(t *testing.T) {
+		idempotencyRepository.Mock.On("ClaimIdempotencyKey", mock.Anything, mock.Anything, now).Return(false, nil).Once()
+		idempotencyRepository.Mock.On("GetIdempotencyKey", mock.Anything, "user:1", "reused").Return(&models.IdempotencyKeyDTO{
+			RequestHash: "other",
+			StatusCode:  200,
+		}, nil).Once()
+
+		_, err := idempotencyServiceTest.Begin(context.Background(), "user:1", "reused", "hash")
+		assert.True(t, errors.Is(err, ErrKeyReused))
+	})
+
+	t.Run("FailedBegin_InProgress", func(t *testing.T) {
+		idempotencyRepository.Mock.On("ClaimIdempotencyKey", mock.Anything, mock.Anything, now).Return(false, nil).Once()
+		idempotencyRepository.Mock.On("GetIdempotencyKey", mock.Anything, "user:1", "running").Return(&models.IdempotencyKeyDTO{
+			RequestHash: "hash",
+		}, nil).Once()
+
+		_, err := idempotencyServiceTest.Begin(context.Background(), "user:1", "running", "hash")
+		assert.True(t, errors.Is(err, ErrKeyInProgress))
+	})
+
+	t.Run("FailedBegin_Released", func(t *testing.T) {
+		idempotencyRepository.Mock.On("ClaimIdempotencyKey", mock.Anything, mock.Anything, now).Return(false, nil).Once()
+		idempotencyRepository.Mock.On("GetIdempotencyKey", mock.Anything, "user:1", "released").Return(nil, sql.ErrNoRows).Once()
+
+		_, err := idempotencyServiceTest.Begin(context.Background(), "user:1", "released", "hash")
+		assert.True(t, errors.Is(err, ErrKeyInProgress))
+	})
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/helper/taxonomy"
+	transform "test-bpjs/v2/helper/transform"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/request"
+	"test-bpjs/v2/models/response"
+	"test-bpjs/v2/repository"
+	authorizationService "test-bpjs/v2/service/authorization"
+	"time"
+)
+
+const defaultPageSize = 20
+
+var ErrJobPostingNotFound = errors.New("job posting not found")
+
+type JobPostingService interface {
+	GetJobPostings(ctx context.Context) (*response.JobPostingList, error)
+	GetJobPosting(ctx context.Context, id int) (*response.JobPostingResponse, error)
+	CreateJobPosting(ctx context.Context, payload request.CreateJobPostingRequest) (*response.JobPostingResponse, error)
+	UpdateJobPosting(ctx context.Context, payload request.UpdateJobPostingRequest) (*response.JobPostingResponse, error)
+	DeleteJobPosting(ctx context.Context, id int) error
+	MatchCandidates(ctx context.Context, payload request.MatchJobPostingRequest) (*response.JobMatchList, error)
+}
+
+type jobPostingService struct {
+	jobPostingRepo repository.JobPostingRepository
+	authorizer     authorizationService.Authorizer
+	now            func() time.Time
+}
+
+func NewJobPostingService(jobPostingRepo repository.JobPostingRepository, authorizer authorizationService.Authorizer) *jobPostingService {
+	return &jobPostingService{jobPostingRepo: jobPostingRepo, authorizer: authorizer, now: time.Now}
+}
+
+// GetJobPostings lists the caller's postings, or every posting for admins.
+func (j *jobPostingService) GetJobPostings(ctx context.Context) (*response.JobPostingList, error) {
+	claims, err := j.recruiter(ctx, auth.ScopeJobRead)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerId := claims.UserId
+	if claims.Role == auth.RoleAdmin {
+		ownerId = 0
+	}
+	jobPostings, err := j.jobPostingRepo.GetJobPostings(ctx, ownerId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job postings: %v", err)
+	}
+
+	result := &response.JobPostingList{Data: []*response.JobPostingResponse{}}
+	for _, jobPosting := range jobPostings {
+		result.Data = append(result.Data, transform.TransformJobPosting(jobPosting))
+	}
+	return result, nil
+}
+
+func (j *jobPostingService) GetJobPosting(ctx context.Context, id int) (*response.JobPostingResponse, error) {
+	jobPosting, err := j.jobPosting(ctx, id, auth.ScopeJobRead)
+	if err != nil {
+		return nil, err
+	}
+	return transform.TransformJobPosting(jobPosting), nil
+}
+
+func (j *jobPostingService) CreateJobPosting(ctx context.Context, payload request.CreateJobPostingRequest) (*response.JobPostingResponse, error) {
+	claims, err := j.recruiter(ctx, auth.ScopeJobWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	jobPosting := &models.JobPosting{
+		OwnerId:            claims.UserId,
+		Title:              payload.Title,
+		Skills:             jobPostingSkills(payload.Skills),
+		MinYearsExperience: payload.MinYearsExperience,
+		City:               payload.City,
+		Country:            payload.Country,
+	}
+	created, err := j.jobPostingRepo.CreateJobPosting(ctx, jobPosting)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job posting: %v", err)
+	}
+
+	return transform.TransformJobPosting(&models.JobPostingDTO{
+		Id:                 created.Id,
+		OwnerId:            jobPosting.OwnerId,
+		Title:              jobPosting.Title,
+		Skills:             jobPosting.Skills,
+		MinYearsExperience: jobPosting.MinYearsExperience,
+		City:               jobPosting.City,
+		Country:            jobPosting.Country,
+		CreatedAt:          created.CreatedAt,
+	}), nil
+}
+
+func (j *jobPostingService) UpdateJobPosting(ctx context.Context, payload request.UpdateJobPostingRequest) (*response.JobPostingResponse, error) {
+	current, err := j.jobPosting(ctx, payload.Id, auth.ScopeJobWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	jobPosting := &models.JobPosting{
+		Id:                 current.Id,
+		Title:              payload.Title,
+		Skills:             jobPostingSkills(payload.Skills),
+		MinYearsExperience: payload.MinYearsExperience,
+		City:               payload.City,
+		Country:            payload.Country,
+	}
+	err = j.jobPostingRepo.UpdateJobPosting(ctx, jobPosting)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrJobPostingNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update job posting: %v", err)
+	}
+
+	return transform.TransformJobPosting(&models.JobPostingDTO{
+		Id:                 current.Id,
+		OwnerId:            current.OwnerId,
+		Title:              jobPosting.Title,
+		Skills:             jobPosting.Skills,
+		MinYearsExperience: jobPosting.MinYearsExperience,
+		City:               jobPosting.City,
+		Country:            jobPosting.Country,
+		CreatedAt:          current.CreatedAt,
+		UpdatedAt:          jobPosting.UpdatedAt,
+	}), nil
+}
+
+func (j *jobPostingService) DeleteJobPosting(ctx context.Context, id int) error {
+	if _, err := j.jobPosting(ctx, id, auth.ScopeJobWrite); err != nil {
+		return err
+	}
+
+	err := j.jobPostingRepo.DeleteJobPosting(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrJobPostingNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete job posting: %v", err)
+	}
+	return nil
+}
+
+// MatchCandidates scores the profiles the caller may read against a posting,
+// best match first. Only profiles having at least one of the posting's
+// skills are considered.
+func (j *jobPostingService) MatchCandidates(ctx context.Context, payload request.MatchJobPostingRequest) (*response.JobMatchList, error) {
+	jobPosting, err := j.jobPosting(ctx, payload.Id, auth.ScopeJobRead)
+	if err != nil {
+		return nil, err
+	}
+	visibility, err := j.authorizer.ProfileVisibility(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	page, pageSize := payload.Page, payload.PageSize
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+
+	// rows stored before the taxonomy may still use an alias of the skill
+	skills := make([]string, 0, len(jobPosting.Skills))
+	for _, skill := range jobPosting.Skills {
+		skills = append(skills, taxonomy.Default().Spellings(skill.Skill)...)
+	}
+	candidates, err := j.jobPostingRepo.GetMatchCandidates(ctx, *visibility, skills)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get match candidates: %v", err)
+	}
+
+	now := j.now()
+	matches := make([]*response.JobMatchResponse, 0, len(candidates))
+	for _, candidate := range candidates {
+		matches = append(matches, matchCandidate(jobPosting, candidate, now))
+	}
+	sort.SliceStable(matches, func(a, b int) bool {
+		if matches[a].Score != matches[b].Score {
+			return matches[a].Score > matches[b].Score
+		}
+		return matches[a].ProfileCode < matches[b].ProfileCode
+	})
+
+	result := &response.JobMatchList{
+		JobPostingId: jobPosting.Id,
+		Data:         []*response.JobMatchResponse{},
+		Page:         page,
+		PageSize:     pageSize,
+		Total:        len(matches),
+	}
+	if offset := (page - 1) * pageSize; offset < len(matches) {
+		result.Data = matches[offset:min(offset+pageSize, len(matches))]
+	}
+	return result, nil
+}
+
+// recruiter lets recruiters and admins work with job postings. API keys also
+// need the given scope.
+func (j *jobPostingService) recruiter(ctx context.Context, scope string) (*auth.Claims, error) {
+	claims, err := j.authorizer.CurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Role != auth.RoleRecruiter && claims.Role != auth.RoleAdmin {
+		return nil, authorizationService.ErrForbidden
+	}
+	if !claims.HasScope(scope) {
+		return nil, authorizationService.ErrForbidden
+	}
+	return claims, nil
+}
+
+// jobPosting loads a posting the caller may use: recruiters only have access
+// to their own postings, admins to every posting.
+func (j *jobPostingService) jobPosting(ctx context.Context, id int, scope string) (*models.JobPostingDTO, error) {
+	claims, err := j.recruiter(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	jobPosting, err := j.jobPostingRepo.GetJobPostingById(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrJobPostingNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job posting: %v", err)
+	}
+	if claims.Role != auth.RoleAdmin && jobPosting.OwnerId != claims.UserId {
+		return nil, authorizationService.ErrForbidden
+	}
+	return jobPosting, nil
+}
+
+// jobPostingSkills spells the skills the way the taxonomy does. Levels outside
+// models.SkillLevels are kept, in lower case.
+func jobPostingSkills(rows []request.JobPostingSkillRow) []models.JobPostingSkill {
+	skills := make([]models.JobPostingSkill, 0, len(rows))
+	for _, row := range rows {
+		level, _ := models.NormalizeSkillLevel(row.Level)
+		skills = append(skills, models.JobPostingSkill{
+			Skill:    taxonomy.Default().Canonical(row.Skill),
+			Level:    level,
+			Required: row.Required,
+		})
+	}
+	return skills
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/request"
+	"test-bpjs/v2/repository"
+	mocks "test-bpjs/v2/repository/mocks"
+	authorizationService "test-bpjs/v2/service/authorization"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var jobPostingRepository = &mocks.JobPostingRepository{Mock: mock.Mock{}}
+var profileRepository = &mocks.ProfileRepository{Mock: mock.Mock{}}
+var consentRepository = &mocks.ConsentRepository{Mock: mock.Mock{}}
+var authorizer = authorizationService.NewAuthorizer(profileRepository, consentRepository, "")
+var jobPostingServiceTest = jobPostingService{
+	jobPostingRepo: jobPostingRepository,
+	authorizer:     authorizer,
+	now:            func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) },
+}
+
+// the tests run as recruiter 5, who owns the postings below 100
+var recruiterCtx = auth.WithClaims(context.Background(), &auth.Claims{UserId: 5, Role: auth.RoleRecruiter})
+var adminCtx = auth.WithClaims(context.Background(), &auth.Claims{UserId: 2, Role: auth.RoleAdmin})
+var userCtx = auth.WithClaims(context.Background(), &auth.Claims{UserId: 1, Role: auth.RoleUser})
+
+func TestInitJobPostingService(t *testing.T) {
+	t.Run("SuccessInitJobPostingService", func(t *testing.T) {
+		assert.NotNil(t, NewJobPostingService(jobPostingRepository, authorizer))
+	})
+}
+
+func TestGetJobPostings(t *testing.T) {
+	t.Run("SuccessGetOwnJobPostings", func(t *testing.T) {
+		jobPostingRepository.Mock.On("GetJobPostings", mock.Anything, 5).Return([]*models.JobPostingDTO{
+			{Id: 1, OwnerId: 5, Title: "Backend Engineer", Skills: []models.JobPostingSkill{{Skill: "Golang", Level: "Advanced", Required: true}}},
+		}, nil).Once()
+
+		result, err := jobPostingServiceTest.GetJobPostings(recruiterCtx)
+		assert.Nil(t, err)
+		assert.Len(t, result.Data, 1)
+		assert.Equal(t, "Golang", result.Data[0].Skills[0].Skill)
+		assert.Nil(t, result.Data[0].UpdatedAt)
+	})
+
+	t.Run("SuccessGetEveryJobPostingAsAdmin", func(t *testing.T) {
+		jobPostingRepository.Mock.On("GetJobPostings", mock.Anything, 0).Return([]*models.JobPostingDTO{}, nil).Once()
+
+		result, err := jobPostingServiceTest.GetJobPostings(adminCtx)
+		assert.Nil(t, err)
+		assert.Empty(t, result.Data)
+	})
+
+	t.Run("FailedGetJobPostingsAsCandidate", func(t *testing.T) {
+		_, err := jobPostingServiceTest.GetJobPostings(userCtx)
+		assert.ErrorIs(t, err, authorizationService.ErrForbidden)
+	})
+
+	t.Run("FailedGetJobPostingsWithoutScope", func(t *testing.T) {
+		ctx := auth.WithClaims(context.Background(), &auth.Claims{UserId: 5, Role: auth.RoleRecruiter, ApiKeyId: 3, Scopes: []string{auth.ScopeProfileRead}})
+		_, err := jobPostingServiceTest.GetJobPostings(ctx)
+		assert.ErrorIs(t, err, authorizationService.ErrForbidden)
+	})
+}
+
+func TestGetJobPosting(t *testing.T) {
+	t.Run("SuccessGetJobPosting", func(t *testing.T) {
+		jobPostingRepository.Mock.On("GetJobPostingById", mock.Anything, 2).Return(&models.JobPostingDTO{Id: 2, OwnerId: 5, Title: "Data Engineer"}, nil).Once()
+
+		result, err := jobPostingServiceTest.GetJobPosting(recruiterCtx, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, "Data Engineer", result.Title)
+	})
+
+	t.Run("FailedGetJobPostingOfAnotherRecruiter", func(t *testing.T) {
+		jobPostingRepository.Mock.On("GetJobPostingById", mock.Anything, 100).Return(&models.JobPostingDTO{Id: 100, OwnerId: 6}, nil).Once()
+
+		_, err := jobPostingServiceTest.GetJobPosting(recruiterCtx, 100)
+		assert.ErrorIs(t, err, authorizationService.ErrForbidden)
+	})
+
+	t.Run("FailedGetJobPostingNotFound", func(t *testing.T) {
+		jobPostingRepository.Mock.On("GetJobPostingById", mock.Anything, 3).Return(&models.JobPostingDTO{}, sql.ErrNoRows).Once()
+
+		_, err := jobPostingServiceTest.GetJobPosting(recruiterCtx, 3)
+		assert.ErrorIs(t, err, ErrJobPostingNotFound)
+	})
+}
+
+func TestCreateJobPosting(t *testing.T) {
+	t.Run("SuccessCreateJobPosting", func(t *testing.T) {
+		createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		jobPostingRepository.Mock.On("CreateJobPosting", mock.Anything, mock.MatchedBy(func(jobPosting *models.JobPosting) bool {
+			return jobPosting.OwnerId == 5 && jobPosting.Title == "Mobile Engineer" &&
+				len(jobPosting.Skills) == 1 && jobPosting.Skills[0] == models.JobPostingSkill{Skill: "Go", Level: "intermediate", Required: true}
+		})).Return(&models.JobPostingDTO{Id: 4, CreatedAt: createdAt}, nil).Once()
+
+		result, err := jobPostingServiceTest.CreateJobPosting(recruiterCtx, request.CreateJobPostingRequest{
+			Title:  "Mobile Engineer",
+			Skills: []request.JobPostingSkillRow{{Skill: " golang ", Level: "Intermediate", Required: true}},
+			City:   "Bandung",
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 4, result.Id)
+		assert.Equal(t, 5, result.OwnerId)
+		assert.Equal(t, createdAt, result.CreatedAt)
+	})
+
+	t.Run("FailedCreateJobPosting", func(t *testing.T) {
+		jobPostingRepository.Mock.On("CreateJobPosting", mock.Anything, mock.MatchedBy(func(jobPosting *models.JobPosting) bool {
+			return jobPosting.Title == "Broken"
+		})).Return(&models.JobPostingDTO{}, errors.New("database down")).Once()
+
+		_, err := jobPostingServiceTest.CreateJobPosting(recruiterCtx, request.CreateJobPostingRequest{Title: "Broken"})
+		assert.EqualError(t, err, "failed to create job posting: database down")
+	})
+}
+
+func TestUpdateJobPosting(t *testing.T) {
+	t.Run("SuccessUpdateJobPosting", func(t *testing.T) {
+		jobPostingRepository.Mock.On("GetJobPostingById", mock.Anything, 5).Return(&models.JobPostingDTO{Id: 5, OwnerId: 5, Title: "Old"}, nil).Once()
+		jobPostingRepository.Mock.On("UpdateJobPosting", mock.Anything, mock.MatchedBy(func(jobPosting *models.JobPosting) bool {
+			return jobPosting.Id == 5 && jobPosting.Title == "New" && jobPosting.MinYearsExperience == 3
+		})).Return(nil).Once()
+
+		result, err := jobPostingServiceTest.UpdateJobPosting(recruiterCtx, request.UpdateJobPostingRequest{
+			Id: 5, Title: "New", Skills: []request.JobPostingSkillRow{{Skill: "Golang"}}, MinYearsExperience: 3,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, "New", result.Title)
+		assert.Equal(t, 5, result.OwnerId)
+	})
+
+	t.Run("FailedUpdateJobPostingDeletedMeanwhile", func(t *testing.T) {
+		jobPostingRepository.Mock.On("GetJobPostingById", mock.Anything, 6).Return(&models.JobPostingDTO{Id: 6, OwnerId: 5}, nil).Once()
+		jobPostingRepository.Mock.On("UpdateJobPosting", mock.Anything, mock.MatchedBy(func(jobPosting *models.JobPosting) bool {
+			return jobPosting.Id == 6
+		})).Return(repository.ErrNotFound).Once()
+
+		_, err := jobPostingServiceTest.UpdateJobPosting(recruiterCtx, request.UpdateJobPostingRequest{Id: 6, Title: "New"})
+		assert.ErrorIs(t, err, ErrJobPostingNotFound)
+	})
+}
+
+func TestDeleteJobPosting(t *testing.T) {
+	t.Run("SuccessDeleteJobPostingAsAdmin", func(t *testing.T) {
+		jobPostingRepository.Mock.On("GetJobPostingById", mock.Anything, 101).Return(&models.JobPostingDTO{Id: 101, OwnerId: 6}, nil).Once()
+		jobPostingRepository.Mock.On("DeleteJobPosting", mock.Anything, 101).Return(nil).Once()
+
+		assert.Nil(t, jobPostingServiceTest.DeleteJobPosting(adminCtx, 101))
+	})
+
+	t.Run("FailedDeleteJobPostingOfAnotherRecruiter", func(t *testing.T) {
+		jobPostingRepository.Mock.On("GetJobPostingById", mock.Anything, 102).Return(&models.JobPostingDTO{Id: 102, OwnerId: 6}, nil).Once()
+
+		err := jobPostingServiceTest.DeleteJobPosting(recruiterCtx, 102)
+		assert.ErrorIs(t, err, authorizationService.ErrForbidden)
+		jobPostingRepository.Mock.AssertNotCalled(t, "DeleteJobPosting", mock.Anything, 102)
+	})
+}
+
+func TestMatchCandidates(t *testing.T) {
+	jobPosting := &models.JobPostingDTO{
+		Id:      7,
+		OwnerId: 5,
+		Title:   "Backend Engineer",
+		Skills: []models.JobPostingSkill{
+			{Skill: "Golang", Level: "Advanced", Required: true},
+			{Skill: "PostgreSQL", Required: false},
+		},
+		MinYearsExperience: 2,
+		City:               "Jakarta",
+		Country:            "Indonesia",
+	}
+
+	t.Run("SuccessMatchCandidates", func(t *testing.T) {
+		jobPostingRepository.Mock.On("GetJobPostingById", mock.Anything, 7).Return(jobPosting, nil).Once()
+		jobPostingRepository.Mock.On("GetMatchCandidates", mock.Anything, models.ProfileVisibility{OwnerId: 5, Consented: true}, []string{"go", "golang", "go lang", "postgresql", "postgres", "psql", "pgsql"}).Return([]*models.MatchCandidateDTO{
+			{
+				ProfileCode: 11, City: "Bandung", Country: "Indonesia",
+				Skills:     []*models.SkillDTO{{Skill: "golang", Level: "Intermediate"}},
+				Employment: []*models.EmploymentDTO{{StartDate: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}},
+			},
+			{
+				ProfileCode: 12, City: "jakarta", Country: "Indonesia",
+				Skills:     []*models.SkillDTO{{Skill: "Golang", Level: "Expert"}, {Skill: "PostgreSQL", Level: "Beginner"}},
+				Employment: []*models.EmploymentDTO{{StartDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}},
+			},
+		}, nil).Once()
+
+		result, err := jobPostingServiceTest.MatchCandidates(recruiterCtx, request.MatchJobPostingRequest{Id: 7})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, result.Total)
+		assert.Equal(t, defaultPageSize, result.PageSize)
+		// the best match comes first
+		assert.Equal(t, 12, result.Data[0].ProfileCode)
+		assert.Equal(t, float64(100), result.Data[0].Score)
+		assert.Equal(t, 11, result.Data[1].ProfileCode)
+		// half the Golang points, no PostgreSQL, about half the experience
+		// and the same country
+		assert.InDelta(t, 20+0+12.5+7.5, result.Data[1].Score, 0.05)
+	})
+
+	t.Run("SuccessMatchCandidatesPastLastPage", func(t *testing.T) {
+		jobPostingRepository.Mock.On("GetJobPostingById", mock.Anything, 8).Return(&models.JobPostingDTO{Id: 8, OwnerId: 5, Skills: []models.JobPostingSkill{{Skill: "Rust"}}}, nil).Once()
+		jobPostingRepository.Mock.On("GetMatchCandidates", mock.Anything, mock.Anything, []string{"rust", "rustlang"}).Return([]*models.MatchCandidateDTO{{ProfileCode: 13}}, nil).Once()
+
+		result, err := jobPostingServiceTest.MatchCandidates(recruiterCtx, request.MatchJobPostingRequest{Id: 8, Page: 2, PageSize: 10})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, result.Total)
+		assert.Empty(t, result.Data)
+	})
+
+	t.Run("FailedMatchCandidates", func(t *testing.T) {
+		jobPostingRepository.Mock.On("GetJobPostingById", mock.Anything, 9).Return(&models.JobPostingDTO{Id: 9, OwnerId: 5, Skills: []models.JobPostingSkill{{Skill: "Elixir"}}}, nil).Once()
+		jobPostingRepository.Mock.On("GetMatchCandidates", mock.Anything, mock.Anything, []string{"elixir"}).Return(nil, errors.New("database down")).Once()
+
+		_, err := jobPostingServiceTest.MatchCandidates(recruiterCtx, request.MatchJobPostingRequest{Id: 9})
+		assert.EqualError(t, err, "failed to get match candidates: database down")
+	})
+}
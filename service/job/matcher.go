@@ -0,0 +1,214 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"test-bpjs/v2/helper/taxonomy"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/response"
+	timelineService "test-bpjs/v2/service/timeline"
+	"time"
+)
+
+const (
+	CriterionSkill      = "skill"
+	CriterionExperience = "experience"
+	CriterionLocation   = "location"
+)
+
+// The points of each criterion add up to a score out of 100. The skill
+// points are split between the skills of the posting, required skills
+// weighing twice as much as optional ones.
+const (
+	skillPoints      = 60.0
+	experiencePoints = 25.0
+	locationPoints   = 15.0
+
+	requiredSkillWeight = 2.0
+	optionalSkillWeight = 1.0
+)
+
+// levelRanks orders the usual skill levels. Levels not listed here only
+// match themselves.
+var levelRanks = map[string]int{
+	models.SkillLevelBeginner:     1,
+	models.SkillLevelIntermediate: 2,
+	models.SkillLevelAdvanced:     3,
+	models.SkillLevelExpert:       4,
+}
+
+const daysPerYear = 365.25
+
+// matchCandidate scores a candidate against a job posting and explains the
+// score criterion by criterion.
+func matchCandidate(jobPosting *models.JobPostingDTO, candidate *models.MatchCandidateDTO, now time.Time) *response.JobMatchResponse {
+	years := yearsOfExperience(candidate.Employment, now)
+
+	criteria := matchSkills(jobPosting.Skills, candidate.Skills)
+	criteria = append(criteria, matchExperience(jobPosting.MinYearsExperience, years), matchLocation(jobPosting, candidate))
+
+	var score float64
+	for _, criterion := range criteria {
+		score += criterion.Points
+	}
+	return &response.JobMatchResponse{
+		ProfileCode:       candidate.ProfileCode,
+		PublicId:          candidate.PublicId,
+		WantedJobTitle:    candidate.WantedJobTitle,
+		FirstName:         candidate.FirstName,
+		LastName:          candidate.LastName,
+		City:              candidate.City,
+		Country:           candidate.Country,
+		Score:             round(score),
+		YearsOfExperience: math.Round(years*10) / 10,
+		Criteria:          criteria,
+	}
+}
+
+// matchSkills gives each skill of the posting its share of the skill points
+// when the candidate has it at the asked level or above, and half of it when
+// the candidate has it at a lower level. Skills are compared by their name in
+// the taxonomy, so "golang" matches "Go".
+func matchSkills(wanted []models.JobPostingSkill, skills []*models.SkillDTO) []*response.MatchCriterionResponse {
+	levels := make(map[string]string, len(skills))
+	for _, skill := range skills {
+		name := skillKey(skill.Skill)
+		if current, ok := levels[name]; !ok || levelRanks[strings.ToLower(skill.Level)] > levelRanks[strings.ToLower(current)] {
+			levels[name] = skill.Level
+		}
+	}
+
+	var totalWeight float64
+	for _, skill := range wanted {
+		totalWeight += skillWeight(skill)
+	}
+
+	criteria := make([]*response.MatchCriterionResponse, 0, len(wanted)+2)
+	for _, skill := range wanted {
+		maxPoints := skillPoints * skillWeight(skill) / totalWeight
+		criterion := &response.MatchCriterionResponse{Criterion: CriterionSkill, Name: skill.Skill, MaxPoints: round(maxPoints)}
+
+		level, ok := levels[skillKey(skill.Skill)]
+		switch {
+		case !ok:
+			criterion.Detail = "missing"
+		case meetsLevel(level, skill.Level):
+			criterion.Points = maxPoints
+			criterion.Detail = describeLevel(level)
+		default:
+			criterion.Points = maxPoints / 2
+			criterion.Detail = fmt.Sprintf("%s, needs %s", describeLevel(level), skill.Level)
+		}
+		if skill.Required {
+			criterion.Detail = "required, " + criterion.Detail
+		}
+		criterion.Matched = criterion.Points == maxPoints
+		criterion.Points = round(criterion.Points)
+		criteria = append(criteria, criterion)
+	}
+	return criteria
+}
+
+func skillKey(skill string) string {
+	return strings.ToLower(taxonomy.Default().Canonical(skill))
+}
+
+func skillWeight(skill models.JobPostingSkill) float64 {
+	if skill.Required {
+		return requiredSkillWeight
+	}
+	return optionalSkillWeight
+}
+
+func meetsLevel(level, wanted string) bool {
+	if wanted == "" {
+		return true
+	}
+	if strings.EqualFold(level, wanted) {
+		return true
+	}
+	rank, wantedRank := levelRanks[strings.ToLower(level)], levelRanks[strings.ToLower(wanted)]
+	return rank > 0 && wantedRank > 0 && rank >= wantedRank
+}
+
+func describeLevel(level string) string {
+	if level == "" {
+		return "has it, level unknown"
+	}
+	return "has it at " + level
+}
+
+// matchExperience gives the experience points in proportion to how much of
+// the minimum experience the candidate has.
+func matchExperience(minYears int, years float64) *response.MatchCriterionResponse {
+	criterion := &response.MatchCriterionResponse{
+		Criterion: CriterionExperience,
+		Name:      "years of experience",
+		MaxPoints: experiencePoints,
+		Detail:    fmt.Sprintf("%.1f years, needs %d", years, minYears),
+	}
+	if minYears <= 0 || years >= float64(minYears) {
+		criterion.Points = experiencePoints
+	} else {
+		criterion.Points = round(experiencePoints * years / float64(minYears))
+	}
+	criterion.Matched = criterion.Points == experiencePoints
+	return criterion
+}
+
+// yearsOfExperience is the total experience of the employment timeline, so
+// jobs held at once are not counted twice. Rows without an end date are
+// still ongoing.
+func yearsOfExperience(employment []*models.EmploymentDTO, now time.Time) float64 {
+	return float64(timelineService.ExperienceDays(employment, now)) / daysPerYear
+}
+
+// matchLocation gives the location points to candidates living in the city
+// of the posting, and half of them to candidates from the same country.
+// Postings without a location accept candidates from anywhere.
+func matchLocation(jobPosting *models.JobPostingDTO, candidate *models.MatchCandidateDTO) *response.MatchCriterionResponse {
+	criterion := &response.MatchCriterionResponse{Criterion: CriterionLocation, Name: "any location", MaxPoints: locationPoints}
+	if jobPosting.City != "" || jobPosting.Country != "" {
+		criterion.Name = location(jobPosting.City, jobPosting.Country)
+	}
+
+	sameCountry := jobPosting.Country == "" || strings.EqualFold(strings.TrimSpace(jobPosting.Country), strings.TrimSpace(candidate.Country))
+	sameCity := jobPosting.City == "" || strings.EqualFold(strings.TrimSpace(jobPosting.City), strings.TrimSpace(candidate.City))
+	switch {
+	case jobPosting.City == "" && jobPosting.Country == "":
+		criterion.Points = locationPoints
+		criterion.Detail = livesIn(candidate)
+	case sameCountry && sameCity:
+		criterion.Points = locationPoints
+		criterion.Detail = livesIn(candidate)
+	case sameCountry && jobPosting.Country != "":
+		criterion.Points = locationPoints / 2
+		criterion.Detail = "same country, " + livesIn(candidate)
+	default:
+		criterion.Detail = livesIn(candidate)
+	}
+	criterion.Matched = criterion.Points == locationPoints
+	return criterion
+}
+
+func livesIn(candidate *models.MatchCandidateDTO) string {
+	if candidate.City == "" && candidate.Country == "" {
+		return "location unknown"
+	}
+	return "lives in " + location(candidate.City, candidate.Country)
+}
+
+func location(city, country string) string {
+	switch {
+	case city == "":
+		return country
+	case country == "":
+		return city
+	}
+	return city + ", " + country
+}
+
+func round(points float64) float64 {
+	return math.Round(points*100) / 100
+}
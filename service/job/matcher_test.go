@@ -0,0 +1,96 @@
+package service
+
+import (
+	"test-bpjs/v2/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchSkills(t *testing.T) {
+	wanted := []models.JobPostingSkill{
+		{Skill: "Golang", Level: "Advanced", Required: true},
+		{Skill: "Docker", Level: "Intermediate", Required: true},
+		{Skill: "Kubernetes"},
+		{Skill: "COBOL", Level: "Fluent"},
+	}
+	criteria := matchSkills(wanted, []*models.SkillDTO{
+		{Skill: "golang", Level: "Beginner"},
+		{Skill: "Golang", Level: "Expert"},
+		{Skill: "Docker", Level: "Beginner"},
+		{Skill: "Kubernetes"},
+		{Skill: "COBOL", Level: "fluent"},
+	})
+
+	assert.Len(t, criteria, 4)
+	// required skills weigh twice as much: 60 points split 2:2:1:1
+	assert.Equal(t, 20.0, criteria[0].MaxPoints)
+	assert.Equal(t, 10.0, criteria[2].MaxPoints)
+
+	// the best level of a skill listed twice counts
+	assert.True(t, criteria[0].Matched)
+	assert.Equal(t, "required, has it at Expert", criteria[0].Detail)
+
+	assert.False(t, criteria[1].Matched)
+	assert.Equal(t, 10.0, criteria[1].Points)
+	assert.Equal(t, "required, has it at Beginner, needs Intermediate", criteria[1].Detail)
+
+	// any level will do when the posting does not ask for one
+	assert.True(t, criteria[2].Matched)
+
+	// levels outside the usual scale only match themselves
+	assert.True(t, criteria[3].Matched)
+
+	// skills are compared by their name in the taxonomy
+	criteria = matchSkills([]models.JobPostingSkill{{Skill: "Postgres"}}, []*models.SkillDTO{{Skill: "PostgreSQL"}})
+	assert.True(t, criteria[0].Matched)
+
+	criteria = matchSkills(wanted[:1], nil)
+	assert.Equal(t, 0.0, criteria[0].Points)
+	assert.Equal(t, "required, missing", criteria[0].Detail)
+}
+
+func TestYearsOfExperience(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	years := yearsOfExperience([]*models.EmploymentDTO{
+		{StartDate: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		// ongoing
+		{StartDate: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+		// without a start date
+		{EndDate: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)},
+		// held alongside the first job, so it adds nothing
+		{StartDate: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)},
+	}, now)
+	assert.InDelta(t, 4.0, years, 0.01)
+}
+
+func TestMatchExperience(t *testing.T) {
+	assert.True(t, matchExperience(0, 0).Matched)
+	assert.True(t, matchExperience(3, 4.2).Matched)
+
+	criterion := matchExperience(4, 1)
+	assert.False(t, criterion.Matched)
+	assert.Equal(t, 6.25, criterion.Points)
+	assert.Equal(t, "1.0 years, needs 4", criterion.Detail)
+}
+
+func TestMatchLocation(t *testing.T) {
+	jobPosting := &models.JobPostingDTO{City: "Jakarta", Country: "Indonesia"}
+
+	criterion := matchLocation(jobPosting, &models.MatchCandidateDTO{City: "JAKARTA", Country: "indonesia"})
+	assert.True(t, criterion.Matched)
+	assert.Equal(t, "Jakarta, Indonesia", criterion.Name)
+
+	criterion = matchLocation(jobPosting, &models.MatchCandidateDTO{City: "Surabaya", Country: "Indonesia"})
+	assert.Equal(t, 7.5, criterion.Points)
+	assert.Equal(t, "same country, lives in Surabaya, Indonesia", criterion.Detail)
+
+	criterion = matchLocation(jobPosting, &models.MatchCandidateDTO{City: "Jakarta", Country: "Singapore"})
+	assert.Equal(t, 0.0, criterion.Points)
+
+	criterion = matchLocation(&models.JobPostingDTO{}, &models.MatchCandidateDTO{})
+	assert.True(t, criterion.Matched)
+	assert.Equal(t, "any location", criterion.Name)
+	assert.Equal(t, "location unknown", criterion.Detail)
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/helper/storage"
+	transform "test-bpjs/v2/helper/transform"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/request"
+	"test-bpjs/v2/models/response"
+	"test-bpjs/v2/repository"
+	authorizationService "test-bpjs/v2/service/authorization"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+// PrivacyService answers data subject requests under the personal data
+// protection law: access (a full export) and erasure.
+type PrivacyService interface {
+	ExportProfileData(ctx context.Context, code int) (*response.DataExport, error)
+	EraseProfile(ctx context.Context, payload request.EraseProfileRequest) (*response.ErasureReceiptResponse, error)
+}
+
+type privacyService struct {
+	profileRepo    repository.ProfileRepository
+	educationRepo  repository.EducationRepository
+	employmentRepo repository.EmploymentRepository
+	skillRepo      repository.SkillRepository
+	consentRepo    repository.ConsentRepository
+	auditRepo      repository.AuditRepository
+	resumeRepo     repository.ResumeRepository
+	privacyRepo    repository.PrivacyRepository
+	authorizer     authorizationService.Authorizer
+}
+
+func NewPrivacyService(
+	profileRepo repository.ProfileRepository,
+	educationRepo repository.EducationRepository,
+	employmentRepo repository.EmploymentRepository,
+	skillRepo repository.SkillRepository,
+	consentRepo repository.ConsentRepository,
+	auditRepo repository.AuditRepository,
+	resumeRepo repository.ResumeRepository,
+	privacyRepo repository.PrivacyRepository,
+	authorizer authorizationService.Authorizer,
+) *privacyService {
+	return &privacyService{
+		profileRepo:    profileRepo,
+		educationRepo:  educationRepo,
+		employmentRepo: employmentRepo,
+		skillRepo:      skillRepo,
+		consentRepo:    consentRepo,
+		auditRepo:      auditRepo,
+		resumeRepo:     resumeRepo,
+		privacyRepo:    privacyRepo,
+		authorizer:     authorizer,
+	}
+}
+
+type exportManifest struct {
+	ProfilePublicId string    `json:"profilePublicId"`
+	GeneratedAt     time.Time `json:"generatedAt"`
+	Files           []string  `json:"files"`
+}
+
+// ExportProfileData returns a zip holding every row and file stored for the
+// profile, with personal data decrypted. API keys need the resume export
+// scope.
+func (p *privacyService) ExportProfileData(ctx context.Context, code int) (*response.DataExport, error) {
+	if err := p.authorizer.CanManageProfileData(ctx, code, auth.ScopeResumeExport); err != nil {
+		return nil, err
+	}
+
+	profile, err := p.profileRepo.GetProfileByCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %v", err)
+	}
+	workingExperience, err := p.profileRepo.GetWorkingExperienceByCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working experience: %v", err)
+	}
+	profile.WorkingExperience = workingExperience.WorkingExperience
+
+	education, err := p.educationRepo.GetEducationByProfileCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get education: %v", err)
+	}
+	employment, err := p.employmentRepo.GetEmploymentByProfileCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get employment: %v", err)
+	}
+	skills, err := p.skillRepo.GetSkillsByProfileCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get skills: %v", err)
+	}
+	consents, err := p.consentRepo.GetConsentsByProfileCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consents: %v", err)
+	}
+	auditLog, _, err := p.auditRepo.GetAuditLogsByProfileCode(ctx, code, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit log: %v", err)
+	}
+	resumeVersions, err := p.resumeVersions(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resume versions: %v", err)
+	}
+
+	// the rows go through the same transforms as the API responses, so the
+	// archive holds no internal codes either
+	educationList := make([]*response.EducationResponse, 0, len(education))
+	for _, row := range education {
+		educationList = append(educationList, transform.TransformEducation(row))
+	}
+	employmentList := make([]*response.EmploymentResponse, 0, len(employment))
+	for _, row := range employment {
+		employmentList = append(employmentList, transform.TransformEmployment(row))
+	}
+	skillList := make([]*response.SkillResponse, 0, len(skills))
+	for _, row := range skills {
+		skillList = append(skillList, transform.TransformSkill(row))
+	}
+	consentList := make([]*response.ConsentResponse, 0, len(consents))
+	for _, row := range consents {
+		consentList = append(consentList, transform.TransformConsent(row))
+	}
+	auditList := make([]*response.AuditLogResponse, 0, len(auditLog))
+	for _, entry := range auditLog {
+		auditList = append(auditList, transform.TransformAuditLog(entry))
+	}
+	versionList := make([]*response.ResumeVersionResponse, 0, len(resumeVersions))
+	for _, version := range resumeVersions {
+		versionList = append(versionList, transform.TransformResumeVersion(version))
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	manifest := exportManifest{ProfilePublicId: profile.PublicId, GeneratedAt: time.Now().UTC()}
+
+	for _, entry := range []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", transform.TransformProfileData(profile)},
+		{"education.json", educationList},
+		{"employment.json", employmentList},
+		{"skills.json", skillList},
+		{"consents.json", consentList},
+		{"audit.json", auditList},
+		{"resume_versions.json", versionList},
+	} {
+		content, err := json.MarshalIndent(entry.data, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %v", entry.name, err)
+		}
+		if err := addToArchive(archive, entry.name, content); err != nil {
+			return nil, err
+		}
+		manifest.Files = append(manifest.Files, entry.name)
+	}
+
+	if profile.PhotoUrl != "" {
+		photo, err := os.ReadFile(storage.ResolvePath(profile.PhotoUrl))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read photo: %v", err)
+		}
+		if err == nil {
+			name := "photos/" + path.Base(profile.PhotoUrl)
+			if err := addToArchive(archive, name, photo); err != nil {
+				return nil, err
+			}
+			manifest.Files = append(manifest.Files, name)
+		}
+	}
+
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %v", err)
+	}
+	if err := addToArchive(archive, "manifest.json", content); err != nil {
+		return nil, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write export archive: %v", err)
+	}
+
+	return &response.DataExport{
+		FileName: fmt.Sprintf("data-export-%s.zip", profile.PublicId),
+		Content:  buf.Bytes(),
+	}, nil
+}
+
+// EraseProfile anonymises or deletes a profile together with its education,
+// employment, skill, consent and resume version rows, redacts its audit log and then drops
+// its photo. The returned receipt is also stored, and holds no personal data.
+func (p *privacyService) EraseProfile(ctx context.Context, payload request.EraseProfileRequest) (*response.ErasureReceiptResponse, error) {
+	if err := p.authorizer.CanManageProfileData(ctx, payload.ProfileCode, auth.ScopeProfileWrite); err != nil {
+		return nil, err
+	}
+	user, err := p.authorizer.CurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := p.profileRepo.GetProfileByCode(ctx, payload.ProfileCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %v", err)
+	}
+
+	receipt := &models.ErasureReceipt{
+		ReceiptId:       ulid.Make().String(),
+		ProfilePublicId: profile.PublicId,
+		Mode:            payload.Mode,
+		Reason:          payload.Reason,
+		RequestedBy:     user.UserId,
+		PhotoRemoved:    profile.PhotoUrl != "",
+		ErasedAt:        time.Now().UTC(),
+	}
+	if err := p.privacyRepo.EraseProfile(ctx, payload.ProfileCode, receipt); err != nil {
+		return nil, fmt.Errorf("failed to erase profile: %v", err)
+	}
+
+	// the rows are gone at this point, so a leftover file is logged rather
+	// than failing a request that can't be retried
+	if err := p.removePhoto(ctx, profile); err != nil {
+		log.WithContext(ctx).Warnf("erasure %s: %v", receipt.ReceiptId, err)
+	}
+
+	return transform.TransformErasureReceipt(receipt), nil
+}
+
+// removePhoto deletes the photo file unless another profile uses the same
+// picture. Photos uploaded before hashing was introduced are never shared.
+func (p *privacyService) removePhoto(ctx context.Context, profile *models.ProfileDTO) error {
+	if profile.PhotoUrl == "" {
+		return nil
+	}
+	if profile.PhotoHash != "" {
+		_, err := storage.ReleasePhoto(ctx, p.profileRepo, profile.PhotoHash, profile.PhotoUrl)
+		return err
+	}
+	if err := os.Remove(storage.ResolvePath(profile.PhotoUrl)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove photo file: %v", err)
+	}
+	return nil
+}
+
+func addToArchive(archive *zip.Writer, name string, content []byte) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s to export: %v", name, err)
+	}
+	if _, err := w.Write(content); err != nil {
+		return fmt.Errorf("failed to add %s to export: %v", name, err)
+	}
+	return nil
+}
+
+// resumeVersions loads every version of the profile's resume with its
+// snapshot, oldest first.
+func (p *privacyService) resumeVersions(ctx context.Context, code int) ([]*models.ResumeVersionDTO, error) {
+	versions, err := p.resumeRepo.GetResumeVersions(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	resumeVersions := make([]*models.ResumeVersionDTO, 0, len(versions))
+	for i := len(versions) - 1; i >= 0; i-- {
+		version, err := p.resumeRepo.GetResumeVersion(ctx, code, versions[i].Version)
+		if err != nil {
+			return nil, err
+		}
+		resumeVersions = append(resumeVersions, version)
+	}
+	return resumeVersions, nil
+}
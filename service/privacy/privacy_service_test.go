@@ -0,0 +1,208 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/helper/storage"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/request"
+	"test-bpjs/v2/models/response"
+	repository "test-bpjs/v2/repository/mocks"
+	authorizationService "test-bpjs/v2/service/authorization"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testPhotoUrl = "public/image/4a567c8956410440a55b7544ebae3d72433f134bf40cabf4f462da50dbb17814.png"
+
+var profileRepository = &repository.ProfileRepository{Mock: mock.Mock{}}
+var educationRepository = &repository.EducationRepository{Mock: mock.Mock{}}
+var employmentRepository = &repository.EmploymentRepository{Mock: mock.Mock{}}
+var skillRepository = &repository.SkillRepository{Mock: mock.Mock{}}
+var privacyRepository = &repository.PrivacyRepository{Mock: mock.Mock{}}
+var consentRepository = &repository.ConsentRepository{Mock: mock.Mock{}}
+var auditRepository = &repository.AuditRepository{Mock: mock.Mock{}}
+var resumeRepository = &repository.ResumeRepository{Mock: mock.Mock{}}
+var authorizer = authorizationService.NewAuthorizer(profileRepository, consentRepository, "")
+var privacyServiceTest = NewPrivacyService(profileRepository, educationRepository, employmentRepository, skillRepository, consentRepository, auditRepository, resumeRepository, privacyRepository, authorizer)
+
+// the tests run as user 1, who owns every profile
+var ownerCtx = auth.WithClaims(context.Background(), &auth.Claims{UserId: 1, Role: auth.RoleUser})
+var _ = profileRepository.Mock.On("GetProfileOwner", mock.Anything, mock.Anything).Return(1, nil)
+
+// photoRoot keeps the photos a test writes out of public/image.
+func photoRoot(t *testing.T) {
+	root := t.TempDir()
+	storage.SetRoot(root)
+	t.Cleanup(func() { storage.SetRoot("") })
+	assert.Nil(t, os.MkdirAll(filepath.Join(root, "public/image"), 0755))
+}
+
+func TestExportProfileData(t *testing.T) {
+	t.Run("SuccessExportProfileData", func(t *testing.T) {
+		photoRoot(t)
+		assert.Nil(t, os.WriteFile(storage.ResolvePath(testPhotoUrl), []byte("png"), 0644))
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 1).Return(&models.ProfileDTO{
+			ProfileCode: 1,
+			PublicId:    "01JAB3Q8W0RM0ZTRBPN7C2Z1K4",
+			FirstName:   "John",
+			Email:       "john@example.com",
+			PhotoUrl:    testPhotoUrl,
+			PhotoHash:   "4a567c8956410440a55b7544ebae3d72433f134bf40cabf4f462da50dbb17814",
+			OwnerId:     1,
+		}, nil).Once()
+		profileRepository.Mock.On("GetWorkingExperienceByCode", mock.Anything, 1).Return(&models.ProfileDTO{
+			WorkingExperience: "10 years of Go",
+		}, nil).Once()
+		educationRepository.Mock.On("GetEducationByProfileCode", mock.Anything, 1).Return([]*models.EducationDTO{{Id: 1, School: "ITB"}}, nil).Once()
+		employmentRepository.Mock.On("GetEmploymentByProfileCode", mock.Anything, 1).Return([]*models.EmploymentDTO{{Id: 2, Employer: "BPJS"}}, nil).Once()
+		skillRepository.Mock.On("GetSkillsByProfileCode", mock.Anything, 1).Return([]*models.SkillDTO{{Id: 3, Skill: "Golang"}}, nil).Once()
+		consentRepository.Mock.On("GetConsentsByProfileCode", mock.Anything, 1).Return([]*models.ConsentDTO{{Id: 4, Purpose: models.ConsentPurposeRecruiterAccess}}, nil).Once()
+		auditRepository.Mock.On("GetAuditLogsByProfileCode", mock.Anything, 1, 0, 0).Return([]*models.AuditLogDTO{{Id: 5, Action: models.AuditActionUpdate, Entity: models.AuditEntityProfile}}, 1, nil).Once()
+		resumeRepository.Mock.On("GetResumeVersions", mock.Anything, 1).Return([]*models.ResumeVersionDTO{{Version: 2}, {Version: 1}}, nil).Once()
+		resumeRepository.Mock.On("GetResumeVersion", mock.Anything, 1, 1).Return(&models.ResumeVersionDTO{Version: 1, Snapshot: &models.ResumeSnapshot{Profile: &models.ProfileDTO{FirstName: "Jon"}}}, nil).Once()
+		resumeRepository.Mock.On("GetResumeVersion", mock.Anything, 1, 2).Return(&models.ResumeVersionDTO{Version: 2, Snapshot: &models.ResumeSnapshot{Profile: &models.ProfileDTO{FirstName: "John"}}}, nil).Once()
+
+		export, err := privacyServiceTest.ExportProfileData(ownerCtx, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, "data-export-01JAB3Q8W0RM0ZTRBPN7C2Z1K4.zip", export.FileName)
+
+		files := readArchive(t, export.Content)
+		assert.ElementsMatch(t, []string{
+			"profile.json", "education.json", "employment.json", "skills.json", "consents.json", "audit.json", "resume_versions.json", "manifest.json",
+			"photos/4a567c8956410440a55b7544ebae3d72433f134bf40cabf4f462da50dbb17814.png",
+		}, keys(files))
+
+		var profile response.ProfileDataResponse
+		assert.Nil(t, json.Unmarshal(files["profile.json"], &profile))
+		assert.Equal(t, "01JAB3Q8W0RM0ZTRBPN7C2Z1K4", profile.PublicId)
+		assert.Equal(t, "john@example.com", profile.Email)
+		assert.Equal(t, "10 years of Go", profile.WorkingExperience)
+		assert.Contains(t, string(files["employment.json"]), "BPJS")
+		assert.Contains(t, string(files["consents.json"]), models.ConsentPurposeRecruiterAccess)
+		assert.Contains(t, string(files["audit.json"]), models.AuditEntityProfile)
+
+		var versions []response.ResumeVersionResponse
+		assert.Nil(t, json.Unmarshal(files["resume_versions.json"], &versions))
+		assert.Len(t, versions, 2)
+		assert.Equal(t, "Jon", versions[0].Resume.Profile.FirstName)
+
+		// the archive hides the same internal fields as the API does
+		for name, content := range files {
+			if strings.HasSuffix(name, ".json") {
+				for _, field := range []string{`"profileCode"`, `"ownerId"`, `"photoHash"`} {
+					assert.NotContains(t, string(content), field, name)
+				}
+			}
+		}
+
+		assert.Equal(t, []byte("png"), files["photos/4a567c8956410440a55b7544ebae3d72433f134bf40cabf4f462da50dbb17814.png"])
+	})
+	t.Run("FailedExportProfileData_Recruiter", func(t *testing.T) {
+		ctx := auth.WithClaims(context.Background(), &auth.Claims{UserId: 2, Role: auth.RoleRecruiter})
+		export, err := privacyServiceTest.ExportProfileData(ctx, 1)
+		assert.Nil(t, export)
+		assert.ErrorIs(t, err, authorizationService.ErrForbidden)
+	})
+	t.Run("FailedExportProfileData_ApiKeyWithoutExportScope", func(t *testing.T) {
+		ctx := auth.WithClaims(context.Background(), &auth.Claims{UserId: 1, Role: auth.RoleUser, ApiKeyId: 7, Scopes: []string{auth.ScopeProfileRead}})
+		export, err := privacyServiceTest.ExportProfileData(ctx, 1)
+		assert.Nil(t, export)
+		assert.ErrorIs(t, err, authorizationService.ErrForbidden)
+	})
+	t.Run("FailedExportProfileData_RepositoryError", func(t *testing.T) {
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 2).Return(nil, errors.New("connection refused")).Once()
+
+		export, err := privacyServiceTest.ExportProfileData(ownerCtx, 2)
+		assert.Nil(t, export)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestEraseProfile(t *testing.T) {
+	t.Run("SuccessEraseProfile", func(t *testing.T) {
+		// photos from before hashing was introduced belong to one profile only
+		photoRoot(t)
+		photoUrl := "public/image/erase-test.png"
+		assert.Nil(t, os.WriteFile(storage.ResolvePath(photoUrl), []byte("png"), 0644))
+
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 3).Return(&models.ProfileDTO{
+			ProfileCode: 3,
+			PublicId:    "01JAB3Q8W0RM0ZTRBPN7C2Z1K5",
+			PhotoUrl:    photoUrl,
+		}, nil).Once()
+		privacyRepository.Mock.On("EraseProfile", mock.Anything, 3, mock.AnythingOfType("*models.ErasureReceipt")).
+			Run(func(args mock.Arguments) {
+				receipt := args.Get(2).(*models.ErasureReceipt)
+				receipt.ProfileRows, receipt.SkillRows = 1, 4
+			}).
+			Return(nil).Once()
+
+		receipt, err := privacyServiceTest.EraseProfile(ownerCtx, request.EraseProfileRequest{
+			ProfileCode: 3,
+			Mode:        models.ErasureModeDelete,
+			Reason:      "subject request",
+		})
+		assert.Nil(t, err)
+		assert.NotEmpty(t, receipt.ReceiptId)
+		assert.Equal(t, "01JAB3Q8W0RM0ZTRBPN7C2Z1K5", receipt.ProfilePublicId)
+		assert.Equal(t, models.ErasureModeDelete, receipt.Mode)
+		assert.Equal(t, 1, receipt.RequestedBy)
+		assert.Equal(t, 1, receipt.ProfileRows)
+		assert.Equal(t, 4, receipt.SkillRows)
+		assert.True(t, receipt.PhotoRemoved)
+
+		_, err = os.Stat(storage.ResolvePath(photoUrl))
+		assert.True(t, os.IsNotExist(err))
+	})
+	t.Run("SuccessEraseProfile_Admin", func(t *testing.T) {
+		ctx := auth.WithClaims(context.Background(), &auth.Claims{UserId: 9, Role: auth.RoleAdmin})
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 4).Return(&models.ProfileDTO{ProfileCode: 4}, nil).Once()
+		privacyRepository.Mock.On("EraseProfile", mock.Anything, 4, mock.AnythingOfType("*models.ErasureReceipt")).Return(nil).Once()
+
+		receipt, err := privacyServiceTest.EraseProfile(ctx, request.EraseProfileRequest{ProfileCode: 4, Mode: models.ErasureModeAnonymise})
+		assert.Nil(t, err)
+		assert.Equal(t, 9, receipt.RequestedBy)
+		assert.False(t, receipt.PhotoRemoved)
+	})
+	t.Run("FailedEraseProfile", func(t *testing.T) {
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 5).Return(&models.ProfileDTO{ProfileCode: 5}, nil).Once()
+		privacyRepository.Mock.On("EraseProfile", mock.Anything, 5, mock.Anything).Return(errors.New("deadlock detected")).Once()
+
+		receipt, err := privacyServiceTest.EraseProfile(ownerCtx, request.EraseProfileRequest{ProfileCode: 5, Mode: models.ErasureModeDelete})
+		assert.Nil(t, receipt)
+		assert.Contains(t, err.Error(), "failed to erase profile:")
+	})
+}
+
+func readArchive(t *testing.T, content []byte) map[string][]byte {
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	assert.Nil(t, err)
+
+	files := map[string][]byte{}
+	for _, f := range archive.File {
+		r, err := f.Open()
+		assert.Nil(t, err)
+		files[f.Name], _ = io.ReadAll(r)
+		r.Close()
+	}
+	return files
+}
+
+func keys(files map[string][]byte) []string {
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	return names
+}
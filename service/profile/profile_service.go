@@ -3,41 +3,78 @@ package service
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"database/sql"
 	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"image"
 	"image/png"
 	"os"
 	"path/filepath"
 	"strings"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/helper/avatar"
+	"test-bpjs/v2/helper/cursor"
+	"test-bpjs/v2/helper/storage"
 	transform "test-bpjs/v2/helper/transform"
 	"test-bpjs/v2/models"
 	"test-bpjs/v2/models/request"
 	"test-bpjs/v2/models/response"
 	"test-bpjs/v2/repository"
-	"time"
+	auditService "test-bpjs/v2/service/audit"
+	authorizationService "test-bpjs/v2/service/authorization"
+	resumeService "test-bpjs/v2/service/resume"
+
+	"github.com/oklog/ulid/v2"
+	log "github.com/sirupsen/logrus"
 )
 
 type ProfileService interface {
 	GetProfileByCode(ctx context.Context, code int) (*response.CreateProfileResponse, error)                //v
 	GetWorkingExperienceByCode(ctx context.Context, code int) (*response.WorkingExperiencesResponse, error) //v
+	ListProfiles(ctx context.Context, payload request.ListProfilesRequest) (*response.ProfileList, error)
 	CreateProfile(ctx context.Context, payload request.CreateProfileRequest) (*response.DefaultResponse, error)
 	UpdateProfile(ctx context.Context, payload request.UpdateProfileRequest) (*response.DefaultResponse, error)
-	DeletePhotoByCode(ctx context.Context, code int) (*response.DefaultResponse, error)
+	PatchProfile(ctx context.Context, payload request.PatchProfileRequest) (*response.DefaultResponse, error)
+	DeleteProfile(ctx context.Context, code, version int) (*response.DefaultResponse, error)
+	RestoreProfile(ctx context.Context, code int) (*response.DefaultResponse, error)
+	DeletePhotoByCode(ctx context.Context, code, version int) (*response.DefaultResponse, error)
 	UploadPhotoByCode(ctx context.Context, payload request.UploadPhotoRequest) (*response.UploadPhotoResponse, error)
 	DownloadPhotoByCode(ctx context.Context, code int) (string, error)
+	DownloadAvatarByCode(ctx context.Context, code int, format string) (string, error)
+	ResolvePublicId(ctx context.Context, publicId string) (int, error)
 }
 
+var (
+	ErrProfileNotFound = errors.New("profile not found")
+	ErrProfileModified = errors.New("profile was changed by another request")
+	ErrInvalidCursor   = errors.New("cursor is malformed or belongs to another sort order")
+)
+
+const defaultPageSize = 20
+
 type profileService struct {
 	profileRepo repository.ProfileRepository
+	authorizer  authorizationService.Authorizer
+	auditor     auditService.AuditService
+	versioner   resumeService.Versioner
 }
 
-func NewProfileService(profileRepo repository.ProfileRepository) *profileService {
-	return &profileService{profileRepo: profileRepo}
+func NewProfileService(profileRepo repository.ProfileRepository, authorizer authorizationService.Authorizer, auditor auditService.AuditService, versioner resumeService.Versioner) *profileService {
+	return &profileService{profileRepo: profileRepo, authorizer: authorizer, auditor: auditor, versioner: versioner}
 }
 
 func (p *profileService) GetProfileByCode(ctx context.Context, code int) (*response.CreateProfileResponse, error) {
+	if err := p.authorizer.CanReadProfile(ctx, code); err != nil {
+		return nil, err
+	}
+
 	profile, err := p.profileRepo.GetProfileByCode(ctx, code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrProfileNotFound
+	}
 	if err != nil {
 		errString := fmt.Errorf("failed to get profile: %v", err)
 		return nil, errString
@@ -45,18 +82,107 @@ func (p *profileService) GetProfileByCode(ctx context.Context, code int) (*respo
 	return transform.TransformProfile(profile), err
 }
 
+// ListProfiles returns a page of the profiles the caller may read, newest
+// first unless asked otherwise.
+func (p *profileService) ListProfiles(ctx context.Context, payload request.ListProfilesRequest) (*response.ProfileList, error) {
+	visibility, err := p.authorizer.ProfileVisibility(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := payload.Limit
+	if limit == 0 {
+		limit = defaultPageSize
+	}
+	filter := &models.ProfileFilter{
+		Country:        payload.Country,
+		City:           payload.City,
+		Nationality:    payload.Nationality,
+		WantedJobTitle: payload.WantedJobTitle,
+		Skill:          payload.Skill,
+		SkillLevel:     payload.SkillLevel,
+		Visibility:     *visibility,
+		Sort:           payload.Sort,
+		Descending:     payload.Order != "asc",
+		// one more than asked for, to know whether there is a next page
+		Limit: limit + 1,
+	}
+	if filter.Sort == "" {
+		filter.Sort = "created_at"
+	}
+	if payload.Cursor != "" {
+		var after models.ProfileCursor
+		if err := cursor.Decode(payload.Cursor, &after); err != nil {
+			return nil, ErrInvalidCursor
+		}
+		if after.Sort != filter.Sort || after.Descending != filter.Descending {
+			return nil, ErrInvalidCursor
+		}
+		filter.After = &after
+	}
+
+	profiles, err := p.profileRepo.ListProfiles(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list profiles: %v", err)
+	}
+
+	result := &response.ProfileList{Data: []*response.ProfileSummaryResponse{}}
+	if len(profiles) > limit {
+		profiles = profiles[:limit]
+		last := profiles[limit-1]
+		next := models.ProfileCursor{Sort: filter.Sort, Descending: filter.Descending, At: last.CreatedAt, PublicId: last.PublicId}
+		if filter.Sort == "updated_at" {
+			next.At = last.UpdatedAt
+		}
+		result.NextCursor, err = cursor.Encode(next)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode cursor: %v", err)
+		}
+	}
+	for _, profile := range profiles {
+		result.Data = append(result.Data, transform.TransformProfileSummary(profile))
+	}
+	return result, nil
+}
+
+// ResolvePublicId maps the public id used in URLs to the internal profile code.
+func (p *profileService) ResolvePublicId(ctx context.Context, publicId string) (int, error) {
+	code, err := p.profileRepo.GetProfileCodeByPublicId(ctx, publicId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrProfileNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve profile: %v", err)
+	}
+	return code, nil
+}
+
 func (p *profileService) GetWorkingExperienceByCode(ctx context.Context, code int) (*response.WorkingExperiencesResponse, error) {
+	if err := p.authorizer.CanReadProfile(ctx, code); err != nil {
+		return nil, err
+	}
+
 	workingExperiences, err := p.profileRepo.GetWorkingExperienceByCode(ctx, code)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get working experience: %v", err)
 	}
 	return &response.WorkingExperiencesResponse{
 		WorkingExperience: workingExperiences.WorkingExperience,
+		Version:           workingExperiences.Version,
 	}, nil
 }
 
 func (p *profileService) CreateProfile(ctx context.Context, payload request.CreateProfileRequest) (*response.DefaultResponse, error) {
+	user, err := p.authorizer.CurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !user.HasScope(auth.ScopeProfileWrite) {
+		return nil, authorizationService.ErrForbidden
+	}
+
 	profile, err := p.profileRepo.CreateProfile(ctx, &models.Profile{
+		PublicId:       ulid.Make().String(),
 		WantedJobTitle: payload.WantedJobTitle,
 		FirstName:      payload.FirstName,
 		LastName:       payload.LastName,
@@ -70,17 +196,54 @@ func (p *profileService) CreateProfile(ctx context.Context, payload request.Crea
 		Nationality:    payload.Nationality,
 		PlaceOfBirth:   payload.PlaceOfBirth,
 		DateOfBirth:    payload.DateOfBirth,
+		OwnerId:        user.UserId,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create profile: %v", err)
 	}
+	p.auditor.Record(ctx, auditService.Entry{
+		ProfileCode: profile.ProfileCode,
+		Action:      models.AuditActionCreate,
+		Entity:      models.AuditEntityProfile,
+		EntityId:    profile.ProfileCode,
+		After: auditedProfile(&models.ProfileDTO{
+			PublicId:       profile.PublicId,
+			WantedJobTitle: payload.WantedJobTitle,
+			FirstName:      payload.FirstName,
+			LastName:       payload.LastName,
+			Email:          payload.Email,
+			Phone:          payload.Phone,
+			Country:        payload.Country,
+			City:           payload.City,
+			Address:        payload.Address,
+			PostalCode:     payload.PostalCode,
+			DrivingLicense: payload.DrivingLicense,
+			Nationality:    payload.Nationality,
+			PlaceOfBirth:   payload.PlaceOfBirth,
+			DateOfBirth:    payload.DateOfBirth,
+		}),
+	})
+	p.versioner.Snapshot(ctx, profile.ProfileCode)
 	return &response.DefaultResponse{
 		ProfileCode: profile.ProfileCode,
+		PublicId:    profile.PublicId,
 	}, nil
 }
 
 func (p *profileService) UpdateProfile(ctx context.Context, payload request.UpdateProfileRequest) (*response.DefaultResponse, error) {
-	profile, err := p.profileRepo.UpdateProfile(ctx, payload.ProfileCode, &models.Profile{
+	if err := p.authorizer.CanWriteProfile(ctx, payload.ProfileCode); err != nil {
+		return nil, err
+	}
+
+	before, err := p.snapshot(ctx, payload.ProfileCode)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrProfileNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update profile: %v", err)
+	}
+
+	profile, err := p.profileRepo.UpdateProfile(ctx, payload.ProfileCode, payload.Version, &models.Profile{
 		WantedJobTitle:    payload.WantedJobTitle,
 		FirstName:         payload.FirstName,
 		LastName:          payload.LastName,
@@ -96,28 +259,221 @@ func (p *profileService) UpdateProfile(ctx context.Context, payload request.Upda
 		DateOfBirth:       payload.DateOfBirth,
 		WorkingExperience: payload.WorkingExperience,
 	})
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrProfileNotFound
+	}
+	if errors.Is(err, repository.ErrVersionMismatch) {
+		return nil, ErrProfileModified
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to update profile: %v", err)
 	}
+
+	// the update skips empty fields, so the new state is read back rather
+	// than derived from the payload
+	after, err := p.snapshot(ctx, payload.ProfileCode)
+	if err != nil {
+		log.WithContext(ctx).Errorf("failed to audit update of profile %d: %v", payload.ProfileCode, err)
+	} else {
+		p.auditor.Record(ctx, auditService.Entry{
+			ProfileCode: payload.ProfileCode,
+			Action:      models.AuditActionUpdate,
+			Entity:      models.AuditEntityProfile,
+			EntityId:    payload.ProfileCode,
+			Before:      auditedProfile(before),
+			After:       auditedProfile(after),
+		})
+	}
+	p.versioner.Snapshot(ctx, payload.ProfileCode)
+	return &response.DefaultResponse{
+		ProfileCode: profile.ProfileCode,
+		Version:     profile.Version,
+	}, nil
+}
+
+// PatchProfile applies a JSON Merge Patch to a profile. Unlike
+// UpdateProfile it writes empty values too, so fields can be cleared.
+func (p *profileService) PatchProfile(ctx context.Context, payload request.PatchProfileRequest) (*response.DefaultResponse, error) {
+	if err := p.authorizer.CanWriteProfile(ctx, payload.ProfileCode); err != nil {
+		return nil, err
+	}
+
+	before, err := p.snapshot(ctx, payload.ProfileCode)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrProfileNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch profile: %v", err)
+	}
+
+	profile := &models.Profile{}
+	var columns []string
+	set := func(column string, ok bool) {
+		if ok {
+			columns = append(columns, column)
+		}
+	}
+	set("wanted_job_title", payload.WantedJobTitle.Apply(&profile.WantedJobTitle))
+	set("first_name", payload.FirstName.Apply(&profile.FirstName))
+	set("last_name", payload.LastName.Apply(&profile.LastName))
+	set("email", payload.Email.Apply(&profile.Email))
+	set("phone", payload.Phone.Apply(&profile.Phone))
+	set("country", payload.Country.Apply(&profile.Country))
+	set("city", payload.City.Apply(&profile.City))
+	set("address", payload.Address.Apply(&profile.Address))
+	set("postal_code", payload.PostalCode.Apply(&profile.PostalCode))
+	set("driving_license", payload.DrivingLicense.Apply(&profile.DrivingLicense))
+	set("nationality", payload.Nationality.Apply(&profile.Nationality))
+	set("place_of_birth", payload.PlaceOfBirth.Apply(&profile.PlaceOfBirth))
+	set("date_of_birth", payload.DateOfBirth.Apply(&profile.DateOfBirth))
+	set("working_experience", payload.WorkingExperience.Apply(&profile.WorkingExperience))
+
+	patched, err := p.profileRepo.PatchProfile(ctx, payload.ProfileCode, payload.Version, profile, columns)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrProfileNotFound
+	}
+	if errors.Is(err, repository.ErrVersionMismatch) {
+		return nil, ErrProfileModified
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch profile: %v", err)
+	}
+
+	after, err := p.snapshot(ctx, payload.ProfileCode)
+	if err != nil {
+		log.WithContext(ctx).Errorf("failed to audit patch of profile %d: %v", payload.ProfileCode, err)
+	} else {
+		p.auditor.Record(ctx, auditService.Entry{
+			ProfileCode: payload.ProfileCode,
+			Action:      models.AuditActionUpdate,
+			Entity:      models.AuditEntityProfile,
+			EntityId:    payload.ProfileCode,
+			Before:      auditedProfile(before),
+			After:       auditedProfile(after),
+		})
+	}
+	p.versioner.Snapshot(ctx, payload.ProfileCode)
+	return &response.DefaultResponse{
+		ProfileCode: patched.ProfileCode,
+		Version:     patched.Version,
+	}, nil
+}
+
+// DeleteProfile moves the profile to the trash, from where its owner can
+// restore it until the trash is purged.
+func (p *profileService) DeleteProfile(ctx context.Context, code, version int) (*response.DefaultResponse, error) {
+	if err := p.authorizer.CanWriteProfile(ctx, code); err != nil {
+		return nil, err
+	}
+
+	before, err := p.snapshot(ctx, code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrProfileNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete profile: %v", err)
+	}
+
+	profile, err := p.profileRepo.DeleteProfile(ctx, code, version)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrProfileNotFound
+	}
+	if errors.Is(err, repository.ErrVersionMismatch) {
+		return nil, ErrProfileModified
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete profile: %v", err)
+	}
+	p.auditor.Record(ctx, auditService.Entry{
+		ProfileCode: code,
+		Action:      models.AuditActionDelete,
+		Entity:      models.AuditEntityProfile,
+		EntityId:    code,
+		Before:      auditedProfile(before),
+	})
 	return &response.DefaultResponse{
 		ProfileCode: profile.ProfileCode,
+		PublicId:    profile.PublicId,
 	}, nil
 }
 
-func (p *profileService) DeletePhotoByCode(ctx context.Context, code int) (*response.DefaultResponse, error) {
-	profileCode, err := p.profileRepo.DeletePhotoByCode(ctx, code)
+func (p *profileService) RestoreProfile(ctx context.Context, code int) (*response.DefaultResponse, error) {
+	if err := p.authorizer.CanWriteProfile(ctx, code); err != nil {
+		return nil, err
+	}
+
+	profile, err := p.profileRepo.RestoreProfile(ctx, code)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrProfileNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore profile: %v", err)
+	}
+
+	after, err := p.snapshot(ctx, code)
+	if err != nil {
+		log.WithContext(ctx).Errorf("failed to audit restore of profile %d: %v", code, err)
+	} else {
+		p.auditor.Record(ctx, auditService.Entry{
+			ProfileCode: code,
+			Action:      models.AuditActionRestore,
+			Entity:      models.AuditEntityProfile,
+			EntityId:    code,
+			After:       auditedProfile(after),
+		})
+	}
+	p.versioner.Snapshot(ctx, code)
+	return &response.DefaultResponse{
+		ProfileCode: profile.ProfileCode,
+		PublicId:    profile.PublicId,
+	}, nil
+}
+
+func (p *profileService) DeletePhotoByCode(ctx context.Context, code, version int) (*response.DefaultResponse, error) {
+	if err := p.authorizer.CanWriteProfile(ctx, code); err != nil {
+		return nil, err
+	}
+
+	profile, err := p.profileRepo.GetProfileByCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete photo: %v", err)
+	}
+
+	profileCode, err := p.profileRepo.DeletePhotoByCode(ctx, code, version)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrProfileNotFound
+	}
+	if errors.Is(err, repository.ErrVersionMismatch) {
+		return nil, ErrProfileModified
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to delete photo: %v", err)
 	}
+
+	after := *profile
+	after.PhotoUrl, after.PhotoHash = "", ""
+	p.auditor.Record(ctx, auditService.Entry{
+		ProfileCode: code,
+		Action:      models.AuditActionUpdate,
+		Entity:      models.AuditEntityProfile,
+		EntityId:    code,
+		Before:      auditedProfile(profile),
+		After:       auditedProfile(&after),
+	})
+
+	if _, err := storage.ReleasePhoto(ctx, p.profileRepo, profile.PhotoHash, profile.PhotoUrl); err != nil {
+		return nil, fmt.Errorf("failed to delete photo: %v", err)
+	}
+
 	return &response.DefaultResponse{
 		ProfileCode: profileCode.ProfileCode,
 	}, nil
 }
 
 func (p *profileService) UploadPhotoByCode(ctx context.Context, payload request.UploadPhotoRequest) (*response.UploadPhotoResponse, error) {
-	imgPath := filepath.Join("public/image", fmt.Sprintf("%d-%d.png", payload.ProfileCode, time.Now().Unix()))
-	imgFullPath := filepath.Join("../../", imgPath)
-	imgFullPath2 := filepath.Join("../", imgPath)
+	if err := p.authorizer.CanWriteProfile(ctx, payload.ProfileCode); err != nil {
+		return nil, err
+	}
 
 	b64data := payload.Base64Img[strings.IndexByte(payload.Base64Img, ',')+1:]
 	imgData, err := base64.StdEncoding.DecodeString(b64data)
@@ -132,31 +488,64 @@ func (p *profileService) UploadPhotoByCode(ctx context.Context, payload request.
 		return nil, fmt.Errorf("failed to decode image: %v", err)
 	}
 
-	outFile, err := os.Create(imgFullPath)
+	var buf bytes.Buffer
+	err = png.Encode(&buf, img)
 	if err != nil {
-		outFile, err = os.Create(imgFullPath2)
-		if err != nil {
-			outFile, err = os.Create(imgPath)
-			if err != nil {
-				return nil, fmt.Errorf("failed creating output file: %v", err)
-			}
-		}
+		return nil, fmt.Errorf("failed to encode image to file: %v", err)
 	}
 
-	defer outFile.Close()
+	// photos are stored under the hash of the processed png, so the same
+	// picture is only ever written once no matter how many profiles use it
+	sum := sha256.Sum256(buf.Bytes())
+	hash := hex.EncodeToString(sum[:])
+	imgPath := filepath.Join("public/image", hash+".png")
 
-	err = png.Encode(outFile, img)
+	current, err := p.profileRepo.GetProfileByCode(ctx, payload.ProfileCode)
 	if err != nil {
-		return nil, fmt.Errorf("failed to encode image to file: %v", err)
+		return nil, fmt.Errorf("failed to get profile: %v", err)
+	}
+
+	if current.PhotoHash == hash {
+		return &response.UploadPhotoResponse{
+			ProfileCode: payload.ProfileCode,
+			PhotoUrl:    current.PhotoUrl,
+		}, nil
 	}
 
-	profile, err := p.profileRepo.UpdateProfile(ctx, payload.ProfileCode, &models.Profile{
-		PhotoUrl: imgPath,
+	var profile *models.ProfileDTO
+	err = storage.StorePhoto(imgPath, buf.Bytes(), func() error {
+		var err error
+		profile, err = p.profileRepo.UpdateProfile(ctx, payload.ProfileCode, payload.Version, &models.Profile{
+			PhotoUrl:  imgPath,
+			PhotoHash: hash,
+		})
+		return err
 	})
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrProfileNotFound
+	}
+	if errors.Is(err, repository.ErrVersionMismatch) {
+		return nil, ErrProfileModified
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	after := *current
+	after.PhotoUrl, after.PhotoHash = imgPath, hash
+	p.auditor.Record(ctx, auditService.Entry{
+		ProfileCode: payload.ProfileCode,
+		Action:      models.AuditActionUpdate,
+		Entity:      models.AuditEntityProfile,
+		EntityId:    payload.ProfileCode,
+		Before:      auditedProfile(current),
+		After:       auditedProfile(&after),
+	})
+
+	if _, err := storage.ReleasePhoto(ctx, p.profileRepo, current.PhotoHash, current.PhotoUrl); err != nil {
+		return nil, err
+	}
+
 	return &response.UploadPhotoResponse{
 		ProfileCode: profile.ProfileCode,
 		PhotoUrl:    imgPath,
@@ -166,18 +555,26 @@ func (p *profileService) UploadPhotoByCode(ctx context.Context, payload request.
 func (p *profileService) DownloadPhotoByCode(ctx context.Context, code int) (string, error) {
 	var buf bytes.Buffer
 
+	if err := p.authorizer.CanReadProfile(ctx, code); err != nil {
+		return "", err
+	}
+
 	profile, err := p.profileRepo.GetProfileByCode(ctx, code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrProfileNotFound
+	}
 	if err != nil {
 		return "", err
 	}
 
-	imgFullPath := filepath.Join("../../", profile.PhotoUrl)
-	imgFile, err := os.Open(imgFullPath)
+	// profiles without an uploaded photo get a generated avatar instead
+	if profile.PhotoUrl == "" {
+		return renderAvatar(code, profile, "png")
+	}
+
+	imgFile, err := os.Open(storage.ResolvePath(profile.PhotoUrl))
 	if err != nil {
-		imgFile, err = os.Open(profile.PhotoUrl)
-		if err != nil {
-			return "", fmt.Errorf("failed to open image file: %v", err)
-		}
+		return "", fmt.Errorf("failed to open image file: %v", err)
 	}
 	defer imgFile.Close()
 
@@ -197,3 +594,79 @@ func (p *profileService) DownloadPhotoByCode(ctx context.Context, code int) (str
 
 	return res, nil
 }
+
+func (p *profileService) DownloadAvatarByCode(ctx context.Context, code int, format string) (string, error) {
+	if err := p.authorizer.CanReadProfile(ctx, code); err != nil {
+		return "", err
+	}
+
+	profile, err := p.profileRepo.GetProfileByCode(ctx, code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrProfileNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return renderAvatar(code, profile, format)
+}
+
+// snapshot reads the profile together with its working experience, which
+// GetProfileByCode leaves out.
+func (p *profileService) snapshot(ctx context.Context, code int) (*models.ProfileDTO, error) {
+	profile, err := p.profileRepo.GetProfileByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	workingExperience, err := p.profileRepo.GetWorkingExperienceByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	profile.WorkingExperience = workingExperience.WorkingExperience
+	return profile, nil
+}
+
+// auditedProfile leaves the internal codes out of the audit log, which the
+// owner reads through the API.
+func auditedProfile(profile *models.ProfileDTO) interface{} {
+	if profile == nil {
+		return nil
+	}
+	return &models.AuditedProfile{
+		PublicId:          profile.PublicId,
+		WantedJobTitle:    profile.WantedJobTitle,
+		FirstName:         profile.FirstName,
+		LastName:          profile.LastName,
+		Email:             profile.Email,
+		Phone:             profile.Phone,
+		Country:           profile.Country,
+		City:              profile.City,
+		Address:           profile.Address,
+		PostalCode:        profile.PostalCode,
+		DrivingLicense:    profile.DrivingLicense,
+		Nationality:       profile.Nationality,
+		PlaceOfBirth:      profile.PlaceOfBirth,
+		DateOfBirth:       profile.DateOfBirth,
+		PhotoUrl:          profile.PhotoUrl,
+		WorkingExperience: profile.WorkingExperience,
+	}
+}
+
+func renderAvatar(code int, profile *models.ProfileDTO, format string) (string, error) {
+	initials := avatar.Initials(profile.FirstName, profile.LastName)
+	background := avatar.Color(code)
+
+	switch format {
+	case "", "png":
+		img, err := avatar.RenderPNG(initials, background, avatar.DefaultSize)
+		if err != nil {
+			return "", fmt.Errorf("failed to render avatar: %v", err)
+		}
+		return fmt.Sprintf("data:image/png;base64,%s", base64.StdEncoding.EncodeToString(img)), nil
+	case "svg":
+		img := avatar.RenderSVG(initials, background, avatar.DefaultSize)
+		return fmt.Sprintf("data:image/svg+xml;base64,%s", base64.StdEncoding.EncodeToString(img)), nil
+	default:
+		return "", fmt.Errorf("unsupported avatar format: %s", format)
+	}
+}
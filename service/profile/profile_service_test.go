@@ -2,11 +2,22 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
-	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/helper/storage"
 	"test-bpjs/v2/models"
 	"test-bpjs/v2/models/request"
+	dataRepository "test-bpjs/v2/repository"
 	repository "test-bpjs/v2/repository/mocks"
+	auditService "test-bpjs/v2/service/audit"
+	authorizationService "test-bpjs/v2/service/authorization"
 	"testing"
 	"time"
 
@@ -15,11 +26,31 @@ import (
 )
 
 var profileRepository = &repository.ProfileRepository{Mock: mock.Mock{}}
-var profileServiceTest = profileService{profileRepo: profileRepository}
+var consentRepository = &repository.ConsentRepository{Mock: mock.Mock{}}
+var authorizer = authorizationService.NewAuthorizer(profileRepository, consentRepository, "")
+var auditRepository = &repository.AuditRepository{Mock: mock.Mock{}}
+var auditor = auditService.NewAuditService(auditRepository, authorizer)
+var versioner = &versionRecorder{}
+var profileServiceTest = profileService{profileRepo: profileRepository, authorizer: authorizer, auditor: auditor, versioner: versioner}
+
+// the tests run as user 1, who owns every profile
+var ownerCtx = auth.WithClaims(context.Background(), &auth.Claims{UserId: 1, Role: auth.RoleUser})
+var _ = profileRepository.Mock.On("GetProfileOwner", mock.Anything, mock.Anything).Return(1, nil)
+var _ = auditRepository.Mock.On("CreateAuditLog", mock.Anything, mock.Anything).Return(nil)
+
+// versionRecorder stands in for the resume service and remembers which
+// profiles were versioned
+type versionRecorder struct {
+	codes []int
+}
+
+func (v *versionRecorder) Snapshot(ctx context.Context, code int) {
+	v.codes = append(v.codes, code)
+}
 
 func TestInitProfileService(t *testing.T) {
 	t.Run("SuccessInitSProfileService", func(t *testing.T) {
-		assert.NotNil(t, NewProfileService(profileRepository))
+		assert.NotNil(t, NewProfileService(profileRepository, authorizer, auditor, versioner))
 	})
 }
 
@@ -30,7 +61,7 @@ func TestGetProfile(t *testing.T) {
 		}
 		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 2).Return(result, nil)
 
-		profile, err := profileServiceTest.GetProfileByCode(context.Background(), 2)
+		profile, err := profileServiceTest.GetProfileByCode(ownerCtx, 2)
 		assert.Nil(t, err)
 		assert.NotNil(t, profile)
 		assert.Equal(t, result.ProfileCode, profile.ProfileCode)
@@ -39,12 +70,58 @@ func TestGetProfile(t *testing.T) {
 		// program mock
 		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 1).Return(nil, errors.New("sql: no rows in result set"))
 
-		profile, err := profileServiceTest.GetProfileByCode(context.Background(), 1)
+		profile, err := profileServiceTest.GetProfileByCode(ownerCtx, 1)
 		assert.Nil(t, profile)
 		assert.NotNil(t, err)
 		// assert.Equal(t, "failed to get profile: sql: no rows in result set", err.Error())
 		assert.Contains(t, err.Error(), "failed to get profile:")
 	})
+	t.Run("FailedGetProfile_NotFound", func(t *testing.T) {
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 958).Return(nil, sql.ErrNoRows).Once()
+
+		profile, err := profileServiceTest.GetProfileByCode(ownerCtx, 958)
+		assert.Nil(t, profile)
+		assert.ErrorIs(t, err, ErrProfileNotFound)
+	})
+}
+
+func TestProfileAuthorization(t *testing.T) {
+	otherUserCtx := auth.WithClaims(context.Background(), &auth.Claims{UserId: 2, Role: auth.RoleUser})
+	recruiterCtx := auth.WithClaims(context.Background(), &auth.Claims{UserId: 3, Role: auth.RoleRecruiter})
+	profileRepository.Mock.On("GetProfileByCode", mock.Anything, 60).Return(&models.ProfileDTO{ProfileCode: 60}, nil)
+	consentRepository.Mock.On("HasActiveConsent", mock.Anything, 60, models.ConsentPurposeRecruiterAccess, "").Return(true, nil)
+	consentRepository.Mock.On("HasActiveConsent", mock.Anything, 61, models.ConsentPurposeRecruiterAccess, "").Return(false, nil)
+
+	t.Run("FailedGetProfile_Unauthenticated", func(t *testing.T) {
+		profile, err := profileServiceTest.GetProfileByCode(context.Background(), 60)
+		assert.Nil(t, profile)
+		assert.ErrorIs(t, err, authorizationService.ErrUnauthenticated)
+	})
+	t.Run("FailedGetProfile_NotOwner", func(t *testing.T) {
+		profile, err := profileServiceTest.GetProfileByCode(otherUserCtx, 60)
+		assert.Nil(t, profile)
+		assert.ErrorIs(t, err, authorizationService.ErrForbidden)
+	})
+	t.Run("SuccessGetProfile_Recruiter", func(t *testing.T) {
+		profile, err := profileServiceTest.GetProfileByCode(recruiterCtx, 60)
+		assert.Nil(t, err)
+		assert.Equal(t, 60, profile.ProfileCode)
+	})
+	t.Run("FailedGetProfile_RecruiterWithoutConsent", func(t *testing.T) {
+		profile, err := profileServiceTest.GetProfileByCode(recruiterCtx, 61)
+		assert.Nil(t, profile)
+		assert.ErrorIs(t, err, authorizationService.ErrConsentRequired)
+	})
+	t.Run("FailedUpdateProfile_Recruiter", func(t *testing.T) {
+		profile, err := profileServiceTest.UpdateProfile(recruiterCtx, request.UpdateProfileRequest{ProfileCode: 60, Version: 1, FirstName: "test"})
+		assert.Nil(t, profile)
+		assert.ErrorIs(t, err, authorizationService.ErrForbidden)
+	})
+	t.Run("FailedCreateProfile_Unauthenticated", func(t *testing.T) {
+		profile, err := profileServiceTest.CreateProfile(context.Background(), request.CreateProfileRequest{FirstName: "test"})
+		assert.Nil(t, profile)
+		assert.ErrorIs(t, err, authorizationService.ErrUnauthenticated)
+	})
 }
 
 func TestGetWorkingExperiences(t *testing.T) {
@@ -54,7 +131,7 @@ func TestGetWorkingExperiences(t *testing.T) {
 		}
 		profileRepository.Mock.On("GetWorkingExperienceByCode", mock.Anything, 2).Return(workingExperience, nil)
 
-		result, err := profileServiceTest.GetWorkingExperienceByCode(context.Background(), 2)
+		result, err := profileServiceTest.GetWorkingExperienceByCode(ownerCtx, 2)
 		assert.Nil(t, err)
 		assert.NotNil(t, result)
 		assert.Equal(t, workingExperience.WorkingExperience, result.WorkingExperience)
@@ -63,7 +140,7 @@ func TestGetWorkingExperiences(t *testing.T) {
 		// program mock
 		profileRepository.Mock.On("GetWorkingExperienceByCode", mock.Anything, 1).Return(nil, errors.New("sql: no rows in result set"))
 
-		profile, err := profileServiceTest.GetWorkingExperienceByCode(context.Background(), 1)
+		profile, err := profileServiceTest.GetWorkingExperienceByCode(ownerCtx, 1)
 		assert.Nil(t, profile)
 		assert.NotNil(t, err)
 		assert.Contains(t, err.Error(), "failed to get working experience:")
@@ -76,16 +153,17 @@ func TestCreateProfile(t *testing.T) {
 			ProfileCode: 123456,
 		}
 		profileRepository.Mock.On("CreateProfile", mock.Anything,
-			&models.Profile{
+			anyPublicId(&models.Profile{
 				WantedJobTitle: "test",
 				FirstName:      "test",
 				Email:          "test",
 				Phone:          "0888888888",
 				Country:        "test",
 				City:           "test",
-				Address:        "test"}).Return(profile, nil)
+				Address:        "test",
+				OwnerId:        1})).Return(profile, nil)
 
-		result, err := profileServiceTest.CreateProfile(context.Background(),
+		result, err := profileServiceTest.CreateProfile(ownerCtx,
 			request.CreateProfileRequest{
 				WantedJobTitle: "test",
 				FirstName:      "test",
@@ -98,12 +176,18 @@ func TestCreateProfile(t *testing.T) {
 		assert.Nil(t, err)
 		assert.NotNil(t, result)
 		assert.Equal(t, profile.ProfileCode, result.ProfileCode)
+		// the owner reads the audit log, which must not show internal codes
+		auditRepository.Mock.AssertCalled(t, "CreateAuditLog", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+			return entry.ProfileCode == 123456 && entry.Action == models.AuditActionCreate &&
+				strings.Contains(entry.Changes, `"firstName"`) &&
+				!strings.Contains(entry.Changes, `"profileCode"`) && !strings.Contains(entry.Changes, `"ownerId"`)
+		}))
 	})
 	t.Run("FailedCreateProfile", func(t *testing.T) {
 		// program mock
-		profileRepository.Mock.On("CreateProfile", mock.Anything, &models.Profile{}).Return(nil, errors.New("NOT NULL VIOLATION"))
+		profileRepository.Mock.On("CreateProfile", mock.Anything, anyPublicId(&models.Profile{OwnerId: 1})).Return(nil, errors.New("NOT NULL VIOLATION"))
 
-		profile, err := profileServiceTest.CreateProfile(context.Background(), request.CreateProfileRequest{})
+		profile, err := profileServiceTest.CreateProfile(ownerCtx, request.CreateProfileRequest{})
 		assert.Nil(t, profile)
 		assert.NotNil(t, err)
 		assert.Contains(t, err.Error(), "failed to create profile:")
@@ -115,7 +199,10 @@ func TestUpdateProfile(t *testing.T) {
 		profile := &models.ProfileDTO{
 			ProfileCode: 3,
 		}
-		profileRepository.Mock.On("UpdateProfile", context.Background(), 3,
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 3).Return(&models.ProfileDTO{ProfileCode: 3, FirstName: "old"}, nil).Once()
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 3).Return(&models.ProfileDTO{ProfileCode: 3, FirstName: "test"}, nil).Once()
+		profileRepository.Mock.On("GetWorkingExperienceByCode", mock.Anything, 3).Return(&models.ProfileDTO{}, nil).Twice()
+		profileRepository.Mock.On("UpdateProfile", ownerCtx, 3, 1,
 			&models.Profile{
 				WantedJobTitle: "test",
 				FirstName:      "test",
@@ -125,9 +212,10 @@ func TestUpdateProfile(t *testing.T) {
 				City:           "test",
 				Address:        "test"}).Return(profile, nil)
 
-		result, err := profileServiceTest.UpdateProfile(context.Background(),
+		result, err := profileServiceTest.UpdateProfile(ownerCtx,
 			request.UpdateProfileRequest{
 				ProfileCode:    3,
+				Version:        1,
 				WantedJobTitle: "test",
 				FirstName:      "test",
 				Email:          "test",
@@ -139,32 +227,184 @@ func TestUpdateProfile(t *testing.T) {
 		assert.Nil(t, err)
 		assert.NotNil(t, result)
 		assert.Equal(t, profile.ProfileCode, result.ProfileCode)
+		auditRepository.Mock.AssertCalled(t, "CreateAuditLog", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+			return entry.Action == models.AuditActionUpdate && entry.EntityId == 3 && entry.ActorId == 1 &&
+				entry.Changes == `{"firstName":{"before":"old","after":"test"}}`
+		}))
 	})
 	t.Run("FailedUpdateProfile", func(t *testing.T) {
 		// program mock
-		profileRepository.Mock.On("UpdateProfile", mock.Anything, 2, &models.Profile{}).Return(nil, errors.New("NOT NULL VIOLATION"))
+		profileRepository.Mock.On("UpdateProfile", mock.Anything, 2, 1, &models.Profile{}).Return(nil, errors.New("NOT NULL VIOLATION"))
 
-		profile, err := profileServiceTest.UpdateProfile(context.Background(), request.UpdateProfileRequest{ProfileCode: 2})
+		profile, err := profileServiceTest.UpdateProfile(ownerCtx, request.UpdateProfileRequest{ProfileCode: 2, Version: 1})
 		assert.Nil(t, profile)
 		assert.NotNil(t, err)
 		assert.Contains(t, err.Error(), "failed to update profile:")
 	})
+	t.Run("FailedUpdateProfile_NotFound", func(t *testing.T) {
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 74).Return(&models.ProfileDTO{ProfileCode: 74}, nil).Once()
+		profileRepository.Mock.On("GetWorkingExperienceByCode", mock.Anything, 74).Return(&models.ProfileDTO{}, nil).Once()
+		profileRepository.Mock.On("UpdateProfile", mock.Anything, 74, 1, mock.Anything).Return(&models.ProfileDTO{}, dataRepository.ErrNotFound).Once()
+
+		profile, err := profileServiceTest.UpdateProfile(ownerCtx, request.UpdateProfileRequest{ProfileCode: 74, Version: 1, FirstName: "test"})
+		assert.Nil(t, profile)
+		assert.ErrorIs(t, err, ErrProfileNotFound)
+	})
+	t.Run("FailedUpdateProfile_Modified", func(t *testing.T) {
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 75).Return(&models.ProfileDTO{ProfileCode: 75}, nil).Once()
+		profileRepository.Mock.On("GetWorkingExperienceByCode", mock.Anything, 75).Return(&models.ProfileDTO{}, nil).Once()
+		profileRepository.Mock.On("UpdateProfile", mock.Anything, 75, 4, mock.Anything).Return(&models.ProfileDTO{}, dataRepository.ErrVersionMismatch).Once()
+
+		profile, err := profileServiceTest.UpdateProfile(ownerCtx, request.UpdateProfileRequest{ProfileCode: 75, Version: 4, FirstName: "test"})
+		assert.Nil(t, profile)
+		assert.ErrorIs(t, err, ErrProfileModified)
+		auditRepository.Mock.AssertNotCalled(t, "CreateAuditLog", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+			return entry.EntityId == 75
+		}))
+	})
+}
+
+func TestPatchProfile(t *testing.T) {
+	t.Run("SuccessPatchProfile", func(t *testing.T) {
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 76).Return(&models.ProfileDTO{ProfileCode: 76, LastName: "Doe", PostalCode: 40115}, nil).Once()
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 76).Return(&models.ProfileDTO{ProfileCode: 76}, nil).Once()
+		profileRepository.Mock.On("GetWorkingExperienceByCode", mock.Anything, 76).Return(&models.ProfileDTO{}, nil).Twice()
+		profileRepository.Mock.On("PatchProfile", mock.Anything, 76, 2, &models.Profile{}, []string{"last_name", "postal_code"}).
+			Return(&models.ProfileDTO{ProfileCode: 76, Version: 3}, nil).Once()
+
+		var patch request.PatchProfileRequest
+		assert.Nil(t, json.Unmarshal([]byte(`{"lastName": null, "postalCode": 0}`), &patch))
+		patch.ProfileCode, patch.Version = 76, 2
+
+		result, err := profileServiceTest.PatchProfile(ownerCtx, patch)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, result.Version)
+		auditRepository.Mock.AssertCalled(t, "CreateAuditLog", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+			return entry.Action == models.AuditActionUpdate && entry.EntityId == 76 &&
+				entry.Changes == `{"lastName":{"before":"Doe","after":""},"postalCode":{"before":40115,"after":0}}`
+		}))
+	})
+	t.Run("FailedPatchProfile_Modified", func(t *testing.T) {
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 77).Return(&models.ProfileDTO{ProfileCode: 77}, nil).Once()
+		profileRepository.Mock.On("GetWorkingExperienceByCode", mock.Anything, 77).Return(&models.ProfileDTO{}, nil).Once()
+		profileRepository.Mock.On("PatchProfile", mock.Anything, 77, 1, mock.Anything, mock.Anything).Return(&models.ProfileDTO{}, dataRepository.ErrVersionMismatch).Once()
+
+		result, err := profileServiceTest.PatchProfile(ownerCtx, request.PatchProfileRequest{ProfileCode: 77, Version: 1})
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrProfileModified)
+	})
+}
+
+func TestDeleteProfile(t *testing.T) {
+	t.Run("SuccessDeleteProfile", func(t *testing.T) {
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 70).Return(&models.ProfileDTO{ProfileCode: 70, FirstName: "John"}, nil).Once()
+		profileRepository.Mock.On("GetWorkingExperienceByCode", mock.Anything, 70).Return(&models.ProfileDTO{}, nil).Once()
+		profileRepository.Mock.On("DeleteProfile", mock.Anything, 70, 1).Return(&models.ProfileDTO{ProfileCode: 70, PublicId: "01JAB3Q8W0RM0ZTRBPN7C2Z1K4"}, nil).Once()
+
+		result, err := profileServiceTest.DeleteProfile(ownerCtx, 70, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, "01JAB3Q8W0RM0ZTRBPN7C2Z1K4", result.PublicId)
+		auditRepository.Mock.AssertCalled(t, "CreateAuditLog", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+			return entry.Action == models.AuditActionDelete && entry.Entity == models.AuditEntityProfile && entry.EntityId == 70
+		}))
+	})
+	t.Run("FailedDeleteProfile", func(t *testing.T) {
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 71).Return(&models.ProfileDTO{ProfileCode: 71}, nil).Once()
+		profileRepository.Mock.On("GetWorkingExperienceByCode", mock.Anything, 71).Return(&models.ProfileDTO{}, nil).Once()
+		profileRepository.Mock.On("DeleteProfile", mock.Anything, 71, 1).Return(nil, errors.New("")).Once()
+
+		result, err := profileServiceTest.DeleteProfile(ownerCtx, 71, 1)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "failed to delete profile")
+	})
+	t.Run("FailedDeleteProfile_NotFound", func(t *testing.T) {
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 73).Return(&models.ProfileDTO{}, sql.ErrNoRows).Once()
+
+		result, err := profileServiceTest.DeleteProfile(ownerCtx, 73, 1)
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrProfileNotFound)
+		profileRepository.Mock.AssertNotCalled(t, "DeleteProfile", mock.Anything, 73)
+	})
+}
+
+func TestRestoreProfile(t *testing.T) {
+	t.Run("SuccessRestoreProfile", func(t *testing.T) {
+		versioner.codes = nil
+		profileRepository.Mock.On("RestoreProfile", mock.Anything, 72).Return(&models.ProfileDTO{ProfileCode: 72}, nil).Once()
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 72).Return(&models.ProfileDTO{ProfileCode: 72, FirstName: "John"}, nil).Once()
+		profileRepository.Mock.On("GetWorkingExperienceByCode", mock.Anything, 72).Return(&models.ProfileDTO{}, nil).Once()
+
+		result, err := profileServiceTest.RestoreProfile(ownerCtx, 72)
+		assert.Nil(t, err)
+		assert.Equal(t, 72, result.ProfileCode)
+		assert.Equal(t, []int{72}, versioner.codes)
+		auditRepository.Mock.AssertCalled(t, "CreateAuditLog", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+			return entry.Action == models.AuditActionRestore && entry.Entity == models.AuditEntityProfile && entry.EntityId == 72
+		}))
+	})
+	t.Run("FailedRestoreProfile_NotOwner", func(t *testing.T) {
+		ctx := auth.WithClaims(context.Background(), &auth.Claims{UserId: 2, Role: auth.RoleUser})
+
+		result, err := profileServiceTest.RestoreProfile(ctx, 72)
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, authorizationService.ErrForbidden)
+	})
+}
+
+// photoRoot keeps the photos a test writes out of public/image.
+func photoRoot(t *testing.T) {
+	root := t.TempDir()
+	storage.SetRoot(root)
+	t.Cleanup(func() { storage.SetRoot("") })
+	assert.Nil(t, os.MkdirAll(filepath.Join(root, "public/image"), 0755))
 }
 
 func TestDeletePhoto(t *testing.T) {
 	t.Run("SuccessDeletePhotoByCode", func(t *testing.T) {
-		profileRepository.Mock.On("DeletePhotoByCode", context.Background(), 1).Return(&models.DefaultResponse{ProfileCode: 1}, nil)
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 52).Return(&models.ProfileDTO{ProfileCode: 52}, nil)
+		profileRepository.Mock.On("DeletePhotoByCode", ownerCtx, 52, 1).Return(&models.DefaultResponse{ProfileCode: 52}, nil)
 
-		result, err := profileServiceTest.DeletePhotoByCode(context.Background(), 1)
+		result, err := profileServiceTest.DeletePhotoByCode(ownerCtx, 52, 1)
 		assert.Nil(t, err)
 		assert.NotNil(t, result)
-		assert.Equal(t, 1, result.ProfileCode)
+		assert.Equal(t, 52, result.ProfileCode)
+	})
+	t.Run("SuccessDeletePhotoByCode_RemovesUnreferencedFile", func(t *testing.T) {
+		photoRoot(t)
+		imgPath := "public/image/test-release.png"
+		assert.Nil(t, os.WriteFile(storage.ResolvePath(imgPath), []byte("test"), 0644))
+
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 54).Return(&models.ProfileDTO{ProfileCode: 54, PhotoUrl: imgPath, PhotoHash: "test-release"}, nil)
+		profileRepository.Mock.On("DeletePhotoByCode", mock.Anything, 54, 1).Return(&models.DefaultResponse{ProfileCode: 54}, nil)
+		profileRepository.Mock.On("CountProfilesByPhotoHash", mock.Anything, "test-release").Return(0, nil)
+
+		result, err := profileServiceTest.DeletePhotoByCode(ownerCtx, 54, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, 54, result.ProfileCode)
+		_, err = os.Stat(storage.ResolvePath(imgPath))
+		assert.True(t, os.IsNotExist(err))
+	})
+	t.Run("SuccessDeletePhotoByCode_KeepsSharedFile", func(t *testing.T) {
+		photoRoot(t)
+		imgPath := "public/image/test-shared.png"
+		assert.Nil(t, os.WriteFile(storage.ResolvePath(imgPath), []byte("test"), 0644))
+
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 55).Return(&models.ProfileDTO{ProfileCode: 55, PhotoUrl: imgPath, PhotoHash: "test-shared"}, nil)
+		profileRepository.Mock.On("DeletePhotoByCode", mock.Anything, 55, 1).Return(&models.DefaultResponse{ProfileCode: 55}, nil)
+		profileRepository.Mock.On("CountProfilesByPhotoHash", mock.Anything, "test-shared").Return(1, nil)
+
+		result, err := profileServiceTest.DeletePhotoByCode(ownerCtx, 55, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, 55, result.ProfileCode)
+		_, err = os.Stat(storage.ResolvePath(imgPath))
+		assert.Nil(t, err)
 	})
 	t.Run("FailedDeletePhotoByCode", func(t *testing.T) {
 		// program mock
-		profileRepository.Mock.On("DeletePhotoByCode", mock.Anything, 2).Return(nil, errors.New("a"))
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 53).Return(&models.ProfileDTO{ProfileCode: 53}, nil)
+		profileRepository.Mock.On("DeletePhotoByCode", mock.Anything, 53, 1).Return(nil, errors.New("a"))
 
-		profile, err := profileServiceTest.DeletePhotoByCode(context.Background(), 2)
+		profile, err := profileServiceTest.DeletePhotoByCode(ownerCtx, 53, 1)
 		assert.Nil(t, profile)
 		// assert.Equal(t, 0, profile.ProfileCode)
 		assert.NotNil(t, err)
@@ -172,25 +412,72 @@ func TestDeletePhoto(t *testing.T) {
 	})
 }
 
+// testPhoto is stored under testPhotoHash once converted to png
+const (
+	testPhoto     = "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAABgAAAAYCAYAAADgdz34AAAABHNCSVQICAgIfAhkiAAAAAlwSFlzAAAApgAAAKYB3X3/OAAAABl0RVh0U29mdHdhcmUAd3d3Lmlua3NjYXBlLm9yZ5vuPBoAAANCSURBVEiJtZZPbBtFFMZ/M7ubXdtdb1xSFyeilBapySVU8h8OoFaooFSqiihIVIpQBKci6KEg9Q6H9kovIHoCIVQJJCKE1ENFjnAgcaSGC6rEnxBwA04Tx43t2FnvDAfjkNibxgHxnWb2e/u992bee7tCa00YFsffekFY+nUzFtjW0LrvjRXrCDIAaPLlW0nHL0SsZtVoaF98mLrx3pdhOqLtYPHChahZcYYO7KvPFxvRl5XPp1sN3adWiD1ZAqD6XYK1b/dvE5IWryTt2udLFedwc1+9kLp+vbbpoDh+6TklxBeAi9TL0taeWpdmZzQDry0AcO+jQ12RyohqqoYoo8RDwJrU+qXkjWtfi8Xxt58BdQuwQs9qC/afLwCw8tnQbqYAPsgxE1S6F3EAIXux2oQFKm0ihMsOF71dHYx+f3NND68ghCu1YIoePPQN1pGRABkJ6Bus96CutRZMydTl+TvuiRW1m3n0eDl0vRPcEysqdXn+jsQPsrHMquGeXEaY4Yk4wxWcY5V/9scqOMOVUFthatyTy8QyqwZ+kDURKoMWxNKr2EeqVKcTNOajqKoBgOE28U4tdQl5p5bwCw7BWquaZSzAPlwjlithJtp3pTImSqQRrb2Z8PHGigD4RZuNX6JYj6wj7O4TFLbCO/Mn/m8R+h6rYSUb3ekokRY6f/YukArN979jcW+V/S8g0eT/N3VN3kTqWbQ428m9/8k0P/1aIhF36PccEl6EhOcAUCrXKZXXWS3XKd2vc/TRBG9O5ELC17MmWubD2nKhUKZa26Ba2+D3P+4/MNCFwg59oWVeYhkzgN/JDR8deKBoD7Y+ljEjGZ0sosXVTvbc6RHirr2reNy1OXd6pJsQ+gqjk8VWFYmHrwBzW/n+uMPFiRwHB2I7ih8ciHFxIkd/3Omk5tCDV1t+2nNu5sxxpDFNx+huNhVT3/zMDz8usXC3ddaHBj1GHj/As08fwTS7Kt1HBTmyN29vdwAw+/wbwLVOJ3uAD1wi/dUH7Qei66PfyuRj4Ik9is+hglfbkbfR3cnZm7chlUWLdwmprtCohX4HUtlOcQjLYCu+fzGJH2QRKvP3UNz8bWk1qMxjGTOMThZ3kvgLI5AzFfo379UAAAAASUVORK5CYII="
+	testPhotoHash = "4a567c8956410440a55b7544ebae3d72433f134bf40cabf4f462da50dbb17814"
+)
+
 func TestUploadPhoto(t *testing.T) {
 	t.Run("SuccessUploadPhotoByCode", func(t *testing.T) {
-		profileRepository.Mock.On("UpdateProfile", context.Background(), 1, &models.Profile{
-			PhotoUrl: fmt.Sprintf("public/image/1-%d.png", time.Now().Unix())}).Return(&models.ProfileDTO{ProfileCode: 1, PhotoUrl: fmt.Sprintf("public/image/1-%d.png", time.Now().Unix())}, nil)
+		photoRoot(t)
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 50).Return(&models.ProfileDTO{ProfileCode: 50}, nil)
+		profileRepository.Mock.On("UpdateProfile", ownerCtx, 50, 1, &models.Profile{
+			PhotoUrl:  "public/image/4a567c8956410440a55b7544ebae3d72433f134bf40cabf4f462da50dbb17814.png",
+			PhotoHash: "4a567c8956410440a55b7544ebae3d72433f134bf40cabf4f462da50dbb17814"}).Return(&models.ProfileDTO{ProfileCode: 50}, nil)
 
-		result, err := profileServiceTest.UploadPhotoByCode(context.Background(), request.UploadPhotoRequest{
-			ProfileCode: 1,
-			Base64Img:   "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAABgAAAAYCAYAAADgdz34AAAABHNCSVQICAgIfAhkiAAAAAlwSFlzAAAApgAAAKYB3X3/OAAAABl0RVh0U29mdHdhcmUAd3d3Lmlua3NjYXBlLm9yZ5vuPBoAAANCSURBVEiJtZZPbBtFFMZ/M7ubXdtdb1xSFyeilBapySVU8h8OoFaooFSqiihIVIpQBKci6KEg9Q6H9kovIHoCIVQJJCKE1ENFjnAgcaSGC6rEnxBwA04Tx43t2FnvDAfjkNibxgHxnWb2e/u992bee7tCa00YFsffekFY+nUzFtjW0LrvjRXrCDIAaPLlW0nHL0SsZtVoaF98mLrx3pdhOqLtYPHChahZcYYO7KvPFxvRl5XPp1sN3adWiD1ZAqD6XYK1b/dvE5IWryTt2udLFedwc1+9kLp+vbbpoDh+6TklxBeAi9TL0taeWpdmZzQDry0AcO+jQ12RyohqqoYoo8RDwJrU+qXkjWtfi8Xxt58BdQuwQs9qC/afLwCw8tnQbqYAPsgxE1S6F3EAIXux2oQFKm0ihMsOF71dHYx+f3NND68ghCu1YIoePPQN1pGRABkJ6Bus96CutRZMydTl+TvuiRW1m3n0eDl0vRPcEysqdXn+jsQPsrHMquGeXEaY4Yk4wxWcY5V/9scqOMOVUFthatyTy8QyqwZ+kDURKoMWxNKr2EeqVKcTNOajqKoBgOE28U4tdQl5p5bwCw7BWquaZSzAPlwjlithJtp3pTImSqQRrb2Z8PHGigD4RZuNX6JYj6wj7O4TFLbCO/Mn/m8R+h6rYSUb3ekokRY6f/YukArN979jcW+V/S8g0eT/N3VN3kTqWbQ428m9/8k0P/1aIhF36PccEl6EhOcAUCrXKZXXWS3XKd2vc/TRBG9O5ELC17MmWubD2nKhUKZa26Ba2+D3P+4/MNCFwg59oWVeYhkzgN/JDR8deKBoD7Y+ljEjGZ0sosXVTvbc6RHirr2reNy1OXd6pJsQ+gqjk8VWFYmHrwBzW/n+uMPFiRwHB2I7ih8ciHFxIkd/3Omk5tCDV1t+2nNu5sxxpDFNx+huNhVT3/zMDz8usXC3ddaHBj1GHj/As08fwTS7Kt1HBTmyN29vdwAw+/wbwLVOJ3uAD1wi/dUH7Qei66PfyuRj4Ik9is+hglfbkbfR3cnZm7chlUWLdwmprtCohX4HUtlOcQjLYCu+fzGJH2QRKvP3UNz8bWk1qMxjGTOMThZ3kvgLI5AzFfo379UAAAAASUVORK5CYII=",
+		result, err := profileServiceTest.UploadPhotoByCode(ownerCtx, request.UploadPhotoRequest{
+			ProfileCode: 50,
+			Version:     1,
+			Base64Img:   testPhoto,
 		})
 		assert.Nil(t, err)
 		assert.NotNil(t, result)
-		assert.Equal(t, 1, result.ProfileCode)
+		assert.Equal(t, 50, result.ProfileCode)
+		assert.Equal(t, "public/image/4a567c8956410440a55b7544ebae3d72433f134bf40cabf4f462da50dbb17814.png", result.PhotoUrl)
+		_, err = os.Stat(storage.ResolvePath(result.PhotoUrl))
+		assert.Nil(t, err)
+	})
+	t.Run("FailedUploadPhotoByCode_ModifiedLeavesNoFile", func(t *testing.T) {
+		photoRoot(t)
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 56).Return(&models.ProfileDTO{ProfileCode: 56}, nil).Once()
+		profileRepository.Mock.On("UpdateProfile", ownerCtx, 56, 1, &models.Profile{
+			PhotoUrl:  "public/image/" + testPhotoHash + ".png",
+			PhotoHash: testPhotoHash}).Return(nil, dataRepository.ErrVersionMismatch).Once()
+
+		result, err := profileServiceTest.UploadPhotoByCode(ownerCtx, request.UploadPhotoRequest{
+			ProfileCode: 56,
+			Version:     1,
+			Base64Img:   testPhoto,
+		})
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrProfileModified)
+		_, err = os.Stat(storage.ResolvePath("public/image/" + testPhotoHash + ".png"))
+		assert.True(t, os.IsNotExist(err))
+	})
+	t.Run("SuccessUploadPhotoByCode_SamePhoto", func(t *testing.T) {
+		// the profile already points at this picture, so nothing is written or updated
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 51).Return(&models.ProfileDTO{ProfileCode: 51,
+			PhotoUrl:  "public/image/4a567c8956410440a55b7544ebae3d72433f134bf40cabf4f462da50dbb17814.png",
+			PhotoHash: "4a567c8956410440a55b7544ebae3d72433f134bf40cabf4f462da50dbb17814"}, nil)
+
+		result, err := profileServiceTest.UploadPhotoByCode(ownerCtx, request.UploadPhotoRequest{
+			ProfileCode: 51,
+			Version:     1,
+			Base64Img:   testPhoto,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 51, result.ProfileCode)
+		assert.Equal(t, "public/image/4a567c8956410440a55b7544ebae3d72433f134bf40cabf4f462da50dbb17814.png", result.PhotoUrl)
+		profileRepository.Mock.AssertNotCalled(t, "UpdateProfile", mock.Anything, 51, mock.Anything)
 	})
 	t.Run("FailedUploadPhoto_DecodeToString", func(t *testing.T) {
 		// program mock
-		profileRepository.Mock.On("UpdateProfile", mock.Anything, 2, &models.Profile{}).Return(nil, errors.New("a"))
+		profileRepository.Mock.On("UpdateProfile", mock.Anything, 2, 1, &models.Profile{}).Return(nil, errors.New("a"))
 
-		result, err := profileServiceTest.UploadPhotoByCode(context.Background(), request.UploadPhotoRequest{
+		result, err := profileServiceTest.UploadPhotoByCode(ownerCtx, request.UploadPhotoRequest{
 			ProfileCode: 2,
+			Version:     1,
 			Base64Img:   "data:image/png;base64,i",
 		})
 		assert.Nil(t, result)
@@ -199,9 +486,9 @@ func TestUploadPhoto(t *testing.T) {
 	})
 	// t.Run("FailedUploadPhoto_FormatNotFound", func(t *testing.T) {
 	// 	// program mock
-	// 	profileRepository.Mock.On("UpdateProfile", context.Background(), 101, &models.Profile{}).Return(nil, errors.New(""))
+	// 	profileRepository.Mock.On("UpdateProfile", ownerCtx, 101, 1, &models.Profile{}).Return(nil, errors.New(""))
 
-	// 	_, err := profileServiceTest.UploadPhotoByCode(context.Background(), request.UploadPhotoRequest{
+	// 	_, err := profileServiceTest.UploadPhotoByCode(ownerCtx, request.UploadPhotoRequest{
 	// 		ProfileCode: 101,
 	// 		Base64Img:   "data:image/wep;base64,iVBORw0KGgoAAAANSUhEUgAAABgAAAAYCAYAAADgdz34AAAABHNCSVQICAgIfAhkiAAAAAlwSFlzAAAApgAAAKYB3X3/OAAAABl0RVh0U29mdHdhcmUAd3d3Lmlua3NjYXBlLm9yZ5vuPBoAAANCSURBVEiJtZZPbBtFFMZ/M7ubXdtdb1xSFyeilBapySVU8h8OoFaooFSqiihIVIpQBKci6KEg9Q6H9kovIHoCIVQJJCKE1ENFjnAgcaSGC6rEnxBwA04Tx43t2FnvDAfjkNibxgHxnWb2e/u992bee7tCa00YFsffekFY+nUzFtjW0LrvjRXrCDIAaPLlW0nHL0SsZtVoaF98mLrx3pdhOqLtYPHChahZcYYO7KvPFxvRl5XPp1sN3adWiD1ZAqD6XYK1b/dvE5IWryTt2udLFedwc1+9kLp+vbbpoDh+6TklxBeAi9TL0taeWpdmZzQDry0AcO+jQ12RyohqqoYoo8RDwJrU+qXkjWtfi8Xxt58BdQuwQs9qC/afLwCw8tnQbqYAPsgxE1S6F3EAIXux2oQFKm0ihMsOF71dHYx+f3NND68ghCu1YIoePPQN1pGRABkJ6Bus96CutRZMydTl+TvuiRW1m3n0eDl0vRPcEysqdXn+jsQPsrHMquGeXEaY4Yk4wxWcY5V/9scqOMOVUFthatyTy8QyqwZ+kDURKoMWxNKr2EeqVKcTNOajqKoBgOE28U4tdQl5p5bwCw7BWquaZSzAPlwjlithJtp3pTImSqQRrb2Z8PHGigD4RZuNX6JYj6wj7O4TFLbCO/Mn/m8R+h6rYSUb3ekokRY6f/YukArN979jcW+V/S8g0eT/N3VN3kTqWbQ428m9/8k0P/1aIhF36PccEl6EhOcAUCrXKZXXWS3XKd2vc/TRBG9O5ELC17MmWubD2nKhUKZa26Ba2+D3P+4/MNCFwg59oWVeYhkzgN/JDR8deKBoD7Y+ljEjGZ0sosXVTvbc6RHirr2reNy1OXd6pJsQ+gqjk8VWFYmHrwBzW/n+uMPFiRwHB2I7ih8ciHFxIkd/3Omk5tCDV1t+2nNu5sxxpDFNx+huNhVT3/zMDz8usXC3ddaHBj1GHj/As08fwTS7Kt1HBTmyN29vdwAw+/wbwLVOJ3uAD1wi/dUH7Qei66PfyuRj4Ik9is+hglfbkbfR3cnZm7chlUWLdwmprtCohX4HUtlOcQjLYCu+fzGJH2QRKvP3UNz8bWk1qMxjGTOMThZ3kvgLI5AzFfo379UAAAAASUVORK5CYII=",
 	// 	})
@@ -223,7 +510,7 @@ func TestDownloadPhoto(t *testing.T) {
 			Address:        "test",
 			PhotoUrl:       "public/image/1-1730888286.png"}, nil)
 
-		result, err := profileServiceTest.DownloadPhotoByCode(context.Background(), 8)
+		result, err := profileServiceTest.DownloadPhotoByCode(ownerCtx, 8)
 		assert.Nil(t, err)
 		assert.NotNil(t, result)
 		// assert.Contains(t, result, "iVBORw0KGgoAAAANSUhEUgAAABgAAAAYCAYAAADgdz34AAADJUlEQVR4nLSWTUwbRxTH/zPeZdc2ZjEFtwaVUqgEXFokG/fQqlXVVlRCVP2QWglVqD1RtT3QSr23B7iWS6NwShRFSImUKIqUAwrH5BAwUsglipQPQmKcmIAx+JO1Z6IZy47tXWODlL+02vG8t795b+btWyucc9gpOvnnN0Tlvyrugqb2ZExjLJYFQVAaOcKJJZ9uRpxqPuXIcZOc9i/+d8WOQ0oLRKenXUpS7+lqzW7Ecq4fmInzlY6ej3bh/jAux6lbXhzc7KgCURU/+bT0xe2k3pdvzUb8Cwvp8gKxyZkvGSGXBAeU71CNGyxDldpoOn/ZlPcXZ3otkVIny7McSYCRNwAcUM6/9y3OXyfRyb8+A9gSANV2ryrU8WNE3ncv9DRyFTIBOqYALNAMXKZLm/EqSxVsBYR4UOegq+mAo90sj9HEI4JNOcEymlihpTsL6izIS4wbi3PBpv6/N+55PtlljdxdIwnbcT0JpmBTmIVRd3DP4fl0B0SxT0QfSkIfTL76PZiUc3YSDMESTMFWQFgQnMAd2IPWn0JqxYvchgss5ZAPODx5GJ9vW0BizozoKBwUq5m6C9D60nCH4lC8pbNiQQWMBOShAdJgjMXk2IxpOHzkgvp2BkSz7qCYM8afw3ziRMu7aai+nDUdRgKEhye2APgbburJFD1eZZ9AVDSu10bnCCugfA2cTNTa/j+3ggeP4/C26Wg3dHgNJ7yGLm3xRBbxRAZ74r6fxcA7Xvw+FbIJn68p4DRs91puRhJIpQ/l9fTZ/pGBCl/7DGiYQnWsFhtTtYYGOo+ENuFrCjbF+5dj4GSu1vrtV8No82gN4cJH+FpE+KxgF6uIvDULYL3S3t6m44+pEN7sdNeFC5vwEb41WgfvnkPlFw2r4yOgjpXa1p3PMyzfeIi797exuVXc695uA8PvdeGLj/uhKJZKN8EKIYxeu129gNDa178BmG/2+2AjcZYzCFw9VZoglo9+MZOzAD44JnwdrPBzKfKSrG+ydPCPgpN/7arLNmrC/5HP1MDtM6jUne98ouXKjltsiuW/LcUXlIZlmYtKrKOXAQAA//8+2DMY6mBorgAAAABJRU5ErkJggg==")
@@ -231,13 +518,11 @@ func TestDownloadPhoto(t *testing.T) {
 	})
 	t.Run("FailedDownloadPhoto_UserNotFound", func(t *testing.T) {
 		// program mock
-		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 12).Return(&models.ProfileDTO{}, errors.New("sql: no rows in result set"))
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 12).Return(&models.ProfileDTO{}, sql.ErrNoRows)
 
-		result, err := profileServiceTest.DownloadPhotoByCode(context.Background(), 12)
+		result, err := profileServiceTest.DownloadPhotoByCode(ownerCtx, 12)
 		assert.Equal(t, "", result)
-		assert.NotNil(t, err)
-		assert.Contains(t, err.Error(), "sql: no rows in result set")
-		// assert.Contains(t, err.Error(), "failed to open image file")
+		assert.ErrorIs(t, err, ErrProfileNotFound)
 	})
 	t.Run("FailedDownloadPhoto_FileNotFound", func(t *testing.T) {
 		// program mock
@@ -251,18 +536,28 @@ func TestDownloadPhoto(t *testing.T) {
 			Address:        "test",
 			PhotoUrl:       "publizc/image/2-1730888286.png"}, nil)
 
-		result, err := profileServiceTest.DownloadPhotoByCode(context.Background(), 10)
+		result, err := profileServiceTest.DownloadPhotoByCode(ownerCtx, 10)
 		assert.Equal(t, "", result)
 		assert.NotNil(t, err)
 		assert.Contains(t, err.Error(), "no such file or directory")
 		assert.Contains(t, err.Error(), "failed to open image file")
 	})
 
+	t.Run("SuccessDownloadPhoto_GeneratedAvatar", func(t *testing.T) {
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 40).Return(&models.ProfileDTO{ProfileCode: 40,
+			FirstName: "john",
+			LastName:  "doe"}, nil)
+
+		result, err := profileServiceTest.DownloadPhotoByCode(ownerCtx, 40)
+		assert.Nil(t, err)
+		assert.Contains(t, result, "data:image/png;base64,")
+	})
+
 	t.Run("FailedDownloadPhoto_FailedToDecode", func(t *testing.T) {
 		// program mock
 		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 3).Return(&models.ProfileDTO{ProfileCode: 2, PhotoUrl: "public/image/asdaa.webp"}, nil)
 
-		result, err := profileServiceTest.DownloadPhotoByCode(context.Background(), 3)
+		result, err := profileServiceTest.DownloadPhotoByCode(ownerCtx, 3)
 		assert.Equal(t, "", result)
 		assert.NotNil(t, err)
 		assert.Contains(t, err.Error(), "failed to decode image:")
@@ -272,9 +567,136 @@ func TestDownloadPhoto(t *testing.T) {
 	// 	// program mock
 	// 	profileRepository.Mock.On("GetProfileByCode", mock.Anything, 2).Return(&models.ProfileDTO{ProfileCode: 2, PhotoUrl: "public/image/as.jpg"}, nil)
 
-	// 	result, err := profileServiceTest.DownloadPhotoByCode(context.Background(), 2)
+	// 	result, err := profileServiceTest.DownloadPhotoByCode(ownerCtx, 2)
 	// 	assert.Equal(t, "", result)
 	// 	assert.NotNil(t, err)
 	// 	assert.Contains(t, err.Error(), "failed to encode image:")
 	// })
 }
+
+func TestDownloadAvatar(t *testing.T) {
+	profileRepository.Mock.On("GetProfileByCode", mock.Anything, 41).Return(&models.ProfileDTO{ProfileCode: 41,
+		FirstName: "john",
+		LastName:  "doe"}, nil)
+
+	t.Run("SuccessDownloadAvatar_PNG", func(t *testing.T) {
+		result, err := profileServiceTest.DownloadAvatarByCode(ownerCtx, 41, "png")
+		assert.Nil(t, err)
+		assert.Contains(t, result, "data:image/png;base64,")
+
+		again, err := profileServiceTest.DownloadAvatarByCode(ownerCtx, 41, "")
+		assert.Nil(t, err)
+		assert.Equal(t, result, again)
+	})
+	t.Run("SuccessDownloadAvatar_SVG", func(t *testing.T) {
+		result, err := profileServiceTest.DownloadAvatarByCode(ownerCtx, 41, "svg")
+		assert.Nil(t, err)
+		assert.Contains(t, result, "data:image/svg+xml;base64,")
+
+		svg, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(result, "data:image/svg+xml;base64,"))
+		assert.Nil(t, err)
+		assert.Contains(t, string(svg), ">JD</text>")
+	})
+	t.Run("FailedDownloadAvatar_UnsupportedFormat", func(t *testing.T) {
+		result, err := profileServiceTest.DownloadAvatarByCode(ownerCtx, 41, "gif")
+		assert.Equal(t, "", result)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "unsupported avatar format")
+	})
+	t.Run("FailedDownloadAvatar_UserNotFound", func(t *testing.T) {
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 42).Return(nil, sql.ErrNoRows)
+
+		result, err := profileServiceTest.DownloadAvatarByCode(ownerCtx, 42, "svg")
+		assert.Equal(t, "", result)
+		assert.ErrorIs(t, err, ErrProfileNotFound)
+	})
+}
+
+// anyPublicId matches a profile that got a generated public id and otherwise
+// equals expected.
+func anyPublicId(expected *models.Profile) interface{} {
+	return mock.MatchedBy(func(actual *models.Profile) bool {
+		withoutId := *actual
+		withoutId.PublicId = ""
+		return actual.PublicId != "" && reflect.DeepEqual(*expected, withoutId)
+	})
+}
+
+func TestResolvePublicId(t *testing.T) {
+	t.Run("SuccessResolvePublicId", func(t *testing.T) {
+		profileRepository.Mock.On("GetProfileCodeByPublicId", mock.Anything, "01JAB3Q8W0RM0ZTRBPN7C2Z1K4").Return(61, nil)
+
+		code, err := profileServiceTest.ResolvePublicId(ownerCtx, "01JAB3Q8W0RM0ZTRBPN7C2Z1K4")
+		assert.Nil(t, err)
+		assert.Equal(t, 61, code)
+	})
+	t.Run("FailedResolvePublicId_NotFound", func(t *testing.T) {
+		profileRepository.Mock.On("GetProfileCodeByPublicId", mock.Anything, "61").Return(0, sql.ErrNoRows)
+
+		code, err := profileServiceTest.ResolvePublicId(ownerCtx, "61")
+		assert.Equal(t, 0, code)
+		assert.ErrorIs(t, err, ErrProfileNotFound)
+	})
+	t.Run("FailedResolvePublicId_RepositoryError", func(t *testing.T) {
+		profileRepository.Mock.On("GetProfileCodeByPublicId", mock.Anything, "broken").Return(0, errors.New("connection refused"))
+
+		code, err := profileServiceTest.ResolvePublicId(ownerCtx, "broken")
+		assert.Equal(t, 0, code)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "failed to resolve profile")
+	})
+}
+
+func TestListProfiles(t *testing.T) {
+	created := time.Date(2024, 11, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("SuccessListProfiles_NextPage", func(t *testing.T) {
+		profileRepository.Mock.On("ListProfiles", mock.Anything, mock.MatchedBy(func(filter *models.ProfileFilter) bool {
+			return filter.Country == "Indonesia" && filter.After == nil
+		})).Return([]*models.ProfileDTO{
+			{ProfileCode: 3, PublicId: "01JAB3Q8W0RM0ZTRBPN7C2Z1K3", FirstName: "John", CreatedAt: created},
+			{ProfileCode: 2, PublicId: "01JAB3Q8W0RM0ZTRBPN7C2Z1K2", FirstName: "Jane", CreatedAt: created.Add(-time.Hour)},
+			{ProfileCode: 1, PublicId: "01JAB3Q8W0RM0ZTRBPN7C2Z1K1", FirstName: "Jim", CreatedAt: created.Add(-2 * time.Hour)},
+		}, nil).Once()
+
+		page, err := profileServiceTest.ListProfiles(ownerCtx, request.ListProfilesRequest{Country: "Indonesia", Limit: 2})
+		assert.Nil(t, err)
+		assert.Len(t, page.Data, 2)
+		assert.Equal(t, "01JAB3Q8W0RM0ZTRBPN7C2Z1K2", page.Data[1].PublicId)
+		profileRepository.Mock.AssertCalled(t, "ListProfiles", mock.Anything, mock.MatchedBy(func(filter *models.ProfileFilter) bool {
+			return filter.Country == "Indonesia" && filter.Sort == "created_at" && filter.Descending &&
+				filter.Limit == 3 && filter.Visibility == models.ProfileVisibility{OwnerId: 1}
+		}))
+
+		// the cursor picks up after the last profile of the page
+		profileRepository.Mock.On("ListProfiles", mock.Anything, mock.MatchedBy(func(filter *models.ProfileFilter) bool {
+			return filter.Country == "Indonesia" && filter.After != nil
+		})).Return([]*models.ProfileDTO{
+			{ProfileCode: 1, PublicId: "01JAB3Q8W0RM0ZTRBPN7C2Z1K1", FirstName: "Jim", CreatedAt: created.Add(-2 * time.Hour)},
+		}, nil).Once()
+
+		page, err = profileServiceTest.ListProfiles(ownerCtx, request.ListProfilesRequest{Country: "Indonesia", Limit: 2, Cursor: page.NextCursor})
+		assert.Nil(t, err)
+		assert.Len(t, page.Data, 1)
+		assert.Empty(t, page.NextCursor)
+		profileRepository.Mock.AssertCalled(t, "ListProfiles", mock.Anything, mock.MatchedBy(func(filter *models.ProfileFilter) bool {
+			return filter.After != nil && filter.After.PublicId == "01JAB3Q8W0RM0ZTRBPN7C2Z1K2" && filter.After.At.Equal(created.Add(-time.Hour))
+		}))
+	})
+
+	t.Run("FailedListProfiles_CursorOfOtherSort", func(t *testing.T) {
+		page, err := profileServiceTest.ListProfiles(ownerCtx, request.ListProfilesRequest{Limit: 1, Cursor: "eyJzIjoiY3JlYXRlZF9hdCIsImQiOnRydWUsInQiOiIyMDI0LTExLTAxVDEwOjAwOjAwWiIsImMiOjN9", Order: "asc"})
+		assert.Nil(t, page)
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+
+		page, err = profileServiceTest.ListProfiles(ownerCtx, request.ListProfilesRequest{Cursor: "garbage"})
+		assert.Nil(t, page)
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+
+	t.Run("FailedListProfiles_Unauthenticated", func(t *testing.T) {
+		page, err := profileServiceTest.ListProfiles(context.Background(), request.ListProfilesRequest{})
+		assert.Nil(t, page)
+		assert.ErrorIs(t, err, authorizationService.ErrUnauthenticated)
+	})
+}
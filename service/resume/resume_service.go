@@ -0,0 +1,482 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/helper/jsondiff"
+	"test-bpjs/v2/helper/keywords"
+	"test-bpjs/v2/helper/taxonomy"
+	transform "test-bpjs/v2/helper/transform"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/request"
+	"test-bpjs/v2/models/response"
+	"test-bpjs/v2/repository"
+	auditService "test-bpjs/v2/service/audit"
+	authorizationService "test-bpjs/v2/service/authorization"
+	timelineService "test-bpjs/v2/service/timeline"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var ErrVersionNotFound = errors.New("resume version not found")
+
+const (
+	RowAdded    = "added"
+	RowRemoved  = "removed"
+	RowModified = "modified"
+)
+
+// The parts of a resume the ATS check looks for keywords in.
+const (
+	SectionSkills     = "skills"
+	SectionEmployment = "employment"
+	SectionEducation  = "education"
+)
+
+// maxAtsKeywords caps the keywords taken from a job description, so a long
+// one does not drown the report in rarely used words.
+const maxAtsKeywords = 40
+
+// Versioner takes an automatic resume version after a change. Services that
+// modify a resume call it once the change is committed.
+type Versioner interface {
+	Snapshot(ctx context.Context, code int)
+}
+
+type ResumeService interface {
+	Versioner
+	GetResume(ctx context.Context, code int) (*response.ResumeResponse, error)
+	GetResumeVersions(ctx context.Context, code int) (*response.ResumeVersionList, error)
+	GetResumeVersion(ctx context.Context, payload request.GetResumeVersionRequest) (*response.ResumeVersionResponse, error)
+	SaveResumeVersion(ctx context.Context, payload request.SaveResumeVersionRequest) (*response.ResumeVersionResponse, error)
+	DiffResumeVersions(ctx context.Context, payload request.DiffResumeVersionsRequest) (*response.ResumeDiffResponse, error)
+	RestoreResumeVersion(ctx context.Context, payload request.RestoreResumeVersionRequest) (*response.ResumeVersionResponse, error)
+	CheckAts(ctx context.Context, payload request.AtsCheckRequest) (*response.AtsCheckResponse, error)
+	GetResumeScore(ctx context.Context, code int) (*response.ResumeScoreResponse, error)
+}
+
+type resumeService struct {
+	profileRepo    repository.ProfileRepository
+	educationRepo  repository.EducationRepository
+	employmentRepo repository.EmploymentRepository
+	skillRepo      repository.SkillRepository
+	resumeRepo     repository.ResumeRepository
+	authorizer     authorizationService.Authorizer
+	auditor        auditService.AuditService
+	timeline       timelineService.TimelineService
+}
+
+func NewResumeService(
+	profileRepo repository.ProfileRepository,
+	educationRepo repository.EducationRepository,
+	employmentRepo repository.EmploymentRepository,
+	skillRepo repository.SkillRepository,
+	resumeRepo repository.ResumeRepository,
+	authorizer authorizationService.Authorizer,
+	auditor auditService.AuditService,
+	timeline timelineService.TimelineService,
+) *resumeService {
+	return &resumeService{
+		profileRepo:    profileRepo,
+		educationRepo:  educationRepo,
+		employmentRepo: employmentRepo,
+		skillRepo:      skillRepo,
+		resumeRepo:     resumeRepo,
+		authorizer:     authorizer,
+		auditor:        auditor,
+		timeline:       timeline,
+	}
+}
+
+// GetResume returns the profile together with all of its child rows and the
+// timeline of its employment.
+func (r *resumeService) GetResume(ctx context.Context, code int) (*response.ResumeResponse, error) {
+	if err := r.authorizer.CanReadProfile(ctx, code); err != nil {
+		return nil, err
+	}
+
+	snapshot, err := r.capture(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resume: %v", err)
+	}
+	resume := transform.TransformResume(snapshot)
+	resume.Timeline = r.timeline.Analyze(code, snapshot.Employment)
+	return resume, nil
+}
+
+func (r *resumeService) GetResumeVersions(ctx context.Context, code int) (*response.ResumeVersionList, error) {
+	if err := r.authorizer.CanManageProfileData(ctx, code, auth.ScopeProfileRead); err != nil {
+		return nil, err
+	}
+
+	versions, err := r.resumeRepo.GetResumeVersions(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resume versions: %v", err)
+	}
+
+	versionList := make([]*response.ResumeVersionResponse, 0, len(versions))
+	for _, version := range versions {
+		versionList = append(versionList, transform.TransformResumeVersion(version))
+	}
+	return &response.ResumeVersionList{
+		Data: versionList,
+	}, nil
+}
+
+func (r *resumeService) GetResumeVersion(ctx context.Context, payload request.GetResumeVersionRequest) (*response.ResumeVersionResponse, error) {
+	if err := r.authorizer.CanManageProfileData(ctx, payload.ProfileCode, auth.ScopeProfileRead); err != nil {
+		return nil, err
+	}
+
+	version, err := r.getVersion(ctx, payload.ProfileCode, payload.Version)
+	if err != nil {
+		return nil, err
+	}
+	return transform.TransformResumeVersion(version), nil
+}
+
+// SaveResumeVersion stores the current resume as a labelled version, even
+// when it is identical to the latest one.
+func (r *resumeService) SaveResumeVersion(ctx context.Context, payload request.SaveResumeVersionRequest) (*response.ResumeVersionResponse, error) {
+	if err := r.authorizer.CanWriteProfile(ctx, payload.ProfileCode); err != nil {
+		return nil, err
+	}
+
+	snapshot, err := r.capture(ctx, payload.ProfileCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resume: %v", err)
+	}
+	version, err := r.store(ctx, payload.ProfileCode, snapshot, payload.Label, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save resume version: %v", err)
+	}
+	return transform.TransformResumeVersion(version), nil
+}
+
+func (r *resumeService) DiffResumeVersions(ctx context.Context, payload request.DiffResumeVersionsRequest) (*response.ResumeDiffResponse, error) {
+	if err := r.authorizer.CanManageProfileData(ctx, payload.ProfileCode, auth.ScopeProfileRead); err != nil {
+		return nil, err
+	}
+
+	from, err := r.getVersion(ctx, payload.ProfileCode, payload.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := r.getVersion(ctx, payload.ProfileCode, payload.To)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := jsondiff.Diff(from.Snapshot.Profile, to.Snapshot.Profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff resume versions: %v", err)
+	}
+	education, err := diffRows(byId(from.Snapshot.Education, educationId), byId(to.Snapshot.Education, educationId))
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff resume versions: %v", err)
+	}
+	employment, err := diffRows(byId(from.Snapshot.Employment, employmentId), byId(to.Snapshot.Employment, employmentId))
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff resume versions: %v", err)
+	}
+	skills, err := diffRows(byId(from.Snapshot.Skills, skillId), byId(to.Snapshot.Skills, skillId))
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff resume versions: %v", err)
+	}
+
+	return &response.ResumeDiffResponse{
+		From:       payload.From,
+		To:         payload.To,
+		Profile:    transform.TransformFieldChanges(profile),
+		Education:  education,
+		Employment: employment,
+		Skills:     skills,
+	}, nil
+}
+
+// RestoreResumeVersion puts the resume back to the state of an older version.
+// The current state is versioned first, so a restore can itself be undone.
+func (r *resumeService) RestoreResumeVersion(ctx context.Context, payload request.RestoreResumeVersionRequest) (*response.ResumeVersionResponse, error) {
+	if err := r.authorizer.CanWriteProfile(ctx, payload.ProfileCode); err != nil {
+		return nil, err
+	}
+
+	target, err := r.getVersion(ctx, payload.ProfileCode, payload.Version)
+	if err != nil {
+		return nil, err
+	}
+	current, err := r.capture(ctx, payload.ProfileCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resume: %v", err)
+	}
+	if err := r.storeIfChanged(ctx, payload.ProfileCode, current); err != nil {
+		return nil, fmt.Errorf("failed to save resume version: %v", err)
+	}
+
+	if err := r.resumeRepo.RestoreResume(ctx, payload.ProfileCode, target.Snapshot); err != nil {
+		return nil, fmt.Errorf("failed to restore resume: %v", err)
+	}
+	r.auditor.Record(ctx, auditService.Entry{
+		ProfileCode: payload.ProfileCode,
+		Action:      models.AuditActionRestore,
+		Entity:      models.AuditEntityResume,
+		EntityId:    payload.Version,
+		Before:      transform.TransformResume(current),
+		After:       transform.TransformResume(target.Snapshot),
+	})
+
+	restored, err := r.capture(ctx, payload.ProfileCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resume: %v", err)
+	}
+	version, err := r.store(ctx, payload.ProfileCode, restored, fmt.Sprintf("restored from version %d", payload.Version), true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save resume version: %v", err)
+	}
+	return transform.TransformResumeVersion(version), nil
+}
+
+// Snapshot stores the current resume as an automatic version unless nothing
+// changed since the latest one. The change it follows is already committed,
+// so failures are logged only.
+func (r *resumeService) Snapshot(ctx context.Context, code int) {
+	snapshot, err := r.capture(ctx, code)
+	if err == nil {
+		err = r.storeIfChanged(ctx, code, snapshot)
+	}
+	if err != nil {
+		log.WithContext(ctx).Errorf("failed to version resume of profile %d: %v", code, err)
+	}
+}
+
+func (r *resumeService) getVersion(ctx context.Context, code, version int) (*models.ResumeVersionDTO, error) {
+	resumeVersion, err := r.resumeRepo.GetResumeVersion(ctx, code, version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrVersionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resume version: %v", err)
+	}
+	return resumeVersion, nil
+}
+
+// GetResumeScore rates how complete the resume is and suggests what to add.
+func (r *resumeService) GetResumeScore(ctx context.Context, code int) (*response.ResumeScoreResponse, error) {
+	if err := r.authorizer.CanReadProfile(ctx, code); err != nil {
+		return nil, err
+	}
+
+	snapshot, err := r.capture(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resume: %v", err)
+	}
+	return scoreResume(snapshot), nil
+}
+
+// CheckAts looks up the keywords of a job description in the skill,
+// employment and education rows, the way applicant tracking systems filter
+// resumes. Keywords naming a skill of the taxonomy are found under any of its
+// spellings, so "Golang" in the description matches "Go" in the resume.
+func (r *resumeService) CheckAts(ctx context.Context, payload request.AtsCheckRequest) (*response.AtsCheckResponse, error) {
+	code := payload.ProfileCode
+	if err := r.authorizer.CanReadProfile(ctx, code); err != nil {
+		return nil, err
+	}
+
+	skills, err := r.skillRepo.GetSkillsByProfileCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get skills: %v", err)
+	}
+	employment, err := r.employmentRepo.GetEmploymentByProfileCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get employment: %v", err)
+	}
+	education, err := r.educationRepo.GetEducationByProfileCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get education: %v", err)
+	}
+
+	var skillText, employmentText, educationText []string
+	for _, row := range skills {
+		skillText = append(skillText, row.Skill)
+	}
+	for _, row := range employment {
+		employmentText = append(employmentText, row.JobTitle, row.Employer, row.Description)
+	}
+	for _, row := range education {
+		educationText = append(educationText, row.Degree, row.School, row.Description)
+	}
+	sections := []struct {
+		name string
+		text keywords.Text
+	}{
+		{SectionSkills, keywords.NewText(skillText...)},
+		{SectionEmployment, keywords.NewText(employmentText...)},
+		{SectionEducation, keywords.NewText(educationText...)},
+	}
+
+	result := &response.AtsCheckResponse{
+		ProfileCode: code,
+		Found:       []*response.AtsKeywordResponse{},
+		Missing:     []*response.AtsKeywordResponse{},
+	}
+	extracted := keywords.Extract(payload.JobDescription, maxAtsKeywords)
+	for _, keyword := range extracted {
+		report := &response.AtsKeywordResponse{Keyword: keyword.Term, Occurrences: keyword.Count, FoundIn: []string{}}
+		spellings := append(taxonomy.Default().Spellings(keyword.Term), keyword.Term)
+		for _, section := range sections {
+			if section.text.ContainsAny(spellings...) {
+				report.FoundIn = append(report.FoundIn, section.name)
+			}
+		}
+		if len(report.FoundIn) > 0 {
+			result.Found = append(result.Found, report)
+		} else {
+			result.Missing = append(result.Missing, report)
+		}
+	}
+	if len(extracted) > 0 {
+		result.Coverage = math.Round(float64(len(result.Found))*1000/float64(len(extracted))) / 10
+	}
+	return result, nil
+}
+
+// capture reads the whole resume. Child rows are sorted by id so equal
+// resumes always encode the same.
+func (r *resumeService) capture(ctx context.Context, code int) (*models.ResumeSnapshot, error) {
+	profile, err := r.profileRepo.GetProfileByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	workingExperience, err := r.profileRepo.GetWorkingExperienceByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	profile.WorkingExperience = workingExperience.WorkingExperience
+
+	education, err := r.educationRepo.GetEducationByProfileCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	employment, err := r.employmentRepo.GetEmploymentByProfileCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	skills, err := r.skillRepo.GetSkillsByProfileCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(education, func(i, j int) bool { return education[i].Id < education[j].Id })
+	sort.Slice(employment, func(i, j int) bool { return employment[i].Id < employment[j].Id })
+	sort.Slice(skills, func(i, j int) bool { return skills[i].Id < skills[j].Id })
+
+	return &models.ResumeSnapshot{
+		Profile:    profile,
+		Education:  education,
+		Employment: employment,
+		Skills:     skills,
+	}, nil
+}
+
+func (r *resumeService) storeIfChanged(ctx context.Context, code int, snapshot *models.ResumeSnapshot) error {
+	encoded, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	latest, err := r.resumeRepo.GetLatestResumeChecksum(ctx, code)
+	if err != nil {
+		return err
+	}
+	if latest == checksum(encoded) {
+		return nil
+	}
+	_, err = r.store(ctx, code, snapshot, "", true)
+	return err
+}
+
+func (r *resumeService) store(ctx context.Context, code int, snapshot *models.ResumeSnapshot, label string, automatic bool) (*models.ResumeVersionDTO, error) {
+	encoded, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	version := &models.ResumeVersion{
+		ProfileCode: code,
+		Label:       label,
+		Automatic:   automatic,
+		Checksum:    checksum(encoded),
+		Snapshot:    string(encoded),
+		CreatedAt:   time.Now().UTC(),
+	}
+	if claims, ok := auth.ClaimsFromContext(ctx); ok {
+		version.CreatedBy = claims.UserId
+	}
+	return r.resumeRepo.CreateResumeVersion(ctx, version)
+}
+
+func checksum(encoded []byte) string {
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
+
+func educationId(row *models.EducationDTO) int   { return row.Id }
+func employmentId(row *models.EmploymentDTO) int { return row.Id }
+func skillId(row *models.SkillDTO) int           { return row.Id }
+
+func byId[T any](rows []T, id func(T) int) map[int]interface{} {
+	rowsById := make(map[int]interface{}, len(rows))
+	for _, row := range rows {
+		rowsById[id(row)] = row
+	}
+	return rowsById
+}
+
+// diffRows matches child rows by id, in id order.
+func diffRows(before, after map[int]interface{}) ([]*response.RowDiffResponse, error) {
+	ids := make([]int, 0, len(before)+len(after))
+	for id := range before {
+		ids = append(ids, id)
+	}
+	for id := range after {
+		if _, ok := before[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	rows := []*response.RowDiffResponse{}
+	for _, id := range ids {
+		old, hadRow := before[id]
+		new, hasRow := after[id]
+		change := RowModified
+		if !hadRow {
+			old, change = nil, RowAdded
+		}
+		if !hasRow {
+			new, change = nil, RowRemoved
+		}
+
+		fields, err := jsondiff.Diff(old, new)
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		rows = append(rows, &response.RowDiffResponse{
+			Id:     id,
+			Change: change,
+			Fields: transform.TransformFieldChanges(fields),
+		})
+	}
+	return rows, nil
+}
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/request"
+	repository "test-bpjs/v2/repository/mocks"
+	auditService "test-bpjs/v2/service/audit"
+	authorizationService "test-bpjs/v2/service/authorization"
+	timelineService "test-bpjs/v2/service/timeline"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var profileRepository = &repository.ProfileRepository{Mock: mock.Mock{}}
+var educationRepository = &repository.EducationRepository{Mock: mock.Mock{}}
+var employmentRepository = &repository.EmploymentRepository{Mock: mock.Mock{}}
+var skillRepository = &repository.SkillRepository{Mock: mock.Mock{}}
+var resumeRepository = &repository.ResumeRepository{Mock: mock.Mock{}}
+var consentRepository = &repository.ConsentRepository{Mock: mock.Mock{}}
+var authorizer = authorizationService.NewAuthorizer(profileRepository, consentRepository, "")
+var auditRepository = &repository.AuditRepository{Mock: mock.Mock{}}
+var auditor = auditService.NewAuditService(auditRepository, authorizer)
+var timeline = timelineService.NewTimelineService(employmentRepository, authorizer, 0)
+var resumeServiceTest = NewResumeService(profileRepository, educationRepository, employmentRepository, skillRepository, resumeRepository, authorizer, auditor, timeline)
+
+// the tests run as user 1, who owns every profile
+var ownerCtx = auth.WithClaims(context.Background(), &auth.Claims{UserId: 1, Role: auth.RoleUser})
+var _ = profileRepository.Mock.On("GetProfileOwner", mock.Anything, mock.Anything).Return(1, nil)
+var _ = auditRepository.Mock.On("CreateAuditLog", mock.Anything, mock.Anything).Return(nil)
+
+// mockResume programs the repositories to return the snapshot once for the
+// profile
+func mockResume(code int, snapshot *models.ResumeSnapshot) {
+	profile := *snapshot.Profile
+	profileRepository.Mock.On("GetProfileByCode", mock.Anything, code).Return(&profile, nil).Once()
+	profileRepository.Mock.On("GetWorkingExperienceByCode", mock.Anything, code).Return(&models.ProfileDTO{WorkingExperience: profile.WorkingExperience}, nil).Once()
+	educationRepository.Mock.On("GetEducationByProfileCode", mock.Anything, code).Return(snapshot.Education, nil).Once()
+	employmentRepository.Mock.On("GetEmploymentByProfileCode", mock.Anything, code).Return(snapshot.Employment, nil).Once()
+	skillRepository.Mock.On("GetSkillsByProfileCode", mock.Anything, code).Return(snapshot.Skills, nil).Once()
+}
+
+func resume(code int, firstName string) *models.ResumeSnapshot {
+	return &models.ResumeSnapshot{
+		Profile:    &models.ProfileDTO{ProfileCode: code, FirstName: firstName, WorkingExperience: "5 years"},
+		Education:  []*models.EducationDTO{{ProfileCode: code, Id: 1, School: "ITB"}},
+		Employment: []*models.EmploymentDTO{},
+		Skills:     []*models.SkillDTO{{ProfileCode: code, Id: 3, Skill: "Golang", Level: "Expert"}, {ProfileCode: code, Id: 2, Skill: "SQL", Level: "Beginner"}},
+	}
+}
+
+func TestInitResumeService(t *testing.T) {
+	t.Run("SuccessInitResumeService", func(t *testing.T) {
+		assert.NotNil(t, NewResumeService(profileRepository, educationRepository, employmentRepository, skillRepository, resumeRepository, authorizer, auditor, timeline))
+	})
+}
+
+func TestGetResume(t *testing.T) {
+	t.Run("SuccessGetResume", func(t *testing.T) {
+		mockResume(1, resume(1, "John"))
+
+		res, err := resumeServiceTest.GetResume(ownerCtx, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, "John", res.Profile.FirstName)
+		assert.Equal(t, "5 years", res.WorkingExperience)
+		assert.Len(t, res.Education, 1)
+		assert.NotNil(t, res.Employment)
+		// skills come back in id order
+		assert.Equal(t, "SQL", res.Skills[0].Skill)
+		assert.Equal(t, 1, res.Timeline.ProfileCode)
+		assert.Equal(t, 0, res.Timeline.TotalExperience.Days)
+	})
+	t.Run("FailedGetResume", func(t *testing.T) {
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 2).Return(nil, errors.New("")).Once()
+
+		res, err := resumeServiceTest.GetResume(ownerCtx, 2)
+		assert.Nil(t, res)
+		assert.Contains(t, err.Error(), "failed to get resume")
+	})
+}
+
+func TestSaveResumeVersion(t *testing.T) {
+	t.Run("SuccessSaveResumeVersion", func(t *testing.T) {
+		mockResume(3, resume(3, "John"))
+		resumeRepository.Mock.On("CreateResumeVersion", mock.Anything, mock.MatchedBy(func(v *models.ResumeVersion) bool {
+			return v.ProfileCode == 3
+		})).Return(&models.ResumeVersionDTO{ProfileCode: 3, Version: 4, Label: "before applying"}, nil).Once()
+
+		res, err := resumeServiceTest.SaveResumeVersion(ownerCtx, request.SaveResumeVersionRequest{ProfileCode: 3, Label: "before applying"})
+		assert.Nil(t, err)
+		assert.Equal(t, 4, res.Version)
+		resumeRepository.Mock.AssertCalled(t, "CreateResumeVersion", mock.Anything, mock.MatchedBy(func(v *models.ResumeVersion) bool {
+			return v.ProfileCode == 3 && v.Label == "before applying" && !v.Automatic && v.CreatedBy == 1 &&
+				v.Checksum != "" && json.Valid([]byte(v.Snapshot))
+		}))
+	})
+	t.Run("FailedSaveResumeVersion_Recruiter", func(t *testing.T) {
+		ctx := auth.WithClaims(context.Background(), &auth.Claims{UserId: 2, Role: auth.RoleRecruiter})
+
+		res, err := resumeServiceTest.SaveResumeVersion(ctx, request.SaveResumeVersionRequest{ProfileCode: 3})
+		assert.Nil(t, res)
+		assert.ErrorIs(t, err, authorizationService.ErrForbidden)
+	})
+}
+
+func TestSnapshot(t *testing.T) {
+	t.Run("SuccessSnapshot_Changed", func(t *testing.T) {
+		mockResume(5, resume(5, "John"))
+		resumeRepository.Mock.On("GetLatestResumeChecksum", mock.Anything, 5).Return("", nil).Once()
+		resumeRepository.Mock.On("CreateResumeVersion", mock.Anything, mock.MatchedBy(func(v *models.ResumeVersion) bool {
+			return v.ProfileCode == 5
+		})).Return(&models.ResumeVersionDTO{ProfileCode: 5, Version: 1}, nil).Once()
+
+		resumeServiceTest.Snapshot(ownerCtx, 5)
+		resumeRepository.Mock.AssertCalled(t, "CreateResumeVersion", mock.Anything, mock.MatchedBy(func(v *models.ResumeVersion) bool {
+			return v.ProfileCode == 5 && v.Automatic
+		}))
+	})
+	t.Run("SuccessSnapshot_Unchanged", func(t *testing.T) {
+		snapshot := resume(6, "John")
+		mockResume(6, snapshot)
+		// the stored snapshot has its children sorted by id
+		snapshot.Skills[0], snapshot.Skills[1] = snapshot.Skills[1], snapshot.Skills[0]
+		encoded, _ := json.Marshal(snapshot)
+		resumeRepository.Mock.On("GetLatestResumeChecksum", mock.Anything, 6).Return(checksum(encoded), nil).Once()
+
+		resumeServiceTest.Snapshot(ownerCtx, 6)
+		resumeRepository.Mock.AssertNotCalled(t, "CreateResumeVersion", mock.Anything, mock.MatchedBy(func(v *models.ResumeVersion) bool {
+			return v.ProfileCode == 6
+		}))
+	})
+}
+
+func TestGetResumeVersion(t *testing.T) {
+	t.Run("SuccessGetResumeVersion", func(t *testing.T) {
+		resumeRepository.Mock.On("GetResumeVersion", mock.Anything, 7, 1).Return(&models.ResumeVersionDTO{ProfileCode: 7, Version: 1, Snapshot: resume(7, "John")}, nil).Once()
+
+		res, err := resumeServiceTest.GetResumeVersion(ownerCtx, request.GetResumeVersionRequest{ProfileCode: 7, Version: 1})
+		assert.Nil(t, err)
+		assert.Equal(t, "John", res.Resume.Profile.FirstName)
+	})
+	t.Run("FailedGetResumeVersion_NotFound", func(t *testing.T) {
+		resumeRepository.Mock.On("GetResumeVersion", mock.Anything, 7, 9).Return(nil, sql.ErrNoRows).Once()
+
+		res, err := resumeServiceTest.GetResumeVersion(ownerCtx, request.GetResumeVersionRequest{ProfileCode: 7, Version: 9})
+		assert.Nil(t, res)
+		assert.ErrorIs(t, err, ErrVersionNotFound)
+	})
+}
+
+func TestDiffResumeVersions(t *testing.T) {
+	t.Run("SuccessDiffResumeVersions", func(t *testing.T) {
+		from := resume(8, "Jon")
+		to := resume(8, "John")
+		to.Education = []*models.EducationDTO{{ProfileCode: 8, Id: 4, School: "UI"}}
+		to.Skills[0].Level = "Advanced"
+		resumeRepository.Mock.On("GetResumeVersion", mock.Anything, 8, 1).Return(&models.ResumeVersionDTO{Version: 1, Snapshot: from}, nil).Once()
+		resumeRepository.Mock.On("GetResumeVersion", mock.Anything, 8, 2).Return(&models.ResumeVersionDTO{Version: 2, Snapshot: to}, nil).Once()
+
+		res, err := resumeServiceTest.DiffResumeVersions(ownerCtx, request.DiffResumeVersionsRequest{ProfileCode: 8, From: 1, To: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, json.RawMessage(`"Jon"`), res.Profile["firstName"].Before)
+		assert.Equal(t, json.RawMessage(`"John"`), res.Profile["firstName"].After)
+
+		assert.Len(t, res.Education, 2)
+		assert.Equal(t, 1, res.Education[0].Id)
+		assert.Equal(t, RowRemoved, res.Education[0].Change)
+		assert.Equal(t, 4, res.Education[1].Id)
+		assert.Equal(t, RowAdded, res.Education[1].Change)
+		assert.Equal(t, json.RawMessage(`"UI"`), res.Education[1].Fields["school"].After)
+
+		assert.Empty(t, res.Employment)
+		assert.Len(t, res.Skills, 1)
+		assert.Equal(t, 3, res.Skills[0].Id)
+		assert.Equal(t, RowModified, res.Skills[0].Change)
+		assert.Len(t, res.Skills[0].Fields, 1)
+	})
+	t.Run("FailedDiffResumeVersions_NotFound", func(t *testing.T) {
+		resumeRepository.Mock.On("GetResumeVersion", mock.Anything, 8, 3).Return(&models.ResumeVersionDTO{Version: 3, Snapshot: resume(8, "John")}, nil).Once()
+		resumeRepository.Mock.On("GetResumeVersion", mock.Anything, 8, 9).Return(nil, sql.ErrNoRows).Once()
+
+		res, err := resumeServiceTest.DiffResumeVersions(ownerCtx, request.DiffResumeVersionsRequest{ProfileCode: 8, From: 3, To: 9})
+		assert.Nil(t, res)
+		assert.ErrorIs(t, err, ErrVersionNotFound)
+	})
+}
+
+func TestRestoreResumeVersion(t *testing.T) {
+	t.Run("SuccessRestoreResumeVersion", func(t *testing.T) {
+		target := resume(10, "Jon")
+		resumeRepository.Mock.On("GetResumeVersion", mock.Anything, 10, 1).Return(&models.ResumeVersionDTO{Version: 1, Snapshot: target}, nil).Once()
+		mockResume(10, resume(10, "John"))
+		resumeRepository.Mock.On("GetLatestResumeChecksum", mock.Anything, 10).Return("", nil).Once()
+		resumeRepository.Mock.On("CreateResumeVersion", mock.Anything, mock.MatchedBy(func(v *models.ResumeVersion) bool {
+			return v.ProfileCode == 10 && v.Label == ""
+		})).Return(&models.ResumeVersionDTO{ProfileCode: 10, Version: 2}, nil).Once()
+		resumeRepository.Mock.On("RestoreResume", mock.Anything, 10, target).Return(nil).Once()
+		mockResume(10, target)
+		resumeRepository.Mock.On("CreateResumeVersion", mock.Anything, mock.MatchedBy(func(v *models.ResumeVersion) bool {
+			return v.ProfileCode == 10 && v.Label == "restored from version 1"
+		})).Return(&models.ResumeVersionDTO{ProfileCode: 10, Version: 3, Label: "restored from version 1", Automatic: true}, nil).Once()
+
+		res, err := resumeServiceTest.RestoreResumeVersion(ownerCtx, request.RestoreResumeVersionRequest{ProfileCode: 10, Version: 1})
+		assert.Nil(t, err)
+		assert.Equal(t, 3, res.Version)
+		resumeRepository.Mock.AssertCalled(t, "RestoreResume", mock.Anything, 10, target)
+		auditRepository.Mock.AssertCalled(t, "CreateAuditLog", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+			return entry.ProfileCode == 10 && entry.Action == models.AuditActionRestore && entry.Entity == models.AuditEntityResume && entry.EntityId == 1
+		}))
+	})
+	t.Run("FailedRestoreResumeVersion", func(t *testing.T) {
+		target := resume(11, "Jon")
+		resumeRepository.Mock.On("GetResumeVersion", mock.Anything, 11, 1).Return(&models.ResumeVersionDTO{Version: 1, Snapshot: target}, nil).Once()
+		mockResume(11, target)
+		current := resume(11, "Jon")
+		current.Skills[0], current.Skills[1] = current.Skills[1], current.Skills[0]
+		encoded, _ := json.Marshal(current)
+		resumeRepository.Mock.On("GetLatestResumeChecksum", mock.Anything, 11).Return(checksum(encoded), nil).Once()
+		resumeRepository.Mock.On("RestoreResume", mock.Anything, 11, target).Return(errors.New("")).Once()
+
+		res, err := resumeServiceTest.RestoreResumeVersion(ownerCtx, request.RestoreResumeVersionRequest{ProfileCode: 11, Version: 1})
+		assert.Nil(t, res)
+		assert.Contains(t, err.Error(), "failed to restore resume")
+	})
+}
+
+func TestCheckAts(t *testing.T) {
+	t.Run("SuccessCheckAts", func(t *testing.T) {
+		skillRepository.Mock.On("GetSkillsByProfileCode", mock.Anything, 20).Return([]*models.SkillDTO{
+			{Skill: "Golang", Level: "Expert"}, {Skill: "Docker"},
+		}, nil).Once()
+		employmentRepository.Mock.On("GetEmploymentByProfileCode", mock.Anything, 20).Return([]*models.EmploymentDTO{
+			{JobTitle: "Backend Engineer", Employer: "Tokopedia", Description: "Built payment services in Golang on Google Cloud."},
+		}, nil).Once()
+		educationRepository.Mock.On("GetEducationByProfileCode", mock.Anything, 20).Return([]*models.EducationDTO{
+			{Degree: "Computer Science", School: "Universitas Indonesia"},
+		}, nil).Once()
+
+		res, err := resumeServiceTest.CheckAts(ownerCtx, request.AtsCheckRequest{
+			ProfileCode:    20,
+			JobDescription: "We need a Golang developer with Kubernetes experience. A degree in Computer Science and Google Cloud knowledge is a plus.",
+		})
+		assert.Nil(t, err)
+		found := map[string][]string{}
+		for _, keyword := range res.Found {
+			found[keyword.Keyword] = keyword.FoundIn
+		}
+		assert.Equal(t, []string{SectionSkills, SectionEmployment}, found["golang"])
+		assert.Equal(t, []string{SectionEducation}, found["computer science"])
+		assert.Equal(t, []string{SectionEmployment}, found["google cloud"])
+
+		missing := []string{}
+		for _, keyword := range res.Missing {
+			missing = append(missing, keyword.Keyword)
+		}
+		assert.ElementsMatch(t, []string{"developer", "kubernetes", "degree"}, missing)
+		assert.Equal(t, 50.0, res.Coverage)
+	})
+
+	t.Run("SuccessCheckAtsTaxonomySpellings", func(t *testing.T) {
+		skillRepository.Mock.On("GetSkillsByProfileCode", mock.Anything, 22).Return([]*models.SkillDTO{
+			{Skill: "Go"}, {Skill: "Node.js"},
+		}, nil).Once()
+		employmentRepository.Mock.On("GetEmploymentByProfileCode", mock.Anything, 22).Return([]*models.EmploymentDTO{}, nil).Once()
+		educationRepository.Mock.On("GetEducationByProfileCode", mock.Anything, 22).Return([]*models.EducationDTO{}, nil).Once()
+
+		res, err := resumeServiceTest.CheckAts(ownerCtx, request.AtsCheckRequest{ProfileCode: 22, JobDescription: "Golang, NodeJS"})
+		assert.Nil(t, err)
+		assert.Len(t, res.Found, 2)
+		for _, keyword := range res.Found {
+			assert.Equal(t, []string{SectionSkills}, keyword.FoundIn, keyword.Keyword)
+		}
+		assert.Empty(t, res.Missing)
+		assert.Equal(t, 100.0, res.Coverage)
+	})
+
+	t.Run("FailedCheckAts", func(t *testing.T) {
+		skillRepository.Mock.On("GetSkillsByProfileCode", mock.Anything, 21).Return(nil, errors.New("database down")).Once()
+
+		res, err := resumeServiceTest.CheckAts(ownerCtx, request.AtsCheckRequest{ProfileCode: 21, JobDescription: "Golang"})
+		assert.Nil(t, res)
+		assert.EqualError(t, err, "failed to get skills: database down")
+	})
+}
+
+func TestGetResumeScore(t *testing.T) {
+	t.Run("SuccessGetResumeScore", func(t *testing.T) {
+		mockResume(22, resume(22, "John"))
+
+		res, err := resumeServiceTest.GetResumeScore(ownerCtx, 22)
+		assert.Nil(t, err)
+		assert.Equal(t, 22, res.ProfileCode)
+		assert.Equal(t, 100, res.MaxScore)
+		assert.NotEmpty(t, res.Suggestions)
+	})
+
+	t.Run("FailedGetResumeScore", func(t *testing.T) {
+		profileRepository.Mock.On("GetProfileByCode", mock.Anything, 23).Return(nil, sql.ErrNoRows).Once()
+
+		res, err := resumeServiceTest.GetResumeScore(ownerCtx, 23)
+		assert.Nil(t, res)
+		assert.Contains(t, err.Error(), "failed to get resume")
+	})
+}
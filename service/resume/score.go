@@ -0,0 +1,244 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/response"
+	"time"
+	"unicode/utf8"
+)
+
+// SectionProfile is the part of the resume scored from the profile fields.
+// The other parts share their names with the ATS check.
+const SectionProfile = "profile"
+
+const (
+	PriorityHigh   = "high"
+	PriorityMedium = "medium"
+	PriorityLow    = "low"
+)
+
+// The points of each part of the resume add up to 100.
+const (
+	photoPoints          = 10.0
+	phonePoints          = 5.0
+	summaryPoints        = 15.0
+	wantedJobTitlePoints = 5.0
+	locationPoints       = 5.0
+
+	firstEmploymentPoints  = 10.0
+	secondEmploymentPoints = 5.0
+	employmentDetailPoints = 15.0
+
+	educationPoints       = 8.0
+	educationDetailPoints = 7.0
+
+	skillCountPoints = 8.0
+	skillLevelPoints = 7.0
+)
+
+const (
+	// minSummaryLength is how long a summary has to be to earn every point.
+	minSummaryLength = 150
+	// minDescriptionLength is how long the description of an employment or
+	// education row has to be to count.
+	minDescriptionLength = 50
+	// wantedSkills is how many skills earn every skill count point.
+	wantedSkills = 5
+)
+
+type resumeScore struct {
+	sections    map[string]*response.ResumeSectionScore
+	suggestions []*response.ResumeSuggestionResponse
+}
+
+// earn adds points out of maxPoints to a section, and suggests the message
+// when points were missed.
+func (s *resumeScore) earn(section string, points, maxPoints float64, message string) {
+	score := s.sections[section]
+	score.Score += points
+	score.MaxScore += maxPoints
+	if missed := maxPoints - points; missed > 0 && message != "" {
+		s.suggestions = append(s.suggestions, &response.ResumeSuggestionResponse{
+			Section: section,
+			Message: message,
+			Points:  round(missed),
+		})
+	}
+}
+
+// check gives a section every point when ok, or none and the suggestion.
+func (s *resumeScore) check(section string, ok bool, points float64, message string) {
+	if ok {
+		s.earn(section, points, points, "")
+		return
+	}
+	s.earn(section, 0, points, message)
+}
+
+// scoreResume rates how complete a resume is: the contact and summary
+// fields of the profile, how many employment and education rows there are
+// and how well they are filled in, and how many skills have a level.
+func scoreResume(snapshot *models.ResumeSnapshot) *response.ResumeScoreResponse {
+	score := &resumeScore{sections: map[string]*response.ResumeSectionScore{}}
+	order := []string{SectionProfile, SectionEmployment, SectionEducation, SectionSkills}
+	for _, section := range order {
+		score.sections[section] = &response.ResumeSectionScore{Section: section}
+	}
+
+	scoreProfile(score, snapshot.Profile)
+	scoreEmployment(score, snapshot.Employment)
+	scoreEducation(score, snapshot.Education)
+	scoreSkills(score, snapshot.Skills)
+
+	result := &response.ResumeScoreResponse{
+		ProfileCode: snapshot.Profile.ProfileCode,
+		Suggestions: score.suggestions,
+	}
+	var total, maxScore float64
+	for _, section := range order {
+		sectionScore := score.sections[section]
+		total += sectionScore.Score
+		maxScore += sectionScore.MaxScore
+		sectionScore.Score = round(sectionScore.Score)
+		result.Sections = append(result.Sections, sectionScore)
+	}
+	result.Score = int(math.Round(total))
+	result.MaxScore = int(math.Round(maxScore))
+
+	sort.SliceStable(result.Suggestions, func(i, j int) bool {
+		return result.Suggestions[i].Points > result.Suggestions[j].Points
+	})
+	for _, suggestion := range result.Suggestions {
+		suggestion.Priority = priority(suggestion.Points)
+	}
+	if result.Suggestions == nil {
+		result.Suggestions = []*response.ResumeSuggestionResponse{}
+	}
+	return result
+}
+
+func scoreProfile(score *resumeScore, profile *models.ProfileDTO) {
+	score.check(SectionProfile, filled(profile.PhotoUrl), photoPoints, "Add a profile photo")
+	score.check(SectionProfile, filled(profile.Phone), phonePoints, "Add a phone number recruiters can call")
+	score.check(SectionProfile, filled(profile.WantedJobTitle), wantedJobTitlePoints, "Add the job title you are looking for")
+	score.check(SectionProfile, filled(profile.City) && filled(profile.Country), locationPoints, "Add the city and country you live in")
+
+	length := utf8.RuneCountInString(strings.TrimSpace(profile.WorkingExperience))
+	switch {
+	case length == 0:
+		score.earn(SectionProfile, 0, summaryPoints, "Write a summary of your working experience")
+	case length < minSummaryLength:
+		score.earn(SectionProfile, summaryPoints/2, summaryPoints,
+			fmt.Sprintf("Expand your summary to at least %d characters", minSummaryLength))
+	default:
+		score.earn(SectionProfile, summaryPoints, summaryPoints, "")
+	}
+}
+
+// scoreEmployment splits the detail points between the rows: half for the
+// dates and half for the description of each row.
+func scoreEmployment(score *resumeScore, employment []*models.EmploymentDTO) {
+	score.check(SectionEmployment, len(employment) > 0, firstEmploymentPoints, "Add the jobs you have held")
+	if len(employment) == 0 {
+		score.earn(SectionEmployment, 0, secondEmploymentPoints+employmentDetailPoints, "")
+		return
+	}
+	score.check(SectionEmployment, len(employment) > 1, secondEmploymentPoints, "Add your previous jobs too")
+
+	share := employmentDetailPoints / float64(len(employment)) / 2
+	for _, row := range employment {
+		name := describeRow(row.JobTitle, row.Employer)
+		problem := dateProblem(row.StartDate, row.EndDate)
+		score.check(SectionEmployment, problem == "", share, fmt.Sprintf(problem, "your employment "+name))
+		score.check(SectionEmployment, described(row.Description), share, "Describe what you did and achieved in your employment "+name)
+	}
+}
+
+// scoreEducation splits the detail points between the rows: half for the
+// dates and half for the degree of each row.
+func scoreEducation(score *resumeScore, education []*models.EducationDTO) {
+	score.check(SectionEducation, len(education) > 0, educationPoints, "Add your education")
+	if len(education) == 0 {
+		score.earn(SectionEducation, 0, educationDetailPoints, "")
+		return
+	}
+
+	share := educationDetailPoints / float64(len(education)) / 2
+	for _, row := range education {
+		name := describeRow(row.Degree, row.School)
+		problem := dateProblem(row.StartDate, row.EndDate)
+		score.check(SectionEducation, problem == "", share, fmt.Sprintf(problem, "your education "+name))
+		score.check(SectionEducation, filled(row.Degree), share, "Add the degree of your education "+name)
+	}
+}
+
+func scoreSkills(score *resumeScore, skills []*models.SkillDTO) {
+	count := math.Min(float64(len(skills)), wantedSkills)
+	score.earn(SectionSkills, skillCountPoints*count/wantedSkills, skillCountPoints,
+		fmt.Sprintf("List at least %d skills", wantedSkills))
+
+	if len(skills) == 0 {
+		score.earn(SectionSkills, 0, skillLevelPoints, "")
+		return
+	}
+	var missing []string
+	for _, skill := range skills {
+		if !filled(skill.Level) {
+			missing = append(missing, skill.Skill)
+		}
+	}
+	levelled := float64(len(skills)-len(missing)) / float64(len(skills))
+	score.earn(SectionSkills, skillLevelPoints*levelled, skillLevelPoints,
+		"Add your level of "+strings.Join(missing, ", "))
+}
+
+func filled(value string) bool {
+	return strings.TrimSpace(value) != ""
+}
+
+func described(description string) bool {
+	return utf8.RuneCountInString(strings.TrimSpace(description)) >= minDescriptionLength
+}
+
+// dateProblem tells what is wrong with the dates of a row, as a suggestion
+// to format with the name of the row, or nothing. Rows without an end date
+// are ongoing.
+func dateProblem(start, end time.Time) string {
+	switch {
+	case start.IsZero():
+		return "Add the start date of %s"
+	case !end.IsZero() && end.Before(start):
+		return "Fix the dates of %s, which ends before it starts"
+	}
+	return ""
+}
+
+func describeRow(title, place string) string {
+	switch {
+	case title != "" && place != "":
+		return fmt.Sprintf("%q at %q", title, place)
+	case title != "":
+		return fmt.Sprintf("%q", title)
+	case place != "":
+		return fmt.Sprintf("at %q", place)
+	}
+	return "without a title"
+}
+
+func priority(points float64) string {
+	switch {
+	case points >= 10:
+		return PriorityHigh
+	case points >= 5:
+		return PriorityMedium
+	}
+	return PriorityLow
+}
+
+func round(points float64) float64 {
+	return math.Round(points*100) / 100
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"strings"
+	"test-bpjs/v2/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScoreResume(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	description := strings.Repeat("Built and ran the payment services. ", 3)
+
+	t.Run("SuccessScoreCompleteResume", func(t *testing.T) {
+		score := scoreResume(&models.ResumeSnapshot{
+			Profile: &models.ProfileDTO{
+				ProfileCode: 1, PhotoUrl: "photo.jpg", Phone: "0812", WantedJobTitle: "Engineer", City: "Jakarta", Country: "Indonesia",
+				WorkingExperience: strings.Repeat("x", minSummaryLength),
+			},
+			Employment: []*models.EmploymentDTO{
+				{JobTitle: "Engineer", Employer: "BPJS", StartDate: start, Description: description},
+				{JobTitle: "Intern", Employer: "Telkom", StartDate: start.AddDate(-1, 0, 0), EndDate: start, Description: description},
+			},
+			Education: []*models.EducationDTO{{Degree: "Computer Science", School: "ITB", StartDate: start.AddDate(-5, 0, 0)}},
+			Skills: []*models.SkillDTO{
+				{Skill: "Golang", Level: "Expert"}, {Skill: "SQL", Level: "Advanced"}, {Skill: "Docker", Level: "Intermediate"},
+				{Skill: "Redis", Level: "Beginner"}, {Skill: "Kafka", Level: "Beginner"},
+			},
+		})
+		assert.Equal(t, 100, score.Score)
+		assert.Equal(t, 100, score.MaxScore)
+		assert.Empty(t, score.Suggestions)
+		assert.Len(t, score.Sections, 4)
+	})
+
+	t.Run("SuccessScoreEmptyResume", func(t *testing.T) {
+		score := scoreResume(&models.ResumeSnapshot{Profile: &models.ProfileDTO{ProfileCode: 2}})
+		assert.Equal(t, 0, score.Score)
+		assert.Equal(t, 100, score.MaxScore)
+		// the biggest gains come first
+		assert.Equal(t, "Write a summary of your working experience", score.Suggestions[0].Message)
+		assert.Equal(t, PriorityHigh, score.Suggestions[0].Priority)
+		assert.Equal(t, 15.0, score.Suggestions[0].Points)
+		assert.Equal(t, PriorityMedium, score.Suggestions[len(score.Suggestions)-1].Priority)
+	})
+
+	t.Run("SuccessScorePartialResume", func(t *testing.T) {
+		score := scoreResume(&models.ResumeSnapshot{
+			Profile: &models.ProfileDTO{ProfileCode: 3, PhotoUrl: "photo.jpg", Phone: "0812", WantedJobTitle: "Engineer", City: "Jakarta", WorkingExperience: "Engineer"},
+			Employment: []*models.EmploymentDTO{
+				{JobTitle: "Engineer", Employer: "BPJS", StartDate: start, EndDate: start.AddDate(-1, 0, 0)},
+			},
+			Skills: []*models.SkillDTO{{Skill: "Golang", Level: "Expert"}, {Skill: "SQL"}},
+		})
+
+		messages := map[string]float64{}
+		for _, suggestion := range score.Suggestions {
+			messages[suggestion.Message] = suggestion.Points
+		}
+		assert.Equal(t, PriorityLow, score.Suggestions[len(score.Suggestions)-1].Priority)
+		assert.Equal(t, 5.0, messages["Add the city and country you live in"])
+		assert.Equal(t, 7.5, messages["Expand your summary to at least 150 characters"])
+		assert.Equal(t, 5.0, messages["Add your previous jobs too"])
+		assert.Equal(t, 7.5, messages[`Fix the dates of your employment "Engineer" at "BPJS", which ends before it starts`])
+		assert.Equal(t, 7.5, messages[`Describe what you did and achieved in your employment "Engineer" at "BPJS"`])
+		assert.Equal(t, 8.0, messages["Add your education"])
+		assert.Equal(t, 4.8, messages["List at least 5 skills"])
+		assert.Equal(t, 3.5, messages["Add your level of SQL"])
+		// 10 + 5 + 5 + 7.5 for the profile, 10 for one job, 3.2 + 3.5 for
+		// the skills
+		assert.Equal(t, 44, score.Score)
+	})
+}
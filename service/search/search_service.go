@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	transform "test-bpjs/v2/helper/transform"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/request"
+	"test-bpjs/v2/models/response"
+	"test-bpjs/v2/repository"
+	authorizationService "test-bpjs/v2/service/authorization"
+)
+
+const defaultPageSize = 20
+
+// SearchService finds profiles by the text of the profile and its
+// education, employment and skill rows.
+type SearchService interface {
+	Search(ctx context.Context, payload request.SearchRequest) (*response.SearchResultList, error)
+}
+
+type searchService struct {
+	searchRepo repository.SearchRepository
+	authorizer authorizationService.Authorizer
+}
+
+func NewSearchService(searchRepo repository.SearchRepository, authorizer authorizationService.Authorizer) *searchService {
+	return &searchService{searchRepo: searchRepo, authorizer: authorizer}
+}
+
+// Search returns a page of the profiles the caller may read that match the
+// query, best match first. Text is parsed as English unless Indonesian is
+// asked for.
+func (s *searchService) Search(ctx context.Context, payload request.SearchRequest) (*response.SearchResultList, error) {
+	visibility, err := s.authorizer.ProfileVisibility(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	page, pageSize := payload.Page, payload.PageSize
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	language := payload.Language
+	if language == "" {
+		language = models.SearchLanguageEnglish
+	}
+
+	results, err := s.searchRepo.SearchProfiles(ctx, &models.ProfileSearch{
+		Query:      payload.Query,
+		Language:   language,
+		Visibility: *visibility,
+		Limit:      pageSize,
+		Offset:     (page - 1) * pageSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search profiles: %v", err)
+	}
+
+	resultList := make([]*response.SearchResultResponse, 0, len(results))
+	for _, result := range results {
+		resultList = append(resultList, transform.TransformSearchResult(result))
+	}
+	return &response.SearchResultList{
+		Data:     resultList,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
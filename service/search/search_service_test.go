@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/request"
+	repository "test-bpjs/v2/repository/mocks"
+	authorizationService "test-bpjs/v2/service/authorization"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var searchRepository = &repository.SearchRepository{Mock: mock.Mock{}}
+var profileRepository = &repository.ProfileRepository{Mock: mock.Mock{}}
+var consentRepository = &repository.ConsentRepository{Mock: mock.Mock{}}
+var authorizer = authorizationService.NewAuthorizer(profileRepository, consentRepository, "")
+var searchServiceTest = searchService{searchRepo: searchRepository, authorizer: authorizer}
+
+var ownerCtx = auth.WithClaims(context.Background(), &auth.Claims{UserId: 1, Role: auth.RoleUser})
+
+func TestInitSearchService(t *testing.T) {
+	t.Run("SuccessInitSearchService", func(t *testing.T) {
+		assert.NotNil(t, NewSearchService(searchRepository, authorizer))
+	})
+}
+
+func TestSearch(t *testing.T) {
+	t.Run("SuccessSearchDefaults", func(t *testing.T) {
+		searchRepository.Mock.On("SearchProfiles", mock.Anything, &models.ProfileSearch{
+			Query:      "golang engineer",
+			Language:   models.SearchLanguageEnglish,
+			Visibility: models.ProfileVisibility{OwnerId: 1},
+			Limit:      defaultPageSize,
+			Offset:     0,
+		}).Return([]*models.SearchResultDTO{
+			{ProfileCode: 1, FirstName: "Budi", Rank: 0.5, Snippet: "<mark>Golang</mark> <mark>engineer</mark>"},
+		}, nil).Once()
+
+		result, err := searchServiceTest.Search(ownerCtx, request.SearchRequest{Query: "golang engineer"})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, result.Page)
+		assert.Equal(t, defaultPageSize, result.PageSize)
+		assert.Len(t, result.Data, 1)
+		assert.Equal(t, 1, result.Data[0].ProfileCode)
+		assert.Equal(t, "<mark>Golang</mark> <mark>engineer</mark>", result.Data[0].Snippet)
+	})
+
+	t.Run("SuccessSearchIndonesianPage", func(t *testing.T) {
+		ctx := auth.WithClaims(context.Background(), &auth.Claims{UserId: 2, Role: auth.RoleAdmin})
+		searchRepository.Mock.On("SearchProfiles", mock.Anything, &models.ProfileSearch{
+			Query:      "pengembang perangkat lunak",
+			Language:   models.SearchLanguageIndonesian,
+			Visibility: models.ProfileVisibility{All: true},
+			Limit:      10,
+			Offset:     20,
+		}).Return([]*models.SearchResultDTO{}, nil).Once()
+
+		result, err := searchServiceTest.Search(ctx, request.SearchRequest{
+			Query: "pengembang perangkat lunak", Language: models.SearchLanguageIndonesian, Page: 3, PageSize: 10,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 3, result.Page)
+		assert.Empty(t, result.Data)
+	})
+
+	t.Run("FailedSearchUnauthenticated", func(t *testing.T) {
+		_, err := searchServiceTest.Search(context.Background(), request.SearchRequest{Query: "golang"})
+		assert.ErrorIs(t, err, authorizationService.ErrUnauthenticated)
+	})
+
+	t.Run("FailedSearchRepository", func(t *testing.T) {
+		searchRepository.Mock.On("SearchProfiles", mock.Anything, mock.MatchedBy(func(search *models.ProfileSearch) bool {
+			return search.Query == "broken"
+		})).Return(nil, errors.New("database down")).Once()
+
+		_, err := searchServiceTest.Search(ownerCtx, request.SearchRequest{Query: "broken"})
+		assert.EqualError(t, err, "failed to search profiles: database down")
+	})
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"test-bpjs/v2/helper/taxonomy"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/repository"
+)
+
+// backfillBatchSize is how many skill rows Backfill loads at a time.
+const backfillBatchSize = 500
+
+// SkillBackfillService rewrites the skill rows stored before the taxonomy, or
+// under an older version of it, the way CreateSkill stores them today.
+type SkillBackfillService interface {
+	Backfill(ctx context.Context, dryRun bool) (*models.SkillBackfill, error)
+}
+
+type skillBackfillService struct {
+	skillRepo repository.SkillRepository
+	taxonomy  *taxonomy.Taxonomy
+}
+
+func NewSkillBackfillService(skillRepo repository.SkillRepository) *skillBackfillService {
+	return &skillBackfillService{skillRepo: skillRepo, taxonomy: taxonomy.Default()}
+}
+
+// Backfill spells every skill row, trash included, the way the taxonomy does
+// and its level the way models.SkillLevels does. Levels that are no skill
+// level are left alone and counted. With dryRun nothing is written, but the
+// counts are the same. Rows changed or purged since they were read are
+// skipped; running the backfill again picks up the changed ones.
+func (b *skillBackfillService) Backfill(ctx context.Context, dryRun bool) (*models.SkillBackfill, error) {
+	backfill := &models.SkillBackfill{TaxonomyVersion: b.taxonomy.Version}
+	lastId := 0
+	for {
+		rows, err := b.skillRepo.GetSkillsAfter(ctx, lastId, backfillBatchSize)
+		if err != nil {
+			return backfill, fmt.Errorf("failed to get skills: %v", err)
+		}
+		if len(rows) == 0 {
+			return backfill, nil
+		}
+
+		for _, row := range rows {
+			lastId = row.Id
+			backfill.Checked++
+
+			if _, ok := b.taxonomy.Lookup(row.Skill); !ok {
+				backfill.Unrecognized++
+			}
+			skill := b.taxonomy.Canonical(row.Skill)
+			level, ok := models.NormalizeSkillLevel(row.Level)
+			if !ok {
+				backfill.InvalidLevels++
+				level = row.Level
+			}
+			if skill == row.Skill && level == row.Level {
+				continue
+			}
+
+			if !dryRun {
+				err := b.skillRepo.NormalizeSkill(ctx, row.Id, row.Version, skill, level)
+				if errors.Is(err, repository.ErrNotFound) || errors.Is(err, repository.ErrVersionMismatch) {
+					backfill.Skipped++
+					continue
+				}
+				if err != nil {
+					return backfill, fmt.Errorf("failed to normalize skill %d: %v", row.Id, err)
+				}
+			}
+			backfill.Updated++
+		}
+	}
+}
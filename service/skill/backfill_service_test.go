@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"test-bpjs/v2/helper/taxonomy"
+	"test-bpjs/v2/models"
+	dataRepository "test-bpjs/v2/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var skillBackfillServiceTest = skillBackfillService{skillRepo: skillRepository, taxonomy: taxonomy.Default()}
+
+func mockSkillsToBackfill() {
+	skillRepository.Mock.On("GetSkillsAfter", mock.Anything, 0, backfillBatchSize).Return([]*models.SkillDTO{
+		{Id: 31, Skill: "golang", Level: "Expert", Version: 1},
+		{Id: 32, Skill: "Go", Level: "expert", Version: 1},
+		{Id: 33, Skill: " Underwater  Basket Weaving", Level: "beginner", Version: 2},
+		{Id: 34, Skill: "k8s", Level: "Guru", Version: 1},
+		{Id: 35, Skill: "nodejs", Level: "Beginner", Version: 3},
+	}, nil).Once()
+	skillRepository.Mock.On("GetSkillsAfter", mock.Anything, 35, backfillBatchSize).Return([]*models.SkillDTO{}, nil).Once()
+}
+
+func TestInitSkillBackfillService(t *testing.T) {
+	t.Run("SuccessInitSkillBackfillService", func(t *testing.T) {
+		assert.NotNil(t, NewSkillBackfillService(skillRepository))
+	})
+}
+
+func TestBackfill(t *testing.T) {
+	t.Run("SuccessBackfill", func(t *testing.T) {
+		mockSkillsToBackfill()
+		skillRepository.Mock.On("NormalizeSkill", mock.Anything, 31, 1, "Go", "expert").Return(nil).Once()
+		skillRepository.Mock.On("NormalizeSkill", mock.Anything, 33, 2, "Underwater Basket Weaving", "beginner").Return(nil).Once()
+		// unknown levels are kept
+		skillRepository.Mock.On("NormalizeSkill", mock.Anything, 34, 1, "Kubernetes", "Guru").Return(dataRepository.ErrNotFound).Once()
+		skillRepository.Mock.On("NormalizeSkill", mock.Anything, 35, 3, "Node.js", "beginner").Return(dataRepository.ErrVersionMismatch).Once()
+
+		backfill, err := skillBackfillServiceTest.Backfill(context.Background(), false)
+		assert.Nil(t, err)
+		assert.Equal(t, taxonomy.Default().Version, backfill.TaxonomyVersion)
+		assert.Equal(t, 5, backfill.Checked)
+		// row 34 was purged and row 35 edited meanwhile
+		assert.Equal(t, 2, backfill.Updated)
+		assert.Equal(t, 2, backfill.Skipped)
+		assert.Equal(t, 1, backfill.Unrecognized)
+		assert.Equal(t, 1, backfill.InvalidLevels)
+		skillRepository.Mock.AssertNotCalled(t, "NormalizeSkill", mock.Anything, 32, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("SuccessBackfillDryRun", func(t *testing.T) {
+		mockSkillsToBackfill()
+
+		calls := len(skillRepository.Mock.Calls)
+		backfill, err := skillBackfillServiceTest.Backfill(context.Background(), true)
+		assert.Nil(t, err)
+		assert.Equal(t, 4, backfill.Updated)
+		assert.Equal(t, 0, backfill.Skipped)
+		for _, call := range skillRepository.Mock.Calls[calls:] {
+			assert.NotEqual(t, "NormalizeSkill", call.Method)
+		}
+	})
+
+	t.Run("FailedBackfill", func(t *testing.T) {
+		skillRepository.Mock.On("GetSkillsAfter", mock.Anything, 0, backfillBatchSize).Return([]*models.SkillDTO{
+			{Id: 41, Skill: "golang", Version: 1},
+		}, nil).Once()
+		skillRepository.Mock.On("NormalizeSkill", mock.Anything, 41, 1, "Go", "").Return(errors.New("connection refused")).Once()
+
+		backfill, err := skillBackfillServiceTest.Backfill(context.Background(), false)
+		assert.Equal(t, "failed to normalize skill 41: connection refused", err.Error())
+		assert.Equal(t, 1, backfill.Checked)
+		assert.Equal(t, 0, backfill.Updated)
+	})
+}
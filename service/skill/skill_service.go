@@ -2,29 +2,59 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/helper/taxonomy"
 	transform "test-bpjs/v2/helper/transform"
 	"test-bpjs/v2/models"
 	"test-bpjs/v2/models/request"
 	"test-bpjs/v2/models/response"
 	"test-bpjs/v2/repository"
+	auditService "test-bpjs/v2/service/audit"
+	authorizationService "test-bpjs/v2/service/authorization"
+	resumeService "test-bpjs/v2/service/resume"
 )
 
 type SkillService interface {
 	GetSkillsByCode(ctx context.Context, code int) (*response.SkillList, error)
+	GetDeletedSkillsByCode(ctx context.Context, code int) (*response.SkillList, error)
 	CreateSkill(ctx context.Context, payload request.CreateSkillRequest) (*response.DefaultResponseWithId, error)
-	DeleteSkill(ctx context.Context, code, id int) (*response.DefaultResponse, error)
+	CreateSkills(ctx context.Context, payload request.CreateSkillsRequest) (*response.DefaultResponseWithIds, error)
+	ReplaceSkills(ctx context.Context, payload request.ReplaceSkillsRequest) (*response.DefaultResponseWithIds, error)
+	PatchSkill(ctx context.Context, payload request.PatchSkillRequest) (*response.DefaultResponseWithId, error)
+	DeleteSkill(ctx context.Context, code, id, version int) (*response.DefaultResponse, error)
+	RestoreSkill(ctx context.Context, code, id int) (*response.DefaultResponseWithId, error)
+	SuggestSkills(ctx context.Context, payload request.SuggestSkillsRequest) (*response.SkillSuggestionList, error)
 }
 
+var (
+	ErrSkillNotFound     = errors.New("skill not found")
+	ErrSkillModified     = errors.New("skill was changed by another request")
+	ErrInvalidSkillLevel = errors.New("invalid skill level. use beginner, intermediate, advanced or expert")
+)
+
+// defaultSuggestions is how many skills SuggestSkills returns when no limit
+// is asked for.
+const defaultSuggestions = 10
+
 type skillService struct {
-	skillRepo repository.SkillRepository
+	skillRepo  repository.SkillRepository
+	authorizer authorizationService.Authorizer
+	auditor    auditService.AuditService
+	versioner  resumeService.Versioner
+	taxonomy   *taxonomy.Taxonomy
 }
 
-func NewSkillService(skillRepo repository.SkillRepository) *skillService {
-	return &skillService{skillRepo: skillRepo}
+func NewSkillService(skillRepo repository.SkillRepository, authorizer authorizationService.Authorizer, auditor auditService.AuditService, versioner resumeService.Versioner) *skillService {
+	return &skillService{skillRepo: skillRepo, authorizer: authorizer, auditor: auditor, versioner: versioner, taxonomy: taxonomy.Default()}
 }
 
 func (s *skillService) GetSkillsByCode(ctx context.Context, code int) (*response.SkillList, error) {
+	if err := s.authorizer.CanReadProfile(ctx, code); err != nil {
+		return nil, err
+	}
+
 	var skillList []*response.SkillResponse
 
 	skills, err := s.skillRepo.GetSkillsByProfileCode(ctx, code)
@@ -40,27 +70,304 @@ func (s *skillService) GetSkillsByCode(ctx context.Context, code int) (*response
 	}, nil
 }
 
+// GetDeletedSkillsByCode lists the skill rows in the trash.
+func (s *skillService) GetDeletedSkillsByCode(ctx context.Context, code int) (*response.SkillList, error) {
+	if err := s.authorizer.CanManageProfileData(ctx, code, auth.ScopeProfileRead); err != nil {
+		return nil, err
+	}
+
+	var skillList []*response.SkillResponse
+
+	skills, err := s.skillRepo.GetDeletedSkillsByProfileCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted skills: %v", err)
+	}
+
+	for _, row := range skills {
+		skillList = append(skillList, transform.TransformSkill(row))
+	}
+	return &response.SkillList{
+		Data: skillList,
+	}, nil
+}
+
+// CreateSkill adds a skill row under the canonical name of the skill.
 func (s *skillService) CreateSkill(ctx context.Context, payload request.CreateSkillRequest) (*response.DefaultResponseWithId, error) {
-	skill, err := s.skillRepo.CreateSkill(ctx, &models.Skill{
-		ProfileCode: payload.ProfileCode,
-		Skill:       payload.Skill,
-		Level:       payload.Level,
-	})
+	if err := s.authorizer.CanWriteProfile(ctx, payload.ProfileCode); err != nil {
+		return nil, err
+	}
+
+	row, err := s.skillRow(payload.ProfileCode, payload.Skill, payload.Level)
+	if err != nil {
+		return nil, err
+	}
+	skill, err := s.skillRepo.CreateSkill(ctx, row)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create skill: %v", err)
 	}
+	s.auditor.Record(ctx, auditService.Entry{
+		ProfileCode: payload.ProfileCode,
+		Action:      models.AuditActionCreate,
+		Entity:      models.AuditEntitySkill,
+		EntityId:    skill.Id,
+		After: &models.SkillDTO{
+			ProfileCode: payload.ProfileCode,
+			Id:          skill.Id,
+			Skill:       row.Skill,
+			Level:       row.Level,
+		},
+	})
+	s.versioner.Snapshot(ctx, payload.ProfileCode)
 	return &response.DefaultResponseWithId{
 		ProfileCode: payload.ProfileCode,
 		Id:          skill.Id,
 	}, nil
 }
 
-func (s *skillService) DeleteSkill(ctx context.Context, code, id int) (*response.DefaultResponse, error) {
-	err := s.skillRepo.DeleteSkill(ctx, code, id)
+// CreateSkills adds several skill rows at once. The ids come back in the
+// order the rows were sent.
+func (s *skillService) CreateSkills(ctx context.Context, payload request.CreateSkillsRequest) (*response.DefaultResponseWithIds, error) {
+	if err := s.authorizer.CanWriteProfile(ctx, payload.ProfileCode); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.skillRows(payload.ProfileCode, payload.Data)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := s.skillRepo.CreateSkills(ctx, rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create skills: %v", err)
+	}
+	s.recordCreated(ctx, rows)
+	s.versioner.Snapshot(ctx, payload.ProfileCode)
+	return &response.DefaultResponseWithIds{
+		ProfileCode: payload.ProfileCode,
+		Ids:         ids,
+	}, nil
+}
+
+// ReplaceSkills replaces every skill row of the profile with the given rows.
+// The replaced rows go to the trash, where they can be restored from. The
+// version the client read is the profile's, since the rows are replaced as a
+// whole; ErrSkillModified means the profile has been changed since.
+func (s *skillService) ReplaceSkills(ctx context.Context, payload request.ReplaceSkillsRequest) (*response.DefaultResponseWithIds, error) {
+	if err := s.authorizer.CanWriteProfile(ctx, payload.ProfileCode); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.skillRows(payload.ProfileCode, payload.Data)
+	if err != nil {
+		return nil, err
+	}
+	removed, ids, version, err := s.skillRepo.ReplaceSkills(ctx, payload.ProfileCode, payload.Version, rows)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrSkillNotFound
+	}
+	if errors.Is(err, repository.ErrVersionMismatch) {
+		return nil, ErrSkillModified
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to replace skills: %v", err)
+	}
+	for _, row := range removed {
+		s.auditor.Record(ctx, auditService.Entry{
+			ProfileCode: payload.ProfileCode,
+			Action:      models.AuditActionDelete,
+			Entity:      models.AuditEntitySkill,
+			EntityId:    row.Id,
+			Before:      row,
+		})
+	}
+	s.recordCreated(ctx, rows)
+	s.versioner.Snapshot(ctx, payload.ProfileCode)
+	return &response.DefaultResponseWithIds{
+		ProfileCode: payload.ProfileCode,
+		Ids:         ids,
+		Version:     version,
+	}, nil
+}
+
+func (s *skillService) recordCreated(ctx context.Context, rows []*models.Skill) {
+	for _, row := range rows {
+		s.auditor.Record(ctx, auditService.Entry{
+			ProfileCode: row.ProfileCode,
+			Action:      models.AuditActionCreate,
+			Entity:      models.AuditEntitySkill,
+			EntityId:    row.Id,
+			After: &models.SkillDTO{
+				ProfileCode: row.ProfileCode,
+				Id:          row.Id,
+				Skill:       row.Skill,
+				Level:       row.Level,
+			},
+		})
+	}
+}
+
+func (s *skillService) skillRows(code int, data []request.SkillRow) ([]*models.Skill, error) {
+	rows := make([]*models.Skill, 0, len(data))
+	for _, row := range data {
+		skill, err := s.skillRow(code, row.Skill, row.Level)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, skill)
+	}
+	return rows, nil
+}
+
+// skillRow spells the skill the way the taxonomy does and the level the way
+// models.SkillLevels does.
+func (s *skillService) skillRow(code int, skill, level string) (*models.Skill, error) {
+	level, ok := models.NormalizeSkillLevel(level)
+	if !ok {
+		return nil, ErrInvalidSkillLevel
+	}
+	return &models.Skill{
+		ProfileCode: code,
+		Skill:       s.taxonomy.Canonical(skill),
+		Level:       level,
+	}, nil
+}
+
+// PatchSkill applies a JSON Merge Patch to a skill row.
+func (s *skillService) PatchSkill(ctx context.Context, payload request.PatchSkillRequest) (*response.DefaultResponseWithId, error) {
+	if err := s.authorizer.CanWriteProfile(ctx, payload.ProfileCode); err != nil {
+		return nil, err
+	}
+
+	skills, err := s.skillRepo.GetSkillsByProfileCode(ctx, payload.ProfileCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch skill: %v", err)
+	}
+	var before *models.SkillDTO
+	for _, row := range skills {
+		if row.Id == payload.Id {
+			before = row
+		}
+	}
+	if before == nil {
+		return nil, ErrSkillNotFound
+	}
+
+	skill := &models.Skill{}
+	var columns []string
+	set := func(column string, ok bool) {
+		if ok {
+			columns = append(columns, column)
+		}
+	}
+	set("skill", payload.Skill.Apply(&skill.Skill))
+	set("level", payload.Level.Apply(&skill.Level))
+	normalized, err := s.skillRow(payload.ProfileCode, skill.Skill, skill.Level)
+	if err != nil {
+		return nil, err
+	}
+	skill.Skill, skill.Level = normalized.Skill, normalized.Level
+
+	after, err := s.skillRepo.PatchSkill(ctx, payload.ProfileCode, payload.Id, payload.Version, skill, columns)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrSkillNotFound
+	}
+	if errors.Is(err, repository.ErrVersionMismatch) {
+		return nil, ErrSkillModified
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch skill: %v", err)
+	}
+	s.auditor.Record(ctx, auditService.Entry{
+		ProfileCode: payload.ProfileCode,
+		Action:      models.AuditActionUpdate,
+		Entity:      models.AuditEntitySkill,
+		EntityId:    payload.Id,
+		Before:      before,
+		After:       after,
+	})
+	s.versioner.Snapshot(ctx, payload.ProfileCode)
+	return &response.DefaultResponseWithId{
+		ProfileCode: payload.ProfileCode,
+		Id:          payload.Id,
+		Version:     after.Version,
+	}, nil
+}
+
+func (s *skillService) DeleteSkill(ctx context.Context, code, id, version int) (*response.DefaultResponse, error) {
+	if err := s.authorizer.CanWriteProfile(ctx, code); err != nil {
+		return nil, err
+	}
+
+	skill, err := s.skillRepo.DeleteSkill(ctx, code, id, version)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrSkillNotFound
+	}
+	if errors.Is(err, repository.ErrVersionMismatch) {
+		return nil, ErrSkillModified
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to delete skill: %v", err)
 	}
+	s.auditor.Record(ctx, auditService.Entry{
+		ProfileCode: code,
+		Action:      models.AuditActionDelete,
+		Entity:      models.AuditEntitySkill,
+		EntityId:    id,
+		Before:      skill,
+	})
+	s.versioner.Snapshot(ctx, code)
 	return &response.DefaultResponse{
 		ProfileCode: code,
 	}, nil
 }
+
+func (s *skillService) RestoreSkill(ctx context.Context, code, id int) (*response.DefaultResponseWithId, error) {
+	if err := s.authorizer.CanWriteProfile(ctx, code); err != nil {
+		return nil, err
+	}
+
+	skill, err := s.skillRepo.RestoreSkill(ctx, code, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrSkillNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore skill: %v", err)
+	}
+	s.auditor.Record(ctx, auditService.Entry{
+		ProfileCode: code,
+		Action:      models.AuditActionRestore,
+		Entity:      models.AuditEntitySkill,
+		EntityId:    id,
+		After:       skill,
+	})
+	s.versioner.Snapshot(ctx, code)
+	return &response.DefaultResponseWithId{
+		ProfileCode: code,
+		Id:          id,
+	}, nil
+}
+
+// SuggestSkills completes a skill name from the taxonomy, for autocomplete.
+func (s *skillService) SuggestSkills(ctx context.Context, payload request.SuggestSkillsRequest) (*response.SkillSuggestionList, error) {
+	if _, err := s.authorizer.CurrentUser(ctx); err != nil {
+		return nil, err
+	}
+
+	limit := payload.Limit
+	if limit == 0 {
+		limit = defaultSuggestions
+	}
+	suggestions := s.taxonomy.Suggest(payload.Query, limit)
+
+	result := &response.SkillSuggestionList{
+		TaxonomyVersion: s.taxonomy.Version,
+		Data:            make([]*response.SkillSuggestionResponse, 0, len(suggestions)),
+	}
+	for _, suggestion := range suggestions {
+		result.Data = append(result.Data, &response.SkillSuggestionResponse{
+			Name:     suggestion.Name,
+			Category: suggestion.Category,
+			Alias:    suggestion.Alias,
+		})
+	}
+	return result, nil
+}
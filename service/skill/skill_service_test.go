@@ -2,22 +2,51 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/helper/taxonomy"
 	"test-bpjs/v2/models"
 	"test-bpjs/v2/models/request"
+	dataRepository "test-bpjs/v2/repository"
 	repository "test-bpjs/v2/repository/mocks"
+	auditService "test-bpjs/v2/service/audit"
+	authorizationService "test-bpjs/v2/service/authorization"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 var skillRepository = &repository.SkillRepository{Mock: mock.Mock{}}
-var skillServiceTest = skillService{skillRepo: skillRepository}
+var profileRepository = &repository.ProfileRepository{Mock: mock.Mock{}}
+var consentRepository = &repository.ConsentRepository{Mock: mock.Mock{}}
+var authorizer = authorizationService.NewAuthorizer(profileRepository, consentRepository, "")
+var auditRepository = &repository.AuditRepository{Mock: mock.Mock{}}
+var auditor = auditService.NewAuditService(auditRepository, authorizer)
+var versioner = &versionRecorder{}
+var skillServiceTest = skillService{skillRepo: skillRepository, authorizer: authorizer, auditor: auditor, versioner: versioner, taxonomy: taxonomy.Default()}
+
+// the tests run as user 1, who owns every profile
+var ownerCtx = auth.WithClaims(context.Background(), &auth.Claims{UserId: 1, Role: auth.RoleUser})
+var _ = profileRepository.Mock.On("GetProfileOwner", mock.Anything, mock.Anything).Return(1, nil)
+var _ = auditRepository.Mock.On("CreateAuditLog", mock.Anything, mock.Anything).Return(nil)
+
+// versionRecorder stands in for the resume service and remembers which
+// profiles were versioned
+type versionRecorder struct {
+	codes []int
+}
+
+func (v *versionRecorder) Snapshot(ctx context.Context, code int) {
+	v.codes = append(v.codes, code)
+}
 
 func TestInitSkillService(t *testing.T) {
 	t.Run("SuccessInitSkillService", func(t *testing.T) {
-		assert.NotNil(t, NewSkillService(skillRepository))
+		assert.NotNil(t, NewSkillService(skillRepository, authorizer, auditor, versioner))
 	})
 }
 
@@ -32,7 +61,7 @@ func TestGetSkill(t *testing.T) {
 		response = append(response, result)
 		skillRepository.Mock.On("GetSkillsByProfileCode", mock.Anything, 1).Return(response, nil)
 
-		skills, err := skillServiceTest.GetSkillsByCode(context.Background(), 1)
+		skills, err := skillServiceTest.GetSkillsByCode(ownerCtx, 1)
 		assert.Nil(t, err)
 		assert.NotNil(t, skills)
 	})
@@ -40,7 +69,7 @@ func TestGetSkill(t *testing.T) {
 		// program mock
 		skillRepository.Mock.On("GetSkillsByProfileCode", mock.Anything, 2).Return(nil, errors.New("sql: no rows in result set"))
 
-		skill, err := skillServiceTest.GetSkillsByCode(context.Background(), 2)
+		skill, err := skillServiceTest.GetSkillsByCode(ownerCtx, 2)
 		assert.Nil(t, skill)
 		assert.NotNil(t, err)
 		// assert.Equal(t, "failed to get profile: sql: no rows in result set", err.Error())
@@ -57,27 +86,29 @@ func TestCreateSkill(t *testing.T) {
 		}
 		skillRepository.Mock.On("CreateSkill", mock.Anything, &models.Skill{
 			ProfileCode: 1,
-			Skill:       "Golang",
-			Level:       "Beginner",
+			Skill:       "Go",
+			Level:       "beginner",
 		}).Return(result, nil)
 
-		skills, err := skillServiceTest.CreateSkill(context.Background(), request.CreateSkillRequest{
+		skills, err := skillServiceTest.CreateSkill(ownerCtx, request.CreateSkillRequest{
 			ProfileCode: 1,
 			Skill:       "Golang",
 			Level:       "Beginner",
 		})
 		assert.Nil(t, err)
 		assert.NotNil(t, skills)
+		assert.Contains(t, versioner.codes, 1)
 	})
 	t.Run("FailedCreateSkill", func(t *testing.T) {
 		// program mock
 		skillRepository.Mock.On("CreateSkill", mock.Anything, &models.Skill{
 			ProfileCode: 2,
-			Skill:       "Golang",
-			Level:       "Beginner",
+			Skill:       "Go",
+			Level:       "beginner",
 		}).Return(nil, errors.New(""))
 
-		skill, err := skillServiceTest.CreateSkill(context.Background(), request.CreateSkillRequest{
+		versioner.codes = nil
+		skill, err := skillServiceTest.CreateSkill(ownerCtx, request.CreateSkillRequest{
 			ProfileCode: 2,
 			Skill:       "Golang",
 			Level:       "Beginner",
@@ -86,25 +117,264 @@ func TestCreateSkill(t *testing.T) {
 		assert.NotNil(t, err)
 		// assert.Equal(t, "failed to get profile: sql: no rows in result set", err.Error())
 		assert.Contains(t, err.Error(), "failed to create skill")
+		assert.Empty(t, versioner.codes)
+	})
+	t.Run("SuccessCreateSkillUnknownToTaxonomy", func(t *testing.T) {
+		skillRepository.Mock.On("CreateSkill", mock.Anything, &models.Skill{
+			ProfileCode: 96,
+			Skill:       "Underwater Basket Weaving",
+		}).Return(&models.SkillDTO{Id: 7}, nil).Once()
+
+		skill, err := skillServiceTest.CreateSkill(ownerCtx, request.CreateSkillRequest{
+			ProfileCode: 96,
+			Skill:       " Underwater  Basket Weaving ",
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 7, skill.Id)
+	})
+	t.Run("FailedCreateSkillInvalidLevel", func(t *testing.T) {
+		skill, err := skillServiceTest.CreateSkill(ownerCtx, request.CreateSkillRequest{
+			ProfileCode: 96,
+			Skill:       "Golang",
+			Level:       "Guru",
+		})
+		assert.Nil(t, skill)
+		assert.ErrorIs(t, err, ErrInvalidSkillLevel)
+		skillRepository.Mock.AssertNotCalled(t, "CreateSkill", mock.Anything, mock.MatchedBy(func(skill *models.Skill) bool {
+			return skill.Level == "guru"
+		}))
 	})
 }
 
 func TestDeleteSkill(t *testing.T) {
 	t.Run("SuccessDeleteSkill", func(t *testing.T) {
-		skillRepository.Mock.On("DeleteSkill", mock.Anything, 1, 1).Return(nil)
+		skillRepository.Mock.On("DeleteSkill", mock.Anything, 1, 1, 1).Return(&models.SkillDTO{ProfileCode: 1, Id: 1}, nil)
 
-		skills, err := skillServiceTest.DeleteSkill(context.Background(), 1, 1)
+		skills, err := skillServiceTest.DeleteSkill(ownerCtx, 1, 1, 1)
 		assert.Nil(t, err)
 		assert.NotNil(t, skills)
 	})
 	t.Run("FailedCreateSkill", func(t *testing.T) {
 		// program mock
-		skillRepository.Mock.On("DeleteSkill", mock.Anything, 1, 2).Return(errors.New(""))
+		skillRepository.Mock.On("DeleteSkill", mock.Anything, 1, 2, 1).Return(nil, errors.New(""))
 
-		skill, err := skillServiceTest.DeleteSkill(context.Background(), 1, 2)
+		skill, err := skillServiceTest.DeleteSkill(ownerCtx, 1, 2, 1)
 		assert.Nil(t, skill)
 		assert.NotNil(t, err)
 		// assert.Equal(t, "failed to get profile: sql: no rows in result set", err.Error())
 		assert.Contains(t, err.Error(), "failed to delete skill")
 	})
+	t.Run("FailedDeleteSkill_NotFound", func(t *testing.T) {
+		skillRepository.Mock.On("DeleteSkill", mock.Anything, 1, 3, 1).Return(&models.SkillDTO{}, dataRepository.ErrNotFound)
+
+		skill, err := skillServiceTest.DeleteSkill(ownerCtx, 1, 3, 1)
+		assert.Nil(t, skill)
+		assert.ErrorIs(t, err, ErrSkillNotFound)
+	})
+	t.Run("FailedDeleteSkill_Modified", func(t *testing.T) {
+		skillRepository.Mock.On("DeleteSkill", mock.Anything, 1, 4, 2).Return(&models.SkillDTO{}, dataRepository.ErrVersionMismatch)
+
+		skill, err := skillServiceTest.DeleteSkill(ownerCtx, 1, 4, 2)
+		assert.Nil(t, skill)
+		assert.ErrorIs(t, err, ErrSkillModified)
+	})
+}
+
+func TestPatchSkill(t *testing.T) {
+	t.Run("SuccessPatchSkill", func(t *testing.T) {
+		skillRepository.Mock.On("GetSkillsByProfileCode", mock.Anything, 90).Return([]*models.SkillDTO{
+			{Id: 5, Skill: "Golang", Level: "Beginner", Version: 2},
+		}, nil).Once()
+		skillRepository.Mock.On("PatchSkill", mock.Anything, 90, 5, 2, &models.Skill{Level: ""}, []string{"level"}).
+			Return(&models.SkillDTO{Id: 5, Skill: "Golang", Version: 3}, nil).Once()
+
+		var patch request.PatchSkillRequest
+		assert.Nil(t, json.Unmarshal([]byte(`{"level": null}`), &patch))
+		patch.ProfileCode, patch.Id, patch.Version = 90, 5, 2
+
+		result, err := skillServiceTest.PatchSkill(ownerCtx, patch)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, result.Version)
+		auditRepository.Mock.AssertCalled(t, "CreateAuditLog", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+			return entry.Action == models.AuditActionUpdate && entry.ProfileCode == 90 &&
+				entry.Changes == `{"level":{"before":"Beginner","after":""}}`
+		}))
+	})
+	t.Run("FailedPatchSkill_NotFound", func(t *testing.T) {
+		skillRepository.Mock.On("GetSkillsByProfileCode", mock.Anything, 91).Return([]*models.SkillDTO{}, nil).Once()
+
+		result, err := skillServiceTest.PatchSkill(ownerCtx, request.PatchSkillRequest{ProfileCode: 91, Id: 5, Version: 1})
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrSkillNotFound)
+	})
+}
+
+func TestGetDeletedSkills(t *testing.T) {
+	t.Run("SuccessGetDeletedSkills", func(t *testing.T) {
+		deletedAt := time.Now()
+		skillRepository.Mock.On("GetDeletedSkillsByProfileCode", mock.Anything, 1).Return([]*models.SkillDTO{
+			{Id: 4, Skill: "Golang", Level: "Beginner", DeletedAt: &deletedAt},
+		}, nil).Once()
+
+		skills, err := skillServiceTest.GetDeletedSkillsByCode(ownerCtx, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, &deletedAt, skills.Data[0].DeletedAt)
+	})
+	t.Run("FailedGetDeletedSkills_Recruiter", func(t *testing.T) {
+		ctx := auth.WithClaims(context.Background(), &auth.Claims{UserId: 2, Role: auth.RoleRecruiter})
+
+		skills, err := skillServiceTest.GetDeletedSkillsByCode(ctx, 1)
+		assert.Nil(t, skills)
+		assert.ErrorIs(t, err, authorizationService.ErrForbidden)
+	})
+}
+
+func TestRestoreSkill(t *testing.T) {
+	t.Run("SuccessRestoreSkill", func(t *testing.T) {
+		versioner.codes = nil
+		skillRepository.Mock.On("RestoreSkill", mock.Anything, 1, 4).Return(&models.SkillDTO{ProfileCode: 1, Id: 4, Skill: "Golang"}, nil).Once()
+
+		skill, err := skillServiceTest.RestoreSkill(ownerCtx, 1, 4)
+		assert.Nil(t, err)
+		assert.Equal(t, 4, skill.Id)
+		assert.Equal(t, []int{1}, versioner.codes)
+		auditRepository.Mock.AssertCalled(t, "CreateAuditLog", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+			return entry.Action == models.AuditActionRestore && entry.Entity == models.AuditEntitySkill && entry.EntityId == 4
+		}))
+	})
+	t.Run("FailedRestoreSkill", func(t *testing.T) {
+		skillRepository.Mock.On("RestoreSkill", mock.Anything, 1, 5).Return(nil, sql.ErrNoRows).Once()
+
+		skill, err := skillServiceTest.RestoreSkill(ownerCtx, 1, 5)
+		assert.Nil(t, skill)
+		assert.Contains(t, err.Error(), "failed to restore skill")
+	})
+}
+
+func TestCreateSkills(t *testing.T) {
+	t.Run("SuccessCreateSkills", func(t *testing.T) {
+		skillRepository.Mock.On("CreateSkills", mock.Anything, mock.MatchedBy(func(rows []*models.Skill) bool {
+			return len(rows) == 2 && rows[0].ProfileCode == 92 && rows[0].Skill == "Go" && rows[0].Level == "expert" && rows[1].Skill == "SQL"
+		})).Run(func(args mock.Arguments) {
+			rows := args.Get(1).([]*models.Skill)
+			rows[0].Id, rows[1].Id = 11, 12
+		}).Return([]int{11, 12}, nil).Once()
+
+		result, err := skillServiceTest.CreateSkills(ownerCtx, request.CreateSkillsRequest{
+			ProfileCode: 92,
+			Data:        []request.SkillRow{{Skill: "Golang", Level: "Expert"}, {Skill: "SQL", Level: "Beginner"}},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []int{11, 12}, result.Ids)
+		auditRepository.Mock.AssertCalled(t, "CreateAuditLog", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+			return entry.Action == models.AuditActionCreate && entry.ProfileCode == 92 && entry.EntityId == 12
+		}))
+	})
+	t.Run("FailedCreateSkills", func(t *testing.T) {
+		skillRepository.Mock.On("CreateSkills", mock.Anything, mock.MatchedBy(func(rows []*models.Skill) bool {
+			return len(rows) == 1 && rows[0].ProfileCode == 93
+		})).Return(nil, errors.New("unexpected")).Once()
+
+		result, err := skillServiceTest.CreateSkills(ownerCtx, request.CreateSkillsRequest{
+			ProfileCode: 93,
+			Data:        []request.SkillRow{{Skill: "Golang"}},
+		})
+		assert.Nil(t, result)
+		assert.Error(t, err)
+	})
+}
+
+func TestReplaceSkills(t *testing.T) {
+	t.Run("SuccessReplaceSkills", func(t *testing.T) {
+		skillRepository.Mock.On("ReplaceSkills", mock.Anything, 94, 1, mock.MatchedBy(func(rows []*models.Skill) bool {
+			return len(rows) == 1 && rows[0].Skill == "Rust"
+		})).Run(func(args mock.Arguments) {
+			args.Get(3).([]*models.Skill)[0].Id = 21
+		}).Return([]*models.SkillDTO{{ProfileCode: 94, Id: 20, Skill: "Golang"}}, []int{21}, 2, nil).Once()
+
+		result, err := skillServiceTest.ReplaceSkills(ownerCtx, request.ReplaceSkillsRequest{
+			ProfileCode: 94,
+			Version:     1,
+			Data:        []request.SkillRow{{Skill: "Rust", Level: "Beginner"}},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, result.Version)
+		assert.Equal(t, []int{21}, result.Ids)
+		auditRepository.Mock.AssertCalled(t, "CreateAuditLog", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+			return entry.Action == models.AuditActionDelete && entry.ProfileCode == 94 && entry.EntityId == 20
+		}))
+		auditRepository.Mock.AssertCalled(t, "CreateAuditLog", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+			return entry.Action == models.AuditActionCreate && entry.ProfileCode == 94 && entry.EntityId == 21
+		}))
+	})
+	t.Run("SuccessReplaceSkills_Empty", func(t *testing.T) {
+		skillRepository.Mock.On("ReplaceSkills", mock.Anything, 95, 1, []*models.Skill{}).Return([]*models.SkillDTO{}, []int{}, 2, nil).Once()
+
+		result, err := skillServiceTest.ReplaceSkills(ownerCtx, request.ReplaceSkillsRequest{ProfileCode: 95, Version: 1, Data: []request.SkillRow{}})
+		assert.Nil(t, err)
+		assert.Empty(t, result.Ids)
+	})
+	t.Run("FailedReplaceSkills_Modified", func(t *testing.T) {
+		skillRepository.Mock.On("ReplaceSkills", mock.Anything, 96, 1, mock.Anything).Return(nil, nil, 0, dataRepository.ErrVersionMismatch).Once()
+
+		result, err := skillServiceTest.ReplaceSkills(ownerCtx, request.ReplaceSkillsRequest{ProfileCode: 96, Version: 1, Data: []request.SkillRow{{Skill: "Rust", Level: "Beginner"}}})
+		assert.Nil(t, result)
+		assert.True(t, errors.Is(err, ErrSkillModified))
+	})
+}
+
+func TestPatchSkillNormalizes(t *testing.T) {
+	t.Run("SuccessPatchSkillNormalizes", func(t *testing.T) {
+		skillRepository.Mock.On("GetSkillsByProfileCode", mock.Anything, 97).Return([]*models.SkillDTO{
+			{Id: 8, Skill: "Go", Level: "beginner", Version: 1},
+		}, nil).Once()
+		skillRepository.Mock.On("PatchSkill", mock.Anything, 97, 8, 1, &models.Skill{Skill: "Kubernetes", Level: "advanced"}, []string{"skill", "level"}).
+			Return(&models.SkillDTO{Id: 8, Skill: "Kubernetes", Level: "advanced", Version: 2}, nil).Once()
+
+		var patch request.PatchSkillRequest
+		assert.Nil(t, json.Unmarshal([]byte(`{"skill": "k8s", "level": "Advanced"}`), &patch))
+		patch.ProfileCode, patch.Id, patch.Version = 97, 8, 1
+
+		result, err := skillServiceTest.PatchSkill(ownerCtx, patch)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, result.Version)
+	})
+	t.Run("FailedPatchSkillInvalidLevel", func(t *testing.T) {
+		skillRepository.Mock.On("GetSkillsByProfileCode", mock.Anything, 98).Return([]*models.SkillDTO{
+			{Id: 9, Skill: "Go", Version: 1},
+		}, nil).Once()
+
+		var patch request.PatchSkillRequest
+		assert.Nil(t, json.Unmarshal([]byte(`{"level": "Guru"}`), &patch))
+		patch.ProfileCode, patch.Id, patch.Version = 98, 9, 1
+
+		result, err := skillServiceTest.PatchSkill(ownerCtx, patch)
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrInvalidSkillLevel)
+	})
+}
+
+func TestSuggestSkills(t *testing.T) {
+	t.Run("SuccessSuggestSkills", func(t *testing.T) {
+		result, err := skillServiceTest.SuggestSkills(ownerCtx, request.SuggestSkillsRequest{Query: "postgres"})
+		assert.Nil(t, err)
+		assert.Equal(t, taxonomy.Default().Version, result.TaxonomyVersion)
+		assert.Equal(t, "PostgreSQL", result.Data[0].Name)
+		assert.Equal(t, "database", result.Data[0].Category)
+		assert.Equal(t, "postgres", result.Data[0].Alias)
+	})
+	t.Run("SuccessSuggestSkillsLimit", func(t *testing.T) {
+		result, err := skillServiceTest.SuggestSkills(ownerCtx, request.SuggestSkillsRequest{Query: "a", Limit: 3})
+		assert.Nil(t, err)
+		assert.Len(t, result.Data, 3)
+
+		result, err = skillServiceTest.SuggestSkills(ownerCtx, request.SuggestSkillsRequest{Query: "a"})
+		assert.Nil(t, err)
+		assert.Len(t, result.Data, defaultSuggestions)
+	})
+	t.Run("FailedSuggestSkillsUnauthenticated", func(t *testing.T) {
+		result, err := skillServiceTest.SuggestSkills(context.Background(), request.SuggestSkillsRequest{Query: "go"})
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, authorizationService.ErrUnauthenticated)
+	})
 }
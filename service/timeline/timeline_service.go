@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/models/response"
+	"test-bpjs/v2/repository"
+	authorizationService "test-bpjs/v2/service/authorization"
+	"time"
+)
+
+// DefaultGapThreshold is how long a break between jobs may last before it is
+// reported as a gap when no threshold is configured.
+const DefaultGapThreshold = 90 * 24 * time.Hour
+
+// UnspecifiedType groups the experience of rows without an employment type.
+const UnspecifiedType = "unspecified"
+
+// typeOrder is the order the experience per employment type is listed in.
+var typeOrder = append(append([]string(nil), models.EmploymentTypes...), UnspecifiedType)
+
+const (
+	day         = 24 * time.Hour
+	daysPerYear = 365.25
+)
+
+// TimelineService derives the experience of a profile from its employment
+// rows: how long it worked in total and per employment type, the gaps
+// between jobs and the jobs held at once.
+type TimelineService interface {
+	GetTimeline(ctx context.Context, code int) (*response.EmploymentTimelineResponse, error)
+	Analyze(code int, employment []*models.EmploymentDTO) *response.EmploymentTimelineResponse
+}
+
+type timelineService struct {
+	employmentRepo repository.EmploymentRepository
+	authorizer     authorizationService.Authorizer
+	gapThreshold   time.Duration
+	now            func() time.Time
+}
+
+func NewTimelineService(employmentRepo repository.EmploymentRepository, authorizer authorizationService.Authorizer, gapThreshold time.Duration) *timelineService {
+	if gapThreshold <= 0 {
+		gapThreshold = DefaultGapThreshold
+	}
+	return &timelineService{employmentRepo: employmentRepo, authorizer: authorizer, gapThreshold: gapThreshold, now: time.Now}
+}
+
+func (t *timelineService) GetTimeline(ctx context.Context, code int) (*response.EmploymentTimelineResponse, error) {
+	if err := t.authorizer.CanReadProfile(ctx, code); err != nil {
+		return nil, err
+	}
+
+	employment, err := t.employmentRepo.GetEmploymentByProfileCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get employments: %v", err)
+	}
+	return t.Analyze(code, employment), nil
+}
+
+// Analyze builds the timeline of the given rows. Rows without an end date are
+// still ongoing, and rows without a start date or ending before they start
+// are left out.
+func (t *timelineService) Analyze(code int, employment []*models.EmploymentDTO) *response.EmploymentTimelineResponse {
+	today := t.now().UTC().Truncate(day)
+	jobs := jobPeriods(employment, today)
+
+	timeline := &response.EmploymentTimelineResponse{
+		ProfileCode: code,
+		ByType:      []*response.TypeExperienceResponse{},
+		Gaps:        []*response.EmploymentGapResponse{},
+		Overlaps:    []*response.EmploymentOverlapResponse{},
+	}
+
+	periods := make([]period, 0, len(jobs))
+	byType := map[string][]period{}
+	for _, job := range jobs {
+		periods = append(periods, job.period)
+		byType[job.employmentType] = append(byType[job.employmentType], job.period)
+	}
+
+	merged := merge(periods)
+	total := days(merged)
+	timeline.TotalExperience = response.ExperienceResponse{Days: total, Years: years(total)}
+	for _, employmentType := range typeOrder {
+		typePeriods, ok := byType[employmentType]
+		if !ok {
+			continue
+		}
+		total := days(merge(typePeriods))
+		timeline.ByType = append(timeline.ByType, &response.TypeExperienceResponse{
+			EmploymentType: employmentType,
+			Days:           total,
+			Years:          years(total),
+		})
+	}
+
+	for i := 1; i < len(merged); i++ {
+		if gap := merged[i].start.Sub(merged[i-1].end); gap > t.gapThreshold {
+			timeline.Gaps = append(timeline.Gaps, &response.EmploymentGapResponse{
+				From: merged[i-1].end,
+				To:   merged[i].start,
+				Days: int(gap / day),
+			})
+		}
+	}
+	if len(merged) > 0 {
+		last := merged[len(merged)-1]
+		if gap := today.Sub(last.end); gap > t.gapThreshold {
+			timeline.Gaps = append(timeline.Gaps, &response.EmploymentGapResponse{
+				From:    last.end,
+				To:      today,
+				Days:    int(gap / day),
+				Current: true,
+			})
+		}
+	}
+
+	for i := range jobs {
+		for j := i + 1; j < len(jobs); j++ {
+			if !jobs[j].start.Before(jobs[i].end) {
+				// jobs are sorted by start, so no later job overlaps either
+				break
+			}
+			to := jobs[i].end
+			if jobs[j].end.Before(to) {
+				to = jobs[j].end
+			}
+			timeline.Overlaps = append(timeline.Overlaps, &response.EmploymentOverlapResponse{
+				EmploymentIds: []int{jobs[i].id, jobs[j].id},
+				From:          jobs[j].start,
+				To:            to,
+				Days:          int(to.Sub(jobs[j].start) / day),
+			})
+		}
+	}
+	return timeline
+}
+
+// ExperienceDays counts the days worked in the given rows until now, the
+// way Analyze totals them: time spent in several jobs at once counts once.
+func ExperienceDays(employment []*models.EmploymentDTO, now time.Time) int {
+	jobs := jobPeriods(employment, now.UTC().Truncate(day))
+	periods := make([]period, 0, len(jobs))
+	for _, job := range jobs {
+		periods = append(periods, job.period)
+	}
+	return days(merge(periods))
+}
+
+type period struct {
+	start time.Time
+	end   time.Time
+}
+
+type job struct {
+	period
+	id             int
+	employmentType string
+}
+
+// jobPeriods turns the rows into periods sorted by start. Ongoing rows and
+// rows ending in the future end today.
+func jobPeriods(employment []*models.EmploymentDTO, today time.Time) []job {
+	jobs := make([]job, 0, len(employment))
+	for _, row := range employment {
+		if row.StartDate.IsZero() {
+			continue
+		}
+		start := row.StartDate.UTC().Truncate(day)
+		end := row.EndDate.UTC().Truncate(day)
+		if row.EndDate.IsZero() || end.After(today) {
+			end = today
+		}
+		if !end.After(start) {
+			continue
+		}
+		employmentType := row.EmploymentType
+		if employmentType == "" {
+			employmentType = UnspecifiedType
+		}
+		jobs = append(jobs, job{period: period{start: start, end: end}, id: row.Id, employmentType: employmentType})
+	}
+	sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].start.Before(jobs[j].start) })
+	return jobs
+}
+
+// merge joins overlapping and touching periods, so time spent in several
+// jobs at once is only counted once. It returns them sorted by start.
+func merge(periods []period) []period {
+	sorted := append([]period(nil), periods...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].start.Before(sorted[j].start) })
+
+	var merged []period
+	for _, p := range sorted {
+		if n := len(merged); n > 0 && !p.start.After(merged[n-1].end) {
+			if p.end.After(merged[n-1].end) {
+				merged[n-1].end = p.end
+			}
+			continue
+		}
+		merged = append(merged, p)
+	}
+	return merged
+}
+
+func days(periods []period) int {
+	var total time.Duration
+	for _, p := range periods {
+		total += p.end.Sub(p.start)
+	}
+	return int(total / day)
+}
+
+func years(days int) float64 {
+	return math.Round(float64(days)/daysPerYear*10) / 10
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"test-bpjs/v2/helper/auth"
+	"test-bpjs/v2/models"
+	repository "test-bpjs/v2/repository/mocks"
+	authorizationService "test-bpjs/v2/service/authorization"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var employmentRepository = &repository.EmploymentRepository{Mock: mock.Mock{}}
+var profileRepository = &repository.ProfileRepository{Mock: mock.Mock{}}
+var consentRepository = &repository.ConsentRepository{Mock: mock.Mock{}}
+var authorizer = authorizationService.NewAuthorizer(profileRepository, consentRepository, "")
+var timelineServiceTest = timelineService{
+	employmentRepo: employmentRepository,
+	authorizer:     authorizer,
+	gapThreshold:   DefaultGapThreshold,
+	now:            func() time.Time { return date(2024, 1, 1) },
+}
+
+// the tests run as user 1, who owns every profile
+var ownerCtx = auth.WithClaims(context.Background(), &auth.Claims{UserId: 1, Role: auth.RoleUser})
+var _ = profileRepository.Mock.On("GetProfileOwner", mock.Anything, mock.Anything).Return(1, nil)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestInitTimelineService(t *testing.T) {
+	t.Run("SuccessInitTimelineService", func(t *testing.T) {
+		timeline := NewTimelineService(employmentRepository, authorizer, 0)
+		assert.NotNil(t, timeline)
+		assert.Equal(t, DefaultGapThreshold, timeline.gapThreshold)
+	})
+}
+
+func TestGetTimeline(t *testing.T) {
+	t.Run("SuccessGetTimeline", func(t *testing.T) {
+		employmentRepository.Mock.On("GetEmploymentByProfileCode", mock.Anything, 1).Return([]*models.EmploymentDTO{
+			{Id: 1, StartDate: date(2022, 1, 1), EndDate: date(2023, 1, 1), EmploymentType: models.EmploymentTypeFullTime},
+		}, nil).Once()
+
+		timeline, err := timelineServiceTest.GetTimeline(ownerCtx, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, timeline.ProfileCode)
+		assert.Equal(t, 365, timeline.TotalExperience.Days)
+		assert.Equal(t, 1.0, timeline.TotalExperience.Years)
+	})
+
+	t.Run("FailedGetTimeline", func(t *testing.T) {
+		employmentRepository.Mock.On("GetEmploymentByProfileCode", mock.Anything, 2).Return(nil, errors.New("connection refused")).Once()
+
+		timeline, err := timelineServiceTest.GetTimeline(ownerCtx, 2)
+		assert.Nil(t, timeline)
+		assert.Equal(t, "failed to get employments: connection refused", err.Error())
+	})
+
+	t.Run("FailedGetTimelineUnauthenticated", func(t *testing.T) {
+		timeline, err := timelineServiceTest.GetTimeline(context.Background(), 3)
+		assert.Nil(t, timeline)
+		assert.ErrorIs(t, err, authorizationService.ErrUnauthenticated)
+	})
+}
+
+func TestAnalyze(t *testing.T) {
+	t.Run("SuccessMergeOverlappingJobs", func(t *testing.T) {
+		timeline := timelineServiceTest.Analyze(1, []*models.EmploymentDTO{
+			{Id: 1, StartDate: date(2020, 1, 1), EndDate: date(2021, 1, 1), EmploymentType: models.EmploymentTypeFullTime},
+			{Id: 2, StartDate: date(2020, 7, 1), EndDate: date(2020, 10, 1), EmploymentType: models.EmploymentTypeFreelance},
+		})
+
+		assert.Equal(t, 366, timeline.TotalExperience.Days)
+		assert.Len(t, timeline.ByType, 2)
+		assert.Equal(t, models.EmploymentTypeFullTime, timeline.ByType[0].EmploymentType)
+		assert.Equal(t, 366, timeline.ByType[0].Days)
+		assert.Equal(t, models.EmploymentTypeFreelance, timeline.ByType[1].EmploymentType)
+		assert.Equal(t, 92, timeline.ByType[1].Days)
+
+		assert.Len(t, timeline.Overlaps, 1)
+		assert.Equal(t, []int{1, 2}, timeline.Overlaps[0].EmploymentIds)
+		assert.Equal(t, date(2020, 7, 1), timeline.Overlaps[0].From)
+		assert.Equal(t, date(2020, 10, 1), timeline.Overlaps[0].To)
+		assert.Equal(t, 92, timeline.Overlaps[0].Days)
+	})
+
+	t.Run("SuccessGroupRowsWithoutType", func(t *testing.T) {
+		timeline := timelineServiceTest.Analyze(1, []*models.EmploymentDTO{
+			{Id: 1, StartDate: date(2023, 1, 1), EndDate: date(2023, 7, 1)},
+			{Id: 2, StartDate: date(2023, 7, 1), EmploymentType: models.EmploymentTypeContract},
+		})
+
+		assert.Equal(t, 365, timeline.TotalExperience.Days)
+		assert.Equal(t, models.EmploymentTypeContract, timeline.ByType[0].EmploymentType)
+		assert.Equal(t, 184, timeline.ByType[0].Days)
+		assert.Equal(t, UnspecifiedType, timeline.ByType[1].EmploymentType)
+		assert.Equal(t, 181, timeline.ByType[1].Days)
+		assert.Empty(t, timeline.Gaps)
+		assert.Empty(t, timeline.Overlaps)
+	})
+
+	t.Run("SuccessReportGaps", func(t *testing.T) {
+		timeline := timelineServiceTest.Analyze(1, []*models.EmploymentDTO{
+			{Id: 1, StartDate: date(2019, 1, 1), EndDate: date(2020, 1, 1)},
+			{Id: 2, StartDate: date(2020, 3, 1), EndDate: date(2021, 1, 1)},
+			{Id: 3, StartDate: date(2021, 9, 1), EndDate: date(2023, 1, 1)},
+		})
+
+		// the two months before job 2 stay under the threshold
+		assert.Len(t, timeline.Gaps, 2)
+		assert.Equal(t, date(2021, 1, 1), timeline.Gaps[0].From)
+		assert.Equal(t, date(2021, 9, 1), timeline.Gaps[0].To)
+		assert.Equal(t, 243, timeline.Gaps[0].Days)
+		assert.False(t, timeline.Gaps[0].Current)
+		assert.Equal(t, date(2023, 1, 1), timeline.Gaps[1].From)
+		assert.Equal(t, date(2024, 1, 1), timeline.Gaps[1].To)
+		assert.True(t, timeline.Gaps[1].Current)
+	})
+
+	t.Run("SuccessSkipInvalidRows", func(t *testing.T) {
+		timeline := timelineServiceTest.Analyze(1, []*models.EmploymentDTO{
+			{Id: 1, EndDate: date(2020, 1, 1)},
+			{Id: 2, StartDate: date(2021, 1, 1), EndDate: date(2020, 1, 1)},
+			{Id: 3, StartDate: date(2023, 12, 1), EndDate: date(2025, 1, 1)},
+		})
+
+		assert.Equal(t, 31, timeline.TotalExperience.Days)
+		assert.Empty(t, timeline.Gaps)
+	})
+
+	t.Run("SuccessAnalyzeNoEmployment", func(t *testing.T) {
+		timeline := timelineServiceTest.Analyze(1, nil)
+
+		assert.Equal(t, 0, timeline.TotalExperience.Days)
+		assert.Empty(t, timeline.ByType)
+		assert.Empty(t, timeline.Gaps)
+		assert.Empty(t, timeline.Overlaps)
+	})
+}
+
+func TestExperienceDays(t *testing.T) {
+	t.Run("SuccessMatchAnalyzeTotal", func(t *testing.T) {
+		employment := []*models.EmploymentDTO{
+			{Id: 1, StartDate: date(2020, 1, 1), EndDate: date(2021, 1, 1)},
+			{Id: 2, StartDate: date(2020, 7, 1), EndDate: date(2020, 10, 1)},
+			{Id: 3, StartDate: date(2023, 7, 1)},
+		}
+
+		total := timelineServiceTest.Analyze(1, employment).TotalExperience.Days
+		assert.Equal(t, 550, total)
+		assert.Equal(t, total, ExperienceDays(employment, date(2024, 1, 1)))
+	})
+}
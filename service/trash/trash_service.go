@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"test-bpjs/v2/helper/storage"
+	"test-bpjs/v2/models"
+	"test-bpjs/v2/repository"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+// purgeReason is stored on the erasure receipts of purged profiles.
+const purgeReason = "trash retention expired"
+
+// TrashService empties the trash of rows deleted longer ago than the
+// retention period.
+type TrashService interface {
+	PurgeTrash(ctx context.Context, before time.Time) (*models.TrashPurge, error)
+}
+
+type trashService struct {
+	trashRepo   repository.TrashRepository
+	profileRepo repository.ProfileRepository
+	privacyRepo repository.PrivacyRepository
+}
+
+func NewTrashService(trashRepo repository.TrashRepository, profileRepo repository.ProfileRepository, privacyRepo repository.PrivacyRepository) *trashService {
+	return &trashService{trashRepo: trashRepo, profileRepo: profileRepo, privacyRepo: privacyRepo}
+}
+
+// PurgeTrash removes education, employment and skill rows deleted before the
+// given time, then erases the profiles deleted before it along with all of
+// their rows and their photo. Profiles already erased stay erased when a
+// later one fails.
+func (t *trashService) PurgeTrash(ctx context.Context, before time.Time) (*models.TrashPurge, error) {
+	purge := &models.TrashPurge{}
+	if err := t.trashRepo.PurgeDeletedRows(ctx, before, purge); err != nil {
+		return purge, fmt.Errorf("failed to purge deleted rows: %v", err)
+	}
+
+	profiles, err := t.trashRepo.GetDeletedProfiles(ctx, before)
+	if err != nil {
+		return purge, fmt.Errorf("failed to get deleted profiles: %v", err)
+	}
+	for _, profile := range profiles {
+		// RequestedBy stays 0, as nobody asked for this erasure
+		receipt := &models.ErasureReceipt{
+			ReceiptId:       ulid.Make().String(),
+			ProfilePublicId: profile.PublicId,
+			Mode:            models.ErasureModeDelete,
+			Reason:          purgeReason,
+			PhotoRemoved:    profile.PhotoUrl != "",
+			ErasedAt:        time.Now().UTC(),
+		}
+		if err := t.privacyRepo.EraseProfile(ctx, profile.ProfileCode, receipt); err != nil {
+			return purge, fmt.Errorf("failed to erase profile %d: %v", profile.ProfileCode, err)
+		}
+		purge.Profiles++
+		purge.ReceiptIds = append(purge.ReceiptIds, receipt.ReceiptId)
+
+		if _, err := storage.ReleasePhoto(ctx, t.profileRepo, profile.PhotoHash, profile.PhotoUrl); err != nil {
+			log.WithContext(ctx).Warnf("erasure %s: %v", receipt.ReceiptId, err)
+		}
+	}
+	return purge, nil
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"test-bpjs/v2/models"
+	repository "test-bpjs/v2/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var trashRepository = &repository.TrashRepository{Mock: mock.Mock{}}
+var profileRepository = &repository.ProfileRepository{Mock: mock.Mock{}}
+var privacyRepository = &repository.PrivacyRepository{Mock: mock.Mock{}}
+var trashServiceTest = NewTrashService(trashRepository, profileRepository, privacyRepository)
+
+func TestPurgeTrash(t *testing.T) {
+	t.Run("SuccessPurgeTrash", func(t *testing.T) {
+		before := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+		trashRepository.Mock.On("PurgeDeletedRows", mock.Anything, before, mock.Anything).Run(func(args mock.Arguments) {
+			purge := args.Get(2).(*models.TrashPurge)
+			purge.Education, purge.Skills = 2, 3
+		}).Return(nil).Once()
+		trashRepository.Mock.On("GetDeletedProfiles", mock.Anything, before).Return([]*models.ProfileDTO{
+			{ProfileCode: 1, PublicId: "01JAB3Q8W0RM0ZTRBPN7C2Z1K4"},
+			{ProfileCode: 2, PublicId: "01JAB3Q8W0RM0ZTRBPN7C2Z1K5"},
+		}, nil).Once()
+		privacyRepository.Mock.On("EraseProfile", mock.Anything, 1, mock.Anything).Return(nil).Once()
+		privacyRepository.Mock.On("EraseProfile", mock.Anything, 2, mock.Anything).Return(nil).Once()
+
+		purge, err := trashServiceTest.PurgeTrash(context.Background(), before)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, purge.Profiles)
+		assert.Equal(t, 2, purge.Education)
+		assert.Equal(t, 3, purge.Skills)
+		assert.Len(t, purge.ReceiptIds, 2)
+		privacyRepository.Mock.AssertCalled(t, "EraseProfile", mock.Anything, 2, mock.MatchedBy(func(receipt *models.ErasureReceipt) bool {
+			return receipt.Mode == models.ErasureModeDelete && receipt.Reason == purgeReason &&
+				receipt.ProfilePublicId == "01JAB3Q8W0RM0ZTRBPN7C2Z1K5" && receipt.RequestedBy == 0
+		}))
+	})
+	t.Run("FailedPurgeTrash", func(t *testing.T) {
+		before := time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+		trashRepository.Mock.On("PurgeDeletedRows", mock.Anything, before, mock.Anything).Return(nil).Once()
+		trashRepository.Mock.On("GetDeletedProfiles", mock.Anything, before).Return([]*models.ProfileDTO{
+			{ProfileCode: 3, PublicId: "01JAB3Q8W0RM0ZTRBPN7C2Z1K6"},
+			{ProfileCode: 4, PublicId: "01JAB3Q8W0RM0ZTRBPN7C2Z1K7"},
+		}, nil).Once()
+		privacyRepository.Mock.On("EraseProfile", mock.Anything, 3, mock.Anything).Return(nil).Once()
+		privacyRepository.Mock.On("EraseProfile", mock.Anything, 4, mock.Anything).Return(errors.New("")).Once()
+
+		purge, err := trashServiceTest.PurgeTrash(context.Background(), before)
+		assert.Contains(t, err.Error(), "failed to erase profile 4")
+		assert.Equal(t, 1, purge.Profiles)
+	})
+}